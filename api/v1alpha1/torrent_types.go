@@ -2,13 +2,42 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // TorrentSpec defines the desired state of Torrent.
+// +kubebuilder:validation:XValidation:rule="has(self.magnet_uri) || (has(self.magnetURIs) && size(self.magnetURIs) > 0) || has(self.hash)",message="either magnet_uri, magnetURIs or hash must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.hash) || (has(self.adopt) && self.adopt)",message="hash requires adopt"
+// +kubebuilder:validation:XValidation:rule="!has(self.categorySavePath) || (has(self.category) && size(self.category) > 0 && has(self.createCategoryIfMissing) && self.createCategoryIfMissing)",message="categorySavePath requires category and createCategoryIfMissing"
 type TorrentSpec struct {
 	// MagnetURI is the magnet link for the torrent to download.
-	// +kubebuilder:validation:Required
-	MagnetURI string `json:"magnet_uri"`
+	// Either MagnetURI or MagnetURIs must be set.
+	// +optional
+	MagnetURI string `json:"magnet_uri,omitempty"`
+
+	// MagnetURIs lists fallback magnet links for the same content, tried in order
+	// after MagnetURI until one yields metadata within MetadataTimeout.
+	// The first source that yields metadata is pinned and recorded in status.source.
+	// +optional
+	MagnetURIs []string `json:"magnetURIs,omitempty"`
+
+	// Adopt manages a torrent already present in qBittorrent, e.g. when importing an existing instance,
+	// instead of adding it: a torrent missing from qBittorrent is reported Degraded and never added.
+	// OnDelete then defaults to "orphan", leaving the torrent and its files in qBittorrent when the Torrent is deleted.
+	// +optional
+	Adopt *bool `json:"adopt,omitempty"`
+
+	// Hash identifies the adopted torrent by its info hash when no magnet link is at hand.
+	// Requires Adopt; when a magnet link is also set, both must point to the same torrent.
+	// +kubebuilder:validation:Pattern=`^([0-9a-fA-F]{40}|[0-9a-fA-F]{64})$`
+	// +optional
+	Hash string `json:"hash,omitempty"`
+
+	// MetadataTimeout is how long a source may take to yield metadata before
+	// the controller falls back to the next one. Only used with multiple sources.
+	// +kubebuilder:default="10m"
+	// +optional
+	MetadataTimeout *metav1.Duration `json:"metadataTimeout,omitempty"`
 
 	// ClientConfigRef is an explicit reference to a TorrentClientConfiguration in the same namespace.
 	// If not set, the controller will auto-discover a TCC in the namespace
@@ -16,11 +45,244 @@ type TorrentSpec struct {
 	// +optional
 	ClientConfigRef *LocalObjectReference `json:"clientConfigRef,omitempty"`
 
+	// Selector restricts auto-discovery to the TorrentClientConfigurations whose labels match,
+	// e.g. {"role": "private-tracker"}; exactly one must match. Mutually exclusive with ClientConfigRef.
+	// +optional
+	Selector map[string]string `json:"selector,omitempty"`
+
+	// DisplayName renames the torrent in qBittorrent, overriding the name from the magnet/metadata.
+	// The torrent is renamed after it is added and whenever this field changes.
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+
+	// FileRenames renames files inside the torrent once its metadata is available.
+	// Renames that would collide with another file are not applied.
+	// +optional
+	FileRenames []FileRename `json:"fileRenames,omitempty"`
+
+	// MaxConnections caps the peer connections of this torrent.
+	// The qBittorrent WebUI API only exposes the instance-wide max_connec_per_torrent preference,
+	// so it is applied there and caps every torrent of the instance; the lowest value declared by
+	// the Torrents sharing the client configuration wins, and unsetting it keeps the last value.
+	// A value declared in the preferences of the managing TorrentServer takes precedence.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConnections *int32 `json:"maxConnections,omitempty"`
+
+	// MaxUploads caps the upload slots of this torrent.
+	// Applied through the instance-wide max_uploads_per_torrent preference, like MaxConnections.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxUploads *int32 `json:"maxUploads,omitempty"`
+
+	// ContentLayout controls how qBittorrent lays out the torrent files on disk:
+	// "Original" keeps the torrent structure, "Subfolder" always wraps the files in a folder
+	// (single-file torrents included) and "NoSubfolder" strips the root folder.
+	// +kubebuilder:validation:Enum=Original;Subfolder;NoSubfolder
+	// +kubebuilder:default=Original
+	// +optional
+	ContentLayout ContentLayout `json:"contentLayout,omitempty"`
+
+	// Priority sets the position of the torrent in the qBittorrent queue, when queueing is enabled.
+	// An integer is the target position (1 is the head of the queue): the torrent moves one position
+	// towards it on every reconcile. "top", "bottom", "up" and "down" move it once per spec change.
+	// +kubebuilder:validation:XIntOrString
+	// +kubebuilder:validation:XValidation:rule="type(self) == int ? self >= 1 : self in ['top', 'bottom', 'up', 'down']",message="priority must be a queue position >= 1 or one of top, bottom, up, down"
+	// +optional
+	Priority *intstr.IntOrString `json:"priority,omitempty"`
+
+	// SkipHashCheck adds the torrent without rechecking data already on disk,
+	// e.g. when re-adding a library restored from backup.
+	// Only safe for data known to be complete and valid: unverified pieces are seeded as-is.
+	// +optional
+	SkipHashCheck *bool `json:"skipHashCheck,omitempty"`
+
+	// Paused keeps the torrent stopped: it is added stopped, or, with FilePriorities,
+	// stopped once its metadata is received and left stopped after the priorities are applied.
+	// Later changes are not enforced, so starting or stopping the torrent by hand sticks.
+	// +optional
+	Paused *bool `json:"paused,omitempty"`
+
+	// AddToTopOfQueue adds the torrent at the top of the qBittorrent queue, so that an urgent download
+	// starts first without a later queue move. Only honoured while queueing is enabled on the instance,
+	// otherwise the QueueingDisabled condition is reported. Only applied when the controller adds the torrent.
+	// +optional
+	AddToTopOfQueue *bool `json:"addToTopOfQueue,omitempty"`
+
+	// Category is the qBittorrent category the torrent is added to, e.g. to group torrents
+	// sharing a save path. Set when the controller adds the torrent, and applied again when
+	// the periodic settings verification finds it changed.
+	// +optional
+	Category string `json:"category,omitempty"`
+
+	// CreateCategoryIfMissing creates Category on the qBittorrent instance before adding the torrent
+	// when it does not exist yet. CategorySavePath is used when set, then the save path declared for the
+	// category in the TorrentClientConfiguration spec.categories; otherwise the category saves to the
+	// default save path.
+	// +optional
+	CreateCategoryIfMissing *bool `json:"createCategoryIfMissing,omitempty"`
+
+	// CategorySavePath is the save path of Category when CreateCategoryIfMissing creates it,
+	// e.g. "/downloads/tv". The save path of an existing category is left untouched.
+	// Requires Category and CreateCategoryIfMissing.
+	// +optional
+	CategorySavePath string `json:"categorySavePath,omitempty"`
+
+	// FilePriorities sets the download priority of the files matching each rule, e.g. to skip samples.
+	// The torrent is added so that qBittorrent stops it once its metadata is received, the priorities
+	// are applied and it is then started, unless Paused is set: no unwanted piece is downloaded.
+	// The first matching rule wins; unmatched files keep the normal priority.
+	// Only applied to torrents added by the controller; the periodic settings verification
+	// applies them again when they drift, unless files were renamed.
+	// +optional
+	FilePriorities []FilePriority `json:"filePriorities,omitempty"`
+
+	// MetadataOnly adds the torrent so that qBittorrent stops it once its metadata is received,
+	// and keeps it stopped without downloading anything: the file list is reported in status.files
+	// to choose spec.filePriorities from. Unsetting it applies FilePriorities and starts the torrent,
+	// unless Paused is set. Only applied to torrents added by the controller.
+	// +optional
+	MetadataOnly *bool `json:"metadataOnly,omitempty"`
+
+	// StopSeedingOnComplete stops the torrent once it has completed instead of seeding it,
+	// e.g. on bandwidth-limited connections. The torrent is stopped once: resuming it afterwards is left alone.
+	// +optional
+	StopSeedingOnComplete *bool `json:"stopSeedingOnComplete,omitempty"`
+
+	// ReadyWhen is the progress at which the Available condition turns True, so that consumers
+	// waiting on it start at the right moment: "added" once qBittorrent knows the torrent,
+	// "downloaded" once every wanted piece is downloaded, "seeding" once it is actively seeding.
+	// +kubebuilder:validation:Enum=added;downloaded;seeding
+	// +kubebuilder:default=added
+	// +optional
+	ReadyWhen ReadyWhen `json:"readyWhen,omitempty"`
+
+	// ExportToSecret writes the .torrent file into a Secret owned by the Torrent once the metadata
+	// is received, e.g. to back up torrents added from a magnet URI. The Secret is written once
+	// and rewritten only if its content is removed.
+	// +optional
+	ExportToSecret *ExportToSecret `json:"exportToSecret,omitempty"`
+
 	// DeleteFilesOnRemoval controls whether downloaded files are deleted
 	// when the Torrent resource is deleted.
 	// +kubebuilder:default=true
 	// +optional
 	DeleteFilesOnRemoval *bool `json:"deleteFilesOnRemoval,omitempty"`
+
+	// OnDelete controls what happens to the torrent in qBittorrent when the Torrent resource is deleted:
+	// "remove" deletes it (honouring DeleteFilesOnRemoval), "orphan" leaves it running
+	// and hands it back to manual management. Defaults to "remove", or to "orphan" for an adopted torrent
+	// so that files which predate the Torrent are never deleted by default.
+	// +kubebuilder:validation:Enum=remove;orphan
+	// +optional
+	OnDelete DeletePolicy `json:"onDelete,omitempty"`
+}
+
+// ReadyWhen defines the progress at which a Torrent is reported Available.
+type ReadyWhen string
+
+const (
+	// ReadyWhenAdded reports the torrent Available once it is added to qBittorrent.
+	ReadyWhenAdded ReadyWhen = "added"
+	// ReadyWhenDownloaded reports the torrent Available once its download completed.
+	ReadyWhenDownloaded ReadyWhen = "downloaded"
+	// ReadyWhenSeeding reports the torrent Available once it is seeding.
+	ReadyWhenSeeding ReadyWhen = "seeding"
+)
+
+// ExportToSecret configures the Secret receiving the exported .torrent file.
+type ExportToSecret struct {
+	// Name of the Secret, in the Torrent namespace. Defaults to "<torrent name>-torrent".
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// DeletePolicy defines what happens to a torrent in qBittorrent when its Torrent resource is deleted.
+type DeletePolicy string
+
+const (
+	// DeletePolicyRemove removes the torrent from qBittorrent.
+	DeletePolicyRemove DeletePolicy = "remove"
+	// DeletePolicyOrphan leaves the torrent in qBittorrent.
+	DeletePolicyOrphan DeletePolicy = "orphan"
+)
+
+// ContentLayout defines how the files of a torrent are laid out on disk.
+type ContentLayout string
+
+const (
+	// ContentLayoutOriginal keeps the layout of the torrent.
+	ContentLayoutOriginal ContentLayout = "Original"
+	// ContentLayoutSubfolder wraps the torrent files in a folder.
+	ContentLayoutSubfolder ContentLayout = "Subfolder"
+	// ContentLayoutNoSubfolder strips the torrent root folder.
+	ContentLayoutNoSubfolder ContentLayout = "NoSubfolder"
+)
+
+// FileRename maps files matching a path pattern to a new path.
+type FileRename struct {
+	// Match is a path pattern tested against the file path relative to the torrent root, e.g. "*/sample.mkv".
+	// Segments are matched with path.Match syntax, case-sensitively; "*" does not cross a "/",
+	// while a "**" segment matches any number of directories, e.g. "**/*.mkv".
+	Match string `json:"match"`
+
+	// Rename is the new file path. If it has no directory component,
+	// the file is renamed in place within its current directory.
+	Rename string `json:"rename"`
+}
+
+// FilePriority sets the download priority of the files matching a path pattern.
+type FilePriority struct {
+	// Match is a path pattern tested against the file path relative to the torrent root, e.g. "*/Sample/*".
+	// Segments are matched with path.Match syntax, case-sensitively; "*" does not cross a "/",
+	// while a "**" segment matches any number of directories, e.g. "**/*.mkv".
+	Match string `json:"match"`
+
+	// Priority is the download priority of the matching files; "skip" does not download them.
+	// +kubebuilder:validation:Enum=skip;normal;high;maximum
+	Priority FilePriorityLevel `json:"priority"`
+}
+
+// FilePriorityLevel defines the download priority of a file.
+type FilePriorityLevel string
+
+const (
+	// FilePrioritySkip does not download the file.
+	FilePrioritySkip FilePriorityLevel = "skip"
+	// FilePriorityNormal downloads the file with the default priority.
+	FilePriorityNormal FilePriorityLevel = "normal"
+	// FilePriorityHigh downloads the file before the normal ones.
+	FilePriorityHigh FilePriorityLevel = "high"
+	// FilePriorityMaximum downloads the file first.
+	FilePriorityMaximum FilePriorityLevel = "maximum"
+)
+
+// AddPhase tracks the steps of adding a torrent with file priorities.
+type AddPhase string
+
+const (
+	// AddPhaseAwaitingMetadata means the torrent was added and stops once its metadata is received.
+	AddPhaseAwaitingMetadata AddPhase = "AwaitingMetadata"
+	// AddPhaseFilesSelected means the file priorities were applied to the stopped torrent.
+	AddPhaseFilesSelected AddPhase = "FilesSelected"
+	// AddPhaseStarted means the torrent was started after its file priorities were applied.
+	AddPhaseStarted AddPhase = "Started"
+)
+
+// TorrentFileStatus describes a file of the torrent, as listed in its metadata.
+type TorrentFileStatus struct {
+	// Index is the position of the file in the torrent.
+	Index int `json:"index"`
+	// Name is the path of the file relative to the torrent root, as matched by spec.filePriorities.
+	Name string `json:"name"`
+	// Size is the size of the file in bytes.
+	Size int64 `json:"size"`
+}
+
+// AppliedFileRename records a file rename applied by the controller.
+type AppliedFileRename struct {
+	From string `json:"from"`
+	To   string `json:"to"`
 }
 
 // LocalObjectReference is a reference to an object in the same namespace.
@@ -40,9 +302,119 @@ type TorrentStatus struct {
 	AmountLeft  int64  `json:"amount_left,omitempty"`
 	Hash        string `json:"hash,omitempty"`
 
+	// TotalSizeHuman is TotalSize formatted for display, e.g. "1.5 GiB".
+	TotalSizeHuman string `json:"totalSizeHuman,omitempty"`
+
+	// AmountLeftHuman is AmountLeft formatted for display, e.g. "512.0 MiB".
+	AmountLeftHuman string `json:"amountLeftHuman,omitempty"`
+
+	// Progress is the download progress as a percentage, e.g. "42.5%".
+	Progress string `json:"progress,omitempty"`
+
+	// DownloadSpeed is the current download speed, e.g. "1.2 MiB/s".
+	DownloadSpeed string `json:"downloadSpeed,omitempty"`
+
+	// Ratio is the share ratio reported by qBittorrent (uploaded / downloaded), e.g. "0.52".
+	// It is a decimal string since floating point fields are not portable in Kubernetes APIs.
+	Ratio string `json:"ratio,omitempty"`
+
+	// Availability is the number of distributed copies in the swarm, e.g. "1.25".
+	// Below "1.00" no peer has every piece and the torrent may never complete.
+	Availability string `json:"availability,omitempty"`
+
+	// Peers is the number of connected peers (leechers).
+	Peers int32 `json:"peers,omitempty"`
+
+	// Seeds is the number of connected seeds.
+	Seeds int32 `json:"seeds,omitempty"`
+
+	// TotalDownloaded is the number of bytes downloaded over the resource lifetime. It accumulates the growth
+	// of the qBittorrent counter, so it survives counter resets, e.g. when the torrent is added again.
+	TotalDownloaded int64 `json:"totalDownloaded,omitempty"`
+
+	// TotalUploaded is the number of bytes uploaded over the resource lifetime, accumulated like TotalDownloaded.
+	TotalUploaded int64 `json:"totalUploaded,omitempty"`
+
+	// LastSeenDownloaded is the qBittorrent download counter at the last reconcile, from which
+	// the growth added to TotalDownloaded is computed.
+	LastSeenDownloaded *int64 `json:"lastSeenDownloaded,omitempty"`
+
+	// LastSeenUploaded is the qBittorrent upload counter at the last reconcile.
+	LastSeenUploaded *int64 `json:"lastSeenUploaded,omitempty"`
+
+	// QueuePosition is the position of the torrent in the qBittorrent queue,
+	// 0 when queueing is disabled or the torrent is seeding.
+	QueuePosition int32 `json:"queuePosition,omitempty"`
+
+	// PriorityGeneration is the spec generation whose relative priority move (top, bottom, up, down) was applied.
+	PriorityGeneration int64 `json:"priorityGeneration,omitempty"`
+
+	// ConnectionsLimit is the peer connection limit qBittorrent applies to the torrent,
+	// -1 when unlimited.
+	ConnectionsLimit int32 `json:"connectionsLimit,omitempty"`
+
+	// Message explains why qBittorrent reports the torrent as errored, including the
+	// latest qBittorrent log line about it when available. Cleared once the torrent recovers.
+	Message string `json:"message,omitempty"`
+
+	// Comment is the comment embedded in the torrent metadata, recorded once the metadata is available.
+	Comment string `json:"comment,omitempty"`
+
+	// CreatedBy is the program that created the torrent, as embedded in its metadata.
+	CreatedBy string `json:"createdBy,omitempty"`
+
+	// SeedingStopped is true once the torrent was stopped on completion for spec.stopSeedingOnComplete.
+	SeedingStopped bool `json:"seedingStopped,omitempty"`
+
+	// Source is the magnet URI currently in use among the configured sources.
+	Source string `json:"source,omitempty"`
+
+	// SourcePinned is true once Source yielded metadata; the controller no longer falls back.
+	SourcePinned bool `json:"sourcePinned,omitempty"`
+
+	// SourceAttemptStartedAt is when Source was added to qBittorrent, used for the metadata timeout.
+	SourceAttemptStartedAt *metav1.Time `json:"sourceAttemptStartedAt,omitempty"`
+
+	// FailedSources lists the magnet URIs that did not yield metadata in time.
+	// They are retried after the spec changes.
+	FailedSources []string `json:"failedSources,omitempty"`
+
+	// FailedSourcesGeneration is the spec generation FailedSources refers to.
+	FailedSourcesGeneration int64 `json:"failedSourcesGeneration,omitempty"`
+
+	// AddPhase tracks the add, select files, then start steps of spec.filePriorities,
+	// so that completed steps are not redone.
+	AddPhase AddPhase `json:"addPhase,omitempty"`
+
+	// Files lists the files of a torrent added with spec.metadataOnly, once its metadata is received.
+	Files []TorrentFileStatus `json:"files,omitempty"`
+
+	// FilesListed records that Files was listed, so that a torrent without files is not listed again.
+	FilesListed bool `json:"filesListed,omitempty"`
+
+	// ExportedSecretName is the Secret holding the .torrent file exported for spec.exportToSecret.
+	ExportedSecretName string `json:"exportedSecretName,omitempty"`
+
+	// AppliedFileRenames lists the file renames applied from spec.fileRenames.
+	AppliedFileRenames []AppliedFileRename `json:"appliedFileRenames,omitempty"`
+
+	// ArchiveRequestedTo is the archive-to path qBittorrent was asked to move the files of the deleted torrent to.
+	ArchiveRequestedTo string `json:"archiveRequestedTo,omitempty"`
+
+	// ArchiveRequestedAt is when the move to ArchiveRequestedTo was requested, so that it is not requested
+	// again while qBittorrent has not started it yet.
+	ArchiveRequestedAt *metav1.Time `json:"archiveRequestedAt,omitempty"`
+
+	// SettingsVerifiedAt is when the declarative settings were last checked against qBittorrent,
+	// which may lose them across a restart; drifted settings are applied again.
+	SettingsVerifiedAt *metav1.Time `json:"settingsVerifiedAt,omitempty"`
+
 	// ClientConfigurationName is the resolved TCC name being used.
 	ClientConfigurationName string `json:"clientConfigurationName,omitempty"`
 
+	// ObservedRefresh is the value of the torrent.qbittorrent.io/refresh annotation last acted upon.
+	ObservedRefresh string `json:"observedRefresh,omitempty"`
+
 	// Conditions represent the latest available observations of a torrent's current state.
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 }
@@ -52,8 +424,12 @@ type TorrentStatus struct {
 // +kubebuilder:resource:shortName=to
 // +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
 // +kubebuilder:printcolumn:name="Name",type="string",JSONPath=".status.name"
-// +kubebuilder:printcolumn:name="Size",type="string",JSONPath=".status.total_size"
-// +kubebuilder:printcolumn:name="Progress",type="string",JSONPath=".status.amount_left"
+// +kubebuilder:printcolumn:name="Size",type="string",JSONPath=".status.totalSizeHuman"
+// +kubebuilder:printcolumn:name="Progress",type="string",JSONPath=".status.progress"
+// +kubebuilder:printcolumn:name="Speed",type="string",JSONPath=".status.downloadSpeed"
+// +kubebuilder:printcolumn:name="Ratio",type="string",JSONPath=".status.ratio"
+// +kubebuilder:printcolumn:name="Left",type="string",JSONPath=".status.amountLeftHuman",priority=1
+// +kubebuilder:printcolumn:name="Hash",type="string",JSONPath=".status.hash",priority=1
 
 // Torrent is the Schema for the torrents API.
 type Torrent struct {
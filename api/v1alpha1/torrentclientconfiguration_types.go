@@ -5,20 +5,133 @@ import (
 )
 
 // TorrentClientConfigurationSpec defines the desired state of TorrentClientConfiguration.
+// +kubebuilder:validation:XValidation:rule="has(self.credentialsSecret) || (has(self.auth) && has(self.auth.tokenFile))",message="credentialsSecret is required unless auth.tokenFile is set"
 type TorrentClientConfigurationSpec struct {
 	// URL is the base URL of the qBittorrent WebUI (e.g., "http://qbittorrent:8080").
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:Pattern=`^https?://`
 	URL string `json:"url"`
 
-	// CredentialsSecret references a Secret containing 'username' and 'password' keys.
-	// +kubebuilder:validation:Required
-	CredentialsSecret SecretReference `json:"credentialsSecret"`
+	// CredentialsSecret references a Secret containing 'username' and 'password' keys,
+	// or the bearer token key when Auth.Type is Bearer.
+	// +optional
+	CredentialsSecret *SecretReference `json:"credentialsSecret,omitempty"`
+
+	// Auth selects how the operator authenticates to qBittorrent. Defaults to the WebUI login.
+	// +optional
+	Auth *ClientAuthSpec `json:"auth,omitempty"`
 
 	// CheckInterval is how often the controller checks connectivity (e.g., "60s").
 	// +kubebuilder:default="60s"
 	// +optional
 	CheckInterval string `json:"checkInterval,omitempty"`
+
+	// FailureThreshold is the number of consecutive failed checks before an Available
+	// TCC is marked Degraded, so a single transient failure does not cascade to its Torrents.
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+
+	// Privacy declares peer discovery settings enforced on the qBittorrent instance
+	// after every successful connectivity check. The keys also declared by the managing
+	// TorrentServer are left to it and reported by the PrivacyConflict condition when they differ.
+	// +optional
+	Privacy *PrivacySpec `json:"privacy,omitempty"`
+
+	// MaintenanceWindow is a daily window, e.g. a scheduled qBittorrent restart, during which
+	// failed checks set the informational Maintenance condition instead of counting towards Degraded.
+	// +optional
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+
+	// Categories declares the torrent categories created on the qBittorrent instance.
+	// A category whose save path drifts is edited back; undeclared categories are left untouched.
+	// The categories also declared in the categoryPaths of the managing TorrentServer are left to it
+	// and reported by the CategoryConflict condition when their save path differs.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Categories []CategorySpec `json:"categories,omitempty"`
+}
+
+// ClientAuthType is how the operator authenticates to qBittorrent.
+// +kubebuilder:validation:Enum=Login;Bearer
+type ClientAuthType string
+
+const (
+	// ClientAuthLogin logs in to the WebUI with the username and password of CredentialsSecret.
+	ClientAuthLogin ClientAuthType = "Login"
+	// ClientAuthBearer sends a token as an "Authorization: Bearer" header on every request instead of
+	// logging in, e.g. for qBittorrent behind an OIDC or OAuth proxy.
+	ClientAuthBearer ClientAuthType = "Bearer"
+)
+
+// ClientAuthSpec configures how the operator authenticates to qBittorrent.
+// +kubebuilder:validation:XValidation:rule="self.type == 'Bearer' || (!has(self.tokenKey) && !has(self.tokenFile))",message="tokenKey and tokenFile require type Bearer"
+// +kubebuilder:validation:XValidation:rule="!has(self.tokenKey) || !has(self.tokenFile)",message="tokenKey and tokenFile are mutually exclusive"
+type ClientAuthSpec struct {
+	// Type is Login or Bearer.
+	// +kubebuilder:default=Login
+	// +optional
+	Type ClientAuthType `json:"type,omitempty"`
+
+	// TokenKey is the key of CredentialsSecret holding the bearer token. Defaults to "token".
+	// +optional
+	TokenKey string `json:"tokenKey,omitempty"`
+
+	// TokenFile reads the bearer token from a file mounted in the operator pod instead, e.g. a projected
+	// service account token. The path is relative to the directory the operator allows with
+	// --bearer-token-dir, and the file is read again on every reconcile so rotated tokens are used.
+	// +kubebuilder:validation:Pattern=`^[^/]`
+	// +optional
+	TokenFile string `json:"tokenFile,omitempty"`
+}
+
+// CategorySpec declares a qBittorrent category and where its torrents are saved.
+type CategorySpec struct {
+	// Name is the category name.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// SavePath is the directory the torrents of the category are saved to.
+	// Empty uses the default save path of the instance.
+	// +optional
+	SavePath string `json:"savePath,omitempty"`
+}
+
+// MaintenanceWindow recurs every day from Start for Duration.
+type MaintenanceWindow struct {
+	// Start is the time of day the window opens, in 24h "HH:MM" format (e.g., "03:00").
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// Duration is how long the window stays open (e.g., "30m"), above zero and up to 24h.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	// +kubebuilder:validation:XValidation:rule="duration(self) > duration('0s') && duration(self) <= duration('24h')",message="duration must be above 0s and at most 24h"
+	Duration string `json:"duration"`
+
+	// TimeZone is the IANA time zone of Start (e.g., "Europe/Rome"). Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// PrivacySpec toggles the peer discovery mechanisms of qBittorrent.
+// Unset fields leave the corresponding preference untouched.
+type PrivacySpec struct {
+	// DHT enables the distributed hash table, used to find peers without a tracker.
+	// +optional
+	DHT *bool `json:"dht,omitempty"`
+
+	// PeX enables peer exchange, used to learn peers from connected peers.
+	// +optional
+	PeX *bool `json:"pex,omitempty"`
+
+	// LSD enables local service discovery, used to find peers on the local network.
+	// +optional
+	LSD *bool `json:"lsd,omitempty"`
 }
 
 // TorrentClientConfigurationStatus defines the observed state of TorrentClientConfiguration.
@@ -32,6 +145,13 @@ type TorrentClientConfigurationStatus struct {
 	// QBittorrentVersion is the version reported by the qBittorrent instance.
 	QBittorrentVersion string `json:"qbittorrentVersion,omitempty"`
 
+	// APIVersion is the WebUI API version reported by the qBittorrent instance.
+	// It gates which endpoints the operator uses against this instance.
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	// ConsecutiveFailures counts the failed checks since the last successful one.
+	ConsecutiveFailures int32 `json:"consecutiveFailures,omitempty"`
+
 	// Conditions represent the latest available observations.
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 }
@@ -2,20 +2,25 @@ package v1alpha1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // TorrentServerSpec defines the desired state of TorrentServer.
+// +kubebuilder:validation:XValidation:rule="!has(self.replicas) || self.replicas <= 1 || (has(self.configStorage) && (has(self.configStorage.existingClaimName) || (has(self.configStorage.accessModes) && self.configStorage.accessModes.exists(m, m == 'ReadWriteMany'))))",message="replicas above 1 require configStorage.accessModes to include ReadWriteMany"
 type TorrentServerSpec struct {
-	// Image is the qBittorrent container image.
-	// +kubebuilder:default="lscr.io/linuxserver/qbittorrent:amd64-5.1.4"
+	// Image is the qBittorrent container image. When empty, the operator --default-qbittorrent-image
+	// is used, else the latest tested linuxserver image. The operator --image-registry-prefix, when set,
+	// replaces the registry of this image.
 	// +optional
 	Image string `json:"image,omitempty"`
 
-	// Replicas is the number of replicas. Must be 0 or 1.
+	// Replicas is the number of replicas, usually 0 or 1. More replicas require a ReadWriteMany config volume
+	// and are reported by the MultipleReplicas condition: qBittorrent does not coordinate replicas sharing
+	// a config, so something else must keep a single one active.
 	// +kubebuilder:default=1
 	// +kubebuilder:validation:Minimum=0
-	// +kubebuilder:validation:Maximum=1
 	// +optional
 	Replicas *int32 `json:"replicas,omitempty"`
 
@@ -23,10 +28,34 @@ type TorrentServerSpec struct {
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 
+	// InitResources defines resource requests/limits for the config-init init container.
+	// When unset, small defaults are used so the pod schedules in namespaces enforcing a ResourceQuota.
+	// +optional
+	InitResources *corev1.ResourceRequirements `json:"initResources,omitempty"`
+
 	// Env defines additional environment variables for the qBittorrent container.
+	// Entries overriding WEBUI_PORT, TORRENTING_PORT, PUID, PGID or TZ with a value other than
+	// the one derived from the typed fields are reported by the ConflictingEnv condition.
 	// +optional
 	Env []corev1.EnvVar `json:"env,omitempty"`
 
+	// PUID is the user ID qBittorrent runs as, exported as the PUID environment variable
+	// understood by the linuxserver image. A PUID entry in Env takes precedence.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	PUID *int64 `json:"puid,omitempty"`
+
+	// PGID is the group ID qBittorrent runs as, exported as the PGID environment variable.
+	// A PGID entry in Env takes precedence.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	PGID *int64 `json:"pgid,omitempty"`
+
+	// Timezone is the IANA time zone of the container (e.g. "Europe/Rome"),
+	// exported as the TZ environment variable. A TZ entry in Env takes precedence.
+	// +optional
+	Timezone string `json:"timezone,omitempty"`
+
 	// ConfigStorage defines the PVC configuration for the /config volume.
 	// If not provided, a default 1Gi PVC is created.
 	// +optional
@@ -37,6 +66,18 @@ type TorrentServerSpec struct {
 	// +optional
 	DownloadVolumes []DownloadVolumeSpec `json:"downloadVolumes,omitempty"`
 
+	// WaitForDownloadVolumes holds the Deployment back until every download PVC is Bound,
+	// reporting Available=False with reason WaitingForStorage meanwhile.
+	// Leave it unset with WaitForFirstConsumer storage classes: their claims only bind once a pod uses them.
+	// +optional
+	WaitForDownloadVolumes *bool `json:"waitForDownloadVolumes,omitempty"`
+
+	// DefaultSavePath is the qBittorrent default save path for new torrents.
+	// Defaults to the mountPath of the first download volume, if any.
+	// +kubebuilder:validation:Pattern=`^/`
+	// +optional
+	DefaultSavePath string `json:"defaultSavePath,omitempty"`
+
 	// CredentialsSecret references a Secret containing 'username' and 'password' keys
 	// for the qBittorrent WebUI. If not specified, a default Secret is auto-generated.
 	// +optional
@@ -48,14 +89,320 @@ type TorrentServerSpec struct {
 	// +optional
 	ServiceType corev1.ServiceType `json:"serviceType,omitempty"`
 
+	// Service configures the managed WebUI Service. Disable it to front qBittorrent with your own
+	// Service or Gateway API route, giving the URL the operator reaches the WebUI through instead.
+	// +optional
+	Service *ServiceSpec `json:"service,omitempty"`
+
+	// ExternalService creates an additional "<name>-external" Service for the WebUI,
+	// e.g. a LoadBalancer for humans next to the internal Service used by the operator.
+	// The auto-created TorrentClientConfiguration keeps using the primary Service.
+	// +optional
+	ExternalService *ExternalServiceSpec `json:"externalService,omitempty"`
+
+	// HTTPRoute exposes the WebUI through a Gateway API HTTPRoute targeting the managed Service.
+	// Requires the Gateway API CRDs; without them the GatewayAPIUnavailable condition is set instead.
+	// +optional
+	HTTPRoute *HTTPRouteSpec `json:"httpRoute,omitempty"`
+
 	// WebUIPort is the port the qBittorrent WebUI listens on.
 	// +kubebuilder:default=8080
 	// +optional
 	WebUIPort int32 `json:"webUIPort,omitempty"`
+
+	// StartupProbe overrides the startup probe of the qBittorrent container.
+	// If not specified, the WebUI port is probed every 10s for up to 5 minutes,
+	// giving qBittorrent time to restore large resume data before other probes fire.
+	// +optional
+	StartupProbe *corev1.Probe `json:"startupProbe,omitempty"`
+
+	// HostNetwork runs the qBittorrent pod in the node network namespace for maximum
+	// peer connectivity. The WebUI and BitTorrent ports are bound directly on the node,
+	// bypassing the ClusterIP Service for peer traffic.
+	// +optional
+	HostNetwork bool `json:"hostNetwork,omitempty"`
+
+	// PriorityClassName is the PriorityClass assigned to the qBittorrent pod.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// TopologySpreadConstraints describes how the qBittorrent pod is spread across topology domains.
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// ExtraVolumes defines additional volumes attached to the qBittorrent pod.
+	// Names must not collide with the volumes managed by the operator
+	// ("config", "credentials" and "download-<claimName>").
+	// +optional
+	ExtraVolumes []corev1.Volume `json:"extraVolumes,omitempty"`
+
+	// ExtraVolumeMounts defines additional volume mounts for the qBittorrent container.
+	// They can reference both ExtraVolumes and managed volumes. Mount paths must not equal, contain or
+	// be nested in a path mounted by the operator, e.g. /config, /credentials, /incomplete or /ip-filter.
+	// +optional
+	ExtraVolumeMounts []corev1.VolumeMount `json:"extraVolumeMounts,omitempty"`
+
+	// Command overrides the qBittorrent container entrypoint.
+	// Intended for debugging or non-standard images; the image ENTRYPOINT is used when empty.
+	// +optional
+	Command []string `json:"command,omitempty"`
+
+	// Args overrides the arguments passed to the qBittorrent container entrypoint.
+	// The image CMD is used when empty.
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// Preferences are qBittorrent application preferences applied through the WebUI API,
+	// keyed by qBittorrent preference name (e.g. "max_active_downloads").
+	// Drifted values are re-applied on every reconcile; preferences not listed here are left untouched.
+	// +optional
+	Preferences map[string]apiextensionsv1.JSON `json:"preferences,omitempty"`
+
+	// CategoryPaths maps torrent category names to their save path, e.g. "tv": "/downloads/tv".
+	// Each category is created once the WebUI answers, and a save path that drifts is edited back;
+	// categories not listed here are left untouched. An empty path uses the default save path.
+	// +kubebuilder:validation:XValidation:rule="self.all(name, name != \"\")",message="category names must not be empty"
+	// +optional
+	CategoryPaths map[string]string `json:"categoryPaths,omitempty"`
+
+	// BitTorrent configures the peer listening port and peer discovery.
+	// +optional
+	BitTorrent *BitTorrentSpec `json:"bittorrent,omitempty"`
+
+	// Queueing limits how many torrents qBittorrent runs at once.
+	// +optional
+	Queueing *QueueingSpec `json:"queueing,omitempty"`
+
+	// Scheduler switches qBittorrent to its alternative speed limits during a daily time window,
+	// e.g. to throttle overnight. The limits themselves are the alt_dl_limit and alt_up_limit preferences.
+	// +optional
+	Scheduler *SchedulerSpec `json:"scheduler,omitempty"`
+
+	// Suspend stops every torrent on the instance while set. Unsetting it restarts only the torrents
+	// that were running when the instance was suspended; the pod itself keeps running.
+	// +optional
+	Suspend *bool `json:"suspend,omitempty"`
+
+	// AlternativeWebUI serves an alternative WebUI (e.g. VueTorrent) instead of the built-in one.
+	// When unset, the alternative WebUI is disabled and the built-in one is served.
+	// +optional
+	AlternativeWebUI *AlternativeWebUISpec `json:"alternativeWebUI,omitempty"`
+
+	// LowDiskSpaceThreshold sets the LowDiskSpace condition when the free space reported by qBittorrent
+	// for its default save path drops below it. Defaults to 5Gi.
+	// +optional
+	LowDiskSpaceThreshold *resource.Quantity `json:"lowDiskSpaceThreshold,omitempty"`
+
+	// IncompleteStorage keeps incomplete torrents on an emptyDir volume, e.g. fast local disk or tmpfs,
+	// and lets qBittorrent move them to their save path once completed.
+	// +optional
+	IncompleteStorage *IncompleteStorageSpec `json:"incompleteStorage,omitempty"`
+
+	// IPFilter blocks the peers listed in an IP filter file, mounted from a ConfigMap or an existing PVC.
+	// +optional
+	IPFilter *IPFilterSpec `json:"ipFilter,omitempty"`
+}
+
+// SchedulerSpec configures when qBittorrent applies its alternative speed limits.
+// Times are in the timezone of the qBittorrent container, see Timezone.
+type SchedulerSpec struct {
+	// Enabled toggles the scheduler. Defaults to true.
+	// +kubebuilder:default=true
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// From is the start of the window, as HH:MM.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	From string `json:"from"`
+
+	// To is the end of the window, as HH:MM. A window ending before it starts spans midnight.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	To string `json:"to"`
+
+	// Days the window applies to. Defaults to EveryDay.
+	// +kubebuilder:validation:Enum=EveryDay;Weekdays;Weekends;Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+	// +kubebuilder:default=EveryDay
+	// +optional
+	Days string `json:"days,omitempty"`
+}
+
+// IncompleteStorageSpec mounts an emptyDir volume used as qBittorrent's "keep incomplete torrents in" path.
+// The volume is ephemeral: incomplete torrents restart from scratch when the pod is recreated.
+type IncompleteStorageSpec struct {
+	// MountPath is where the volume is mounted in the qBittorrent container.
+	// +kubebuilder:default="/incomplete"
+	// +kubebuilder:validation:Pattern=`^/`
+	// +optional
+	MountPath string `json:"mountPath,omitempty"`
+
+	// Medium backs the volume with tmpfs when set to "Memory"; its usage then counts against the memory limit.
+	// Empty uses the node disk.
+	// +kubebuilder:validation:Enum=Memory
+	// +optional
+	Medium corev1.StorageMedium `json:"medium,omitempty"`
+
+	// SizeLimit caps the volume; the pod is evicted beyond it.
+	// +optional
+	SizeLimit *resource.Quantity `json:"sizeLimit,omitempty"`
+}
+
+// IPFilterSpec mounts an IP filter file (eMule .dat, PeerGuardian .p2p or .p2b) and points qBittorrent at it.
+// qBittorrent reads the file when the filter is enabled or its path changes, so an edited file
+// is picked up by the next pod restart.
+// +kubebuilder:validation:XValidation:rule="has(self.configMapName) != has(self.claimName)",message="exactly one of configMapName or claimName must be set"
+type IPFilterSpec struct {
+	// Enabled toggles the filter while keeping the file mounted. Defaults to true.
+	// +kubebuilder:default=true
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// ConfigMapName mounts the keys of an existing ConfigMap, e.g. a small hand-written block list.
+	// +optional
+	ConfigMapName string `json:"configMapName,omitempty"`
+
+	// ClaimName mounts an existing PVC read-only, e.g. for lists larger than the 1MiB ConfigMap limit.
+	// +optional
+	ClaimName string `json:"claimName,omitempty"`
+
+	// FileName is the path of the filter file inside the ConfigMap or PVC.
+	// +kubebuilder:default="ipfilter.dat"
+	// +kubebuilder:validation:Pattern=`^[^/]`
+	// +kubebuilder:validation:XValidation:rule="!self.split('/').exists(s, s == '..')",message="fileName must not contain '..'"
+	// +optional
+	FileName string `json:"fileName,omitempty"`
+}
+
+// BitTorrentSpec configures qBittorrent's peer connections.
+// Unset fields keep the current qBittorrent value.
+// +kubebuilder:validation:XValidation:rule="!(has(self.port) && has(self.useRandomPort) && self.useRandomPort)",message="port and useRandomPort are mutually exclusive"
+type BitTorrentSpec struct {
+	// Port is the fixed port used for incoming peer connections (TCP and UDP).
+	// It is also exposed on the qBittorrent container and the WebUI Services.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+
+	// UseRandomPort makes qBittorrent pick a random listening port on each start.
+	// +optional
+	UseRandomPort bool `json:"useRandomPort,omitempty"`
+
+	// EnableDHT toggles the DHT network to find more peers.
+	// +optional
+	EnableDHT *bool `json:"enableDHT,omitempty"`
+
+	// EnableLSD toggles Local Peer Discovery.
+	// +optional
+	EnableLSD *bool `json:"enableLSD,omitempty"`
+
+	// EnablePEX toggles Peer Exchange.
+	// +optional
+	EnablePEX *bool `json:"enablePEX,omitempty"`
+}
+
+// QueueingSpec configures qBittorrent's torrent queue.
+// Unset fields keep the current qBittorrent value. The active limits only apply while queueing is enabled.
+type QueueingSpec struct {
+	// Enabled toggles the torrent queue.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// MaxActiveDownloads is the maximum number of downloading torrents, -1 for no limit.
+	// +kubebuilder:validation:Minimum=-1
+	// +optional
+	MaxActiveDownloads *int32 `json:"maxActiveDownloads,omitempty"`
+
+	// MaxActiveUploads is the maximum number of seeding torrents, -1 for no limit.
+	// +kubebuilder:validation:Minimum=-1
+	// +optional
+	MaxActiveUploads *int32 `json:"maxActiveUploads,omitempty"`
+
+	// MaxActiveTorrents is the maximum number of active torrents, downloading or seeding, -1 for no limit.
+	// +kubebuilder:validation:Minimum=-1
+	// +optional
+	MaxActiveTorrents *int32 `json:"maxActiveTorrents,omitempty"`
+}
+
+// ServiceSpec configures the managed WebUI Service.
+// +kubebuilder:validation:XValidation:rule="!has(self.enabled) || self.enabled || has(self.url)",message="url is required when the managed Service is disabled"
+type ServiceSpec struct {
+	// Enabled toggles the managed "<name>" Service. Defaults to true.
+	// +kubebuilder:default=true
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// URL is the qBittorrent WebUI URL used by the operator and the auto-created TorrentClientConfiguration
+	// when the managed Service is disabled, e.g. "http://qbittorrent.media.svc.cluster.local:8080".
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// +optional
+	URL string `json:"url,omitempty"`
+}
+
+// ExternalServiceSpec configures the additional WebUI Service.
+type ExternalServiceSpec struct {
+	// Type is the Kubernetes Service type of the external Service.
+	// +kubebuilder:default="LoadBalancer"
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	// +optional
+	Type corev1.ServiceType `json:"type,omitempty"`
+
+	// Annotations are added to the external Service, e.g. for a load balancer controller.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// HTTPRouteSpec configures the "<name>" Gateway API HTTPRoute for the WebUI.
+type HTTPRouteSpec struct {
+	// ParentRef is the Gateway the route attaches to.
+	ParentRef GatewayReference `json:"parentRef"`
+
+	// Hostname the route matches, e.g. "qbittorrent.example.com". When empty, the route matches
+	// every hostname of the Gateway listener and no URL is reported in status.
+	// +optional
+	Hostname string `json:"hostname,omitempty"`
+
+	// Path is the path prefix routed to the WebUI.
+	// +kubebuilder:default="/"
+	// +kubebuilder:validation:Pattern=`^/`
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// GatewayReference identifies a Gateway API Gateway listener.
+type GatewayReference struct {
+	// Name of the Gateway.
+	Name string `json:"name"`
+
+	// Namespace of the Gateway. Defaults to the TorrentServer namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// SectionName is the name of the Gateway listener to attach to. Defaults to every listener.
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+// AlternativeWebUISpec enables qBittorrent's alternative WebUI.
+type AlternativeWebUISpec struct {
+	// RootFolder is the path of the alternative WebUI files inside the qBittorrent container.
+	// +kubebuilder:validation:Pattern=`^/`
+	RootFolder string `json:"rootFolder"`
+
+	// ConfigMapName mounts the keys of an existing ConfigMap as files in RootFolder.
+	// When empty, the files must be provided at RootFolder by other means, e.g. extraVolumes.
+	// +optional
+	ConfigMapName string `json:"configMapName,omitempty"`
 }
 
 // StorageSpec defines PVC configuration for config storage.
 type StorageSpec struct {
+	// ExistingClaimName mounts an existing PVC instead of creating "<name>-config",
+	// e.g. to migrate an existing qBittorrent config and resume data.
+	// The PVC is not owned by the TorrentServer and is kept when it is deleted.
+	// The other fields are ignored when set.
+	// +optional
+	ExistingClaimName string `json:"existingClaimName,omitempty"`
+
 	// StorageClassName is the name of the StorageClass to use.
 	// +optional
 	StorageClassName *string `json:"storageClassName,omitempty"`
@@ -91,9 +438,30 @@ type TorrentServerStatus struct {
 	// DeploymentName is the name of the managed Deployment.
 	DeploymentName string `json:"deploymentName,omitempty"`
 
-	// ServiceName is the name of the managed Service.
+	// ObservedGeneration is the spec generation whose child resources were last fully reconciled.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// CredentialsSecretName is the name of the credentials Secret in use.
+	CredentialsSecretName string `json:"credentialsSecretName,omitempty"`
+
+	// ConfigSeeded reports whether the qBittorrent config credentials were written by config-init from the
+	// credentials Secret. False means config-init found a pre-existing config, e.g. an imported one,
+	// whose own WebUI credentials are used instead. Unset until a config-init run is observed.
+	// +optional
+	ConfigSeeded *bool `json:"configSeeded,omitempty"`
+
+	// ServiceName is the name of the managed Service, empty when spec.service.enabled is false.
 	ServiceName string `json:"serviceName,omitempty"`
 
+	// ExternalServiceName is the name of the external Service, when spec.externalService is set.
+	ExternalServiceName string `json:"externalServiceName,omitempty"`
+
+	// HTTPRouteName is the name of the managed HTTPRoute, when spec.httpRoute is set.
+	HTTPRouteName string `json:"httpRouteName,omitempty"`
+
+	// HTTPRouteURL is the URL the WebUI is reachable at through the HTTPRoute, when spec.httpRoute.hostname is set.
+	HTTPRouteURL string `json:"httpRouteURL,omitempty"`
+
 	// ConfigPVCName is the name of the managed config PVC.
 	ConfigPVCName string `json:"configPVCName,omitempty"`
 
@@ -106,6 +474,19 @@ type TorrentServerStatus struct {
 	// URL is the internal service URL for the qBittorrent WebUI.
 	URL string `json:"url,omitempty"`
 
+	// Suspended is true once every torrent was stopped for spec.suspend.
+	Suspended bool `json:"suspended,omitempty"`
+
+	// SuspendedTorrents are the hashes of the torrents that were running when the instance was suspended,
+	// recorded before stopping them and restarted when spec.suspend is unset.
+	SuspendedTorrents []string `json:"suspendedTorrents,omitempty"`
+
+	// FreeSpaceOnDisk is the free space in bytes on the volume of the default save path, as reported by qBittorrent.
+	FreeSpaceOnDisk *int64 `json:"freeSpaceOnDisk,omitempty"`
+
+	// FreeSpaceOnDiskHuman is FreeSpaceOnDisk in human-readable form (e.g., "42.0 GiB").
+	FreeSpaceOnDiskHuman string `json:"freeSpaceOnDiskHuman,omitempty"`
+
 	// Conditions represent the latest available observations of the TorrentServer state.
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 }
@@ -115,6 +496,7 @@ type TorrentServerStatus struct {
 // +kubebuilder:resource:shortName=ts
 // +kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.readyReplicas"
 // +kubebuilder:printcolumn:name="URL",type="string",JSONPath=".status.url"
+// +kubebuilder:printcolumn:name="Free",type="string",JSONPath=".status.freeSpaceOnDiskHuman"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // TorrentServer is the Schema for the torrentservers API.
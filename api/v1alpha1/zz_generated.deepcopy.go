@@ -22,10 +22,107 @@ package v1alpha1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlternativeWebUISpec) DeepCopyInto(out *AlternativeWebUISpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlternativeWebUISpec.
+func (in *AlternativeWebUISpec) DeepCopy() *AlternativeWebUISpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AlternativeWebUISpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppliedFileRename) DeepCopyInto(out *AppliedFileRename) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppliedFileRename.
+func (in *AppliedFileRename) DeepCopy() *AppliedFileRename {
+	if in == nil {
+		return nil
+	}
+	out := new(AppliedFileRename)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BitTorrentSpec) DeepCopyInto(out *BitTorrentSpec) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+	if in.EnableDHT != nil {
+		in, out := &in.EnableDHT, &out.EnableDHT
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableLSD != nil {
+		in, out := &in.EnableLSD, &out.EnableLSD
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnablePEX != nil {
+		in, out := &in.EnablePEX, &out.EnablePEX
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BitTorrentSpec.
+func (in *BitTorrentSpec) DeepCopy() *BitTorrentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BitTorrentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CategorySpec) DeepCopyInto(out *CategorySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CategorySpec.
+func (in *CategorySpec) DeepCopy() *CategorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CategorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientAuthSpec) DeepCopyInto(out *ClientAuthSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientAuthSpec.
+func (in *ClientAuthSpec) DeepCopy() *ClientAuthSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientAuthSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DownloadVolumeSpec) DeepCopyInto(out *DownloadVolumeSpec) {
 	*out = *in
@@ -41,6 +138,144 @@ func (in *DownloadVolumeSpec) DeepCopy() *DownloadVolumeSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExportToSecret) DeepCopyInto(out *ExportToSecret) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportToSecret.
+func (in *ExportToSecret) DeepCopy() *ExportToSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(ExportToSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalServiceSpec) DeepCopyInto(out *ExternalServiceSpec) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalServiceSpec.
+func (in *ExternalServiceSpec) DeepCopy() *ExternalServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilePriority) DeepCopyInto(out *FilePriority) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilePriority.
+func (in *FilePriority) DeepCopy() *FilePriority {
+	if in == nil {
+		return nil
+	}
+	out := new(FilePriority)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileRename) DeepCopyInto(out *FileRename) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileRename.
+func (in *FileRename) DeepCopy() *FileRename {
+	if in == nil {
+		return nil
+	}
+	out := new(FileRename)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayReference) DeepCopyInto(out *GatewayReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayReference.
+func (in *GatewayReference) DeepCopy() *GatewayReference {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRouteSpec) DeepCopyInto(out *HTTPRouteSpec) {
+	*out = *in
+	out.ParentRef = in.ParentRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRouteSpec.
+func (in *HTTPRouteSpec) DeepCopy() *HTTPRouteSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRouteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPFilterSpec) DeepCopyInto(out *IPFilterSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPFilterSpec.
+func (in *IPFilterSpec) DeepCopy() *IPFilterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IPFilterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IncompleteStorageSpec) DeepCopyInto(out *IncompleteStorageSpec) {
+	*out = *in
+	if in.SizeLimit != nil {
+		in, out := &in.SizeLimit, &out.SizeLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IncompleteStorageSpec.
+func (in *IncompleteStorageSpec) DeepCopy() *IncompleteStorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IncompleteStorageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LocalObjectReference) DeepCopyInto(out *LocalObjectReference) {
 	*out = *in
@@ -56,6 +291,106 @@ func (in *LocalObjectReference) DeepCopy() *LocalObjectReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrivacySpec) DeepCopyInto(out *PrivacySpec) {
+	*out = *in
+	if in.DHT != nil {
+		in, out := &in.DHT, &out.DHT
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PeX != nil {
+		in, out := &in.PeX, &out.PeX
+		*out = new(bool)
+		**out = **in
+	}
+	if in.LSD != nil {
+		in, out := &in.LSD, &out.LSD
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrivacySpec.
+func (in *PrivacySpec) DeepCopy() *PrivacySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PrivacySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueueingSpec) DeepCopyInto(out *QueueingSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxActiveDownloads != nil {
+		in, out := &in.MaxActiveDownloads, &out.MaxActiveDownloads
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxActiveUploads != nil {
+		in, out := &in.MaxActiveUploads, &out.MaxActiveUploads
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxActiveTorrents != nil {
+		in, out := &in.MaxActiveTorrents, &out.MaxActiveTorrents
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueueingSpec.
+func (in *QueueingSpec) DeepCopy() *QueueingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(QueueingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulerSpec) DeepCopyInto(out *SchedulerSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerSpec.
+func (in *SchedulerSpec) DeepCopy() *SchedulerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretReference) DeepCopyInto(out *SecretReference) {
 	*out = *in
@@ -71,6 +406,26 @@ func (in *SecretReference) DeepCopy() *SecretReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceSpec) DeepCopyInto(out *ServiceSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceSpec.
+func (in *ServiceSpec) DeepCopy() *ServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageSpec) DeepCopyInto(out *StorageSpec) {
 	*out = *in
@@ -128,7 +483,7 @@ func (in *TorrentClientConfiguration) DeepCopyInto(out *TorrentClientConfigurati
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -185,7 +540,31 @@ func (in *TorrentClientConfigurationList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TorrentClientConfigurationSpec) DeepCopyInto(out *TorrentClientConfigurationSpec) {
 	*out = *in
-	out.CredentialsSecret = in.CredentialsSecret
+	if in.CredentialsSecret != nil {
+		in, out := &in.CredentialsSecret, &out.CredentialsSecret
+		*out = new(SecretReference)
+		**out = **in
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(ClientAuthSpec)
+		**out = **in
+	}
+	if in.Privacy != nil {
+		in, out := &in.Privacy, &out.Privacy
+		*out = new(PrivacySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindow)
+		**out = **in
+	}
+	if in.Categories != nil {
+		in, out := &in.Categories, &out.Categories
+		*out = make([]CategorySpec, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TorrentClientConfigurationSpec.
@@ -224,6 +603,21 @@ func (in *TorrentClientConfigurationStatus) DeepCopy() *TorrentClientConfigurati
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TorrentFileStatus) DeepCopyInto(out *TorrentFileStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TorrentFileStatus.
+func (in *TorrentFileStatus) DeepCopy() *TorrentFileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TorrentFileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TorrentList) DeepCopyInto(out *TorrentList) {
 	*out = *in
@@ -324,6 +718,11 @@ func (in *TorrentServerSpec) DeepCopyInto(out *TorrentServerSpec) {
 		**out = **in
 	}
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.InitResources != nil {
+		in, out := &in.InitResources, &out.InitResources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Env != nil {
 		in, out := &in.Env, &out.Env
 		*out = make([]corev1.EnvVar, len(*in))
@@ -331,6 +730,16 @@ func (in *TorrentServerSpec) DeepCopyInto(out *TorrentServerSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PUID != nil {
+		in, out := &in.PUID, &out.PUID
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PGID != nil {
+		in, out := &in.PGID, &out.PGID
+		*out = new(int64)
+		**out = **in
+	}
 	if in.ConfigStorage != nil {
 		in, out := &in.ConfigStorage, &out.ConfigStorage
 		*out = new(StorageSpec)
@@ -341,11 +750,121 @@ func (in *TorrentServerSpec) DeepCopyInto(out *TorrentServerSpec) {
 		*out = make([]DownloadVolumeSpec, len(*in))
 		copy(*out, *in)
 	}
+	if in.WaitForDownloadVolumes != nil {
+		in, out := &in.WaitForDownloadVolumes, &out.WaitForDownloadVolumes
+		*out = new(bool)
+		**out = **in
+	}
 	if in.CredentialsSecret != nil {
 		in, out := &in.CredentialsSecret, &out.CredentialsSecret
 		*out = new(SecretReference)
 		**out = **in
 	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ServiceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalService != nil {
+		in, out := &in.ExternalService, &out.ExternalService
+		*out = new(ExternalServiceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTPRoute != nil {
+		in, out := &in.HTTPRoute, &out.HTTPRoute
+		*out = new(HTTPRouteSpec)
+		**out = **in
+	}
+	if in.StartupProbe != nil {
+		in, out := &in.StartupProbe, &out.StartupProbe
+		*out = new(corev1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraVolumes != nil {
+		in, out := &in.ExtraVolumes, &out.ExtraVolumes
+		*out = make([]corev1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraVolumeMounts != nil {
+		in, out := &in.ExtraVolumeMounts, &out.ExtraVolumeMounts
+		*out = make([]corev1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Preferences != nil {
+		in, out := &in.Preferences, &out.Preferences
+		*out = make(map[string]apiextensionsv1.JSON, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.CategoryPaths != nil {
+		in, out := &in.CategoryPaths, &out.CategoryPaths
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.BitTorrent != nil {
+		in, out := &in.BitTorrent, &out.BitTorrent
+		*out = new(BitTorrentSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Queueing != nil {
+		in, out := &in.Queueing, &out.Queueing
+		*out = new(QueueingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scheduler != nil {
+		in, out := &in.Scheduler, &out.Scheduler
+		*out = new(SchedulerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AlternativeWebUI != nil {
+		in, out := &in.AlternativeWebUI, &out.AlternativeWebUI
+		*out = new(AlternativeWebUISpec)
+		**out = **in
+	}
+	if in.LowDiskSpaceThreshold != nil {
+		in, out := &in.LowDiskSpaceThreshold, &out.LowDiskSpaceThreshold
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.IncompleteStorage != nil {
+		in, out := &in.IncompleteStorage, &out.IncompleteStorage
+		*out = new(IncompleteStorageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IPFilter != nil {
+		in, out := &in.IPFilter, &out.IPFilter
+		*out = new(IPFilterSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TorrentServerSpec.
@@ -361,6 +880,21 @@ func (in *TorrentServerSpec) DeepCopy() *TorrentServerSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TorrentServerStatus) DeepCopyInto(out *TorrentServerStatus) {
 	*out = *in
+	if in.ConfigSeeded != nil {
+		in, out := &in.ConfigSeeded, &out.ConfigSeeded
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SuspendedTorrents != nil {
+		in, out := &in.SuspendedTorrents, &out.SuspendedTorrents
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FreeSpaceOnDisk != nil {
+		in, out := &in.FreeSpaceOnDisk, &out.FreeSpaceOnDisk
+		*out = new(int64)
+		**out = **in
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -383,11 +917,93 @@ func (in *TorrentServerStatus) DeepCopy() *TorrentServerStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TorrentSpec) DeepCopyInto(out *TorrentSpec) {
 	*out = *in
+	if in.MagnetURIs != nil {
+		in, out := &in.MagnetURIs, &out.MagnetURIs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Adopt != nil {
+		in, out := &in.Adopt, &out.Adopt
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MetadataTimeout != nil {
+		in, out := &in.MetadataTimeout, &out.MetadataTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	if in.ClientConfigRef != nil {
 		in, out := &in.ClientConfigRef, &out.ClientConfigRef
 		*out = new(LocalObjectReference)
 		**out = **in
 	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.FileRenames != nil {
+		in, out := &in.FileRenames, &out.FileRenames
+		*out = make([]FileRename, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxConnections != nil {
+		in, out := &in.MaxConnections, &out.MaxConnections
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxUploads != nil {
+		in, out := &in.MaxUploads, &out.MaxUploads
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.SkipHashCheck != nil {
+		in, out := &in.SkipHashCheck, &out.SkipHashCheck
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AddToTopOfQueue != nil {
+		in, out := &in.AddToTopOfQueue, &out.AddToTopOfQueue
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CreateCategoryIfMissing != nil {
+		in, out := &in.CreateCategoryIfMissing, &out.CreateCategoryIfMissing
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FilePriorities != nil {
+		in, out := &in.FilePriorities, &out.FilePriorities
+		*out = make([]FilePriority, len(*in))
+		copy(*out, *in)
+	}
+	if in.MetadataOnly != nil {
+		in, out := &in.MetadataOnly, &out.MetadataOnly
+		*out = new(bool)
+		**out = **in
+	}
+	if in.StopSeedingOnComplete != nil {
+		in, out := &in.StopSeedingOnComplete, &out.StopSeedingOnComplete
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ExportToSecret != nil {
+		in, out := &in.ExportToSecret, &out.ExportToSecret
+		*out = new(ExportToSecret)
+		**out = **in
+	}
 	if in.DeleteFilesOnRemoval != nil {
 		in, out := &in.DeleteFilesOnRemoval, &out.DeleteFilesOnRemoval
 		*out = new(bool)
@@ -408,6 +1024,43 @@ func (in *TorrentSpec) DeepCopy() *TorrentSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TorrentStatus) DeepCopyInto(out *TorrentStatus) {
 	*out = *in
+	if in.LastSeenDownloaded != nil {
+		in, out := &in.LastSeenDownloaded, &out.LastSeenDownloaded
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LastSeenUploaded != nil {
+		in, out := &in.LastSeenUploaded, &out.LastSeenUploaded
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SourceAttemptStartedAt != nil {
+		in, out := &in.SourceAttemptStartedAt, &out.SourceAttemptStartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.FailedSources != nil {
+		in, out := &in.FailedSources, &out.FailedSources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]TorrentFileStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.AppliedFileRenames != nil {
+		in, out := &in.AppliedFileRenames, &out.AppliedFileRenames
+		*out = make([]AppliedFileRename, len(*in))
+		copy(*out, *in)
+	}
+	if in.ArchiveRequestedAt != nil {
+		in, out := &in.ArchiveRequestedAt, &out.ArchiveRequestedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.SettingsVerifiedAt != nil {
+		in, out := &in.SettingsVerifiedAt, &out.SettingsVerifiedAt
+		*out = (*in).DeepCopy()
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
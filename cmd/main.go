@@ -3,18 +3,21 @@ package main
 import (
 	"crypto/tls"
 	"flag"
-	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -25,6 +28,7 @@ import (
 	"github.com/guidonguido/qbittorrent-operator/internal/configinit"
 	"github.com/guidonguido/qbittorrent-operator/internal/controller"
 	"github.com/guidonguido/qbittorrent-operator/internal/qbittorrent"
+	webhookv1alpha1 "github.com/guidonguido/qbittorrent-operator/internal/webhook/v1alpha1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -46,10 +50,11 @@ func main() {
 	// The config-init mode is executed only by the init container,
 	// needed to initialize credentials on qBittorrent server first boot.
 	if len(os.Args) > 1 && os.Args[1] == "config-init" {
-		if err := configinit.Run(); err != nil {
-			fmt.Fprintf(os.Stderr, "config-init failed: %v\n", err)
-			os.Exit(1)
+		seeded, err := configinit.Run()
+		if err != nil {
+			os.Exit(configinit.ReportFailure(os.Stderr, err))
 		}
+		configinit.ReportSuccess(os.Stdout, seeded)
 		os.Exit(0)
 	}
 
@@ -60,6 +65,19 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var clientPoolOptions qbittorrent.ClientPoolOptions
+	var terminalRequeueInterval time.Duration
+	var torrentServerResyncInterval time.Duration
+	var settingsVerifyInterval time.Duration
+	var logExcerptLines int
+	var disallowFileDeletion bool
+	var categoryFromNamespace bool
+	var namespaces string
+	var bearerTokenDir string
+	var clientTrace bool
+	var defaultImage string
+	var imageRegistryPrefix string
+	var minQBittorrentVersion string
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -78,6 +96,42 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	bindClientPoolFlags(flag.CommandLine, &clientPoolOptions)
+	flag.DurationVar(&terminalRequeueInterval, "terminal-requeue-interval", 10*time.Minute,
+		"How long to wait before retrying a Torrent that failed with a terminal error (e.g. invalid magnet). "+
+			"Set to 0 to retry only when the resource changes.")
+	flag.DurationVar(&torrentServerResyncInterval, "torrentserver-resync-interval", 5*time.Minute,
+		"How long to wait before reconciling a healthy TorrentServer again, "+
+			"to check its WebUI, preferences and free disk space. Failures still retry after 10s. "+
+			"Set to 0 to reconcile only when the resource or its children change.")
+	flag.DurationVar(&settingsVerifyInterval, "settings-verify-interval", 10*time.Minute,
+		"How often the category and file priorities of each Torrent are checked against qBittorrent, "+
+			"which may lose them across a restart, and applied again when they drifted. Set to 0 to disable.")
+	flag.IntVar(&logExcerptLines, "log-excerpt-lines", 0,
+		"Number of recent qBittorrent warning and critical log lines emitted as an event when a Torrent becomes Degraded. "+
+			"Set to 0 to never fetch the qBittorrent log.")
+	flag.StringVar(&namespaces, "namespaces", "",
+		"Comma-separated list of namespaces watched by the controllers. Leave empty to watch all namespaces.")
+	flag.StringVar(&bearerTokenDir, "bearer-token-dir", "",
+		"Directory holding the bearer token files TorrentClientConfigurations may reference with spec.auth.tokenFile, "+
+			"e.g. a projected service account token volume. Leave empty to only read tokens from Secrets.")
+	flag.BoolVar(&disallowFileDeletion, "disallow-file-deletion", false,
+		"If set, downloaded files are never deleted when a Torrent is removed, regardless of spec.deleteFilesOnRemoval.")
+	flag.BoolVar(&categoryFromNamespace, "default-category-from-namespace", false,
+		"If set, the Torrent webhook defaults spec.category of new Torrents to their namespace. Requires webhooks.")
+	flag.StringVar(&defaultImage, "default-qbittorrent-image", "",
+		"qBittorrent image of TorrentServers without spec.image, e.g. an internal mirror. "+
+			"Leave empty to use the latest tested linuxserver image.")
+	flag.StringVar(&imageRegistryPrefix, "image-registry-prefix", "",
+		"Registry, with an optional path, replacing the registry of the qBittorrent images set in spec.image "+
+			"and of the built-in default image, e.g. mirror.internal:5000/cache for air-gapped clusters. "+
+			"--default-qbittorrent-image is used as given. Leave empty to pull the images as written.")
+	flag.StringVar(&minQBittorrentVersion, "min-qbittorrent-version", qbittorrent.DefaultMinQBittorrentVersion,
+		"Oldest supported qBittorrent version. TorrentClientConfigurations and TorrentServers running an older one "+
+			"report an UnsupportedVersion condition; nothing is blocked.")
+	flag.BoolVar(&clientTrace, "client-trace", os.Getenv("QBITTORRENT_TRACE") == "true",
+		"If set, every qBittorrent API request is logged with its status and duration, regardless of the log level. "+
+			"Credentials and cookies are redacted. Defaults to the QBITTORRENT_TRACE environment variable.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -85,6 +139,14 @@ func main() {
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	if err := qbittorrent.ValidateAppVersion(minQBittorrentVersion); err != nil {
+		setupLog.Error(err, "invalid --min-qbittorrent-version")
+		os.Exit(1)
+	}
+	if clientTrace {
+		// A dedicated logger, so that --zap-log-level does not hide the trace
+		clientPoolOptions.TraceLogger = zap.New(zap.UseDevMode(opts.Development)).WithName("qbittorrent-trace")
+	}
 
 	if !enableHTTP2 {
 		tlsOpts = append(tlsOpts,
@@ -160,6 +222,7 @@ func main() {
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "e3228fca.qbittorrent.io",
+		Cache:                  cacheOptions(namespaces),
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -168,13 +231,25 @@ func main() {
 
 	// The qBittorrent is shared between TCC and Torrent controllers
 	// So already existing connections will be reused, based on server and credentials
-	clientPool := qbittorrent.NewClientPool(1 * time.Minute)
+	clientPool := qbittorrent.NewClientPoolWithOptions(clientPoolOptions)
+
+	// Expired sessions are swept by a leader election runnable, so only the leader runs the sweep
+	if err := mgr.Add(clientPool); err != nil {
+		setupLog.Error(err, "unable to add client pool cleanup to the manager")
+		os.Exit(1)
+	}
 
 	// Build TS controller and register to the manager
 	if err := (&controller.TorrentServerReconciler{
-		Client:        mgr.GetClient(),
-		Scheme:        mgr.GetScheme(),
-		OperatorImage: os.Getenv("OPERATOR_IMAGE"),
+		Client:                mgr.GetClient(),
+		Scheme:                mgr.GetScheme(),
+		OperatorImage:         os.Getenv("OPERATOR_IMAGE"),
+		ClientPool:            clientPool,
+		Recorder:              mgr.GetEventRecorderFor("torrentserver-controller"),
+		ResyncInterval:        torrentServerResyncInterval,
+		DefaultImage:          defaultImage,
+		ImageRegistryPrefix:   imageRegistryPrefix,
+		MinQBittorrentVersion: minQBittorrentVersion,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "TorrentServer")
 		os.Exit(1)
@@ -182,9 +257,11 @@ func main() {
 
 	// Build TCC controller and register to the manager
 	if err := (&controller.TorrentClientConfigurationReconciler{
-		Client:     mgr.GetClient(),
-		Scheme:     mgr.GetScheme(),
-		ClientPool: clientPool,
+		Client:                mgr.GetClient(),
+		Scheme:                mgr.GetScheme(),
+		ClientPool:            clientPool,
+		BearerTokenDir:        bearerTokenDir,
+		MinQBittorrentVersion: minQBittorrentVersion,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "TorrentClientConfiguration")
 		os.Exit(1)
@@ -192,13 +269,32 @@ func main() {
 
 	// Build Torrent controller and register to the manager
 	if err := (&controller.TorrentReconciler{
-		Client:     mgr.GetClient(),
-		Scheme:     mgr.GetScheme(),
-		ClientPool: clientPool,
+		Client:                  mgr.GetClient(),
+		Scheme:                  mgr.GetScheme(),
+		ClientPool:              clientPool,
+		TerminalRequeueInterval: terminalRequeueInterval,
+		BearerTokenDir:          bearerTokenDir,
+		LogExcerptLines:         logExcerptLines,
+		DisallowFileDeletion:    disallowFileDeletion,
+		SettingsVerifyInterval:  settingsVerifyInterval,
+		Recorder:                mgr.GetEventRecorderFor("torrent-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Torrent")
 		os.Exit(1)
 	}
+
+	// Webhooks need serving certificates, so they are opt-in: the [WEBHOOK] kustomize
+	// sections set ENABLE_WEBHOOKS=true together with the certificate mount
+	if os.Getenv("ENABLE_WEBHOOKS") == "true" {
+		if err := webhookv1alpha1.SetupTorrentClientConfigurationWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "TorrentClientConfiguration")
+			os.Exit(1)
+		}
+		if err := webhookv1alpha1.SetupTorrentWebhookWithManager(mgr, categoryFromNamespace); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Torrent")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if metricsCertWatcher != nil {
@@ -226,9 +322,59 @@ func main() {
 		os.Exit(1)
 	}
 
+	if scope := cacheOptions(namespaces).DefaultNamespaces; len(scope) > 0 {
+		setupLog.Info("restricting controllers to namespaces", "namespaces", namespaces)
+	}
+
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// cacheOptions restricts the manager cache, and so every controller, to the given
+// comma-separated namespaces. An empty list watches all namespaces.
+// Only the pods of TorrentServers are cached, for their config-init outcome
+func cacheOptions(namespaces string) cache.Options {
+	opts := cache.Options{
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.Pod{}: {Label: controller.ManagedPodSelector()},
+		},
+	}
+	for _, namespace := range strings.Split(namespaces, ",") {
+		namespace = strings.TrimSpace(namespace)
+		if namespace == "" {
+			continue
+		}
+		if opts.DefaultNamespaces == nil {
+			opts.DefaultNamespaces = make(map[string]cache.Config)
+		}
+		opts.DefaultNamespaces[namespace] = cache.Config{}
+	}
+	return opts
+}
+
+// bindClientPoolFlags registers the flags tuning the shared qBittorrent client pool
+func bindClientPoolFlags(fs *flag.FlagSet, opts *qbittorrent.ClientPoolOptions) {
+	fs.DurationVar(&opts.TTL, "client-pool-ttl", 1*time.Minute,
+		"How long an unused qBittorrent session stays cached before it is dropped. "+
+			"Longer values trade memory for fewer logins.")
+	fs.IntVar(&opts.MaxSize, "client-pool-max-size", 0,
+		"Maximum number of cached qBittorrent sessions; the least recently used one is evicted beyond it. "+
+			"Set to 0 for no limit.")
+	fs.StringVar(&opts.UserAgent, "client-user-agent", "",
+		"User-Agent sent with every qBittorrent request. Defaults to qbittorrent-operator/<version>.")
+	fs.DurationVar(&opts.MaxSessionAge, "client-session-max-age", 30*time.Minute,
+		"Maximum age of a cached qBittorrent session before the operator logs in again. "+
+			"Keep it below qBittorrent's WebUI session timeout (3600s by default). Set to 0 to disable.")
+	fs.IntVar(&opts.CircuitBreaker.Threshold, "client-circuit-breaker-threshold", 5,
+		"Consecutive failed requests to a qBittorrent URL after which its requests fail fast. Set to 0 to disable.")
+	fs.DurationVar(&opts.CircuitBreaker.Cooldown, "client-circuit-breaker-cooldown", 30*time.Second,
+		"How long requests to a failing qBittorrent URL fail fast before a single probe request is let through.")
+	fs.Float64Var(&opts.RateLimit.QPS, "client-rate-limit-qps", 0,
+		"Maximum sustained requests per second sent to a single qBittorrent URL; requests over it wait. "+
+			"Set to 0 to disable.")
+	fs.IntVar(&opts.RateLimit.Burst, "client-rate-limit-burst", 10,
+		"Requests sent to a single qBittorrent URL without waiting after an idle period, when rate limiting is enabled.")
+}
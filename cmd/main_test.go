@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/guidonguido/qbittorrent-operator/internal/qbittorrent"
+)
+
+func TestBindClientPoolFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	var opts qbittorrent.ClientPoolOptions
+	bindClientPoolFlags(fs, &opts)
+
+	if err := fs.Parse([]string{"--client-pool-ttl=5m", "--client-pool-max-size=10", "--client-user-agent=media-stack/1.0"}); err != nil {
+		t.Fatalf("failed to parse flags: %v", err)
+	}
+
+	pool := qbittorrent.NewClientPoolWithOptions(opts)
+	if pool.TTL() != 5*time.Minute {
+		t.Errorf("expected TTL 5m, got %v", pool.TTL())
+	}
+	if pool.MaxSize() != 10 {
+		t.Errorf("expected max size 10, got %d", pool.MaxSize())
+	}
+	if opts.UserAgent != "media-stack/1.0" {
+		t.Errorf("expected User-Agent media-stack/1.0, got %q", opts.UserAgent)
+	}
+	if opts.MaxSessionAge != 30*time.Minute {
+		t.Errorf("expected default max session age 30m, got %v", opts.MaxSessionAge)
+	}
+}
+
+func TestBindClientPoolFlags_Defaults(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	var opts qbittorrent.ClientPoolOptions
+	bindClientPoolFlags(fs, &opts)
+
+	if err := fs.Parse(nil); err != nil {
+		t.Fatalf("failed to parse flags: %v", err)
+	}
+	if opts.TTL != time.Minute {
+		t.Errorf("expected default TTL 1m, got %v", opts.TTL)
+	}
+	if opts.MaxSize != 0 {
+		t.Errorf("expected unbounded pool by default, got %d", opts.MaxSize)
+	}
+	if opts.CircuitBreaker.Threshold != 5 || opts.CircuitBreaker.Cooldown != 30*time.Second {
+		t.Errorf("expected circuit breaker opening after 5 failures for 30s by default, got %+v", opts.CircuitBreaker)
+	}
+	if opts.RateLimit.QPS != 0 || opts.RateLimit.Burst != 10 {
+		t.Errorf("expected rate limiting disabled with a burst of 10 by default, got %+v", opts.RateLimit)
+	}
+}
+
+func TestCacheOptions(t *testing.T) {
+	if opts := cacheOptions(""); opts.DefaultNamespaces != nil {
+		t.Errorf("expected all namespaces to be watched by default, got %v", opts.DefaultNamespaces)
+	}
+
+	opts := cacheOptions("media, downloads,,media")
+	got := slices.Sorted(maps.Keys(opts.DefaultNamespaces))
+	if want := []string{"downloads", "media"}; !slices.Equal(got, want) {
+		t.Errorf("expected namespaces %v, got %v", want, got)
+	}
+
+	for obj, byObject := range opts.ByObject {
+		if _, ok := obj.(*corev1.Pod); !ok {
+			t.Errorf("expected only pods to be filtered, got %T", obj)
+			continue
+		}
+		if got := byObject.Label.String(); got != "app.kubernetes.io/managed-by=qbittorrent-operator" {
+			t.Errorf("expected only TorrentServer pods to be cached, got selector %q", got)
+		}
+	}
+	if len(opts.ByObject) != 1 {
+		t.Errorf("expected the pod cache to be filtered, got %v", opts.ByObject)
+	}
+}
@@ -1,10 +1,13 @@
 package configinit
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"syscall"
 
 	"github.com/guidonguido/qbittorrent-operator/internal/qbittorrent"
 )
@@ -14,50 +17,313 @@ var (
 	defaultConfigPath      = "/config"
 )
 
-// Read credentials mounted to defaultCredentialsPath and write qBittorrent.conf
-func Run() error {
+// Environment variables set by the TorrentServer controller on the config-init container
+const (
+	// EnvAlternativeWebUIRootFolder enables the alternative WebUI served from the given folder
+	EnvAlternativeWebUIRootFolder = "ALTERNATIVE_WEBUI_ROOT_FOLDER"
+	// EnvPUID and EnvPGID are the user and group qBittorrent runs as, owning the written config
+	EnvPUID = "PUID"
+	EnvPGID = "PGID"
+	// EnvResetCredentials rewrites the credentials of an existing config from the mounted Secret when set.
+	// Its value identifies the rotation, so that changing it rolls the pod
+	EnvResetCredentials = "RESET_CREDENTIALS"
+)
 
-	// Up to qBittorrent 5.1.4, the config file is expected at /config/qBittorrent/qBittorrent.conf
-	configFile := filepath.Join(defaultConfigPath, "qBittorrent", "qBittorrent.conf")
+// chown is replaced in tests, which cannot change ownership without privileges
+var chown = os.Chown
 
-	// Skip config-init if the config file already exists
-	if _, err := os.Stat(configFile); err == nil {
-		fmt.Println("config-init: qBittorrent.conf already exists, skipping")
-		return nil
+// lockFileName is the file in the config directory that serializes concurrent config-init runs
+const lockFileName = ".config-init.lock"
+
+// seededMarkerName is the file in the config directory marking a config whose credentials were written
+// from the credentials Secret, telling it apart from a pre-existing config, e.g. imported with its own credentials
+const seededMarkerName = ".operator-seeded"
+
+// setting is a key of the [Preferences] section managed by config-init
+type setting struct {
+	key   string
+	value string
+}
+
+// Read credentials mounted to defaultCredentialsPath and write qBittorrent.conf. Reports whether the config
+// credentials were seeded from the Secret, by this or a previous run, rather than found in a pre-existing config.
+// Failures are returned as *Failure, reporting the reason, path and targeted config file
+func Run() (bool, error) {
+	// Up to qBittorrent 5.1.4, the config file is expected at /config/qBittorrent/qBittorrent.conf
+	configDir := filepath.Join(defaultConfigPath, "qBittorrent")
+	configFile := filepath.Join(configDir, "qBittorrent.conf")
+	credentials := credentialsNotRead
+	fail := func(reason Reason, path string, err error) error {
+		return &Failure{Reason: reason, Path: path, ConfigFile: configFile, Credentials: credentials, Err: err}
+	}
+	credentialsFailure := func(path string, err error) error {
+		if errors.Is(err, errPasswordHash) {
+			credentials = credentialsFound
+			return fail(ReasonPasswordHashFailed, path, err)
+		}
+		credentials = credentialsMissing
+		return fail(ReasonCredentialsNotFound, path, err)
 	}
+	fmt.Printf("config-init: targeting %s, credentials from %s\n", configFile, defaultCredentialsPath)
 
-	// TorrentServer pods mount credentials from secret at /credentials/username and /credentials/password
-	usernameBytes, err := os.ReadFile(filepath.Join(defaultCredentialsPath, "username"))
+	// Config-init runs sharing the same config directory are serialized: the first one
+	// creates the config file, the next ones find it and only update the managed settings
+	unlock, err := lockConfig(defaultConfigPath)
 	if err != nil {
-		return fmt.Errorf("failed to read username: %w", err)
+		return false, fail(ReasonLockFailed, filepath.Join(defaultConfigPath, lockFileName), err)
 	}
-	passwordBytes, err := os.ReadFile(filepath.Join(defaultCredentialsPath, "password"))
+	defer unlock()
+
+	settings := settingsFromEnv()
+
+	owner, err := ownerFromEnv()
 	if err != nil {
-		return fmt.Errorf("failed to read password: %w", err)
+		return false, fail(ReasonInvalidOwner, "", err)
 	}
 
-	username := strings.TrimSpace(string(usernameBytes))
-	password := strings.TrimSpace(string(passwordBytes))
+	// Keep the existing config file, only updating the settings managed through the TorrentServer spec
+	if _, err := os.Stat(configFile); err == nil {
+		credentials = credentialsUnused
+		reset := os.Getenv(EnvResetCredentials) != ""
+		if reset {
+			username, hashedPassword, path, err := readCredentials()
+			if err != nil {
+				return false, credentialsFailure(path, err)
+			}
+			credentials = credentialsFound
+			settings = append(settings,
+				setting{key: "WebUI\\Username", value: username},
+				setting{key: "WebUI\\Password_PBKDF2", value: `"` + hashedPassword + `"`},
+			)
+		}
+		if len(settings) == 0 {
+			fmt.Println("config-init: qBittorrent.conf already exists, skipping")
+			if err := owner.apply(configFile); err != nil {
+				return false, fail(ReasonOwnershipFailed, configFile, err)
+			}
+			return seededMarkerExists(), nil
+		}
+		content, err := os.ReadFile(configFile)
+		if err != nil {
+			return false, fail(ReasonConfigUnreadable, configFile, fmt.Errorf("failed to read config file: %w", err))
+		}
+		if err := os.WriteFile(configFile, []byte(upsertPreferences(string(content), settings)), 0644); err != nil {
+			return false, fail(ReasonConfigUnwritable, configFile, fmt.Errorf("failed to write config file: %w", err))
+		}
+		fmt.Printf("config-init: updated %d managed settings in existing %s\n", len(settings), configFile)
+		if err := owner.apply(configFile); err != nil {
+			return false, fail(ReasonOwnershipFailed, configFile, err)
+		}
+		if reset {
+			if err := writeSeededMarker(); err != nil {
+				return false, fail(ReasonConfigUnwritable, filepath.Join(defaultConfigPath, seededMarkerName), err)
+			}
+		}
+		return seededMarkerExists(), nil
+	}
 
-	// qBittorrent expects the password to be hashed using PBKDF2
-	hashedPassword, err := qbittorrent.HashPassword(password)
+	username, hashedPassword, path, err := readCredentials()
 	if err != nil {
-		return fmt.Errorf("failed to hash password: %w", err)
+		return false, credentialsFailure(path, err)
 	}
+	credentials = credentialsFound
 
-	configDir := filepath.Join(defaultConfigPath, "qBittorrent")
 	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return fmt.Errorf("failed to create config directory: %w", err)
+		return false, fail(ReasonConfigUnwritable, configDir, fmt.Errorf("failed to create config directory: %w", err))
 	}
 
 	content := fmt.Sprintf("[Preferences]\nWebUI\\Username=%s\nWebUI\\Password_PBKDF2=\"%s\"\n",
 		username, hashedPassword)
+	for _, s := range settings {
+		content += s.key + "=" + s.value + "\n"
+	}
 
 	// Only owner can write the created file
 	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
-		return fmt.Errorf("failed to write config file: %w", err)
+		return false, fail(ReasonConfigUnwritable, configFile, fmt.Errorf("failed to write config file: %w", err))
 	}
 
-	fmt.Printf("config-init: wrote %s/qBittorrent/qBittorrent.conf with pre-seeded credentials\n", configDir)
+	fmt.Printf("config-init: wrote %s with pre-seeded credentials\n", configFile)
+	if err := owner.apply(configFile); err != nil {
+		return false, fail(ReasonOwnershipFailed, configFile, err)
+	}
+	if err := writeSeededMarker(); err != nil {
+		return false, fail(ReasonConfigUnwritable, filepath.Join(defaultConfigPath, seededMarkerName), err)
+	}
+	return true, nil
+}
+
+// writeSeededMarker records that the config credentials were written from the credentials Secret
+func writeSeededMarker() error {
+	if err := os.WriteFile(filepath.Join(defaultConfigPath, seededMarkerName), nil, 0644); err != nil {
+		return fmt.Errorf("failed to write seeded marker: %w", err)
+	}
 	return nil
 }
+
+func seededMarkerExists() bool {
+	_, err := os.Stat(filepath.Join(defaultConfigPath, seededMarkerName))
+	return err == nil
+}
+
+// errPasswordHash marks a readCredentials failure to hash a password that was read
+var errPasswordHash = errors.New("failed to hash password")
+
+// Read the credentials mounted from the Secret at defaultCredentialsPath, returning the username and
+// the password hashed with PBKDF2 as qBittorrent expects it. On failure, path is the offending file
+func readCredentials() (username, hashedPassword, path string, err error) {
+	usernamePath := filepath.Join(defaultCredentialsPath, "username")
+	usernameBytes, err := os.ReadFile(usernamePath)
+	if err != nil {
+		return "", "", usernamePath, fmt.Errorf("failed to read username: %w", err)
+	}
+	passwordPath := filepath.Join(defaultCredentialsPath, "password")
+	passwordBytes, err := os.ReadFile(passwordPath)
+	if err != nil {
+		return "", "", passwordPath, fmt.Errorf("failed to read password: %w", err)
+	}
+
+	hashedPassword, err = qbittorrent.HashPassword(strings.TrimSpace(string(passwordBytes)))
+	if err != nil {
+		return "", "", passwordPath, fmt.Errorf("%w: %w", errPasswordHash, err)
+	}
+	return strings.TrimSpace(string(usernameBytes)), hashedPassword, "", nil
+}
+
+// configOwner is the user and group the config is handed over to, -1 leaving the id unchanged
+type configOwner struct {
+	uid int
+	gid int
+}
+
+// Read the owner of the config from the PUID and PGID environment variables
+func ownerFromEnv() (configOwner, error) {
+	owner := configOwner{uid: -1, gid: -1}
+	for _, id := range []struct {
+		env    string
+		target *int
+	}{{EnvPUID, &owner.uid}, {EnvPGID, &owner.gid}} {
+		value := strings.TrimSpace(os.Getenv(id.env))
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return owner, fmt.Errorf("invalid %s %q: must be a non-negative integer", id.env, value)
+		}
+		*id.target = parsed
+	}
+	return owner, nil
+}
+
+// Hand the config directory and file over to the owner, so qBittorrent can manage them after startup.
+// The file holds the password hash: once owned by qBittorrent, it is made private to it
+func (o configOwner) apply(configFile string) error {
+	if o.uid < 0 && o.gid < 0 {
+		return nil
+	}
+	if err := chown(filepath.Dir(configFile), o.uid, o.gid); err != nil {
+		return fmt.Errorf("failed to change config directory ownership: %w", err)
+	}
+	if err := chown(configFile, o.uid, o.gid); err != nil {
+		return fmt.Errorf("failed to change config file ownership: %w", err)
+	}
+	if err := os.Chmod(configFile, 0600); err != nil {
+		return fmt.Errorf("failed to change config file permissions: %w", err)
+	}
+	fmt.Printf("config-init: handed %s over to uid %d, gid %d\n", configFile, o.uid, o.gid)
+	return nil
+}
+
+// Take an exclusive flock on the lock file in configPath, blocking until concurrent holders release it.
+// The returned function releases the lock
+func lockConfig(configPath string) (func(), error) {
+	file, err := os.OpenFile(filepath.Join(configPath, lockFileName), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to lock config directory: %w", err)
+	}
+	return func() {
+		_ = syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+		_ = file.Close()
+	}, nil
+}
+
+// Build the managed [Preferences] settings from the container environment
+func settingsFromEnv() []setting {
+	var settings []setting
+	if rootFolder := os.Getenv(EnvAlternativeWebUIRootFolder); rootFolder != "" {
+		settings = append(settings,
+			setting{key: "WebUI\\AlternativeUIEnabled", value: "true"},
+			setting{key: "WebUI\\RootFolder", value: rootFolder},
+		)
+	}
+	return settings
+}
+
+// Set the given keys in the [Preferences] section, replacing existing values and
+// appending missing keys at the end of the section. Other lines are kept as is.
+func upsertPreferences(content string, settings []setting) string {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	if content == "" {
+		lines = nil
+	}
+
+	written := make(map[string]bool, len(settings))
+	var out []string
+	inPreferences := false
+	sectionFound := false
+
+	appendMissing := func() {
+		for _, s := range settings {
+			if !written[s.key] {
+				out = append(out, s.key+"="+s.value)
+				written[s.key] = true
+			}
+		}
+	}
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			if inPreferences {
+				appendMissing()
+			}
+			inPreferences = trimmed == "[Preferences]"
+			sectionFound = sectionFound || inPreferences
+			out = append(out, line)
+			continue
+		}
+		if inPreferences {
+			if key, _, ok := strings.Cut(line, "="); ok {
+				if s, managed := findSetting(settings, strings.TrimSpace(key)); managed {
+					out = append(out, s.key+"="+s.value)
+					written[s.key] = true
+					continue
+				}
+			}
+		}
+		out = append(out, line)
+	}
+
+	if inPreferences {
+		appendMissing()
+	}
+	if !sectionFound {
+		out = append(out, "[Preferences]")
+		appendMissing()
+	}
+	return strings.Join(out, "\n") + "\n"
+}
+
+func findSetting(settings []setting, key string) (setting, bool) {
+	for _, s := range settings {
+		if s.key == key {
+			return s, true
+		}
+	}
+	return setting{}, false
+}
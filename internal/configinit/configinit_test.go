@@ -1,10 +1,15 @@
 package configinit
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 // overrideDefaultPaths temporarily overrides the package-level path constants
@@ -37,14 +42,27 @@ func TestRun_FirstBoot(t *testing.T) {
 	overrideDefaultPaths(t, credDir, configDir)
 	setupCredentials(t, credDir, "admin", "secretpass")
 
-	if err := Run(); err != nil {
+	seeded, err := Run()
+	if err != nil {
 		t.Fatalf("Run returned error: %v", err)
 	}
+	if !seeded {
+		t.Error("expected a first boot to report the config as seeded")
+	}
 
 	configFile := filepath.Join(configDir, "qBittorrent", "qBittorrent.conf")
 	if _, err := os.Stat(configFile); os.IsNotExist(err) {
 		t.Fatal("expected config file to be created")
 	}
+
+	// The next pod start finds the config it seeded
+	seeded, err = Run()
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if !seeded {
+		t.Error("expected a config seeded by a previous run to be reported as seeded")
+	}
 }
 
 func TestRun_ExistingConfig(t *testing.T) {
@@ -63,9 +81,13 @@ func TestRun_ExistingConfig(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err := Run(); err != nil {
+	seeded, err := Run()
+	if err != nil {
 		t.Fatalf("Run returned error: %v", err)
 	}
+	if seeded {
+		t.Error("expected a pre-existing config not to be reported as seeded")
+	}
 
 	// Verify the file was NOT overwritten
 	content, err := os.ReadFile(filepath.Join(qbtDir, "qBittorrent.conf"))
@@ -82,7 +104,7 @@ func TestRun_MissingCredentials(t *testing.T) {
 	configDir := t.TempDir()
 	overrideDefaultPaths(t, credDir, configDir)
 
-	err := Run()
+	_, err := Run()
 	if err == nil {
 		t.Fatal("expected error for missing credentials")
 	}
@@ -100,7 +122,7 @@ func TestRun_MissingPassword(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	err := Run()
+	_, err := Run()
 	if err == nil {
 		t.Fatal("expected error for missing password")
 	}
@@ -115,7 +137,7 @@ func TestRun_ConfigContent(t *testing.T) {
 	overrideDefaultPaths(t, credDir, configDir)
 	setupCredentials(t, credDir, "admin", "testpass123")
 
-	if err := Run(); err != nil {
+	if _, err := Run(); err != nil {
 		t.Fatalf("Run returned error: %v", err)
 	}
 
@@ -136,3 +158,314 @@ func TestRun_ConfigContent(t *testing.T) {
 		t.Error("config missing WebUI\\Password_PBKDF2 with @ByteArray format")
 	}
 }
+
+func TestRun_AlternativeWebUIFirstBoot(t *testing.T) {
+	credDir := t.TempDir()
+	configDir := t.TempDir()
+	overrideDefaultPaths(t, credDir, configDir)
+	setupCredentials(t, credDir, "admin", "testpass123")
+	t.Setenv(EnvAlternativeWebUIRootFolder, "/vuetorrent")
+
+	if _, err := Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(configDir, "qBittorrent", "qBittorrent.conf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	contentStr := string(content)
+	if !strings.Contains(contentStr, "WebUI\\AlternativeUIEnabled=true\n") {
+		t.Error("config missing WebUI\\AlternativeUIEnabled=true")
+	}
+	if !strings.Contains(contentStr, "WebUI\\RootFolder=/vuetorrent\n") {
+		t.Error("config missing WebUI\\RootFolder=/vuetorrent")
+	}
+}
+
+func TestRun_AlternativeWebUIExistingConfig(t *testing.T) {
+	credDir := t.TempDir()
+	configDir := t.TempDir()
+	overrideDefaultPaths(t, credDir, configDir)
+	t.Setenv(EnvAlternativeWebUIRootFolder, "/vuetorrent")
+
+	qbtDir := filepath.Join(configDir, "qBittorrent")
+	if err := os.MkdirAll(qbtDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	existingContent := "[BitTorrent]\nSession\\Port=6881\n\n[Preferences]\nWebUI\\RootFolder=/old\nWebUI\\Username=olduser\n"
+	if err := os.WriteFile(filepath.Join(qbtDir, "qBittorrent.conf"), []byte(existingContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(qbtDir, "qBittorrent.conf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "[BitTorrent]\nSession\\Port=6881\n\n[Preferences]\nWebUI\\RootFolder=/vuetorrent\nWebUI\\Username=olduser\nWebUI\\AlternativeUIEnabled=true\n"
+	if string(content) != want {
+		t.Errorf("unexpected config content:\ngot  %q\nwant %q", string(content), want)
+	}
+}
+
+func TestRun_ResetCredentialsExistingConfig(t *testing.T) {
+	credDir := t.TempDir()
+	configDir := t.TempDir()
+	overrideDefaultPaths(t, credDir, configDir)
+	setupCredentials(t, credDir, "admin", "rotatedpass")
+	t.Setenv(EnvResetCredentials, "2026-10-17")
+
+	qbtDir := filepath.Join(configDir, "qBittorrent")
+	if err := os.MkdirAll(qbtDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	existingContent := "[BitTorrent]\nSession\\Port=6881\n\n[Preferences]\nWebUI\\Password_PBKDF2=\"@ByteArray(old:hash)\"\nWebUI\\Username=olduser\n"
+	if err := os.WriteFile(filepath.Join(qbtDir, "qBittorrent.conf"), []byte(existingContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	seeded, err := Run()
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if !seeded {
+		t.Error("expected reset credentials to report the config as seeded")
+	}
+
+	content, err := os.ReadFile(filepath.Join(qbtDir, "qBittorrent.conf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(content)
+	if !strings.HasPrefix(got, "[BitTorrent]\nSession\\Port=6881\n") {
+		t.Errorf("expected the other sections to be kept, got %q", got)
+	}
+	if !strings.Contains(got, "WebUI\\Username=admin\n") || strings.Contains(got, "olduser") {
+		t.Errorf("expected the username to be reset, got %q", got)
+	}
+	if !strings.Contains(got, "WebUI\\Password_PBKDF2=\"@ByteArray(") || strings.Contains(got, "old:hash") {
+		t.Errorf("expected the password hash to be reset, got %q", got)
+	}
+}
+
+func TestRun_ResetCredentialsMissingSecret(t *testing.T) {
+	credDir := t.TempDir()
+	configDir := t.TempDir()
+	overrideDefaultPaths(t, credDir, configDir)
+	t.Setenv(EnvResetCredentials, "2026-10-17")
+
+	qbtDir := filepath.Join(configDir, "qBittorrent")
+	if err := os.MkdirAll(qbtDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(qbtDir, "qBittorrent.conf"), []byte("[Preferences]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := Run()
+	var failure *Failure
+	if !errors.As(err, &failure) || failure.Reason != ReasonCredentialsNotFound {
+		t.Fatalf("expected a %s failure, got %v", ReasonCredentialsNotFound, err)
+	}
+}
+
+func TestRun_ConcurrentRuns(t *testing.T) {
+	credDir := t.TempDir()
+	configDir := t.TempDir()
+	overrideDefaultPaths(t, credDir, configDir)
+	setupCredentials(t, credDir, "admin", "testpass123")
+	t.Setenv(EnvAlternativeWebUIRootFolder, "/vuetorrent")
+
+	const runs = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, runs)
+	for range runs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := Run()
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Run returned error: %v", err)
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(configDir, "qBittorrent", "qBittorrent.conf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	contentStr := string(content)
+	for _, line := range []string{"[Preferences]\n", "WebUI\\Username=admin\n", "WebUI\\RootFolder=/vuetorrent\n"} {
+		if n := strings.Count(contentStr, line); n != 1 {
+			t.Errorf("expected %q once in config, found %d times:\n%s", line, n, contentStr)
+		}
+	}
+}
+
+func TestRun_WaitsForConfigLock(t *testing.T) {
+	credDir := t.TempDir()
+	configDir := t.TempDir()
+	overrideDefaultPaths(t, credDir, configDir)
+	setupCredentials(t, credDir, "admin", "testpass123")
+
+	unlock, err := lockConfig(configDir)
+	if err != nil {
+		t.Fatalf("lockConfig returned error: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := Run()
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("Run completed while the config lock was held: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	unlock()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Run returned error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not complete after the config lock was released")
+	}
+	if _, err := os.Stat(filepath.Join(configDir, "qBittorrent", "qBittorrent.conf")); err != nil {
+		t.Fatalf("expected config file to be created: %v", err)
+	}
+}
+
+// recordChown replaces chown for the test, returning the recorded "path uid:gid" calls
+func recordChown(t *testing.T) *[]string {
+	t.Helper()
+	var calls []string
+	orig := chown
+	chown = func(name string, uid, gid int) error {
+		calls = append(calls, fmt.Sprintf("%s %d:%d", name, uid, gid))
+		return nil
+	}
+	t.Cleanup(func() { chown = orig })
+	return &calls
+}
+
+func TestRun_ChownsConfigToPUIDAndPGID(t *testing.T) {
+	credDir := t.TempDir()
+	configDir := t.TempDir()
+	overrideDefaultPaths(t, credDir, configDir)
+	setupCredentials(t, credDir, "admin", "testpass123")
+	t.Setenv(EnvPUID, "1000")
+	t.Setenv(EnvPGID, "1001")
+	calls := recordChown(t)
+
+	if _, err := Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	qbtDir := filepath.Join(configDir, "qBittorrent")
+	configFile := filepath.Join(qbtDir, "qBittorrent.conf")
+	want := []string{qbtDir + " 1000:1001", configFile + " 1000:1001"}
+	if !slices.Equal(*calls, want) {
+		t.Errorf("unexpected chown calls:\ngot  %v\nwant %v", *calls, want)
+	}
+	info, err := os.Stat(configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("expected config file permissions 0600, got %o", perm)
+	}
+}
+
+func TestRun_ChownsExistingConfig(t *testing.T) {
+	credDir := t.TempDir()
+	configDir := t.TempDir()
+	overrideDefaultPaths(t, credDir, configDir)
+	t.Setenv(EnvPUID, "1000")
+	t.Setenv(EnvPGID, "")
+	calls := recordChown(t)
+
+	qbtDir := filepath.Join(configDir, "qBittorrent")
+	if err := os.MkdirAll(qbtDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	configFile := filepath.Join(qbtDir, "qBittorrent.conf")
+	if err := os.WriteFile(configFile, []byte("[Preferences]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	// An unset PGID leaves the group unchanged
+	want := []string{qbtDir + " 1000:-1", configFile + " 1000:-1"}
+	if !slices.Equal(*calls, want) {
+		t.Errorf("unexpected chown calls:\ngot  %v\nwant %v", *calls, want)
+	}
+}
+
+func TestRun_KeepsOwnershipWithoutPUIDAndPGID(t *testing.T) {
+	credDir := t.TempDir()
+	configDir := t.TempDir()
+	overrideDefaultPaths(t, credDir, configDir)
+	setupCredentials(t, credDir, "admin", "testpass123")
+	t.Setenv(EnvPUID, "")
+	t.Setenv(EnvPGID, "")
+	calls := recordChown(t)
+
+	if _, err := Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	if len(*calls) != 0 {
+		t.Errorf("expected no chown calls, got %v", *calls)
+	}
+	info, err := os.Stat(filepath.Join(configDir, "qBittorrent", "qBittorrent.conf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0644 {
+		t.Errorf("expected config file permissions 0644, got %o", perm)
+	}
+}
+
+func TestRun_InvalidPUID(t *testing.T) {
+	credDir := t.TempDir()
+	configDir := t.TempDir()
+	overrideDefaultPaths(t, credDir, configDir)
+	setupCredentials(t, credDir, "admin", "testpass123")
+	t.Setenv(EnvPUID, "abc")
+	calls := recordChown(t)
+
+	_, err := Run()
+	if err == nil || !strings.Contains(err.Error(), "invalid PUID") {
+		t.Fatalf("expected an invalid PUID error, got %v", err)
+	}
+	if len(*calls) != 0 {
+		t.Errorf("expected no chown calls, got %v", *calls)
+	}
+	if _, err := os.Stat(filepath.Join(configDir, "qBittorrent", "qBittorrent.conf")); !os.IsNotExist(err) {
+		t.Error("expected no config file to be written")
+	}
+}
+
+func TestUpsertPreferences_MissingSection(t *testing.T) {
+	got := upsertPreferences("[BitTorrent]\nSession\\Port=6881\n", []setting{{key: "WebUI\\RootFolder", value: "/ui"}})
+	want := "[BitTorrent]\nSession\\Port=6881\n[Preferences]\nWebUI\\RootFolder=/ui\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
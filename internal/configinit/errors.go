@@ -0,0 +1,123 @@
+package configinit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Reason identifies why config-init failed. Each reason exits with its own code,
+// so the failure mode is visible from the pod status alone.
+type Reason string
+
+const (
+	// ReasonLockFailed means the lock file in the config directory could not be taken,
+	// e.g. the config volume is not mounted or is read-only
+	ReasonLockFailed Reason = "LockFailed"
+	// ReasonInvalidOwner means PUID or PGID is not a non-negative integer
+	ReasonInvalidOwner Reason = "InvalidOwner"
+	// ReasonConfigUnreadable means the existing config file could not be read
+	ReasonConfigUnreadable Reason = "ConfigUnreadable"
+	// ReasonCredentialsNotFound means the credentials Secret is not mounted or lacks a key
+	ReasonCredentialsNotFound Reason = "CredentialsNotFound"
+	// ReasonPasswordHashFailed means the password could not be hashed
+	ReasonPasswordHashFailed Reason = "PasswordHashFailed"
+	// ReasonConfigUnwritable means the config directory or file could not be written
+	ReasonConfigUnwritable Reason = "ConfigUnwritable"
+	// ReasonOwnershipFailed means the config could not be handed over to PUID/PGID
+	ReasonOwnershipFailed Reason = "OwnershipFailed"
+)
+
+// exitCodes maps each failure reason to the exit code of the config-init container
+var exitCodes = map[Reason]int{
+	ReasonLockFailed:          2,
+	ReasonInvalidOwner:        3,
+	ReasonConfigUnreadable:    4,
+	ReasonCredentialsNotFound: 5,
+	ReasonPasswordHashFailed:  6,
+	ReasonConfigUnwritable:    7,
+	ReasonOwnershipFailed:     8,
+}
+
+// Credentials states reported by a Failure
+const (
+	credentialsNotRead = "not-read"
+	credentialsMissing = "missing"
+	credentialsFound   = "found"
+	credentialsUnused  = "unused"
+)
+
+// terminationLogPath is where Kubernetes reads the termination message shown in the pod status
+var terminationLogPath = "/dev/termination-log"
+
+// Failure describes a failed config-init run: what failed, on which path, and for which config file
+type Failure struct {
+	Reason Reason
+	// Path is the file or directory config-init was working on, empty when not applicable
+	Path string
+	// ConfigFile is the qBittorrent config file config-init targeted
+	ConfigFile string
+	// Credentials reports whether the mounted credentials were found: not-read, missing, found or unused
+	Credentials string
+	Err         error
+}
+
+func (f *Failure) Error() string {
+	return fmt.Sprintf("%v (reason=%s path=%s configFile=%s credentials=%s)",
+		f.Err, f.Reason, f.Path, f.ConfigFile, f.Credentials)
+}
+
+func (f *Failure) Unwrap() error {
+	return f.Err
+}
+
+// ExitCode returns the exit code for the error returned by Run: 0 on success,
+// the code of the failure reason, or 1 for unexpected errors
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var failure *Failure
+	if errors.As(err, &failure) {
+		if code, ok := exitCodes[failure.Reason]; ok {
+			return code
+		}
+	}
+	return 1
+}
+
+// successMessagePrefix starts the termination message of a successful run, followed by whether the config was seeded
+const successMessagePrefix = "config-init succeeded: seeded="
+
+// ReportSuccess writes whether the config was seeded to w and to the container termination log,
+// so that the TorrentServer controller can read it from the pod status
+func ReportSuccess(w io.Writer, seeded bool) {
+	message := successMessagePrefix + strconv.FormatBool(seeded) + "\n"
+	_, _ = io.WriteString(w, message)
+	// Best effort: the termination log only exists inside a Kubernetes container
+	_ = os.WriteFile(terminationLogPath, []byte(message), 0644)
+}
+
+// SeededFromTerminationMessage parses the termination message written by ReportSuccess,
+// returning false for messages of failed runs or of config-init versions not reporting it
+func SeededFromTerminationMessage(message string) (seeded, ok bool) {
+	value, found := strings.CutPrefix(strings.TrimSpace(message), successMessagePrefix)
+	if !found {
+		return false, false
+	}
+	seeded, err := strconv.ParseBool(value)
+	return seeded, err == nil
+}
+
+// ReportFailure writes the error to w and to the container termination log,
+// so that it shows in the pod status without exec, and returns the exit code
+func ReportFailure(w io.Writer, err error) int {
+	message := fmt.Sprintf("config-init failed: %v\n", err)
+	_, _ = io.WriteString(w, message)
+	// Best effort: the termination log only exists inside a Kubernetes container
+	_ = os.WriteFile(terminationLogPath, []byte(message), 0644)
+	return ExitCode(err)
+}
@@ -0,0 +1,183 @@
+package configinit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runFailure runs config-init expecting a *Failure
+func runFailure(t *testing.T) *Failure {
+	t.Helper()
+	_, err := Run()
+	var failure *Failure
+	if !errors.As(err, &failure) {
+		t.Fatalf("expected a *Failure, got %v", err)
+	}
+	return failure
+}
+
+func TestRun_FailureMissingUsername(t *testing.T) {
+	credDir := t.TempDir()
+	configDir := t.TempDir()
+	overrideDefaultPaths(t, credDir, configDir)
+
+	failure := runFailure(t)
+	if failure.Reason != ReasonCredentialsNotFound {
+		t.Errorf("got reason %s, want %s", failure.Reason, ReasonCredentialsNotFound)
+	}
+	if want := filepath.Join(credDir, "username"); failure.Path != want {
+		t.Errorf("got path %q, want %q", failure.Path, want)
+	}
+	if want := filepath.Join(configDir, "qBittorrent", "qBittorrent.conf"); failure.ConfigFile != want {
+		t.Errorf("got config file %q, want %q", failure.ConfigFile, want)
+	}
+	if failure.Credentials != credentialsMissing {
+		t.Errorf("got credentials %q, want %q", failure.Credentials, credentialsMissing)
+	}
+	if !errors.Is(failure, os.ErrNotExist) {
+		t.Errorf("expected the failure to wrap os.ErrNotExist, got %v", failure.Err)
+	}
+	if ExitCode(failure) != 5 {
+		t.Errorf("got exit code %d, want 5", ExitCode(failure))
+	}
+	for _, field := range []string{"reason=CredentialsNotFound", "path=" + failure.Path, "configFile=" + failure.ConfigFile, "credentials=missing"} {
+		if !strings.Contains(failure.Error(), field) {
+			t.Errorf("expected %q in %q", field, failure.Error())
+		}
+	}
+}
+
+func TestRun_FailureInvalidOwner(t *testing.T) {
+	credDir := t.TempDir()
+	configDir := t.TempDir()
+	overrideDefaultPaths(t, credDir, configDir)
+	setupCredentials(t, credDir, "admin", "testpass123")
+	t.Setenv(EnvPGID, "-1")
+
+	failure := runFailure(t)
+	if failure.Reason != ReasonInvalidOwner || ExitCode(failure) != 3 {
+		t.Errorf("got reason %s and exit code %d, want %s and 3", failure.Reason, ExitCode(failure), ReasonInvalidOwner)
+	}
+	if failure.Credentials != credentialsNotRead {
+		t.Errorf("got credentials %q, want %q", failure.Credentials, credentialsNotRead)
+	}
+}
+
+func TestRun_FailureLock(t *testing.T) {
+	credDir := t.TempDir()
+	// The config path is a regular file, as when the config volume is not mounted as a directory
+	configDir := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(configDir, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	overrideDefaultPaths(t, credDir, configDir)
+	setupCredentials(t, credDir, "admin", "testpass123")
+
+	failure := runFailure(t)
+	if failure.Reason != ReasonLockFailed || ExitCode(failure) != 2 {
+		t.Errorf("got reason %s and exit code %d, want %s and 2", failure.Reason, ExitCode(failure), ReasonLockFailed)
+	}
+	if want := filepath.Join(configDir, lockFileName); failure.Path != want {
+		t.Errorf("got path %q, want %q", failure.Path, want)
+	}
+}
+
+func TestRun_FailureOwnership(t *testing.T) {
+	credDir := t.TempDir()
+	configDir := t.TempDir()
+	overrideDefaultPaths(t, credDir, configDir)
+	setupCredentials(t, credDir, "admin", "testpass123")
+	t.Setenv(EnvPUID, "1000")
+	orig := chown
+	chown = func(string, int, int) error { return os.ErrPermission }
+	t.Cleanup(func() { chown = orig })
+
+	failure := runFailure(t)
+	if failure.Reason != ReasonOwnershipFailed || ExitCode(failure) != 8 {
+		t.Errorf("got reason %s and exit code %d, want %s and 8", failure.Reason, ExitCode(failure), ReasonOwnershipFailed)
+	}
+	if failure.Credentials != credentialsFound {
+		t.Errorf("got credentials %q, want %q", failure.Credentials, credentialsFound)
+	}
+}
+
+func TestExitCode_DistinctPerReason(t *testing.T) {
+	seen := map[int]Reason{}
+	for reason, code := range exitCodes {
+		if code <= 1 {
+			t.Errorf("reason %s uses reserved exit code %d", reason, code)
+		}
+		if other, ok := seen[code]; ok {
+			t.Errorf("reasons %s and %s share exit code %d", reason, other, code)
+		}
+		seen[code] = reason
+	}
+	if ExitCode(nil) != 0 {
+		t.Errorf("got exit code %d for nil, want 0", ExitCode(nil))
+	}
+	if ExitCode(errors.New("boom")) != 1 {
+		t.Errorf("got exit code %d for an unexpected error, want 1", ExitCode(errors.New("boom")))
+	}
+}
+
+func TestReportFailure_WritesTerminationLog(t *testing.T) {
+	orig := terminationLogPath
+	terminationLogPath = filepath.Join(t.TempDir(), "termination-log")
+	t.Cleanup(func() { terminationLogPath = orig })
+
+	failure := &Failure{
+		Reason:      ReasonConfigUnwritable,
+		Path:        "/config/qBittorrent/qBittorrent.conf",
+		ConfigFile:  "/config/qBittorrent/qBittorrent.conf",
+		Credentials: credentialsFound,
+		Err:         fmt.Errorf("failed to write config file: %w", os.ErrPermission),
+	}
+	var out bytes.Buffer
+	if code := ReportFailure(&out, failure); code != 7 {
+		t.Errorf("got exit code %d, want 7", code)
+	}
+
+	if !strings.HasPrefix(out.String(), "config-init failed: failed to write config file") {
+		t.Errorf("unexpected output %q", out.String())
+	}
+	logged, err := os.ReadFile(terminationLogPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(logged) != out.String() {
+		t.Errorf("termination log %q does not match output %q", logged, out.String())
+	}
+}
+
+func TestReportSuccess_WritesTerminationLog(t *testing.T) {
+	orig := terminationLogPath
+	terminationLogPath = filepath.Join(t.TempDir(), "termination-log")
+	t.Cleanup(func() { terminationLogPath = orig })
+
+	var out bytes.Buffer
+	ReportSuccess(&out, true)
+	logged, err := os.ReadFile(terminationLogPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(logged) != out.String() {
+		t.Errorf("termination log %q does not match output %q", logged, out.String())
+	}
+	if seeded, ok := SeededFromTerminationMessage(string(logged)); !ok || !seeded {
+		t.Errorf("expected the message to parse as seeded, got seeded=%v ok=%v", seeded, ok)
+	}
+
+	out.Reset()
+	ReportSuccess(&out, false)
+	if seeded, ok := SeededFromTerminationMessage(out.String()); !ok || seeded {
+		t.Errorf("expected the message to parse as not seeded, got seeded=%v ok=%v", seeded, ok)
+	}
+	if _, ok := SeededFromTerminationMessage("config-init failed: boom\n"); ok {
+		t.Error("expected a failure message not to report the outcome")
+	}
+}
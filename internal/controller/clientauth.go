@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	torrentv1alpha1 "github.com/guidonguido/qbittorrent-operator/api/v1alpha1"
+	"github.com/guidonguido/qbittorrent-operator/internal/qbittorrent"
+)
+
+// defaultTokenKey is the CredentialsSecret key holding the bearer token when spec.auth.tokenKey is unset
+const defaultTokenKey = "token"
+
+var (
+	// errCredentialsNotFound reports a missing credentials Secret or token file
+	errCredentialsNotFound = errors.New("credentials not found")
+	// errCredentialsInvalid reports credentials lacking a required key or value
+	errCredentialsInvalid = errors.New("credentials invalid")
+)
+
+// clientForTCC returns the pooled qBittorrent client authenticated as the TCC declares.
+// The TCC health check and its Torrents both resolve their client here, so they share the pool entry
+func clientForTCC(ctx context.Context, c client.Reader, pool *qbittorrent.ClientPool,
+	tcc *torrentv1alpha1.TorrentClientConfiguration, tokenDir string) (qbittorrent.QBTClient, error) {
+	if tcc.Spec.Auth != nil && tcc.Spec.Auth.Type == torrentv1alpha1.ClientAuthBearer {
+		token, err := bearerTokenForTCC(ctx, c, tcc, tokenDir)
+		if err != nil {
+			return nil, err
+		}
+		return pool.GetOrCreateWithToken(ctx, tcc.Spec.URL, token)
+	}
+
+	secret, err := credentialsSecretForTCC(ctx, c, tcc)
+	if err != nil {
+		return nil, err
+	}
+	username, hasUsername := secret.Data["username"]
+	password, hasPassword := secret.Data["password"]
+	if !hasUsername || !hasPassword {
+		return nil, fmt.Errorf("%w: credentials secret %q missing 'username' or 'password' key",
+			errCredentialsInvalid, secret.Name)
+	}
+	return pool.GetOrCreate(ctx, tcc.Spec.URL, string(username), string(password))
+}
+
+func credentialsSecretForTCC(ctx context.Context, c client.Reader, tcc *torrentv1alpha1.TorrentClientConfiguration) (*corev1.Secret, error) {
+	if tcc.Spec.CredentialsSecret == nil || tcc.Spec.CredentialsSecret.Name == "" {
+		return nil, fmt.Errorf("%w: spec.credentialsSecret is not set", errCredentialsNotFound)
+	}
+	name := tcc.Spec.CredentialsSecret.Name
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: tcc.Namespace}, secret); err != nil {
+		return nil, fmt.Errorf("%w: credentials secret %q: %v", errCredentialsNotFound, name, err)
+	}
+	return secret, nil
+}
+
+// Read the bearer token from spec.auth.tokenFile, or from the CredentialsSecret key
+func bearerTokenForTCC(ctx context.Context, c client.Reader, tcc *torrentv1alpha1.TorrentClientConfiguration, tokenDir string) (string, error) {
+	auth := tcc.Spec.Auth
+	if auth.TokenFile != "" {
+		return readTokenFile(tokenDir, auth.TokenFile)
+	}
+
+	secret, err := credentialsSecretForTCC(ctx, c, tcc)
+	if err != nil {
+		return "", err
+	}
+	key := auth.TokenKey
+	if key == "" {
+		key = defaultTokenKey
+	}
+	token := strings.TrimSpace(string(secret.Data[key]))
+	if token == "" {
+		return "", fmt.Errorf("%w: credentials secret %q missing %q key", errCredentialsInvalid, secret.Name, key)
+	}
+	return token, nil
+}
+
+// Token files are confined to the directory the operator allows, so that a TCC cannot make
+// the operator send any file it can read, e.g. its own service account token, to a qBittorrent URL
+func readTokenFile(tokenDir, name string) (string, error) {
+	if tokenDir == "" {
+		return "", fmt.Errorf("%w: spec.auth.tokenFile requires the operator to run with --bearer-token-dir", errCredentialsInvalid)
+	}
+	if !filepath.IsLocal(name) {
+		return "", fmt.Errorf("%w: spec.auth.tokenFile %q must be a relative path inside --bearer-token-dir", errCredentialsInvalid, name)
+	}
+	data, err := os.ReadFile(filepath.Join(tokenDir, name))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", errCredentialsNotFound, err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("%w: token file %q is empty", errCredentialsInvalid, name)
+	}
+	return token, nil
+}
@@ -0,0 +1,629 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	torrentv1alpha1 "github.com/guidonguido/qbittorrent-operator/api/v1alpha1"
+	"github.com/guidonguido/qbittorrent-operator/internal/qbittorrent"
+)
+
+// fakeQBTClient is an in-memory qbittorrent.QBTClient used to test controllers
+// without a running qBittorrent instance. Calls are recorded in order.
+type fakeQBTClient struct {
+	mu       sync.Mutex
+	torrents map[string]*qbittorrent.TorrentInfo
+	files    map[string][]qbittorrent.TorrentFile
+	props    map[string]qbittorrent.TorrentProperties
+	calls    []string
+
+	preferences map[string]any
+	categories  map[string]qbittorrent.Category
+	addOptions  map[string]qbittorrent.AddTorrentOptions
+	log         []qbittorrent.LogEntry
+
+	appVersion string
+	apiVersion string
+	freeSpace  int64
+
+	loginErr error
+	pingErr  error
+	addErr   error
+	// Errors returned by the corresponding calls, to simulate failing reconcile steps
+	renameErr         error
+	exportErr         error
+	setPreferencesErr error
+	categoryErr       error
+	pauseAllErr       error
+	setCategoryErr    error
+	preferencesErr    error
+}
+
+var _ qbittorrent.QBTClient = &fakeQBTClient{}
+
+func newFakeQBTClient() *fakeQBTClient {
+	return &fakeQBTClient{
+		torrents: make(map[string]*qbittorrent.TorrentInfo),
+		files:    make(map[string][]qbittorrent.TorrentFile),
+		props:    make(map[string]qbittorrent.TorrentProperties),
+
+		// qBittorrent always reports the alternative WebUI, disabled by default
+		preferences: map[string]any{"alternative_webui_enabled": false},
+		categories:  make(map[string]qbittorrent.Category),
+		addOptions:  make(map[string]qbittorrent.AddTorrentOptions),
+
+		// Plenty of space, so that LowDiskSpace is only set by the tests covering it
+		freeSpace: 100 << 30,
+	}
+}
+
+func (f *fakeQBTClient) record(format string, args ...any) {
+	f.calls = append(f.calls, fmt.Sprintf(format, args...))
+}
+
+// Calls returns a copy of the recorded calls
+func (f *fakeQBTClient) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+// SetTorrent stores or replaces a torrent known by the fake qBittorrent
+func (f *fakeQBTClient) SetTorrent(info qbittorrent.TorrentInfo) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.torrents[info.Hash] = &info
+}
+
+// SetFiles stores the file list of a torrent
+func (f *fakeQBTClient) SetFiles(hash string, files []qbittorrent.TorrentFile) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.files[hash] = files
+}
+
+// SetProperties stores the properties of a torrent
+func (f *fakeQBTClient) SetProperties(hash string, props qbittorrent.TorrentProperties) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.props[hash] = props
+}
+
+// SetLog replaces the entries of the qBittorrent main log
+func (f *fakeQBTClient) SetLog(entries ...qbittorrent.LogEntry) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.log = entries
+}
+
+// AddOptions returns the options the torrent with the given hash was added with
+func (f *fakeQBTClient) AddOptions(hash string) qbittorrent.AddTorrentOptions {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.addOptions[hash]
+}
+
+// QueuePosition returns the queue position of the torrent with the given hash
+func (f *fakeQBTClient) QueuePosition(hash string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.torrents[hash].Priority
+}
+
+func (f *fakeQBTClient) Login(_ context.Context, username, _ string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("Login:%s", username)
+	return f.loginErr
+}
+
+func (f *fakeQBTClient) SetBearerToken(token string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("SetBearerToken:%s", token)
+}
+
+func (f *fakeQBTClient) Ping(_ context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("Ping")
+	return f.pingErr
+}
+
+// SetPreference changes a preference as if it were edited out-of-band in the WebUI
+func (f *fakeQBTClient) SetPreference(key string, value any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.preferences[key] = value
+}
+
+// Preference returns the current value of a preference
+func (f *fakeQBTClient) Preference(key string) any {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.preferences[key]
+}
+
+func (f *fakeQBTClient) GetMainData(_ context.Context) (*qbittorrent.MainData, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("GetMainData")
+	return &qbittorrent.MainData{ServerState: qbittorrent.ServerState{FreeSpaceOnDisk: f.freeSpace}}, nil
+}
+
+func (f *fakeQBTClient) GetPreferences(_ context.Context) (map[string]any, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("GetPreferences")
+	if f.preferencesErr != nil {
+		return nil, f.preferencesErr
+	}
+	preferences := make(map[string]any, len(f.preferences))
+	for key, value := range f.preferences {
+		preferences[key] = value
+	}
+	return preferences, nil
+}
+
+func (f *fakeQBTClient) SetPreferences(_ context.Context, preferences map[string]any) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	keys := make([]string, 0, len(preferences))
+	for key := range preferences {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	f.record("SetPreferences:%s", strings.Join(keys, "|"))
+	if f.setPreferencesErr != nil {
+		return f.setPreferencesErr
+	}
+	for key, value := range preferences {
+		f.preferences[key] = value
+	}
+	return nil
+}
+
+func (f *fakeQBTClient) GetAppVersion(_ context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("GetAppVersion")
+	return f.appVersion, nil
+}
+
+func (f *fakeQBTClient) GetAPIVersion(_ context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("GetAPIVersion")
+	return f.apiVersion, nil
+}
+
+func (f *fakeQBTClient) Capabilities() qbittorrent.Capabilities {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return qbittorrent.CapabilitiesFor(f.apiVersion)
+}
+
+func (f *fakeQBTClient) GetTorrentsInfo(_ context.Context) ([]qbittorrent.TorrentInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var infos []qbittorrent.TorrentInfo
+	for _, info := range f.torrents {
+		infos = append(infos, *info)
+	}
+	return infos, nil
+}
+
+func (f *fakeQBTClient) GetTorrentInfo(_ context.Context, hash string) (*qbittorrent.TorrentInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	info, ok := f.torrents[hash]
+	if !ok {
+		return nil, nil
+	}
+	copied := *info
+	return &copied, nil
+}
+
+func (f *fakeQBTClient) AddTorrent(_ context.Context, magnetURI string, opts qbittorrent.AddTorrentOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("AddTorrent:%s", magnetURI)
+	if f.addErr != nil {
+		return f.addErr
+	}
+	hash, err := qbittorrent.GetTorrentHash(magnetURI)
+	if err != nil {
+		return err
+	}
+	f.addOptions[hash] = opts
+	f.torrents[hash] = &qbittorrent.TorrentInfo{Hash: hash, Name: hash, MagnetURI: magnetURI, Category: opts.Category}
+	return nil
+}
+
+func (f *fakeQBTClient) DeleteTorrent(_ context.Context, hash string, deleteFiles bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("DeleteTorrent:%s:%t", hash, deleteFiles)
+	delete(f.torrents, hash)
+	return nil
+}
+
+func (f *fakeQBTClient) RenameTorrent(_ context.Context, hash, name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("RenameTorrent:%s:%s", hash, name)
+	if f.renameErr != nil {
+		return f.renameErr
+	}
+	if info, ok := f.torrents[hash]; ok {
+		info.Name = name
+	}
+	return nil
+}
+
+func (f *fakeQBTClient) SetTorrentCategory(_ context.Context, hashes []string, category string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("SetTorrentCategory:%s:%s", strings.Join(hashes, "|"), category)
+	if f.setCategoryErr != nil {
+		return f.setCategoryErr
+	}
+	for _, hash := range hashes {
+		if info, ok := f.torrents[hash]; ok {
+			info.Category = category
+		}
+	}
+	return nil
+}
+
+func (f *fakeQBTClient) SetTorrentLocation(_ context.Context, hashes []string, location string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("SetTorrentLocation:%s:%s", strings.Join(hashes, "|"), location)
+	for _, hash := range hashes {
+		if info, ok := f.torrents[hash]; ok {
+			info.SavePath = location
+		}
+	}
+	return nil
+}
+
+func (f *fakeQBTClient) GetTorrentFiles(_ context.Context, hash string) ([]qbittorrent.TorrentFile, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]qbittorrent.TorrentFile(nil), f.files[hash]...), nil
+}
+
+func (f *fakeQBTClient) ExportTorrent(_ context.Context, hash string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("ExportTorrent:%s", hash)
+	if f.exportErr != nil {
+		return nil, f.exportErr
+	}
+	return []byte("d4:infod4:name" + strconv.Itoa(len(hash)) + ":" + hash + "ee"), nil
+}
+
+func (f *fakeQBTClient) GetTorrentProperties(_ context.Context, hash string) (*qbittorrent.TorrentProperties, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	props := f.props[hash]
+	return &props, nil
+}
+
+func (f *fakeQBTClient) GetLog(_ context.Context, opts qbittorrent.LogOptions) ([]qbittorrent.LogEntry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("GetLog")
+	enabled := map[int]bool{
+		qbittorrent.LogTypeNormal:   opts.Normal,
+		qbittorrent.LogTypeInfo:     opts.Info,
+		qbittorrent.LogTypeWarning:  opts.Warning,
+		qbittorrent.LogTypeCritical: opts.Critical,
+	}
+	var entries []qbittorrent.LogEntry
+	for _, entry := range f.log {
+		if enabled[entry.Type] && entry.ID > opts.LastKnownID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (f *fakeQBTClient) TopPriority(_ context.Context, hashes []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("TopPriority:%s", strings.Join(hashes, "|"))
+	for _, hash := range hashes {
+		f.moveInQueue(hash, 1)
+	}
+	return nil
+}
+
+func (f *fakeQBTClient) BottomPriority(_ context.Context, hashes []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("BottomPriority:%s", strings.Join(hashes, "|"))
+	for _, hash := range hashes {
+		f.moveInQueue(hash, f.queueLength())
+	}
+	return nil
+}
+
+func (f *fakeQBTClient) IncreasePriority(_ context.Context, hashes []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("IncreasePriority:%s", strings.Join(hashes, "|"))
+	for _, hash := range hashes {
+		if torrent, ok := f.torrents[hash]; ok {
+			f.moveInQueue(hash, max(torrent.Priority-1, 1))
+		}
+	}
+	return nil
+}
+
+func (f *fakeQBTClient) DecreasePriority(_ context.Context, hashes []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("DecreasePriority:%s", strings.Join(hashes, "|"))
+	for _, hash := range hashes {
+		if torrent, ok := f.torrents[hash]; ok {
+			f.moveInQueue(hash, min(torrent.Priority+1, f.queueLength()))
+		}
+	}
+	return nil
+}
+
+// Number of queued torrents, i.e. with a positive priority
+func (f *fakeQBTClient) queueLength() int {
+	n := 0
+	for _, torrent := range f.torrents {
+		if torrent.Priority > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// Move a queued torrent to the given position, shifting the torrents in between like qBittorrent does
+func (f *fakeQBTClient) moveInQueue(hash string, position int) {
+	moved, ok := f.torrents[hash]
+	if !ok || moved.Priority <= 0 {
+		return
+	}
+	for _, torrent := range f.torrents {
+		switch {
+		case torrent == moved || torrent.Priority <= 0:
+		case position < moved.Priority && torrent.Priority >= position && torrent.Priority < moved.Priority:
+			torrent.Priority++
+		case position > moved.Priority && torrent.Priority <= position && torrent.Priority > moved.Priority:
+			torrent.Priority--
+		}
+	}
+	moved.Priority = position
+}
+
+func (f *fakeQBTClient) PauseTorrents(_ context.Context, hashes []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("PauseTorrents:%s", strings.Join(hashes, "|"))
+	return nil
+}
+
+func (f *fakeQBTClient) ResumeTorrents(_ context.Context, hashes []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("ResumeTorrents:%s", strings.Join(hashes, "|"))
+	return nil
+}
+
+// PauseAll stops every torrent, so that tests can tell which ones were running beforehand
+func (f *fakeQBTClient) PauseAll(_ context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("PauseAll")
+	if f.pauseAllErr != nil {
+		return f.pauseAllErr
+	}
+	for _, torrent := range f.torrents {
+		torrent.State = "stoppedDL"
+	}
+	return nil
+}
+
+// SetCategory creates or changes a category as if it were edited out-of-band in the WebUI
+func (f *fakeQBTClient) SetCategory(name, savePath string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.categories[name] = qbittorrent.Category{Name: name, SavePath: savePath}
+}
+
+// CategoryPath returns the save path of a category and whether the category exists
+func (f *fakeQBTClient) CategoryPath(name string) (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	category, ok := f.categories[name]
+	return category.SavePath, ok
+}
+
+func (f *fakeQBTClient) GetCategories(_ context.Context) (map[string]qbittorrent.Category, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("GetCategories")
+	categories := make(map[string]qbittorrent.Category, len(f.categories))
+	for name, category := range f.categories {
+		categories[name] = category
+	}
+	return categories, nil
+}
+
+func (f *fakeQBTClient) CreateCategory(_ context.Context, name, savePath string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("CreateCategory:%s:%s", name, savePath)
+	if f.categoryErr != nil {
+		return f.categoryErr
+	}
+	if _, ok := f.categories[name]; ok {
+		return fmt.Errorf("category %q already exists", name)
+	}
+	f.categories[name] = qbittorrent.Category{Name: name, SavePath: savePath}
+	return nil
+}
+
+func (f *fakeQBTClient) EditCategory(_ context.Context, name, savePath string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("EditCategory:%s:%s", name, savePath)
+	if f.categoryErr != nil {
+		return f.categoryErr
+	}
+	if _, ok := f.categories[name]; !ok {
+		return fmt.Errorf("category %q does not exist", name)
+	}
+	f.categories[name] = qbittorrent.Category{Name: name, SavePath: savePath}
+	return nil
+}
+
+func (f *fakeQBTClient) PauseCategory(_ context.Context, category string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("PauseCategory:%s", category)
+	return nil
+}
+
+func (f *fakeQBTClient) ResumeCategory(_ context.Context, category string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("ResumeCategory:%s", category)
+	return nil
+}
+
+func (f *fakeQBTClient) RenameFile(_ context.Context, hash, oldPath, newPath string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !qbittorrent.CapabilitiesFor(f.apiVersion).RenameFile {
+		return fmt.Errorf("failed to rename torrent file: %w", qbittorrent.ErrUnsupportedFeature)
+	}
+	f.record("RenameFile:%s:%s:%s", hash, oldPath, newPath)
+	for i := range f.files[hash] {
+		if f.files[hash][i].Name == oldPath {
+			f.files[hash][i].Name = newPath
+		}
+	}
+	return nil
+}
+
+func (f *fakeQBTClient) SetFilePriority(_ context.Context, hash string, indexes []int, priority int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ids := make([]string, len(indexes))
+	for i, index := range indexes {
+		ids[i] = strconv.Itoa(index)
+		f.files[hash][index].Priority = priority
+	}
+	f.record("SetFilePriority:%s:%s:%d", hash, strings.Join(ids, "|"), priority)
+	return nil
+}
+
+// newFakeClientPool returns a ClientPool whose clients are all backed by fake
+func newFakeClientPool(fake *fakeQBTClient) *qbittorrent.ClientPool {
+	pool := qbittorrent.NewClientPool(5 * time.Minute)
+	pool.SetClientFactory(func(string) qbittorrent.QBTClient {
+		return fake
+	})
+	return pool
+}
+
+// createAvailableTCC creates a credentials Secret and a TCC marked Available,
+// so Torrent reconciles can resolve a client through the ClientPool
+func createAvailableTCC(ctx context.Context, tccName, secretName string) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"username": []byte("admin"),
+			"password": []byte("password"),
+		},
+	}
+	Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+
+	tcc := &torrentv1alpha1.TorrentClientConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      tccName,
+			Namespace: "default",
+		},
+		Spec: torrentv1alpha1.TorrentClientConfigurationSpec{
+			URL: "http://" + tccName + ":8080",
+			CredentialsSecret: &torrentv1alpha1.SecretReference{
+				Name: secretName,
+			},
+		},
+	}
+	Expect(k8sClient.Create(ctx, tcc)).To(Succeed())
+
+	meta.SetStatusCondition(&tcc.Status.Conditions, metav1.Condition{
+		Type:   TypeAvailableTCC,
+		Status: metav1.ConditionTrue,
+		Reason: "Connected",
+	})
+	Expect(k8sClient.Status().Update(ctx, tcc)).To(Succeed())
+}
+
+// deleteTCC removes the TCC and credentials Secret created by createAvailableTCC
+func deleteTCC(ctx context.Context, tccName, secretName string) {
+	tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+	if err := k8sClient.Get(ctx, types.NamespacedName{Name: tccName, Namespace: "default"}, tcc); err == nil {
+		Expect(k8sClient.Delete(ctx, tcc)).To(Succeed())
+	}
+	secret := &corev1.Secret{}
+	if err := k8sClient.Get(ctx, types.NamespacedName{Name: secretName, Namespace: "default"}, secret); err == nil {
+		Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+	}
+}
+
+// manageTCCByServer creates a TorrentServer with spec and makes it the controller of the TCC,
+// as for the TCC a TorrentServer creates. It returns a function deleting the TorrentServer.
+func manageTCCByServer(ctx context.Context, tccName string, spec torrentv1alpha1.TorrentServerSpec) func() {
+	ts := &torrentv1alpha1.TorrentServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      tccName + "-server",
+			Namespace: "default",
+		},
+		Spec: spec,
+	}
+	Expect(k8sClient.Create(ctx, ts)).To(Succeed())
+
+	tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+	Expect(k8sClient.Get(ctx, types.NamespacedName{Name: tccName, Namespace: "default"}, tcc)).To(Succeed())
+	Expect(controllerutil.SetControllerReference(ts, tcc, k8sClient.Scheme())).To(Succeed())
+	Expect(k8sClient.Update(ctx, tcc)).To(Succeed())
+
+	return func() {
+		Expect(k8sClient.Delete(ctx, ts)).To(Succeed())
+	}
+}
+
+// deleteTorrent removes a Torrent, dropping its finalizer first
+func deleteTorrent(ctx context.Context, name types.NamespacedName) {
+	torrent := &torrentv1alpha1.Torrent{}
+	if err := k8sClient.Get(ctx, name, torrent); err == nil {
+		torrent.Finalizers = nil
+		Expect(k8sClient.Update(ctx, torrent)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, torrent)).To(Succeed())
+	}
+}
@@ -0,0 +1,32 @@
+package controller
+
+import (
+	"fmt"
+)
+
+// formatBytes renders a byte count with binary (IEC) units, e.g. 1610612736 -> "1.5 GiB"
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", max(bytes, 0))
+	}
+
+	value := float64(bytes)
+	units := []string{"KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	i := -1
+	for value >= unit && i < len(units)-1 {
+		value /= unit
+		i++
+	}
+	return fmt.Sprintf("%.1f %s", value, units[i])
+}
+
+// formatProgress renders a qBittorrent progress in [0, 1] as a percentage, e.g. 0.425 -> "42.5%"
+func formatProgress(progress float64) string {
+	return fmt.Sprintf("%.1f%%", progress*100)
+}
+
+// formatRatio renders a share ratio with two decimals, e.g. 0.5234 -> "0.52"
+func formatRatio(ratio float64) string {
+	return fmt.Sprintf("%.2f", ratio)
+}
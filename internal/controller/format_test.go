@@ -0,0 +1,33 @@
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Status formatting", func() {
+	DescribeTable("formatBytes",
+		func(bytes int64, expected string) {
+			Expect(formatBytes(bytes)).To(Equal(expected))
+		},
+		Entry("zero", int64(0), "0 B"),
+		Entry("bytes", int64(512), "512 B"),
+		Entry("one KiB", int64(1024), "1.0 KiB"),
+		Entry("1.5 MiB", int64(1572864), "1.5 MiB"),
+		Entry("1.5 GiB", int64(1610612736), "1.5 GiB"),
+		Entry("263.6 MiB", int64(276445467), "263.6 MiB"),
+		Entry("2 TiB", int64(2199023255552), "2.0 TiB"),
+		Entry("negative", int64(-1), "0 B"),
+	)
+
+	It("should format progress as a percentage", func() {
+		Expect(formatProgress(0)).To(Equal("0.0%"))
+		Expect(formatProgress(0.425)).To(Equal("42.5%"))
+		Expect(formatProgress(1)).To(Equal("100.0%"))
+	})
+
+	It("should format ratios with two decimals", func() {
+		Expect(formatRatio(0.5234)).To(Equal("0.52"))
+		Expect(formatRatio(2)).To(Equal("2.00"))
+	})
+})
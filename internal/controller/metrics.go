@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	torrentv1alpha1 "github.com/guidonguido/qbittorrent-operator/api/v1alpha1"
+)
+
+var (
+	degradedTCCsDesc = prometheus.NewDesc(
+		"qbittorrent_operator_degraded_tccs",
+		"Number of TorrentClientConfigurations with a True Degraded condition",
+		nil, nil,
+	)
+
+	// degradedTCCs counts the Degraded TCCs once registerDegradedTCCMetric gave it a reader
+	degradedTCCs = &degradedTCCCollector{}
+)
+
+// Register the operator metrics with the controller-runtime registry served on the metrics endpoint
+func init() {
+	metrics.Registry.MustRegister(degradedTCCs)
+}
+
+// degradedTCCCollector counts the Degraded TCCs on each scrape, from the cache of the reader
+type degradedTCCCollector struct {
+	mu     sync.RWMutex
+	reader client.Reader
+}
+
+// registerDegradedTCCMetric makes qbittorrent_operator_degraded_tccs count the TCCs listed by reader,
+// e.g. the manager cache. The metric is not reported until then.
+func registerDegradedTCCMetric(reader client.Reader) {
+	degradedTCCs.mu.Lock()
+	defer degradedTCCs.mu.Unlock()
+	degradedTCCs.reader = reader
+}
+
+func (c *degradedTCCCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- degradedTCCsDesc
+}
+
+func (c *degradedTCCCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	reader := c.reader
+	c.mu.RUnlock()
+	if reader == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	tccs := &torrentv1alpha1.TorrentClientConfigurationList{}
+	// The cache may not be started yet: report nothing rather than a misleading zero
+	if err := reader.List(ctx, tccs); err != nil {
+		return
+	}
+	degraded := 0
+	for i := range tccs.Items {
+		if meta.IsStatusConditionTrue(tccs.Items[i].Status.Conditions, TypeDegradedTCC) {
+			degraded++
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(degradedTCCsDesc, prometheus.GaugeValue, float64(degraded))
+}
@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	torrentv1alpha1 "github.com/guidonguido/qbittorrent-operator/api/v1alpha1"
+)
+
+var _ = Describe("Operator metrics", func() {
+	ctx := context.Background()
+
+	Context("When counting Degraded TCCs", func() {
+		const tccName = "test-tcc-metrics"
+		const secretName = "test-tcc-metrics-creds"
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, tccName, secretName)
+		})
+
+		AfterEach(func() {
+			registerDegradedTCCMetric(nil)
+			deleteTCC(ctx, tccName, secretName)
+		})
+
+		It("should not report the metric before a reader is registered", func() {
+			Expect(testutil.CollectAndCount(degradedTCCs)).To(Equal(0))
+		})
+
+		It("should count the TCCs with a True Degraded condition", func() {
+			registerDegradedTCCMetric(k8sClient)
+			before := testutil.ToFloat64(degradedTCCs)
+
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: tccName, Namespace: "default"}, tcc)).To(Succeed())
+			meta.SetStatusCondition(&tcc.Status.Conditions, metav1.Condition{
+				Type:   TypeDegradedTCC,
+				Status: metav1.ConditionTrue,
+				Reason: "ConnectionFailed",
+			})
+			Expect(k8sClient.Status().Update(ctx, tcc)).To(Succeed())
+
+			Expect(testutil.ToFloat64(degradedTCCs)).To(Equal(before + 1))
+		})
+	})
+})
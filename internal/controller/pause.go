@@ -0,0 +1,14 @@
+package controller
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ReconcileAnnotation pauses reconciliation of a resource when set to "false".
+// Reconcilers no-op until the annotation is removed or set to any other value.
+const ReconcileAnnotation = "torrent.qbittorrent.io/reconcile"
+
+// isReconcilePaused reports whether the object opted out of reconciliation through ReconcileAnnotation
+func isReconcilePaused(obj client.Object) bool {
+	return obj.GetAnnotations()[ReconcileAnnotation] == "false"
+}
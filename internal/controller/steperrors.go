@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ReasonMultipleStepsFailed is the Degraded reason when several independent reconcile steps failed
+const ReasonMultipleStepsFailed = "MultipleStepsFailed"
+
+// stepErrors collects the failures of independent reconcile steps, so that a failing step
+// does not hide the ones after it and every failure is reported in a single condition
+type stepErrors struct {
+	reasons []string
+	errs    []error
+}
+
+// add records the failure of a step with the condition reason it reports on its own
+func (s *stepErrors) add(reason string, err error) {
+	s.reasons = append(s.reasons, reason)
+	s.errs = append(s.errs, err)
+}
+
+func (s *stepErrors) empty() bool {
+	return len(s.errs) == 0
+}
+
+// reason returns the reason of the failed step, or ReasonMultipleStepsFailed when several failed
+func (s *stepErrors) reason() string {
+	if len(s.reasons) == 1 {
+		return s.reasons[0]
+	}
+	return ReasonMultipleStepsFailed
+}
+
+// err joins the failures, prefixing each with its reason when several steps failed
+func (s *stepErrors) err() error {
+	if len(s.errs) == 1 {
+		return s.errs[0]
+	}
+	errs := make([]error, len(s.errs))
+	for i, err := range s.errs {
+		errs[i] = fmt.Errorf("%s: %w", s.reasons[i], err)
+	}
+	return errors.Join(errs...)
+}
+
+// message renders the joined failures on a single line, as shown by kubectl get
+func (s *stepErrors) message() string {
+	return strings.ReplaceAll(s.err().Error(), "\n", "; ")
+}
@@ -2,14 +2,24 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -25,21 +35,69 @@ type TorrentReconciler struct {
 	client.Client
 	Scheme     *runtime.Scheme
 	ClientPool *qbittorrent.ClientPool
+	// BearerTokenDir is the directory spec.auth.tokenFile paths of TCCs are resolved in. Empty disables token files.
+	BearerTokenDir string
+	// TerminalRequeueInterval is how long to wait before retrying a Torrent that failed
+	// with a terminal error (e.g. invalid magnet). Zero disables requeueing until the resource changes.
+	TerminalRequeueInterval time.Duration
+	// LogExcerptLines is how many recent qBittorrent warning and critical log lines are emitted as an event
+	// when a Torrent becomes Degraded. Zero disables fetching the log.
+	LogExcerptLines int
+	// Recorder emits events, e.g. the qBittorrent log excerpt of a Degraded Torrent. Events are skipped when nil.
+	Recorder record.EventRecorder
+	// DisallowFileDeletion keeps the downloaded files of every deleted Torrent, overriding spec.deleteFilesOnRemoval.
+	DisallowFileDeletion bool
+	// SettingsVerifyInterval is how often the declarative settings of a Torrent are checked against qBittorrent,
+	// which may lose them across a restart, and applied again when they drifted. Zero disables the verification.
+	SettingsVerifyInterval time.Duration
 }
 
 const (
 	TypeAvailableTorrent = "Available"
 	TypeDegradedTorrent  = "Degraded"
+	// TypeQueueingDisabledTorrent warns that spec.addToTopOfQueue was ignored as queueing was disabled on the
+	// instance when the torrent was added. The queue position is only set on add, so it is kept afterwards
+	TypeQueueingDisabledTorrent = "QueueingDisabled"
+
+	// TypeConnectionLimitsInstanceWideTorrent reports that spec.maxConnections and spec.maxUploads
+	// are applied through the instance-wide preferences
+	TypeConnectionLimitsInstanceWideTorrent = "ConnectionLimitsInstanceWide"
+
+	// ReasonUnsupportedAPIVersion is the Degraded reason of a spec feature the qBittorrent instance cannot honour
+	ReasonUnsupportedAPIVersion = "UnsupportedAPIVersion"
 )
 
 const TorrentFinalizer = "torrent.qbittorrent.io/finalizer"
 
+// RefreshAnnotation requests a full sync of a Torrent when its value changes, e.g. set to the current timestamp.
+// Settings otherwise applied once per spec generation, such as relative queue moves or retrying
+// failed sources, are applied again.
+const RefreshAnnotation = "torrent.qbittorrent.io/refresh"
+
+// ArchiveOnDeleteAnnotation, set to an absolute path on the qBittorrent instance, moves the files of a deleted
+// Torrent there before removing it from qBittorrent, keeping them whatever spec.deleteFilesOnRemoval says
+const ArchiveOnDeleteAnnotation = "torrent.qbittorrent.io/archive-to"
+
+// archiveRetryInterval is how long a requested archive move may take to start before it is requested again
+const archiveRetryInterval = time.Minute
+
+// AutoDiscoverLabel set to "false" on a TorrentClientConfiguration keeps it out of the auto-discovery of Torrents
+// without spec.clientConfigRef or spec.selector, e.g. for a test instance. It can still be referenced or selected.
+const AutoDiscoverLabel = "torrent.qbittorrent.io/auto-discover"
+
+// ExportedTorrentKey is the key of the .torrent file in the Secret written for spec.exportToSecret
+const ExportedTorrentKey = "torrent"
+
+// errAmbiguousClientConfiguration reports a spec.selector matching several TorrentClientConfigurations
+var errAmbiguousClientConfiguration = errors.New("ambiguous TorrentClientConfiguration selection")
+
 // +kubebuilder:rbac:groups=torrent.qbittorrent.io,resources=torrents,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=torrent.qbittorrent.io,resources=torrents/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=torrent.qbittorrent.io,resources=torrents/finalizers,verbs=update
 // +kubebuilder:rbac:groups=torrent.qbittorrent.io,resources=torrentclientconfigurations,verbs=get;list;watch
 // +kubebuilder:rbac:groups=torrent.qbittorrent.io,resources=torrentclientconfigurations/status,verbs=get
-// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 func (r *TorrentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
@@ -52,6 +110,12 @@ func (r *TorrentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// 1.1. Paused resources are left untouched until the reconcile annotation is removed
+	if isReconcilePaused(torrent) {
+		logger.Info("Reconciliation paused by annotation", "annotation", ReconcileAnnotation)
+		return ctrl.Result{}, nil
+	}
+
 	// 2. Handle deletion
 	if !torrent.DeletionTimestamp.IsZero() {
 		return r.handleDeletion(ctx, torrent)
@@ -69,25 +133,100 @@ func (r *TorrentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return ctrl.Result{RequeueAfter: 1 * time.Second}, nil
 	}
 
+	// 3.1. A new refresh annotation value bypasses the per-generation short-circuits for this reconcile
+	if refresh := torrent.Annotations[RefreshAnnotation]; refresh != "" && refresh != torrent.Status.ObservedRefresh {
+		logger.Info("Refresh requested by annotation, forcing a full sync", "Name", torrent.Name,
+			"annotation", RefreshAnnotation, "value", refresh)
+		torrent.Status.FailedSources = nil
+		torrent.Status.FailedSourcesGeneration = torrent.Generation
+		torrent.Status.PriorityGeneration = 0
+		torrent.Status.ObservedRefresh = refresh
+	}
+
 	// 4. Resolve TCC and get qBittorrent client
-	qbtClient, err := r.getQBTClient(ctx, torrent)
+	qbtClient, tcc, err := r.getQBTClient(ctx, torrent)
 	if err != nil {
 		logger.Error(err, "Failed to resolve qBittorrent client")
-		r.setDegradedCondition(torrent, "ClientResolutionFailed", err.Error())
+		reason := "ClientResolutionFailed"
+		if errors.Is(err, errAmbiguousClientConfiguration) {
+			reason = "AmbiguousClientConfiguration"
+		}
+		r.setDegradedCondition(torrent, reason, err.Error())
 		if statusErr := r.Status().Update(ctx, torrent); statusErr != nil {
 			logger.Error(statusErr, "Failed to update Torrent status")
 		}
 		return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
 	}
 
-	// 5. Check if the torrent is already added in qBittorrent and update status accordingly
-	logger.V(1).Info("Getting torrent hash from magnet URI", "MagnetURI", torrent.Spec.MagnetURI)
-	hash, err := qbittorrent.GetTorrentHash(torrent.Spec.MagnetURI)
+	// 4.1. Attach the recent qBittorrent errors once this reconcile turns the Torrent Degraded
+	if r.LogExcerptLines > 0 {
+		previousReason := degradedReason(torrent)
+		defer r.recordLogExcerpt(ctx, qbtClient, torrent, previousReason)
+	}
+
+	// 5. Resolve the magnet URI to use among the configured sources. An adopted torrent may have none
+	sources := torrentSources(torrent)
+	if len(sources) == 0 && torrent.Spec.Hash == "" {
+		r.setDegradedCondition(torrent, "InvalidMagnetURI",
+			"no magnet URI configured: set spec.magnet_uri, spec.magnetURIs or, with spec.adopt, spec.hash")
+		if err := r.Status().Update(ctx, torrent); err != nil {
+			logger.Error(err, "Failed to update Torrent status")
+		}
+		return ctrl.Result{RequeueAfter: r.TerminalRequeueInterval}, nil
+	}
+	var source string
+	if len(sources) > 0 {
+		if torrent.Status.FailedSourcesGeneration != torrent.Generation {
+			// A spec change gives previously failed sources another chance
+			torrent.Status.FailedSources = nil
+			torrent.Status.FailedSourcesGeneration = torrent.Generation
+		}
+		source = selectSource(torrent, sources)
+		if source == "" {
+			r.setDegradedCondition(torrent, "AllSourcesFailed",
+				fmt.Sprintf("none of the %d magnet URIs yielded metadata", len(sources)))
+			if err := r.Status().Update(ctx, torrent); err != nil {
+				logger.Error(err, "Failed to update Torrent status")
+			}
+			return ctrl.Result{RequeueAfter: r.TerminalRequeueInterval}, nil
+		}
+	}
+
+	// 6. Check if the torrent is already added in qBittorrent and update status accordingly
+	hash := qbittorrent.NormalizeTorrentHash(torrent.Spec.Hash)
+	if hash == "" {
+		logger.V(1).Info("Getting torrent hash from magnet URI", "MagnetURI", source)
+		hash, err = qbittorrent.GetTorrentHash(source)
+		if err != nil {
+			logger.Error(err, "Failed to get torrent hash")
+			if len(sources) > 1 {
+				return r.failSource(ctx, torrent, sources, source, err.Error())
+			}
+			r.setDegradedCondition(torrent, "InvalidMagnetURI", err.Error())
+			if err := r.Status().Update(ctx, torrent); err != nil {
+				logger.Error(err, "Failed to update Torrent status")
+			}
+			return ctrl.Result{RequeueAfter: r.TerminalRequeueInterval}, nil
+		}
+	}
+	logger.V(1).Info("Torrent hash", "Hash", hash)
+
+	// Two Torrents sharing a hash would fight over the same qBittorrent torrent
+	duplicate, err := r.findDuplicateTorrent(ctx, torrent, hash)
 	if err != nil {
-		logger.Error(err, "Failed to get torrent hash")
+		logger.Error(err, "Failed to check for duplicate Torrents")
 		return ctrl.Result{}, err
 	}
-	logger.V(1).Info("Torrent hash", "Hash", hash)
+	if duplicate != "" {
+		logger.Info("Another Torrent manages the same hash, refusing to reconcile", "Name", torrent.Name,
+			"Hash", hash, "conflictsWith", duplicate)
+		r.setDegradedCondition(torrent, "DuplicateHash",
+			fmt.Sprintf("Torrent %q already manages hash %s", duplicate, hash))
+		if err := r.Status().Update(ctx, torrent); err != nil {
+			logger.Error(err, "Failed to update Torrent status")
+		}
+		return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+	}
 
 	torrentInfo, err := qbtClient.GetTorrentInfo(ctx, hash)
 	if err != nil {
@@ -99,10 +238,73 @@ func (r *TorrentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 	}
 
+	// 6.1. An adopted torrent is only managed once present, never added
+	if torrentInfo == nil && isAdopting(torrent) {
+		logger.Info("Adopted torrent not found in qBittorrent, waiting for it", "Name", torrent.Name, "Hash", hash)
+		r.setDegradedCondition(torrent, "AdoptedTorrentNotFound",
+			fmt.Sprintf("spec.adopt is set but qBittorrent has no torrent with hash %s; it is never added by the operator", hash))
+		if err := r.Status().Update(ctx, torrent); err != nil {
+			logger.Error(err, "Failed to update Torrent status")
+		}
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
 	if torrentInfo == nil {
-		logger.Info("Torrent not found in qBittorrent, adding it", "Name", torrent.Name)
-		if err := qbtClient.AddTorrent(ctx, torrent.Spec.MagnetURI); err != nil {
+		// A hash already in status means qBittorrent lost the torrent, e.g. after its config volume was recreated
+		recovering := torrent.Status.Hash != ""
+		if recovering {
+			logger.Info("Torrent disappeared from qBittorrent, adding it again", "Name", torrent.Name, "Hash", torrent.Status.Hash)
+		} else {
+			logger.Info("Torrent not found in qBittorrent, adding it", "Name", torrent.Name)
+		}
+		// File priorities are applied while the torrent is stopped on metadata receipt
+		if stopsOnMetadata(torrent) && !qbittorrent.CapabilitiesFor(tcc.Status.APIVersion).StopCondition {
+			feature := "filePriorities"
+			if isMetadataOnly(torrent) {
+				feature = "metadataOnly"
+			}
+			logger.Info("Stopping on metadata receipt not supported by qBittorrent", "Name", torrent.Name,
+				"feature", feature, "apiVersion", tcc.Status.APIVersion, "required", qbittorrent.MinAPIVersionStopCondition)
+			return r.setFeatureNotSupported(ctx, torrent,
+				fmt.Sprintf("%s requires qBittorrent WebUI API %s or newer, found %s",
+					feature, qbittorrent.MinAPIVersionStopCondition, tcc.Status.APIVersion))
+		}
+		if addsToTopOfQueue(torrent) {
+			if !qbittorrent.CapabilitiesFor(tcc.Status.APIVersion).AddToTopOfQueue {
+				logger.Info("Adding to the top of the queue not supported by qBittorrent", "Name", torrent.Name,
+					"apiVersion", tcc.Status.APIVersion, "required", qbittorrent.MinAPIVersionAddToTopOfQueue)
+				return r.setFeatureNotSupported(ctx, torrent,
+					fmt.Sprintf("addToTopOfQueue requires qBittorrent WebUI API %s or newer, found %s",
+						qbittorrent.MinAPIVersionAddToTopOfQueue, tcc.Status.APIVersion))
+			}
+			// The queue position only matters while queueing is enabled: warn rather than refuse the add
+			if err := r.setQueueingDisabledCondition(ctx, qbtClient, torrent); err != nil {
+				logger.Error(err, "Failed to check whether qBittorrent queueing is enabled")
+			}
+		}
+		if torrent.Spec.Category != "" && torrent.Spec.CreateCategoryIfMissing != nil && *torrent.Spec.CreateCategoryIfMissing {
+			if err := r.ensureCategory(ctx, qbtClient, tcc, torrent); err != nil {
+				logger.Error(err, "Failed to ensure the Torrent category exists", "category", torrent.Spec.Category)
+				r.setDegradedCondition(torrent, "FailedToCreateCategory", err.Error())
+				if err := r.Status().Update(ctx, torrent); err != nil {
+					logger.Error(err, "Failed to update Torrent status")
+				}
+				return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+			}
+		}
+		if err := qbtClient.AddTorrent(ctx, source, addTorrentOptions(torrent)); err != nil {
 			logger.Error(err, "Failed to add Torrent to qBittorrent")
+			// Terminal failures will not succeed on retry, so avoid hammering qBittorrent
+			if qbittorrent.IsTerminalError(err) {
+				if len(sources) > 1 {
+					return r.failSource(ctx, torrent, sources, source, err.Error())
+				}
+				r.setDegradedCondition(torrent, "TorrentRejected", err.Error())
+				if err := r.Status().Update(ctx, torrent); err != nil {
+					logger.Error(err, "Failed to update Torrent status")
+				}
+				return ctrl.Result{RequeueAfter: r.TerminalRequeueInterval}, nil
+			}
 			r.setDegradedCondition(torrent, "FailedToAddTorrent", err.Error())
 			if err := r.Status().Update(ctx, torrent); err != nil {
 				logger.Error(err, "Failed to update Torrent status")
@@ -110,15 +312,180 @@ func (r *TorrentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 		}
 
-		r.setAvailableCondition(torrent, "TorrentAdded", "Torrent added to qBittorrent")
+		r.setSource(torrent, source)
+		reason, message := "TorrentAdded", "Torrent added to qBittorrent"
+		if recovering {
+			// Settings applied to the lost torrent must be applied again to the new one
+			forgetAppliedSettings(torrent)
+			reason, message = "TorrentRecovered", "Torrent missing from qBittorrent was added again"
+		}
+		if when := readyWhen(torrent); when == torrentv1alpha1.ReadyWhenAdded {
+			r.setAvailableCondition(torrent, reason, message)
+		} else {
+			r.setWaitingCondition(torrent, waitingReasons[when],
+				fmt.Sprintf("%s, waiting until %s", message, when))
+		}
+		if stopsOnMetadata(torrent) {
+			torrent.Status.AddPhase = torrentv1alpha1.AddPhaseAwaitingMetadata
+		}
 		if err := r.Status().Update(ctx, torrent); err != nil {
 			logger.Error(err, "Failed to update Torrent status")
 		}
 		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
 	}
 
-	// 6. If torrent already exists, update status
+	// 7. Pin the source once it yields metadata, or fall back to the next one after the timeout
+	if torrent.Status.Source != source {
+		// Torrent already present in qBittorrent, e.g. added before the operator managed it
+		r.setSource(torrent, source)
+	}
+	if !torrent.Status.SourcePinned {
+		if hasMetadata(torrentInfo) {
+			logger.Info("Torrent source yielded metadata, pinning it", "Name", torrent.Name, "source", source)
+			torrent.Status.SourcePinned = true
+			torrent.Status.SourceAttemptStartedAt = nil
+		} else if timeout := metadataTimeout(torrent); len(sources) > 1 && torrent.Status.SourceAttemptStartedAt != nil &&
+			time.Since(torrent.Status.SourceAttemptStartedAt.Time) > timeout {
+			logger.Info("Torrent source did not yield metadata in time, falling back", "Name", torrent.Name,
+				"source", source, "timeout", timeout)
+			if err := qbtClient.DeleteTorrent(ctx, hash, true); err != nil {
+				logger.Error(err, "Failed to remove stalled Torrent source from qBittorrent")
+				r.setDegradedCondition(torrent, "FailedToDeleteTorrent", err.Error())
+				if err := r.Status().Update(ctx, torrent); err != nil {
+					logger.Error(err, "Failed to update Torrent status")
+				}
+				return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+			}
+			return r.failSource(ctx, torrent, sources, source,
+				fmt.Sprintf("no metadata received within %s", timeout))
+		}
+	}
+
+	// Steps 8 to 9.5 are independent of each other: a failure is recorded and the next steps still run,
+	// so that every failure is reported at once in step 10.3
+	var failed stepErrors
+
+	// 8. Rename the torrent when the desired display name drifted from qBittorrent
+	if torrent.Spec.DisplayName != "" && torrentInfo.Name != torrent.Spec.DisplayName {
+		logger.Info("Renaming Torrent in qBittorrent", "Name", torrent.Name,
+			"from", torrentInfo.Name, "to", torrent.Spec.DisplayName)
+		if err := qbtClient.RenameTorrent(ctx, hash, torrent.Spec.DisplayName); err != nil {
+			logger.Error(err, "Failed to rename Torrent")
+			failed.add("FailedToRenameTorrent", err)
+		} else {
+			torrentInfo.Name = torrent.Spec.DisplayName
+		}
+	}
+
+	// 8.1. Select the files of a torrent stopped on metadata receipt, then start it unless spec.paused.
+	// File renames come next, so that the priority rules match the original file paths
+	filesSelected := true
+	if torrent.Status.AddPhase != "" && torrent.Status.AddPhase != torrentv1alpha1.AddPhaseStarted {
+		if err := r.advanceAddPhase(ctx, qbtClient, torrent, torrentInfo); err != nil {
+			logger.Error(err, "Failed to select Torrent files")
+			failed.add("FailedToSelectFiles", err)
+			filesSelected = false
+		}
+	}
+
+	// 9. Apply file renames, which require the torrent metadata to be available
+	if len(torrent.Spec.FileRenames) > 0 && filesSelected {
+		// The TCC reports the detected API version even before the pooled client has seen it
+		if !qbittorrent.CapabilitiesFor(tcc.Status.APIVersion).RenameFile {
+			logger.Info("File renames not supported by qBittorrent", "Name", torrent.Name,
+				"apiVersion", tcc.Status.APIVersion, "required", qbittorrent.MinAPIVersionRenameFile)
+			failed.add(ReasonUnsupportedAPIVersion,
+				fmt.Errorf("fileRenames require qBittorrent WebUI API %s or newer, found %s",
+					qbittorrent.MinAPIVersionRenameFile, tcc.Status.APIVersion))
+		} else if err := r.applyFileRenames(ctx, qbtClient, torrent, hash); err != nil {
+			logger.Error(err, "Failed to rename Torrent files")
+			if errors.Is(err, qbittorrent.ErrUnsupportedFeature) {
+				failed.add(ReasonUnsupportedAPIVersion, err)
+			} else {
+				failed.add("FailedToRenameFiles", err)
+			}
+		}
+	}
+
+	// 9.1. Connection limits: without per-torrent limits in the WebUI API they are applied through the
+	// instance-wide preferences, which cap every torrent of the qBittorrent instance
+	if !qbittorrent.CapabilitiesFor(tcc.Status.APIVersion).PerTorrentConnectionLimits {
+		if limits, err := r.applyInstanceConnectionLimits(ctx, qbtClient, torrent, tcc); err != nil {
+			logger.Error(err, "Failed to apply connection limits")
+			failed.add("FailedToSetConnectionLimits", err)
+		} else {
+			r.setConnectionLimitsCondition(torrent, limits, tcc.Status.APIVersion)
+		}
+	}
+
+	// 9.2. Move the torrent in the queue towards spec.priority
+	if torrent.Spec.Priority != nil {
+		if err := r.applyPriority(ctx, qbtClient, torrent, torrentInfo); err != nil {
+			logger.Error(err, "Failed to set Torrent queue priority")
+			failed.add("FailedToSetPriority", err)
+		}
+	}
+
+	// 9.3. Stop a completed torrent instead of seeding it, only once so that a manual resume sticks
+	if torrent.Spec.StopSeedingOnComplete != nil && *torrent.Spec.StopSeedingOnComplete &&
+		!torrent.Status.SeedingStopped && hasMetadata(torrentInfo) && torrentInfo.Progress >= 1 {
+		logger.Info("Torrent completed, stopping it instead of seeding", "Name", torrent.Name)
+		if err := qbtClient.PauseTorrents(ctx, []string{torrentInfo.Hash}); err != nil {
+			logger.Error(err, "Failed to stop completed Torrent")
+			failed.add("FailedToStopSeeding", err)
+		} else {
+			torrent.Status.SeedingStopped = true
+		}
+	}
+
+	// 9.4. Back up the .torrent file into the owned Secret once the metadata is received
+	if torrent.Spec.ExportToSecret != nil && hasMetadata(torrentInfo) {
+		if !qbittorrent.CapabilitiesFor(tcc.Status.APIVersion).Export {
+			logger.Info("Torrent export not supported by qBittorrent", "Name", torrent.Name,
+				"apiVersion", tcc.Status.APIVersion, "required", qbittorrent.MinAPIVersionExport)
+			failed.add(ReasonUnsupportedAPIVersion,
+				fmt.Errorf("exportToSecret requires qBittorrent WebUI API %s or newer, found %s",
+					qbittorrent.MinAPIVersionExport, tcc.Status.APIVersion))
+		} else if err := r.exportTorrentToSecret(ctx, qbtClient, torrent, hash); err != nil {
+			logger.Error(err, "Failed to export Torrent file")
+			failed.add("FailedToExportTorrent", err)
+		}
+	}
+
+	// 9.5. Periodically check the settings applied when the torrent was added, as a restarted qBittorrent
+	// may have lost them, and apply the drifted ones again without re-adding the torrent.
+	// A failed check is also recorded, so that it is retried after the interval rather than on every requeue
+	if r.settingsVerificationDue(torrent) && hasMetadata(torrentInfo) {
+		corrected, err := r.verifySettings(ctx, qbtClient, torrent, torrentInfo)
+		now := metav1.Now()
+		torrent.Status.SettingsVerifiedAt = &now
+		if err != nil {
+			logger.Error(err, "Failed to verify Torrent settings")
+			failed.add("FailedToVerifySettings", err)
+			if r.Recorder != nil {
+				r.Recorder.Event(torrent, corev1.EventTypeWarning, "SettingsVerificationFailed",
+					"Failed to verify settings, retrying after the verification interval: "+err.Error())
+			}
+		} else if len(corrected) > 0 && r.Recorder != nil {
+			r.Recorder.Event(torrent, corev1.EventTypeNormal, "SettingsReconciled",
+				"Applied drifted settings again: "+strings.Join(corrected, ", "))
+		}
+	}
+
+	// 10. If torrent already exists, update status
 	updated := r.updateTorrentStatus(ctx, torrent, torrentInfo)
+
+	// 10.1. Swarm and transfer properties are diagnostic only: keep the previous values on failure
+	if props, err := qbtClient.GetTorrentProperties(ctx, torrentInfo.Hash); err != nil {
+		logger.Error(err, "Failed to get torrent properties", "hash", torrentInfo.Hash)
+	} else if r.updateTorrentProperties(torrent, torrentInfo, props) {
+		updated = true
+	}
+
+	// 10.2. Surface why qBittorrent reports the torrent as errored, clearing it once recovered
+	if r.updateTorrentMessage(ctx, qbtClient, torrent, torrentInfo) {
+		updated = true
+	}
 	if updated {
 		logger.Info("Updating status reflecting the torrent info", "Name", torrent.Name)
 		if err := r.Status().Update(ctx, torrent); err != nil {
@@ -127,7 +494,19 @@ func (r *TorrentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		}
 	}
 
-	r.setAvailableCondition(torrent, "TorrentActive", "Torrent is active on qBittorrent")
+	// 10.3. Report every failed step at once, otherwise Available waits for the progress requested by spec.readyWhen
+	if !failed.empty() {
+		r.setDegradedCondition(torrent, failed.reason(), failed.message())
+		if err := r.Status().Update(ctx, torrent); err != nil {
+			logger.Error(err, "Failed to update Torrent status")
+		}
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+	if ready, message := torrentReady(torrent, torrentInfo); ready {
+		r.setAvailableCondition(torrent, "TorrentActive", "Torrent is active on qBittorrent")
+	} else {
+		r.setWaitingCondition(torrent, waitingReasons[readyWhen(torrent)], message)
+	}
 	if err := r.Status().Update(ctx, torrent); err != nil {
 		logger.Error(err, "Failed to update Torrent status")
 	}
@@ -136,13 +515,219 @@ func (r *TorrentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
 }
 
+// instanceConnectionLimits are the instance-wide connection limits in effect for a Torrent
+type instanceConnectionLimits struct {
+	// applied by the Torrents sharing the client configuration
+	applied map[string]any
+	// declared by the managing TorrentServer, which owns these keys
+	server     map[string]any
+	serverName string
+}
+
+func (l instanceConnectionLimits) empty() bool {
+	return len(l.applied) == 0 && len(l.server) == 0
+}
+
+// Apply spec.maxConnections and spec.maxUploads through the instance-wide max_connec_per_torrent and
+// max_uploads_per_torrent preferences, returning the limits in effect. As they cap every torrent of the
+// instance, the lowest limit declared by the Torrents sharing the client configuration wins. The keys
+// declared by the managing TorrentServer are left to it.
+func (r *TorrentReconciler) applyInstanceConnectionLimits(ctx context.Context, qbtClient qbittorrent.QBTClient, torrent *torrentv1alpha1.Torrent, tcc *torrentv1alpha1.TorrentClientConfiguration) (instanceConnectionLimits, error) {
+	logger := log.FromContext(ctx)
+
+	var limits instanceConnectionLimits
+	if torrent.Spec.MaxConnections == nil && torrent.Spec.MaxUploads == nil {
+		return limits, nil
+	}
+
+	torrentList := &torrentv1alpha1.TorrentList{}
+	if err := r.List(ctx, torrentList, client.InNamespace(torrent.Namespace)); err != nil {
+		return limits, fmt.Errorf("failed to list Torrents: %w", err)
+	}
+	lowest := func(current, limit *int32) *int32 {
+		if limit == nil || (current != nil && *current <= *limit) {
+			return current
+		}
+		return limit
+	}
+	maxConnections, maxUploads := torrent.Spec.MaxConnections, torrent.Spec.MaxUploads
+	for i := range torrentList.Items {
+		other := &torrentList.Items[i]
+		if other.Name == torrent.Name || !other.DeletionTimestamp.IsZero() ||
+			other.Status.ClientConfigurationName != tcc.Name {
+			continue
+		}
+		maxConnections = lowest(maxConnections, other.Spec.MaxConnections)
+		maxUploads = lowest(maxUploads, other.Spec.MaxUploads)
+	}
+
+	desired := make(map[string]any)
+	if maxConnections != nil {
+		desired[qbittorrent.PreferenceMaxConnectionsPerTorrent] = int(*maxConnections)
+	}
+	if maxUploads != nil {
+		desired[qbittorrent.PreferenceMaxUploadsPerTorrent] = int(*maxUploads)
+	}
+
+	server, serverName, err := serverPreferences(ctx, r.Client, tcc)
+	if err != nil {
+		return limits, err
+	}
+	for key := range desired {
+		if value, ok := server[key]; ok {
+			if limits.server == nil {
+				limits.server = make(map[string]any)
+			}
+			limits.server[key] = value
+			limits.serverName = serverName
+			delete(desired, key)
+		}
+	}
+	if len(desired) == 0 {
+		return limits, nil
+	}
+
+	current, err := qbtClient.GetPreferences(ctx)
+	if err != nil {
+		return limits, fmt.Errorf("failed to get preferences: %w", err)
+	}
+	if drifted := qbittorrent.DiffPreferences(current, desired); len(drifted) > 0 {
+		logger.Info("Applying connection limits to the qBittorrent instance", "Name", torrent.Name, "preferences", drifted)
+		if err := qbtClient.SetPreferences(ctx, drifted); err != nil {
+			return limits, fmt.Errorf("failed to set preferences: %w", err)
+		}
+	}
+	limits.applied = desired
+	return limits, nil
+}
+
+// Report the instance-wide connection limits in effect for spec.maxConnections and spec.maxUploads,
+// removing the condition when the Torrent declares none
+func (r *TorrentReconciler) setConnectionLimitsCondition(torrent *torrentv1alpha1.Torrent, limits instanceConnectionLimits, apiVersion string) {
+	if limits.empty() {
+		meta.RemoveStatusCondition(&torrent.Status.Conditions, TypeConnectionLimitsInstanceWideTorrent)
+		return
+	}
+	format := func(preferences map[string]any) string {
+		var formatted []string
+		for _, key := range []string{qbittorrent.PreferenceMaxConnectionsPerTorrent, qbittorrent.PreferenceMaxUploadsPerTorrent} {
+			if value, ok := preferences[key]; ok {
+				formatted = append(formatted, fmt.Sprintf("%s=%v", key, value))
+			}
+		}
+		return strings.Join(formatted, ", ")
+	}
+	var effective []string
+	if len(limits.applied) > 0 {
+		effective = append(effective, "applied "+format(limits.applied))
+	}
+	if len(limits.server) > 0 {
+		effective = append(effective, fmt.Sprintf("%s declared by TorrentServer %s", format(limits.server), limits.serverName))
+	}
+	condition := metav1.Condition{
+		Type:   TypeConnectionLimitsInstanceWideTorrent,
+		Status: metav1.ConditionTrue,
+		Reason: "InstanceWidePreferences",
+		Message: fmt.Sprintf("qBittorrent WebUI API %s has no per-torrent connection limits: %s, "+
+			"capping every torrent of the instance", apiVersion, strings.Join(effective, "; ")),
+		ObservedGeneration: torrent.Generation,
+		LastTransitionTime: metav1.NewTime(time.Now()),
+	}
+	meta.SetStatusCondition(&torrent.Status.Conditions, condition)
+}
+
+// Reset the status recording settings applied on a qBittorrent torrent that no longer exists,
+// so that the next reconciles apply every declarative setting to the re-added torrent
+func forgetAppliedSettings(torrent *torrentv1alpha1.Torrent) {
+	torrent.Status.AppliedFileRenames = nil
+	torrent.Status.PriorityGeneration = 0
+	torrent.Status.SeedingStopped = false
+	torrent.Status.AddPhase = ""
+}
+
+// isAdopting reports whether the Torrent manages a torrent already present in qBittorrent instead of adding it
+func isAdopting(torrent *torrentv1alpha1.Torrent) bool {
+	return torrent.Spec.Adopt != nil && *torrent.Spec.Adopt
+}
+
+// deletePolicy returns spec.onDelete, defaulting to orphan for an adopted torrent whose files predate the Torrent
+func deletePolicy(torrent *torrentv1alpha1.Torrent) torrentv1alpha1.DeletePolicy {
+	if torrent.Spec.OnDelete != "" {
+		return torrent.Spec.OnDelete
+	}
+	if isAdopting(torrent) {
+		return torrentv1alpha1.DeletePolicyOrphan
+	}
+	return torrentv1alpha1.DeletePolicyRemove
+}
+
+// Record the source in use, starting its metadata timeout
+func (r *TorrentReconciler) setSource(torrent *torrentv1alpha1.Torrent, source string) {
+	now := metav1.Now()
+	torrent.Status.Source = source
+	torrent.Status.SourcePinned = false
+	torrent.Status.SourceAttemptStartedAt = &now
+}
+
+// Mark a source as failed and move on to the next one, or report Degraded when none is left
+func (r *TorrentReconciler) failSource(ctx context.Context, torrent *torrentv1alpha1.Torrent, sources []string, source, message string) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	torrent.Status.FailedSources = append(torrent.Status.FailedSources, source)
+	torrent.Status.Source = ""
+	torrent.Status.SourcePinned = false
+	torrent.Status.SourceAttemptStartedAt = nil
+	torrent.Status.Hash = ""
+
+	if selectSource(torrent, sources) == "" {
+		r.setDegradedCondition(torrent, "AllSourcesFailed",
+			fmt.Sprintf("none of the %d magnet URIs yielded metadata, last error: %s", len(sources), message))
+		if err := r.Status().Update(ctx, torrent); err != nil {
+			logger.Error(err, "Failed to update Torrent status")
+		}
+		return ctrl.Result{RequeueAfter: r.TerminalRequeueInterval}, nil
+	}
+
+	r.setDegradedCondition(torrent, "SourceFailed",
+		fmt.Sprintf("magnet URI failed (%s), falling back to the next source", message))
+	if err := r.Status().Update(ctx, torrent); err != nil {
+		logger.Error(err, "Failed to update Torrent status")
+	}
+	return ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+}
+
+// Report a spec feature the qBittorrent instance cannot honour. Status is refreshed
+// at the usual cadence so an upgraded qBittorrent is picked up.
+func (r *TorrentReconciler) setFeatureNotSupported(ctx context.Context, torrent *torrentv1alpha1.Torrent, message string) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	r.setDegradedCondition(torrent, ReasonUnsupportedAPIVersion, message)
+	if err := r.Status().Update(ctx, torrent); err != nil {
+		logger.Error(err, "Failed to update Torrent status")
+	}
+	return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+}
+
 func (r *TorrentReconciler) handleDeletion(ctx context.Context, torrent *torrentv1alpha1.Torrent) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 	logger.Info("Handling Torrent Deletion", "Name", torrent.Name)
 
+	sharedWith := ""
 	if torrent.Status.Hash != "" {
+		var err error
+		if sharedWith, err = r.findTorrentSharingHash(ctx, torrent); err != nil {
+			logger.Error(err, "Failed to check for Torrents sharing the hash")
+			return ctrl.Result{}, err
+		}
+	}
+
+	if deletePolicy(torrent) == torrentv1alpha1.DeletePolicyOrphan {
+		logger.Info("Orphaning Torrent, leaving it in qBittorrent", "Name", torrent.Name, "Hash", torrent.Status.Hash)
+	} else if sharedWith != "" {
+		logger.Info("Another Torrent manages the same hash, leaving it in qBittorrent", "Name", torrent.Name,
+			"Hash", torrent.Status.Hash, "sharedWith", sharedWith)
+	} else if torrent.Status.Hash != "" {
 		// Resolve TCC to get a client for deletion
-		qbtClient, err := r.getQBTClient(ctx, torrent)
+		qbtClient, _, err := r.getQBTClient(ctx, torrent)
 		if err != nil {
 			logger.Error(err, "Failed to get qBittorrent client for deletion, removing finalizer anyway")
 		} else {
@@ -150,9 +735,33 @@ func (r *TorrentReconciler) handleDeletion(ctx context.Context, torrent *torrent
 			if torrent.Spec.DeleteFilesOnRemoval != nil {
 				deleteFiles = *torrent.Spec.DeleteFilesOnRemoval
 			}
+			if deleteFiles && r.DisallowFileDeletion {
+				logger.Info("File deletion is disallowed by the operator, keeping downloaded files", "Name", torrent.Name)
+				deleteFiles = false
+			}
+
+			// Statuses written by older releases may carry an uppercase hash
+			hash := qbittorrent.NormalizeTorrentHash(torrent.Status.Hash)
+
+			// Archiving moves the files away first, then keeps them whatever spec.deleteFilesOnRemoval says
+			if archivePath := torrent.Annotations[ArchiveOnDeleteAnnotation]; archivePath != "" {
+				archived, err := r.archiveTorrent(ctx, qbtClient, torrent, hash, archivePath)
+				if err != nil {
+					logger.Error(err, "Failed to archive Torrent before deletion")
+					r.setDegradedCondition(torrent, "FailedToArchiveTorrent", err.Error())
+					if err := r.Status().Update(ctx, torrent); err != nil {
+						logger.Error(err, "Failed to update Torrent status")
+					}
+					return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+				}
+				if !archived {
+					return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+				}
+				deleteFiles = false
+			}
 
 			logger.Info("Deleting Torrent from qBittorrent", "Name", torrent.Name)
-			if err := qbtClient.DeleteTorrent(ctx, torrent.Status.Hash, deleteFiles); err != nil {
+			if err := qbtClient.DeleteTorrent(ctx, hash, deleteFiles); err != nil {
 				logger.Error(err, "Failed to delete Torrent from qBittorrent")
 				r.setDegradedCondition(torrent, "FailedToDeleteTorrent", err.Error())
 				if err := r.Status().Update(ctx, torrent); err != nil {
@@ -174,7 +783,57 @@ func (r *TorrentReconciler) handleDeletion(ctx context.Context, torrent *torrent
 	return ctrl.Result{}, nil
 }
 
-func (r *TorrentReconciler) getQBTClient(ctx context.Context, torrent *torrentv1alpha1.Torrent) (*qbittorrent.Client, error) {
+// Move the files of a deleted torrent to archivePath, reporting whether they are there and the torrent
+// can be removed. qBittorrent moves files in the background, so completion is checked on the next reconciles.
+func (r *TorrentReconciler) archiveTorrent(ctx context.Context, qbtClient qbittorrent.QBTClient, torrent *torrentv1alpha1.Torrent, hash, archivePath string) (bool, error) {
+	logger := log.FromContext(ctx)
+
+	if !path.IsAbs(archivePath) {
+		return false, fmt.Errorf("%s must be an absolute path, got %q", ArchiveOnDeleteAnnotation, archivePath)
+	}
+	info, err := qbtClient.GetTorrentInfo(ctx, hash)
+	if err != nil {
+		return false, err
+	}
+	// Nothing left to move
+	if info == nil {
+		return true, nil
+	}
+	if info.State == "moving" {
+		logger.Info("Waiting for the Torrent files to be archived", "Name", torrent.Name, "location", archivePath)
+		return false, nil
+	}
+	if path.Clean(info.SavePath) == path.Clean(archivePath) {
+		logger.Info("Torrent files archived", "Name", torrent.Name, "location", archivePath)
+		if r.Recorder != nil {
+			r.Recorder.Event(torrent, corev1.EventTypeNormal, "TorrentArchived",
+				"Moved the torrent files to "+archivePath+" before removing it from qBittorrent")
+		}
+		return true, nil
+	}
+
+	// qBittorrent may not report the requested move yet: wait for it rather than requesting it on every requeue
+	requestedAt := torrent.Status.ArchiveRequestedAt
+	if torrent.Status.ArchiveRequestedTo == archivePath && requestedAt != nil && time.Since(requestedAt.Time) < archiveRetryInterval {
+		logger.Info("Waiting for qBittorrent to start archiving the Torrent files", "Name", torrent.Name, "location", archivePath)
+		return false, nil
+	}
+
+	logger.Info("Archiving Torrent files before deletion", "Name", torrent.Name,
+		"from", info.SavePath, "to", archivePath)
+	if err := qbtClient.SetTorrentLocation(ctx, []string{hash}, archivePath); err != nil {
+		return false, err
+	}
+	now := metav1.Now()
+	torrent.Status.ArchiveRequestedTo = archivePath
+	torrent.Status.ArchiveRequestedAt = &now
+	if err := r.Status().Update(ctx, torrent); err != nil {
+		return false, fmt.Errorf("failed to record the archive request: %w", err)
+	}
+	return false, nil
+}
+
+func (r *TorrentReconciler) getQBTClient(ctx context.Context, torrent *torrentv1alpha1.Torrent) (qbittorrent.QBTClient, *torrentv1alpha1.TorrentClientConfiguration, error) {
 	logger := log.FromContext(ctx)
 
 	// 1. Get TCC
@@ -182,73 +841,629 @@ func (r *TorrentReconciler) getQBTClient(ctx context.Context, torrent *torrentv1
 
 	if torrent.Spec.ClientConfigRef != nil {
 		// 1.1. Get the referenced TCC
-		tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+		tcc = &torrentv1alpha1.TorrentClientConfiguration{}
 		if err := r.Get(ctx, types.NamespacedName{
 			Name:      torrent.Spec.ClientConfigRef.Name,
 			Namespace: torrent.Namespace,
 		}, tcc); err != nil {
-			return nil, fmt.Errorf("referenced TorrentClientConfiguration %q not found: %w",
+			return nil, nil, fmt.Errorf("referenced TorrentClientConfiguration %q not found: %w",
 				torrent.Spec.ClientConfigRef.Name, err)
 		}
-	}
+	} else if len(torrent.Spec.Selector) > 0 {
+		// 1.2. Select the only TCC whose labels match spec.selector
+		tccList := &torrentv1alpha1.TorrentClientConfigurationList{}
+		if err := r.List(ctx, tccList, client.InNamespace(torrent.Namespace), client.MatchingLabels(torrent.Spec.Selector)); err != nil {
+			return nil, nil, fmt.Errorf("failed to list TorrentClientConfigurations: %w", err)
+		}
 
-	// 1.2. If no explicit reference, try to auto-discover the only TCC in the namespace
-	tccList := &torrentv1alpha1.TorrentClientConfigurationList{}
-	if err := r.List(ctx, tccList, client.InNamespace(torrent.Namespace)); err != nil {
-		return nil, fmt.Errorf("failed to list TorrentClientConfigurations: %w", err)
-	}
+		switch len(tccList.Items) {
+		case 0:
+			return nil, nil, fmt.Errorf("no TorrentClientConfiguration in namespace %s matches selector %s",
+				torrent.Namespace, labels.Set(torrent.Spec.Selector))
+		case 1:
+			logger.V(1).Info("Selected TCC by labels", "name", tccList.Items[0].Name)
+			tcc = &tccList.Items[0]
+		default:
+			names := make([]string, 0, len(tccList.Items))
+			for _, item := range tccList.Items {
+				names = append(names, item.Name)
+			}
+			sort.Strings(names)
+			return nil, nil, fmt.Errorf("%w: selector %s matches %s; narrow spec.selector or set spec.clientConfigRef",
+				errAmbiguousClientConfiguration, labels.Set(torrent.Spec.Selector), strings.Join(names, ", "))
+		}
+	} else {
+		// 1.3. If no explicit reference, try to auto-discover the only TCC in the namespace,
+		// ignoring the TCCs opted out of auto-discovery
+		tccList := &torrentv1alpha1.TorrentClientConfigurationList{}
+		if err := r.List(ctx, tccList, client.InNamespace(torrent.Namespace)); err != nil {
+			return nil, nil, fmt.Errorf("failed to list TorrentClientConfigurations: %w", err)
+		}
+		listed := len(tccList.Items)
+		tccList.Items = slices.DeleteFunc(tccList.Items, func(item torrentv1alpha1.TorrentClientConfiguration) bool {
+			return item.Labels[AutoDiscoverLabel] == "false"
+		})
 
-	switch len(tccList.Items) {
-	case 0:
-		return nil, fmt.Errorf("no TorrentClientConfiguration found in namespace %s", torrent.Namespace)
-	case 1:
-		logger.V(1).Info("Auto-discovered TCC", "name", tccList.Items[0].Name)
-		tcc = &tccList.Items[0]
-	default:
-		return nil, fmt.Errorf("multiple TorrentClientConfigurations found in namespace %s; set spec.clientConfigRef to select one",
-			torrent.Namespace)
+		switch len(tccList.Items) {
+		case 0:
+			if excluded := listed - len(tccList.Items); excluded > 0 {
+				return nil, nil, fmt.Errorf("no TorrentClientConfiguration found in namespace %s: %d excluded by %s=false; "+
+					"set spec.clientConfigRef to select one", torrent.Namespace, excluded, AutoDiscoverLabel)
+			}
+			return nil, nil, fmt.Errorf("no TorrentClientConfiguration found in namespace %s", torrent.Namespace)
+		case 1:
+			logger.V(1).Info("Auto-discovered TCC", "name", tccList.Items[0].Name)
+			tcc = &tccList.Items[0]
+		default:
+			return nil, nil, fmt.Errorf("multiple TorrentClientConfigurations found in namespace %s; set spec.clientConfigRef to select one",
+				torrent.Namespace)
+		}
 	}
 
 	// 2. TCC must be available to connect to qBittorrent
 	availableCondition := meta.FindStatusCondition(tcc.Status.Conditions, TypeAvailableTCC)
 	if availableCondition == nil || availableCondition.Status != metav1.ConditionTrue {
-		return nil, fmt.Errorf("TorrentClientConfiguration %q is not available", tcc.Name)
+		return nil, nil, fmt.Errorf("TorrentClientConfiguration %q is not available", tcc.Name)
 	}
 
 	// 3. Set the discovered TCC in the status
 	torrent.Status.ClientConfigurationName = tcc.Name
 
 	// 4. Get credentials and the related connection
-	secret := &corev1.Secret{}
-	if err := r.Get(ctx, types.NamespacedName{
-		Name:      tcc.Spec.CredentialsSecret.Name,
-		Namespace: tcc.Namespace,
-	}, secret); err != nil {
-		return nil, fmt.Errorf("failed to get credentials secret %q: %w", tcc.Spec.CredentialsSecret.Name, err)
+	qbtClient, err := clientForTCC(ctx, r.Client, r.ClientPool, tcc, r.BearerTokenDir)
+	if err != nil {
+		return nil, nil, err
 	}
-
-	return r.ClientPool.GetOrCreate(
-		ctx,
-		tcc.Spec.URL,
-		string(secret.Data["username"]),
-		string(secret.Data["password"]),
-	)
+	return qbtClient, tcc, nil
 }
 
-func (r *TorrentReconciler) setDegradedCondition(torrent *torrentv1alpha1.Torrent, reason, message string) {
-	condition := metav1.Condition{
-		Type:               TypeDegradedTorrent,
-		Status:             metav1.ConditionTrue,
-		Reason:             reason,
-		Message:            message,
-		LastTransitionTime: metav1.NewTime(time.Now()),
+// Return the name of another Torrent in the namespace that owns the same hash, if any.
+// A Torrent already tracking the hash in status owns it; otherwise the oldest Torrent wins.
+func (r *TorrentReconciler) findDuplicateTorrent(ctx context.Context, torrent *torrentv1alpha1.Torrent, hash string) (string, error) {
+	torrentList := &torrentv1alpha1.TorrentList{}
+	if err := r.List(ctx, torrentList, client.InNamespace(torrent.Namespace)); err != nil {
+		return "", fmt.Errorf("failed to list Torrents: %w", err)
 	}
-	meta.SetStatusCondition(&torrent.Status.Conditions, condition)
-	meta.RemoveStatusCondition(&torrent.Status.Conditions, TypeAvailableTorrent)
+
+	owned := strings.EqualFold(torrent.Status.Hash, hash)
+	for i := range torrentList.Items {
+		other := &torrentList.Items[i]
+		if other.Name == torrent.Name || !other.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		otherOwned := strings.EqualFold(other.Status.Hash, hash)
+		if !otherOwned && !strings.EqualFold(resolvedHash(other), hash) {
+			continue
+		}
+
+		switch {
+		case otherOwned && !owned:
+			return other.Name, nil
+		case owned && !otherOwned:
+			continue
+		case olderTorrent(other, torrent):
+			return other.Name, nil
+		}
+	}
+	return "", nil
 }
 
-func (r *TorrentReconciler) setAvailableCondition(torrent *torrentv1alpha1.Torrent, reason, message string) {
-	condition := metav1.Condition{
+// Return the name of another live Torrent tracking the same hash in status, if any
+func (r *TorrentReconciler) findTorrentSharingHash(ctx context.Context, torrent *torrentv1alpha1.Torrent) (string, error) {
+	torrentList := &torrentv1alpha1.TorrentList{}
+	if err := r.List(ctx, torrentList, client.InNamespace(torrent.Namespace)); err != nil {
+		return "", fmt.Errorf("failed to list Torrents: %w", err)
+	}
+
+	for _, other := range torrentList.Items {
+		if other.Name != torrent.Name && other.DeletionTimestamp.IsZero() &&
+			strings.EqualFold(other.Status.Hash, torrent.Status.Hash) {
+			return other.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// Translate the Torrent spec into the qBittorrent add parameters
+func addTorrentOptions(torrent *torrentv1alpha1.Torrent) qbittorrent.AddTorrentOptions {
+	opts := qbittorrent.AddTorrentOptions{
+		ContentLayout: string(torrent.Spec.ContentLayout),
+		Category:      torrent.Spec.Category,
+	}
+	if torrent.Spec.SkipHashCheck != nil {
+		opts.SkipChecking = *torrent.Spec.SkipHashCheck
+	}
+	opts.AddToTopOfQueue = addsToTopOfQueue(torrent)
+	if stopsOnMetadata(torrent) {
+		// A stopped magnet link never fetches its metadata: let qBittorrent stop the torrent once it has it
+		opts.StopCondition = qbittorrent.StopConditionMetadataReceived
+	} else if torrent.Spec.Paused != nil {
+		opts.Paused = *torrent.Spec.Paused
+	}
+	return opts
+}
+
+// addsToTopOfQueue reports whether the torrent is added at the top of the qBittorrent queue
+func addsToTopOfQueue(torrent *torrentv1alpha1.Torrent) bool {
+	return torrent.Spec.AddToTopOfQueue != nil && *torrent.Spec.AddToTopOfQueue
+}
+
+// Warn when the instance has queueing disabled as the torrent is added, as qBittorrent then ignores
+// spec.addToTopOfQueue. The condition is Unknown when the preferences cannot be read
+func (r *TorrentReconciler) setQueueingDisabledCondition(ctx context.Context, qbtClient qbittorrent.QBTClient, torrent *torrentv1alpha1.Torrent) error {
+	condition := metav1.Condition{
+		Type:               TypeQueueingDisabledTorrent,
+		Status:             metav1.ConditionTrue,
+		Reason:             "QueueingDisabled",
+		Message:            "queueing_enabled was false when the torrent was added, so qBittorrent ignored addToTopOfQueue",
+		ObservedGeneration: torrent.Generation,
+		LastTransitionTime: metav1.NewTime(time.Now()),
+	}
+	preferences, err := qbtClient.GetPreferences(ctx)
+	if err != nil {
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = "PreferencesUnavailable"
+		condition.Message = fmt.Sprintf("failed to check whether queueing is enabled, so addToTopOfQueue may be ignored: %v", err)
+		meta.SetStatusCondition(&torrent.Status.Conditions, condition)
+		return err
+	}
+	if preferences[qbittorrent.PreferenceQueueingEnabled] != false {
+		meta.RemoveStatusCondition(&torrent.Status.Conditions, TypeQueueingDisabledTorrent)
+		return nil
+	}
+	meta.SetStatusCondition(&torrent.Status.Conditions, condition)
+	return nil
+}
+
+// isMetadataOnly reports whether the Torrent only fetches the torrent metadata, downloading nothing
+func isMetadataOnly(torrent *torrentv1alpha1.Torrent) bool {
+	return torrent.Spec.MetadataOnly != nil && *torrent.Spec.MetadataOnly
+}
+
+// stopsOnMetadata reports whether the torrent is added so that qBittorrent stops it once its metadata is received
+func stopsOnMetadata(torrent *torrentv1alpha1.Torrent) bool {
+	return len(torrent.Spec.FilePriorities) > 0 || isMetadataOnly(torrent)
+}
+
+// Create the category of the torrent on the instance unless it already exists, with spec.categorySavePath
+// or else the save path the TCC declares for it. An existing category is left as is: its save path is
+// managed by the TCC spec.categories, if at all.
+func (r *TorrentReconciler) ensureCategory(ctx context.Context, qbtClient qbittorrent.QBTClient, tcc *torrentv1alpha1.TorrentClientConfiguration, torrent *torrentv1alpha1.Torrent) error {
+	logger := log.FromContext(ctx)
+	name := torrent.Spec.Category
+
+	categories, err := qbtClient.GetCategories(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := categories[name]; ok {
+		return nil
+	}
+
+	savePath := torrent.Spec.CategorySavePath
+	if savePath == "" {
+		for _, category := range tcc.Spec.Categories {
+			if category.Name == name {
+				savePath = category.SavePath
+				break
+			}
+		}
+	}
+	logger.Info("Creating missing category before adding the torrent", "category", name, "savePath", savePath)
+	return qbtClient.CreateCategory(ctx, name, savePath)
+}
+
+// filePriorityValues maps the spec priority levels to the qBittorrent file priorities
+var filePriorityValues = map[torrentv1alpha1.FilePriorityLevel]int{
+	torrentv1alpha1.FilePrioritySkip:    qbittorrent.FilePriorityDoNotDownload,
+	torrentv1alpha1.FilePriorityNormal:  qbittorrent.FilePriorityNormal,
+	torrentv1alpha1.FilePriorityHigh:    qbittorrent.FilePriorityHigh,
+	torrentv1alpha1.FilePriorityMaximum: qbittorrent.FilePriorityMaximum,
+}
+
+// Move a torrent stopped on metadata receipt through its add phases: once the metadata is received,
+// list its files while spec.metadataOnly holds it, then apply the priorities to the stopped torrent
+// and start it unless spec.paused keeps it stopped
+func (r *TorrentReconciler) advanceAddPhase(ctx context.Context, qbtClient qbittorrent.QBTClient, torrent *torrentv1alpha1.Torrent, info *qbittorrent.TorrentInfo) error {
+	logger := log.FromContext(ctx)
+
+	if torrent.Status.AddPhase == torrentv1alpha1.AddPhaseAwaitingMetadata {
+		if !hasMetadata(info) || (isMetadataOnly(torrent) && torrent.Status.FilesListed) {
+			return nil
+		}
+		files, err := qbtClient.GetTorrentFiles(ctx, info.Hash)
+		if err != nil {
+			return err
+		}
+		if isMetadataOnly(torrent) {
+			torrent.Status.Files = make([]torrentv1alpha1.TorrentFileStatus, 0, len(files))
+			for i, file := range files {
+				torrent.Status.Files = append(torrent.Status.Files, torrentv1alpha1.TorrentFileStatus{
+					Index: i, Name: file.Name, Size: file.Size,
+				})
+			}
+			torrent.Status.FilesListed = true
+			logger.Info("Listed the files of the metadata-only Torrent", "Name", torrent.Name, "files", len(files))
+			return nil
+		}
+		// The listing only describes the torrent held by spec.metadataOnly
+		torrent.Status.Files = nil
+		torrent.Status.FilesListed = false
+		for _, level := range []torrentv1alpha1.FilePriorityLevel{
+			torrentv1alpha1.FilePrioritySkip, torrentv1alpha1.FilePriorityHigh, torrentv1alpha1.FilePriorityMaximum,
+		} {
+			indexes := filesWithPriority(torrent.Spec.FilePriorities, files, level)
+			if len(indexes) == 0 {
+				continue
+			}
+			if err := qbtClient.SetFilePriority(ctx, info.Hash, indexes, filePriorityValues[level]); err != nil {
+				return err
+			}
+		}
+		logger.Info("Applied Torrent file priorities", "Name", torrent.Name, "files", len(files))
+		torrent.Status.AddPhase = torrentv1alpha1.AddPhaseFilesSelected
+	}
+
+	if torrent.Spec.Paused != nil && *torrent.Spec.Paused {
+		return nil
+	}
+	logger.Info("Starting Torrent after selecting its files", "Name", torrent.Name)
+	if err := qbtClient.ResumeTorrents(ctx, []string{info.Hash}); err != nil {
+		return err
+	}
+	torrent.Status.AddPhase = torrentv1alpha1.AddPhaseStarted
+	return nil
+}
+
+// settingsVerificationDue reports whether the declarative settings of the Torrent are due for verification
+func (r *TorrentReconciler) settingsVerificationDue(torrent *torrentv1alpha1.Torrent) bool {
+	if r.SettingsVerifyInterval <= 0 || isAdopting(torrent) {
+		return false
+	}
+	verifiedAt := torrent.Status.SettingsVerifiedAt
+	return verifiedAt == nil || time.Since(verifiedAt.Time) >= r.SettingsVerifyInterval
+}
+
+// Apply again the category and file priorities of a torrent added by the controller when qBittorrent
+// no longer reports them, returning the names of the corrected settings. File priorities are only
+// verified once applied, and not after file renames, which the priority rules do not match.
+func (r *TorrentReconciler) verifySettings(ctx context.Context, qbtClient qbittorrent.QBTClient, torrent *torrentv1alpha1.Torrent, info *qbittorrent.TorrentInfo) ([]string, error) {
+	logger := log.FromContext(ctx)
+	var corrected []string
+
+	if torrent.Spec.Category != "" && info.Category != torrent.Spec.Category {
+		logger.Info("Torrent category drifted, setting it again", "Name", torrent.Name,
+			"category", info.Category, "desired", torrent.Spec.Category)
+		if err := qbtClient.SetTorrentCategory(ctx, []string{info.Hash}, torrent.Spec.Category); err != nil {
+			return corrected, err
+		}
+		corrected = append(corrected, "category")
+	}
+
+	selected := torrent.Status.AddPhase == torrentv1alpha1.AddPhaseFilesSelected ||
+		torrent.Status.AddPhase == torrentv1alpha1.AddPhaseStarted
+	if len(torrent.Spec.FilePriorities) > 0 && selected && len(torrent.Status.AppliedFileRenames) == 0 {
+		files, err := qbtClient.GetTorrentFiles(ctx, info.Hash)
+		if err != nil {
+			return corrected, err
+		}
+		drifted := false
+		for _, level := range []torrentv1alpha1.FilePriorityLevel{
+			torrentv1alpha1.FilePrioritySkip, torrentv1alpha1.FilePriorityNormal,
+			torrentv1alpha1.FilePriorityHigh, torrentv1alpha1.FilePriorityMaximum,
+		} {
+			var indexes []int
+			for _, i := range filesWithPriority(torrent.Spec.FilePriorities, files, level) {
+				if files[i].Priority != filePriorityValues[level] {
+					indexes = append(indexes, i)
+				}
+			}
+			if len(indexes) == 0 {
+				continue
+			}
+			logger.Info("Torrent file priorities drifted, setting them again", "Name", torrent.Name,
+				"priority", level, "files", len(indexes))
+			if err := qbtClient.SetFilePriority(ctx, info.Hash, indexes, filePriorityValues[level]); err != nil {
+				return corrected, err
+			}
+			drifted = true
+		}
+		if drifted {
+			corrected = append(corrected, "filePriorities")
+		}
+	}
+	return corrected, nil
+}
+
+// Return the indexes of the files whose first matching rule sets the given priority.
+// Unmatched files keep the normal priority qBittorrent gives them
+func filesWithPriority(rules []torrentv1alpha1.FilePriority, files []qbittorrent.TorrentFile, level torrentv1alpha1.FilePriorityLevel) []int {
+	var indexes []int
+	for i, file := range files {
+		for _, rule := range rules {
+			if matched, err := qbittorrent.MatchFilePath(rule.Match, file.Name); err == nil && matched {
+				if rule.Priority == level {
+					indexes = append(indexes, i)
+				}
+				break
+			}
+		}
+	}
+	return indexes
+}
+
+// Return the hash a Torrent manages or is about to add, or an empty string if it cannot be resolved
+func resolvedHash(torrent *torrentv1alpha1.Torrent) string {
+	if torrent.Status.Hash != "" {
+		return torrent.Status.Hash
+	}
+	// An adopted torrent may be identified by spec.hash alone
+	if torrent.Spec.Hash != "" {
+		return qbittorrent.NormalizeTorrentHash(torrent.Spec.Hash)
+	}
+	source := selectSource(torrent, torrentSources(torrent))
+	if source == "" {
+		return ""
+	}
+	hash, err := qbittorrent.GetTorrentHash(source)
+	if err != nil {
+		return ""
+	}
+	return hash
+}
+
+// Report whether a was created before b, breaking ties by name
+func olderTorrent(a, b *torrentv1alpha1.Torrent) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
+
+// Rename the torrent files matching spec.fileRenames, recording applied renames in status
+func (r *TorrentReconciler) applyFileRenames(ctx context.Context, qbtClient qbittorrent.QBTClient, torrent *torrentv1alpha1.Torrent, hash string) error {
+	logger := log.FromContext(ctx)
+
+	files, err := qbtClient.GetTorrentFiles(ctx, hash)
+	if err != nil {
+		return err
+	}
+	// Metadata not received yet, the file list is still unknown
+	if len(files) == 0 {
+		logger.V(1).Info("Torrent metadata not available yet, postponing file renames", "hash", hash)
+		return nil
+	}
+
+	renames, err := planFileRenames(files, torrent.Spec.FileRenames)
+	if err != nil {
+		return err
+	}
+
+	for _, rename := range renames {
+		if err := qbtClient.RenameFile(ctx, hash, rename.From, rename.To); err != nil {
+			return err
+		}
+		torrent.Status.AppliedFileRenames = append(torrent.Status.AppliedFileRenames, rename)
+	}
+	return nil
+}
+
+// Write the exported .torrent file into the Secret of spec.exportToSecret, owned by the Torrent.
+// qBittorrent is only asked for the file while the Secret lacks it.
+func (r *TorrentReconciler) exportTorrentToSecret(ctx context.Context, qbtClient qbittorrent.QBTClient, torrent *torrentv1alpha1.Torrent, hash string) error {
+	logger := log.FromContext(ctx)
+
+	name := torrent.Spec.ExportToSecret.Name
+	if name == "" {
+		name = torrent.Name + "-torrent"
+	}
+
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: torrent.Namespace}, secret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+	if exists && !metav1.IsControlledBy(secret, torrent) {
+		return fmt.Errorf("secret %q already exists and is not owned by the Torrent", name)
+	}
+	torrent.Status.ExportedSecretName = name
+	if exists && len(secret.Data[ExportedTorrentKey]) > 0 {
+		return nil
+	}
+
+	data, err := qbtClient.ExportTorrent(ctx, hash)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: torrent.Namespace,
+			},
+			Type: corev1.SecretTypeOpaque,
+		}
+		if err := controllerutil.SetControllerReference(torrent, secret, r.Scheme); err != nil {
+			return err
+		}
+	}
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte, 1)
+	}
+	secret.Data[ExportedTorrentKey] = data
+
+	logger.Info("Exporting Torrent file to Secret", "Name", torrent.Name, "secret", name, "bytes", len(data))
+	if exists {
+		return r.Update(ctx, secret)
+	}
+	return r.Create(ctx, secret)
+}
+
+// Move the torrent one position towards an integer spec.priority, or apply a relative move
+// (top, bottom, up, down) once per spec generation
+func (r *TorrentReconciler) applyPriority(ctx context.Context, qbtClient qbittorrent.QBTClient, torrent *torrentv1alpha1.Torrent, info *qbittorrent.TorrentInfo) error {
+	logger := log.FromContext(ctx)
+
+	// qBittorrent reports no queue position when queueing is disabled or the torrent seeds
+	if info.Priority <= 0 {
+		logger.V(1).Info("Torrent is not queued, skipping priority", "Name", torrent.Name)
+		return nil
+	}
+
+	hashes := []string{info.Hash}
+	priority := torrent.Spec.Priority
+	if priority.Type == intstr.Int {
+		target := priority.IntValue()
+		switch {
+		case info.Priority > target:
+			logger.Info("Moving Torrent up in the queue", "Name", torrent.Name, "position", info.Priority, "target", target)
+			return qbtClient.IncreasePriority(ctx, hashes)
+		case info.Priority < target:
+			logger.Info("Moving Torrent down in the queue", "Name", torrent.Name, "position", info.Priority, "target", target)
+			return qbtClient.DecreasePriority(ctx, hashes)
+		}
+		return nil
+	}
+
+	if torrent.Status.PriorityGeneration == torrent.Generation {
+		return nil
+	}
+	logger.Info("Moving Torrent in the queue", "Name", torrent.Name, "priority", priority.StrVal)
+	var err error
+	switch priority.StrVal {
+	case "top":
+		err = qbtClient.TopPriority(ctx, hashes)
+	case "bottom":
+		err = qbtClient.BottomPriority(ctx, hashes)
+	case "up":
+		err = qbtClient.IncreasePriority(ctx, hashes)
+	case "down":
+		err = qbtClient.DecreasePriority(ctx, hashes)
+	default:
+		return fmt.Errorf("unknown priority %q", priority.StrVal)
+	}
+	if err != nil {
+		return err
+	}
+	torrent.Status.PriorityGeneration = torrent.Generation
+	return nil
+}
+
+// Compute the file renames to apply. The first matching rule wins for each file.
+// Renames targeting an existing file, or two files targeting the same path, are rejected
+// as a whole so that no file gets overwritten
+func planFileRenames(files []qbittorrent.TorrentFile, fileRenames []torrentv1alpha1.FileRename) ([]torrentv1alpha1.AppliedFileRename, error) {
+	existing := make(map[string]bool, len(files))
+	for _, file := range files {
+		existing[file.Name] = true
+	}
+
+	targets := make(map[string]string)
+	var renames []torrentv1alpha1.AppliedFileRename
+	for _, file := range files {
+		for _, fileRename := range fileRenames {
+			matched, err := qbittorrent.MatchFilePath(fileRename.Match, file.Name)
+			if err != nil {
+				return nil, fmt.Errorf("invalid file rename pattern %q: %w", fileRename.Match, err)
+			}
+			if !matched {
+				continue
+			}
+
+			target := fileRename.Rename
+			if !strings.Contains(target, "/") {
+				target = path.Join(path.Dir(file.Name), target)
+			}
+			if target == file.Name {
+				break
+			}
+			if existing[target] {
+				return nil, fmt.Errorf("renaming %q to %q collides with an existing file", file.Name, target)
+			}
+			if source, ok := targets[target]; ok {
+				return nil, fmt.Errorf("renaming %q and %q to the same path %q", source, file.Name, target)
+			}
+			targets[target] = file.Name
+			renames = append(renames, torrentv1alpha1.AppliedFileRename{From: file.Name, To: target})
+			break
+		}
+	}
+	return renames, nil
+}
+
+// maxLogExcerptLength keeps log excerpts well below the event message size limit
+const maxLogExcerptLength = 1024
+
+// Return the reason of the Degraded condition, or an empty string when the Torrent is not Degraded
+func degradedReason(torrent *torrentv1alpha1.Torrent) string {
+	if condition := meta.FindStatusCondition(torrent.Status.Conditions, TypeDegradedTorrent); condition != nil {
+		return condition.Reason
+	}
+	return ""
+}
+
+// Emit the last qBittorrent warning and critical log lines when the Torrent became Degraded,
+// or changed Degraded reason, since previousReason was observed
+func (r *TorrentReconciler) recordLogExcerpt(ctx context.Context, qbtClient qbittorrent.QBTClient, torrent *torrentv1alpha1.Torrent, previousReason string) {
+	reason := degradedReason(torrent)
+	if reason == "" || reason == previousReason || r.Recorder == nil {
+		return
+	}
+
+	logger := log.FromContext(ctx)
+	entries, err := qbtClient.GetLog(ctx, qbittorrent.LogOptions{Warning: true, Critical: true, LastKnownID: -1})
+	if err != nil {
+		logger.Error(err, "Failed to fetch the qBittorrent log excerpt", "reason", reason)
+		return
+	}
+	if len(entries) == 0 {
+		return
+	}
+	r.Recorder.Event(torrent, corev1.EventTypeWarning, "QBittorrentLog", logExcerpt(entries, r.LogExcerptLines))
+}
+
+// Format the last lines of the log, keeping the most recent ones when the excerpt is too long
+func logExcerpt(entries []qbittorrent.LogEntry, lines int) string {
+	if len(entries) > lines {
+		entries = entries[len(entries)-lines:]
+	}
+	formatted := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		level := "warning"
+		if entry.Type == qbittorrent.LogTypeCritical {
+			level = "critical"
+		}
+		formatted = append(formatted, fmt.Sprintf("[%s] %s", level, entry.Message))
+	}
+
+	excerpt := strings.Join(formatted, "\n")
+	if len(excerpt) <= maxLogExcerptLength {
+		return excerpt
+	}
+	start := len(excerpt) - maxLogExcerptLength + len("...")
+	for start < len(excerpt) && !utf8.RuneStart(excerpt[start]) {
+		start++
+	}
+	return "..." + excerpt[start:]
+}
+
+func (r *TorrentReconciler) setDegradedCondition(torrent *torrentv1alpha1.Torrent, reason, message string) {
+	condition := metav1.Condition{
+		Type:               TypeDegradedTorrent,
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: metav1.NewTime(time.Now()),
+	}
+	meta.SetStatusCondition(&torrent.Status.Conditions, condition)
+	meta.RemoveStatusCondition(&torrent.Status.Conditions, TypeAvailableTorrent)
+}
+
+func (r *TorrentReconciler) setAvailableCondition(torrent *torrentv1alpha1.Torrent, reason, message string) {
+	condition := metav1.Condition{
 		Type:               TypeAvailableTorrent,
 		Status:             metav1.ConditionTrue,
 		Reason:             reason,
@@ -259,16 +1474,73 @@ func (r *TorrentReconciler) setAvailableCondition(torrent *torrentv1alpha1.Torre
 	meta.RemoveStatusCondition(&torrent.Status.Conditions, TypeDegradedTorrent)
 }
 
+// Progress towards spec.readyWhen is expected rather than a failure: report not Available without Degraded
+func (r *TorrentReconciler) setWaitingCondition(torrent *torrentv1alpha1.Torrent, reason, message string) {
+	condition := metav1.Condition{
+		Type:               TypeAvailableTorrent,
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: metav1.NewTime(time.Now()),
+	}
+	meta.SetStatusCondition(&torrent.Status.Conditions, condition)
+	meta.RemoveStatusCondition(&torrent.Status.Conditions, TypeDegradedTorrent)
+}
+
+// waitingReasons are the Available reasons of a torrent that did not reach spec.readyWhen yet
+var waitingReasons = map[torrentv1alpha1.ReadyWhen]string{
+	torrentv1alpha1.ReadyWhenDownloaded: "WaitingForDownload",
+	torrentv1alpha1.ReadyWhenSeeding:    "WaitingForSeeding",
+}
+
+// seedingStates are the qBittorrent states of a completed torrent that is seeding.
+// Queued and stopped torrents are not seeding.
+var seedingStates = map[string]bool{
+	"uploading": true,
+	"stalledUP": true,
+	"forcedUP":  true,
+}
+
+func readyWhen(torrent *torrentv1alpha1.Torrent) torrentv1alpha1.ReadyWhen {
+	if torrent.Spec.ReadyWhen == "" {
+		return torrentv1alpha1.ReadyWhenAdded
+	}
+	return torrent.Spec.ReadyWhen
+}
+
+// Report whether the torrent reached the progress requested by spec.readyWhen, describing it otherwise
+func torrentReady(torrent *torrentv1alpha1.Torrent, info *qbittorrent.TorrentInfo) (bool, string) {
+	switch readyWhen(torrent) {
+	case torrentv1alpha1.ReadyWhenDownloaded:
+		if hasMetadata(info) && info.Progress >= 1 {
+			return true, ""
+		}
+		return false, fmt.Sprintf("Torrent is %.1f%% downloaded (%s), waiting until downloaded", info.Progress*100, info.State)
+	case torrentv1alpha1.ReadyWhenSeeding:
+		if seedingStates[info.State] {
+			return true, ""
+		}
+		return false, fmt.Sprintf("Torrent is %s, waiting until seeding", info.State)
+	}
+	return true, ""
+}
+
 func (r *TorrentReconciler) updateTorrentStatus(ctx context.Context, torrent *torrentv1alpha1.Torrent, qbTorrent *qbittorrent.TorrentInfo) bool {
 	logger := log.FromContext(ctx)
 	updated := false
 
-	if torrent.Status.Hash != qbTorrent.Hash {
-		torrent.Status.Hash = qbTorrent.Hash
+	// qBittorrent may list transient entries without a hash, name or size until the metadata
+	// resolves: keep the recorded values, the finalizer needs the hash to delete the torrent
+	metadataReady := hasMetadata(qbTorrent)
+
+	if hash := qbittorrent.NormalizeTorrentHash(qbTorrent.Hash); hash == "" && torrent.Status.Hash != "" {
+		logger.V(1).Info("Ignoring empty hash reported by qBittorrent", "hash", torrent.Status.Hash)
+	} else if torrent.Status.Hash != hash {
+		torrent.Status.Hash = hash
 		updated = true
 	}
 
-	if torrent.Status.Name != qbTorrent.Name {
+	if (metadataReady || qbTorrent.Name != "") && torrent.Status.Name != qbTorrent.Name {
 		torrent.Status.Name = qbTorrent.Name
 		updated = true
 	}
@@ -281,12 +1553,12 @@ func (r *TorrentReconciler) updateTorrentStatus(ctx context.Context, torrent *to
 		updated = true
 	}
 
-	if torrent.Status.TotalSize != qbTorrent.TotalSize {
+	if (metadataReady || qbTorrent.TotalSize != 0) && torrent.Status.TotalSize != qbTorrent.TotalSize {
 		torrent.Status.TotalSize = qbTorrent.TotalSize
 		updated = true
 	}
 
-	if torrent.Status.ContentPath != qbTorrent.ContentPath {
+	if (metadataReady || qbTorrent.ContentPath != "") && torrent.Status.ContentPath != qbTorrent.ContentPath {
 		torrent.Status.ContentPath = qbTorrent.ContentPath
 		updated = true
 	}
@@ -301,18 +1573,156 @@ func (r *TorrentReconciler) updateTorrentStatus(ctx context.Context, torrent *to
 		updated = true
 	}
 
-	if torrent.Status.AmountLeft != qbTorrent.AmountLeft {
+	if (metadataReady || qbTorrent.AmountLeft != 0) && torrent.Status.AmountLeft != qbTorrent.AmountLeft {
 		torrent.Status.AmountLeft = qbTorrent.AmountLeft
 		updated = true
 	}
 
+	// Pre-formatted values shown by kubectl printcolumns, derived from the values kept above
+	if value := formatBytes(torrent.Status.TotalSize); torrent.Status.TotalSizeHuman != value {
+		torrent.Status.TotalSizeHuman = value
+		updated = true
+	}
+
+	if value := formatBytes(torrent.Status.AmountLeft); torrent.Status.AmountLeftHuman != value {
+		torrent.Status.AmountLeftHuman = value
+		updated = true
+	}
+
+	if value := formatProgress(qbTorrent.Progress); torrent.Status.Progress != value {
+		torrent.Status.Progress = value
+		updated = true
+	}
+
+	if value := formatBytes(qbTorrent.DlSpeed) + "/s"; torrent.Status.DownloadSpeed != value {
+		torrent.Status.DownloadSpeed = value
+		updated = true
+	}
+
+	if value := formatRatio(qbTorrent.Ratio); torrent.Status.Ratio != value {
+		torrent.Status.Ratio = value
+		updated = true
+	}
+
+	if value := int32(max(qbTorrent.Priority, 0)); torrent.Status.QueuePosition != value {
+		torrent.Status.QueuePosition = value
+		updated = true
+	}
+
 	if updated {
-		logger.V(1).Info("Status fields updated", "hash", qbTorrent.Hash)
+		logger.V(1).Info("Status fields updated", "hash", torrent.Status.Hash)
 	}
 
 	return updated
 }
 
+// Copy the swarm availability and cumulative transfer totals into the status
+func (r *TorrentReconciler) updateTorrentProperties(torrent *torrentv1alpha1.Torrent, info *qbittorrent.TorrentInfo, props *qbittorrent.TorrentProperties) bool {
+	updated := false
+
+	if value := formatRatio(props.Availability); torrent.Status.Availability != value {
+		torrent.Status.Availability = value
+		updated = true
+	}
+
+	if value := int32(props.Peers); torrent.Status.Peers != value {
+		torrent.Status.Peers = value
+		updated = true
+	}
+
+	if value := int32(props.Seeds); torrent.Status.Seeds != value {
+		torrent.Status.Seeds = value
+		updated = true
+	}
+
+	if value := accumulateCounter(torrent.Status.TotalDownloaded, torrent.Status.LastSeenDownloaded, props.TotalDownloaded); torrent.Status.TotalDownloaded != value {
+		torrent.Status.TotalDownloaded = value
+		updated = true
+	}
+	if last := torrent.Status.LastSeenDownloaded; last == nil || *last != props.TotalDownloaded {
+		torrent.Status.LastSeenDownloaded = &props.TotalDownloaded
+		updated = true
+	}
+
+	if value := accumulateCounter(torrent.Status.TotalUploaded, torrent.Status.LastSeenUploaded, props.TotalUploaded); torrent.Status.TotalUploaded != value {
+		torrent.Status.TotalUploaded = value
+		updated = true
+	}
+	if last := torrent.Status.LastSeenUploaded; last == nil || *last != props.TotalUploaded {
+		torrent.Status.LastSeenUploaded = &props.TotalUploaded
+		updated = true
+	}
+
+	if value := int32(props.ConnectionsLimit); torrent.Status.ConnectionsLimit != value {
+		torrent.Status.ConnectionsLimit = value
+		updated = true
+	}
+
+	// The provenance fields are part of the metadata and never change: record them once
+	if hasMetadata(info) && torrent.Status.Comment == "" && torrent.Status.CreatedBy == "" &&
+		(props.Comment != "" || props.CreatedBy != "") {
+		torrent.Status.Comment = props.Comment
+		torrent.Status.CreatedBy = props.CreatedBy
+		updated = true
+	}
+
+	return updated
+}
+
+// accumulateCounter adds the growth of a qBittorrent transfer counter since lastSeen to total.
+// A counter below lastSeen was reset, e.g. when the torrent was added again, so all of it is new
+func accumulateCounter(total int64, lastSeen *int64, current int64) int64 {
+	switch {
+	case lastSeen == nil:
+		// First reading, or a total recorded before the counters were tracked
+		return max(total, current)
+	case current >= *lastSeen:
+		return total + current - *lastSeen
+	default:
+		return total + current
+	}
+}
+
+// Torrent states in which qBittorrent stopped the torrent because of an error
+var erroredTorrentStates = map[string]string{
+	"error":        "qBittorrent reports an error for the torrent",
+	"missingFiles": "qBittorrent cannot find the torrent files",
+}
+
+// Set status.message from the errored state of the torrent and the latest qBittorrent
+// warning or critical log line naming it; the log is only fetched while the torrent is errored
+func (r *TorrentReconciler) updateTorrentMessage(ctx context.Context, qbtClient qbittorrent.QBTClient, torrent *torrentv1alpha1.Torrent, info *qbittorrent.TorrentInfo) bool {
+	message, errored := erroredTorrentStates[info.State]
+	if errored {
+		entries, err := qbtClient.GetLog(ctx, qbittorrent.LogOptions{Warning: true, Critical: true, LastKnownID: -1})
+		if err != nil {
+			log.FromContext(ctx).Error(err, "Failed to fetch the qBittorrent log for the torrent error", "hash", info.Hash)
+		} else if entry := lastLogEntryFor(entries, info.Name); entry != "" {
+			message = fmt.Sprintf("%s: %s", message, entry)
+		}
+	}
+
+	if torrent.Status.Message == message {
+		return false
+	}
+	torrent.Status.Message = message
+	return true
+}
+
+// Return the most recent log message naming the torrent, as quoted by qBittorrent, or an empty string
+func lastLogEntryFor(entries []qbittorrent.LogEntry, name string) string {
+	if name == "" {
+		return ""
+	}
+	quoted := `"` + name + `"`
+	for i := len(entries) - 1; i >= 0; i-- {
+		if strings.Contains(entries[i].Message, quoted) {
+			return entries[i].Message
+		}
+	}
+	return ""
+}
+
 func (r *TorrentReconciler) findTorrentsForTCC(ctx context.Context, obj client.Object) []reconcile.Request {
 	logger := log.FromContext(ctx)
 	tcc, ok := obj.(*torrentv1alpha1.TorrentClientConfiguration)
@@ -330,7 +1740,9 @@ func (r *TorrentReconciler) findTorrentsForTCC(ctx context.Context, obj client.O
 	for _, torrent := range torrentList.Items {
 		// Requeue reconciliation request for torrents using the updated TCC
 		if (torrent.Spec.ClientConfigRef != nil && torrent.Spec.ClientConfigRef.Name == tcc.Name) ||
-			(torrent.Spec.ClientConfigRef == nil && torrent.Status.ClientConfigurationName == tcc.Name) {
+			(torrent.Spec.ClientConfigRef == nil && torrent.Status.ClientConfigurationName == tcc.Name) ||
+			(torrent.Spec.ClientConfigRef == nil && len(torrent.Spec.Selector) > 0 &&
+				labels.SelectorFromSet(torrent.Spec.Selector).Matches(labels.Set(tcc.Labels))) {
 			requests = append(requests, reconcile.Request{
 				NamespacedName: types.NamespacedName{
 					Name:      torrent.Name,
@@ -2,14 +2,22 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	torrentv1alpha1 "github.com/guidonguido/qbittorrent-operator/api/v1alpha1"
@@ -100,6 +108,122 @@ var _ = Describe("Torrent Controller", func() {
 		})
 	})
 
+	Context("When a TCC is excluded from auto-discovery", func() {
+		const resourceName = "test-torrent-auto-discover"
+		const namespace = "auto-discover"
+		const hash = "dd8255ecdc7ca55fb0bbf81323d87062db1f6d1c"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: namespace,
+		}
+
+		var fake *fakeQBTClient
+		var controllerReconciler *TorrentReconciler
+
+		// createTCC creates an Available TCC in the test namespace, with its credentials Secret
+		createTCC := func(name string, tccLabels map[string]string) {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: name + "-creds", Namespace: namespace},
+				Data: map[string][]byte{
+					"username": []byte("admin"),
+					"password": []byte("password"),
+				},
+			}
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: tccLabels},
+				Spec: torrentv1alpha1.TorrentClientConfigurationSpec{
+					URL:               "http://" + name + ":8080",
+					CredentialsSecret: &torrentv1alpha1.SecretReference{Name: secret.Name},
+				},
+			}
+			Expect(k8sClient.Create(ctx, tcc)).To(Succeed())
+			meta.SetStatusCondition(&tcc.Status.Conditions, metav1.Condition{
+				Type:   TypeAvailableTCC,
+				Status: metav1.ConditionTrue,
+				Reason: "Connected",
+			})
+			Expect(k8sClient.Status().Update(ctx, tcc)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, tcc)).To(Succeed())
+				Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+			})
+		}
+
+		reconcileOnce := func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			fake = newFakeQBTClient()
+			controllerReconciler = &TorrentReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+
+			resource := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       resourceName,
+					Namespace:  namespace,
+					Finalizers: []string{TorrentFinalizer},
+				},
+				Spec: torrentv1alpha1.TorrentSpec{
+					MagnetURI: "magnet:?xt=urn:btih:" + hash + "&dn=Big+Buck+Bunny",
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			deleteTorrent(ctx, typeNamespacedName)
+		})
+
+		It("should auto-discover the only TCC not opted out", func() {
+			createTCC("test-tcc-production", nil)
+			createTCC("test-tcc-testing", map[string]string{AutoDiscoverLabel: "false"})
+
+			reconcileOnce()
+			Expect(fake.Calls()).To(ContainElement(HavePrefix("AddTorrent:")))
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(torrent.Status.ClientConfigurationName).To(Equal("test-tcc-production"))
+		})
+
+		It("should report the excluded TCCs when none is left to auto-discover", func() {
+			createTCC("test-tcc-testing", map[string]string{AutoDiscoverLabel: "false"})
+
+			reconcileOnce()
+			Expect(fake.Calls()).NotTo(ContainElement(HavePrefix("AddTorrent:")))
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			degraded := meta.FindStatusCondition(torrent.Status.Conditions, TypeDegradedTorrent)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal("ClientResolutionFailed"))
+			Expect(degraded.Message).To(ContainSubstring("1 excluded by " + AutoDiscoverLabel + "=false"))
+		})
+
+		It("should still use an excluded TCC referenced explicitly", func() {
+			createTCC("test-tcc-testing", map[string]string{AutoDiscoverLabel: "false"})
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			torrent.Spec.ClientConfigRef = &torrentv1alpha1.LocalObjectReference{Name: "test-tcc-testing"}
+			Expect(k8sClient.Update(ctx, torrent)).To(Succeed())
+
+			reconcileOnce()
+			Expect(fake.Calls()).To(ContainElement(HavePrefix("AddTorrent:")))
+		})
+	})
+
 	Context("When an explicit clientConfigRef references a non-existent TCC", func() {
 		const resourceName = "test-torrent-bad-ref"
 
@@ -209,7 +333,7 @@ var _ = Describe("Torrent Controller", func() {
 					},
 					Spec: torrentv1alpha1.TorrentClientConfigurationSpec{
 						URL: "http://qbittorrent:8080",
-						CredentialsSecret: torrentv1alpha1.SecretReference{
+						CredentialsSecret: &torrentv1alpha1.SecretReference{
 							Name: secretName,
 						},
 					},
@@ -306,7 +430,7 @@ var _ = Describe("Torrent Controller", func() {
 						},
 						Spec: torrentv1alpha1.TorrentClientConfigurationSpec{
 							URL: "http://qbittorrent:8080",
-							CredentialsSecret: torrentv1alpha1.SecretReference{
+							CredentialsSecret: &torrentv1alpha1.SecretReference{
 								Name: "some-secret",
 							},
 						},
@@ -374,4 +498,3025 @@ var _ = Describe("Torrent Controller", func() {
 			Expect(torrent.Status.Conditions[0].Message).To(ContainSubstring("multiple"))
 		})
 	})
+
+	Context("When a selector is set", func() {
+		const resourceName = "test-torrent-selector"
+		const hash = "dd8255ecdc7ca55fb0bbf81323d87062db1f6d1c"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		tccs := map[string]map[string]string{
+			"test-tcc-selector-public":  {"role": "public", "site": "home"},
+			"test-tcc-selector-private": {"role": "private-tracker", "site": "home"},
+		}
+
+		var fake *fakeQBTClient
+		var controllerReconciler *TorrentReconciler
+
+		BeforeEach(func() {
+			for name, tccLabels := range tccs {
+				createAvailableTCC(ctx, name, name+"-creds")
+				tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, tcc)).To(Succeed())
+				tcc.Labels = tccLabels
+				Expect(k8sClient.Update(ctx, tcc)).To(Succeed())
+			}
+
+			fake = newFakeQBTClient()
+			controllerReconciler = &TorrentReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+		})
+
+		AfterEach(func() {
+			deleteTorrent(ctx, typeNamespacedName)
+			for name := range tccs {
+				deleteTCC(ctx, name, name+"-creds")
+			}
+		})
+
+		createTorrent := func(selector map[string]string) {
+			resource := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       resourceName,
+					Namespace:  "default",
+					Finalizers: []string{TorrentFinalizer},
+				},
+				Spec: torrentv1alpha1.TorrentSpec{
+					MagnetURI: "magnet:?xt=urn:btih:" + hash + "&dn=Big+Buck+Bunny",
+					Selector:  selector,
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		}
+
+		It("should select the single TCC whose labels match", func() {
+			createTorrent(map[string]string{"role": "private-tracker"})
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(torrent.Status.ClientConfigurationName).To(Equal("test-tcc-selector-private"))
+			Expect(fake.Calls()).To(ContainElement(ContainSubstring("AddTorrent:")))
+		})
+
+		It("should set AmbiguousClientConfiguration when several TCCs match", func() {
+			createTorrent(map[string]string{"site": "home"})
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			degraded := meta.FindStatusCondition(torrent.Status.Conditions, TypeDegradedTorrent)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal("AmbiguousClientConfiguration"))
+			Expect(degraded.Message).To(ContainSubstring("test-tcc-selector-private, test-tcc-selector-public"))
+			Expect(fake.Calls()).NotTo(ContainElement(ContainSubstring("AddTorrent:")))
+		})
+
+		It("should set ClientResolutionFailed when no TCC matches", func() {
+			createTorrent(map[string]string{"role": "archive"})
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			degraded := meta.FindStatusCondition(torrent.Status.Conditions, TypeDegradedTorrent)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal("ClientResolutionFailed"))
+			Expect(degraded.Message).To(ContainSubstring("role=archive"))
+		})
+	})
+
+	Context("When magnets differ only in hash casing", func() {
+		const resourceName = "test-torrent-hash-casing"
+		const tccName = "test-tcc-hash-casing"
+		const secretName = "test-tcc-hash-casing-creds"
+		const hash = "dd8255ecdc7ca55fb0bbf81323d87062db1f6d1c"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var fake *fakeQBTClient
+		var controllerReconciler *TorrentReconciler
+
+		createTorrent := func(magnetURI string) *torrentv1alpha1.Torrent {
+			resource := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       resourceName,
+					Namespace:  "default",
+					Finalizers: []string{TorrentFinalizer},
+				},
+				Spec: torrentv1alpha1.TorrentSpec{
+					MagnetURI:       magnetURI,
+					ClientConfigRef: &torrentv1alpha1.LocalObjectReference{Name: tccName},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			return resource
+		}
+
+		reconcileOnce := func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, tccName, secretName)
+
+			fake = newFakeQBTClient()
+			controllerReconciler = &TorrentReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+		})
+
+		AfterEach(func() {
+			deleteTorrent(ctx, typeNamespacedName)
+			deleteTCC(ctx, tccName, secretName)
+		})
+
+		It("should resolve uppercase and lowercase magnets to the same status.hash", func() {
+			for _, magnetURI := range []string{
+				"magnet:?xt=urn:btih:" + strings.ToUpper(hash) + "&dn=Big+Buck+Bunny",
+				"magnet:?xt=urn:btih:" + hash + "&tr=udp%3A%2F%2Ftracker.example.org%3A1337",
+			} {
+				createTorrent(magnetURI)
+				reconcileOnce()
+				reconcileOnce()
+
+				torrent := &torrentv1alpha1.Torrent{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+				Expect(torrent.Status.Hash).To(Equal(hash))
+
+				deleteTorrent(ctx, typeNamespacedName)
+			}
+		})
+
+		It("should delete by the lowercase hash when status carries an uppercase one", func() {
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny"})
+			resource := createTorrent("magnet:?xt=urn:btih:" + hash)
+			resource.Status.Hash = strings.ToUpper(hash)
+			Expect(k8sClient.Status().Update(ctx, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+
+			reconcileOnce()
+			Expect(fake.Calls()).To(ContainElement("DeleteTorrent:" + hash + ":true"))
+		})
+	})
+
+	Context("When spec.adopt is set", func() {
+		const resourceName = "test-torrent-adopt"
+		const tccName = "test-tcc-adopt"
+		const secretName = "test-tcc-adopt-creds"
+		const hash = "dd8255ecdc7ca55fb0bbf81323d87062db1f6d1c"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var fake *fakeQBTClient
+		var controllerReconciler *TorrentReconciler
+
+		createTorrent := func() {
+			adopt := true
+			resource := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       resourceName,
+					Namespace:  "default",
+					Finalizers: []string{TorrentFinalizer},
+				},
+				Spec: torrentv1alpha1.TorrentSpec{
+					Adopt:           &adopt,
+					Hash:            strings.ToUpper(hash),
+					OnDelete:        torrentv1alpha1.DeletePolicyOrphan,
+					ClientConfigRef: &torrentv1alpha1.LocalObjectReference{Name: tccName},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		}
+
+		reconcileOnce := func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, tccName, secretName)
+
+			fake = newFakeQBTClient()
+			controllerReconciler = &TorrentReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+		})
+
+		AfterEach(func() {
+			deleteTorrent(ctx, typeNamespacedName)
+			deleteTCC(ctx, tccName, secretName)
+		})
+
+		It("should manage an existing torrent found by hash without adding it", func() {
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", State: "uploading", Progress: 1})
+			createTorrent()
+			reconcileOnce()
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(torrent.Status.Hash).To(Equal(hash))
+			Expect(torrent.Status.Name).To(Equal("Big Buck Bunny"))
+			Expect(fake.Calls()).NotTo(ContainElement(ContainSubstring("AddTorrent:")))
+
+			Expect(k8sClient.Delete(ctx, torrent)).To(Succeed())
+			reconcileOnce()
+			Expect(fake.Calls()).NotTo(ContainElement(ContainSubstring("DeleteTorrent:")))
+		})
+
+		It("should report a missing adopted torrent as Degraded and never add it", func() {
+			createTorrent()
+			reconcileOnce()
+			reconcileOnce()
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			degraded := meta.FindStatusCondition(torrent.Status.Conditions, TypeDegradedTorrent)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal("AdoptedTorrentNotFound"))
+			Expect(fake.Calls()).NotTo(ContainElement(ContainSubstring("AddTorrent:")))
+		})
+
+		It("should leave an adopted torrent in qBittorrent on deletion unless onDelete is set", func() {
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", State: "uploading", Progress: 1})
+			adopt := true
+			resource := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       resourceName,
+					Namespace:  "default",
+					Finalizers: []string{TorrentFinalizer},
+				},
+				Spec: torrentv1alpha1.TorrentSpec{
+					Adopt:           &adopt,
+					Hash:            hash,
+					ClientConfigRef: &torrentv1alpha1.LocalObjectReference{Name: tccName},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			reconcileOnce()
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(torrent.Status.Hash).To(Equal(hash))
+			Expect(deletePolicy(torrent)).To(Equal(torrentv1alpha1.DeletePolicyOrphan))
+
+			Expect(k8sClient.Delete(ctx, torrent)).To(Succeed())
+			reconcileOnce()
+			Expect(fake.Calls()).NotTo(ContainElement(HavePrefix("DeleteTorrent:")))
+		})
+
+		It("should let only one of two Torrents adopting the same hash proceed", func() {
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", State: "uploading", Progress: 1})
+			createTorrent()
+
+			adopt := true
+			secondNamespacedName := types.NamespacedName{Name: resourceName + "-second", Namespace: "default"}
+			second := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       secondNamespacedName.Name,
+					Namespace:  "default",
+					Finalizers: []string{TorrentFinalizer},
+				},
+				Spec: torrentv1alpha1.TorrentSpec{
+					Adopt:           &adopt,
+					Hash:            hash,
+					OnDelete:        torrentv1alpha1.DeletePolicyOrphan,
+					ClientConfigRef: &torrentv1alpha1.LocalObjectReference{Name: tccName},
+				},
+			}
+			Expect(k8sClient.Create(ctx, second)).To(Succeed())
+			defer deleteTorrent(ctx, secondNamespacedName)
+
+			By("reconciling the newer Torrent before the older one has recorded the hash in status")
+			calls := len(fake.Calls())
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: secondNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fake.Calls()[calls:]).To(HaveEach(HavePrefix("Login:")))
+
+			Expect(k8sClient.Get(ctx, secondNamespacedName, second)).To(Succeed())
+			Expect(second.Status.Hash).To(BeEmpty())
+			degraded := meta.FindStatusCondition(second.Status.Conditions, TypeDegradedTorrent)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal("DuplicateHash"))
+			Expect(degraded.Message).To(ContainSubstring(resourceName))
+
+			By("letting the older Torrent adopt the hash")
+			reconcileOnce()
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(torrent.Status.Hash).To(Equal(hash))
+			Expect(meta.FindStatusCondition(torrent.Status.Conditions, TypeDegradedTorrent)).To(BeNil())
+		})
+	})
+
+	Context("When add options are set", func() {
+		const resourceName = "test-torrent-add-options"
+		const tccName = "test-tcc-add-options"
+		const secretName = "test-tcc-add-options-creds"
+		const hash = "dd8255ecdc7ca55fb0bbf81323d87062db1f6d1c"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var fake *fakeQBTClient
+		var controllerReconciler *TorrentReconciler
+
+		createTorrent := func(mutate func(spec *torrentv1alpha1.TorrentSpec)) {
+			resource := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       resourceName,
+					Namespace:  "default",
+					Finalizers: []string{TorrentFinalizer},
+				},
+				Spec: torrentv1alpha1.TorrentSpec{
+					MagnetURI:       "magnet:?xt=urn:btih:" + hash + "&dn=Big+Buck+Bunny",
+					ClientConfigRef: &torrentv1alpha1.LocalObjectReference{Name: tccName},
+				},
+			}
+			mutate(&resource.Spec)
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		}
+
+		reconcileOnce := func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fake.Calls()).To(ContainElement(ContainSubstring("AddTorrent:")))
+		}
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, tccName, secretName)
+
+			fake = newFakeQBTClient()
+			controllerReconciler = &TorrentReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+		})
+
+		AfterEach(func() {
+			deleteTorrent(ctx, typeNamespacedName)
+			deleteTCC(ctx, tccName, secretName)
+		})
+
+		It("should ask qBittorrent to skip the recheck", func() {
+			skip := true
+			createTorrent(func(spec *torrentv1alpha1.TorrentSpec) { spec.SkipHashCheck = &skip })
+
+			reconcileOnce()
+			Expect(fake.AddOptions(hash).SkipChecking).To(BeTrue())
+		})
+
+		It("should recheck by default", func() {
+			createTorrent(func(*torrentv1alpha1.TorrentSpec) {})
+
+			reconcileOnce()
+			Expect(fake.AddOptions(hash).SkipChecking).To(BeFalse())
+		})
+
+		It("should forward the content layout", func() {
+			createTorrent(func(spec *torrentv1alpha1.TorrentSpec) {
+				spec.ContentLayout = torrentv1alpha1.ContentLayoutSubfolder
+			})
+
+			reconcileOnce()
+			Expect(fake.AddOptions(hash).ContentLayout).To(Equal("Subfolder"))
+		})
+
+		It("should create a missing category with the TCC save path before adding the torrent", func() {
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: tccName, Namespace: "default"}, tcc)).To(Succeed())
+			tcc.Spec.Categories = []torrentv1alpha1.CategorySpec{{Name: "movies", SavePath: "/downloads/movies"}}
+			Expect(k8sClient.Update(ctx, tcc)).To(Succeed())
+
+			create := true
+			createTorrent(func(spec *torrentv1alpha1.TorrentSpec) {
+				spec.Category = "movies"
+				spec.CreateCategoryIfMissing = &create
+			})
+
+			reconcileOnce()
+			Expect(fake.Calls()).To(ContainElements(
+				"GetCategories",
+				"CreateCategory:movies:/downloads/movies",
+				ContainSubstring("AddTorrent:"),
+			))
+			calls := fake.Calls()
+			createdAt := slices.Index(calls, "CreateCategory:movies:/downloads/movies")
+			addedAt := slices.IndexFunc(calls, func(call string) bool { return strings.HasPrefix(call, "AddTorrent:") })
+			Expect(createdAt).To(BeNumerically("<", addedAt))
+			Expect(fake.AddOptions(hash).Category).To(Equal("movies"))
+		})
+
+		It("should create a missing category with spec.categorySavePath before adding the torrent", func() {
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: tccName, Namespace: "default"}, tcc)).To(Succeed())
+			tcc.Spec.Categories = []torrentv1alpha1.CategorySpec{{Name: "tv", SavePath: "/downloads/series"}}
+			Expect(k8sClient.Update(ctx, tcc)).To(Succeed())
+
+			create := true
+			createTorrent(func(spec *torrentv1alpha1.TorrentSpec) {
+				spec.Category = "tv"
+				spec.CreateCategoryIfMissing = &create
+				spec.CategorySavePath = "/downloads/tv"
+			})
+
+			reconcileOnce()
+			calls := fake.Calls()
+			createdAt := slices.Index(calls, "CreateCategory:tv:/downloads/tv")
+			addedAt := slices.IndexFunc(calls, func(call string) bool { return strings.HasPrefix(call, "AddTorrent:") })
+			Expect(createdAt).NotTo(Equal(-1))
+			Expect(createdAt).To(BeNumerically("<", addedAt))
+			Expect(fake.AddOptions(hash).Category).To(Equal("tv"))
+			savePath, _ := fake.CategoryPath("tv")
+			Expect(savePath).To(Equal("/downloads/tv"))
+		})
+
+		It("should add the torrent to an existing category without creating it", func() {
+			fake.SetCategory("movies", "/mnt/movies")
+			create := true
+			createTorrent(func(spec *torrentv1alpha1.TorrentSpec) {
+				spec.Category = "movies"
+				spec.CreateCategoryIfMissing = &create
+			})
+
+			reconcileOnce()
+			Expect(fake.Calls()).NotTo(ContainElement(HavePrefix("CreateCategory:")))
+			Expect(fake.AddOptions(hash).Category).To(Equal("movies"))
+			savePath, _ := fake.CategoryPath("movies")
+			Expect(savePath).To(Equal("/mnt/movies"))
+		})
+
+		It("should not edit an existing category to spec.categorySavePath", func() {
+			fake.SetCategory("movies", "/mnt/movies")
+			create := true
+			createTorrent(func(spec *torrentv1alpha1.TorrentSpec) {
+				spec.Category = "movies"
+				spec.CreateCategoryIfMissing = &create
+				spec.CategorySavePath = "/downloads/movies"
+			})
+
+			reconcileOnce()
+			Expect(fake.Calls()).NotTo(ContainElement(HavePrefix("CreateCategory:")))
+			Expect(fake.Calls()).NotTo(ContainElement(HavePrefix("EditCategory:")))
+			Expect(fake.AddOptions(hash).Category).To(Equal("movies"))
+			savePath, _ := fake.CategoryPath("movies")
+			Expect(savePath).To(Equal("/mnt/movies"))
+		})
+
+		It("should not look up the category unless createCategoryIfMissing is set", func() {
+			createTorrent(func(spec *torrentv1alpha1.TorrentSpec) { spec.Category = "movies" })
+
+			reconcileOnce()
+			Expect(fake.Calls()).NotTo(ContainElement("GetCategories"))
+			Expect(fake.AddOptions(hash).Category).To(Equal("movies"))
+		})
+	})
+
+	Context("When connection limits are set", func() {
+		const resourceName = "test-torrent-connection-limits"
+		const tccName = "test-tcc-connection-limits"
+		const secretName = "test-tcc-connection-limits-creds"
+		const hash = "dd8255ecdc7ca55fb0bbf81323d87062db1f6d1c"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var fake *fakeQBTClient
+		var controllerReconciler *TorrentReconciler
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, tccName, secretName)
+
+			maxConnections := int32(50)
+			maxUploads := int32(4)
+			resource := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       resourceName,
+					Namespace:  "default",
+					Finalizers: []string{TorrentFinalizer},
+				},
+				Spec: torrentv1alpha1.TorrentSpec{
+					MagnetURI:       "magnet:?xt=urn:btih:" + hash + "&dn=Big+Buck+Bunny",
+					ClientConfigRef: &torrentv1alpha1.LocalObjectReference{Name: tccName},
+					MaxConnections:  &maxConnections,
+					MaxUploads:      &maxUploads,
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			fake = newFakeQBTClient()
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny"})
+			controllerReconciler = &TorrentReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+		})
+
+		AfterEach(func() {
+			deleteTorrent(ctx, typeNamespacedName)
+			deleteTCC(ctx, tccName, secretName)
+		})
+
+		It("should apply the limits through the instance-wide preferences", func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fake.Calls()).To(ContainElement("SetPreferences:max_connec_per_torrent|max_uploads_per_torrent"))
+			Expect(fake.Preference("max_connec_per_torrent")).To(Equal(50))
+			Expect(fake.Preference("max_uploads_per_torrent")).To(Equal(4))
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			instanceWide := meta.FindStatusCondition(torrent.Status.Conditions, TypeConnectionLimitsInstanceWideTorrent)
+			Expect(instanceWide).NotTo(BeNil())
+			Expect(instanceWide.Reason).To(Equal("InstanceWidePreferences"))
+			Expect(instanceWide.Message).To(ContainSubstring("max_connec_per_torrent=50, max_uploads_per_torrent=4"))
+			Expect(meta.FindStatusCondition(torrent.Status.Conditions, TypeDegradedTorrent)).To(BeNil())
+			Expect(meta.IsStatusConditionTrue(torrent.Status.Conditions, TypeAvailableTorrent)).To(BeTrue())
+
+			By("not writing the preferences again while they match")
+			calls := len(fake.Calls())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fake.Calls()[calls:]).NotTo(ContainElement(HavePrefix("SetPreferences")))
+
+			By("removing the condition once the limits are unset")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			torrent.Spec.MaxConnections = nil
+			torrent.Spec.MaxUploads = nil
+			Expect(k8sClient.Update(ctx, torrent)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(meta.FindStatusCondition(torrent.Status.Conditions, TypeConnectionLimitsInstanceWideTorrent)).To(BeNil())
+		})
+
+		It("should apply the lowest limit declared by the Torrents sharing the client configuration", func() {
+			const otherHash = "08ada5a7a6183aae1e09d831df6748d566095a10"
+			otherNamespacedName := types.NamespacedName{Name: resourceName + "-other", Namespace: "default"}
+			maxConnections := int32(20)
+			other := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       otherNamespacedName.Name,
+					Namespace:  "default",
+					Finalizers: []string{TorrentFinalizer},
+				},
+				Spec: torrentv1alpha1.TorrentSpec{
+					MagnetURI:       "magnet:?xt=urn:btih:" + otherHash + "&dn=Sintel",
+					ClientConfigRef: &torrentv1alpha1.LocalObjectReference{Name: tccName},
+					MaxConnections:  &maxConnections,
+				},
+			}
+			Expect(k8sClient.Create(ctx, other)).To(Succeed())
+			defer deleteTorrent(ctx, otherNamespacedName)
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: otherHash, Name: "Sintel"})
+
+			for _, name := range []types.NamespacedName{otherNamespacedName, typeNamespacedName} {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: name})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(fake.Preference("max_connec_per_torrent")).To(Equal(20))
+			Expect(fake.Preference("max_uploads_per_torrent")).To(Equal(4))
+		})
+
+		It("should leave the limits declared by the managing TorrentServer to it", func() {
+			deleteServer := manageTCCByServer(ctx, tccName, torrentv1alpha1.TorrentServerSpec{
+				Preferences: map[string]apiextensionsv1.JSON{
+					"max_uploads_per_torrent": {Raw: []byte(`2`)},
+				},
+			})
+			defer deleteServer()
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fake.Calls()).To(ContainElement("SetPreferences:max_connec_per_torrent"))
+			Expect(fake.Preference("max_uploads_per_torrent")).To(BeNil())
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			instanceWide := meta.FindStatusCondition(torrent.Status.Conditions, TypeConnectionLimitsInstanceWideTorrent)
+			Expect(instanceWide).NotTo(BeNil())
+			Expect(instanceWide.Message).To(ContainSubstring(
+				"applied max_connec_per_torrent=50; max_uploads_per_torrent=2 declared by TorrentServer " + tccName + "-server"))
+		})
+	})
+
+	Context("When spec.priority is set", func() {
+		const resourceName = "test-torrent-priority"
+		const tccName = "test-tcc-priority"
+		const secretName = "test-tcc-priority-creds"
+		const hash = "dd8255ecdc7ca55fb0bbf81323d87062db1f6d1c"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var fake *fakeQBTClient
+		var controllerReconciler *TorrentReconciler
+
+		createTorrent := func(priority intstr.IntOrString) {
+			resource := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       resourceName,
+					Namespace:  "default",
+					Finalizers: []string{TorrentFinalizer},
+				},
+				Spec: torrentv1alpha1.TorrentSpec{
+					MagnetURI:       "magnet:?xt=urn:btih:" + hash + "&dn=Big+Buck+Bunny",
+					ClientConfigRef: &torrentv1alpha1.LocalObjectReference{Name: tccName},
+					Priority:        &priority,
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		}
+
+		reconcileOnce := func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		countCalls := func(call string) int {
+			n := 0
+			for _, c := range fake.Calls() {
+				if c == call {
+					n++
+				}
+			}
+			return n
+		}
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, tccName, secretName)
+
+			// A queue of four torrents, the managed one in third position
+			fake = newFakeQBTClient()
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: "aaaa", Priority: 1})
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: "bbbb", Priority: 2})
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", Priority: 3})
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: "cccc", Priority: 4})
+			controllerReconciler = &TorrentReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+		})
+
+		AfterEach(func() {
+			deleteTorrent(ctx, typeNamespacedName)
+			deleteTCC(ctx, tccName, secretName)
+		})
+
+		It("should move the torrent to the top once per spec change", func() {
+			createTorrent(intstr.FromString("top"))
+
+			reconcileOnce()
+			Expect(fake.QueuePosition(hash)).To(Equal(1))
+			Expect(fake.QueuePosition("aaaa")).To(Equal(2))
+
+			// A manual move in qBittorrent is not overridden until the spec changes
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", Priority: 4})
+			reconcileOnce()
+			Expect(countCalls("TopPriority:" + hash)).To(Equal(1))
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(torrent.Status.QueuePosition).To(Equal(int32(4)))
+			Expect(torrent.Status.PriorityGeneration).To(Equal(torrent.Generation))
+		})
+
+		It("should move the torrent again when the refresh annotation changes", func() {
+			createTorrent(intstr.FromString("top"))
+			reconcileOnce()
+			Expect(countCalls("TopPriority:" + hash)).To(Equal(1))
+
+			// A manual move in qBittorrent, then a refresh request to sync it back
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", Priority: 4})
+			setRefresh := func(value string) {
+				torrent := &torrentv1alpha1.Torrent{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+				if torrent.Annotations == nil {
+					torrent.Annotations = map[string]string{}
+				}
+				torrent.Annotations[RefreshAnnotation] = value
+				Expect(k8sClient.Update(ctx, torrent)).To(Succeed())
+			}
+			setRefresh("2026-10-17T10:00:00Z")
+			reconcileOnce()
+			Expect(countCalls("TopPriority:" + hash)).To(Equal(2))
+			Expect(fake.QueuePosition(hash)).To(Equal(1))
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(torrent.Status.ObservedRefresh).To(Equal("2026-10-17T10:00:00Z"))
+
+			By("not moving it again until the annotation changes once more")
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", Priority: 4})
+			reconcileOnce()
+			Expect(countCalls("TopPriority:" + hash)).To(Equal(2))
+
+			setRefresh("2026-10-17T10:05:00Z")
+			reconcileOnce()
+			Expect(countCalls("TopPriority:" + hash)).To(Equal(3))
+		})
+
+		It("should move the torrent to the bottom", func() {
+			createTorrent(intstr.FromString("bottom"))
+
+			reconcileOnce()
+			Expect(fake.QueuePosition(hash)).To(Equal(4))
+			Expect(fake.QueuePosition("cccc")).To(Equal(3))
+		})
+
+		It("should converge to an integer position one step per reconcile", func() {
+			createTorrent(intstr.FromInt32(1))
+
+			reconcileOnce()
+			Expect(fake.QueuePosition(hash)).To(Equal(2))
+			reconcileOnce()
+			Expect(fake.QueuePosition(hash)).To(Equal(1))
+			reconcileOnce()
+			Expect(countCalls("IncreasePriority:" + hash)).To(Equal(2))
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(torrent.Status.QueuePosition).To(Equal(int32(1)))
+		})
+
+		It("should not move a torrent that is not queued", func() {
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", Priority: -1})
+			createTorrent(intstr.FromString("top"))
+
+			reconcileOnce()
+			Expect(countCalls("TopPriority:" + hash)).To(BeZero())
+		})
+	})
+
+	Context("When stopSeedingOnComplete is set", func() {
+		const resourceName = "test-torrent-stop-seeding"
+		const tccName = "test-tcc-stop-seeding"
+		const secretName = "test-tcc-stop-seeding-creds"
+		const hash = "dd8255ecdc7ca55fb0bbf81323d87062db1f6d1c"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var fake *fakeQBTClient
+		var controllerReconciler *TorrentReconciler
+
+		reconcileOnce := func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		countPauses := func() int {
+			n := 0
+			for _, call := range fake.Calls() {
+				if call == "PauseTorrents:"+hash {
+					n++
+				}
+			}
+			return n
+		}
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, tccName, secretName)
+
+			stopSeeding := true
+			resource := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       resourceName,
+					Namespace:  "default",
+					Finalizers: []string{TorrentFinalizer},
+				},
+				Spec: torrentv1alpha1.TorrentSpec{
+					MagnetURI:             "magnet:?xt=urn:btih:" + hash + "&dn=Big+Buck+Bunny",
+					ClientConfigRef:       &torrentv1alpha1.LocalObjectReference{Name: tccName},
+					StopSeedingOnComplete: &stopSeeding,
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			fake = newFakeQBTClient()
+			controllerReconciler = &TorrentReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+		})
+
+		AfterEach(func() {
+			deleteTorrent(ctx, typeNamespacedName)
+			deleteTCC(ctx, tccName, secretName)
+		})
+
+		It("should stop the torrent exactly once when it completes", func() {
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", State: "downloading", Progress: 0.5})
+			reconcileOnce()
+			Expect(countPauses()).To(BeZero())
+
+			By("completing the download")
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", State: "uploading", Progress: 1})
+			reconcileOnce()
+			Expect(countPauses()).To(Equal(1))
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(torrent.Status.SeedingStopped).To(BeTrue())
+
+			By("leaving a manually resumed torrent seeding")
+			reconcileOnce()
+			Expect(countPauses()).To(Equal(1))
+		})
+	})
+
+	Context("When readyWhen is set", func() {
+		const resourceName = "test-torrent-ready-when"
+		const tccName = "test-tcc-ready-when"
+		const secretName = "test-tcc-ready-when-creds"
+		const hash = "ff8255ecdc7ca55fb0bbf81323d87062db1f6d1c"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var fake *fakeQBTClient
+		var controllerReconciler *TorrentReconciler
+
+		reconcileTorrent := func() *torrentv1alpha1.Torrent {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			return torrent
+		}
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, tccName, secretName)
+
+			resource := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       resourceName,
+					Namespace:  "default",
+					Finalizers: []string{TorrentFinalizer},
+				},
+				Spec: torrentv1alpha1.TorrentSpec{
+					MagnetURI:       "magnet:?xt=urn:btih:" + hash + "&dn=Big+Buck+Bunny",
+					ClientConfigRef: &torrentv1alpha1.LocalObjectReference{Name: tccName},
+					ReadyWhen:       torrentv1alpha1.ReadyWhenDownloaded,
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			fake = newFakeQBTClient()
+			controllerReconciler = &TorrentReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+		})
+
+		AfterEach(func() {
+			deleteTorrent(ctx, typeNamespacedName)
+			deleteTCC(ctx, tccName, secretName)
+		})
+
+		It("should only report Available once the download completed", func() {
+			torrent := reconcileTorrent()
+			available := meta.FindStatusCondition(torrent.Status.Conditions, TypeAvailableTorrent)
+			Expect(available).NotTo(BeNil())
+			Expect(available.Status).To(Equal(metav1.ConditionFalse))
+			Expect(available.Reason).To(Equal("WaitingForDownload"))
+
+			By("downloading half of the torrent")
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", State: "downloading", Progress: 0.5})
+			torrent = reconcileTorrent()
+			available = meta.FindStatusCondition(torrent.Status.Conditions, TypeAvailableTorrent)
+			Expect(available.Status).To(Equal(metav1.ConditionFalse))
+			Expect(available.Message).To(ContainSubstring("50.0% downloaded"))
+			Expect(meta.FindStatusCondition(torrent.Status.Conditions, TypeDegradedTorrent)).To(BeNil())
+
+			By("completing the download")
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", State: "stalledUP", Progress: 1})
+			torrent = reconcileTorrent()
+			Expect(meta.IsStatusConditionTrue(torrent.Status.Conditions, TypeAvailableTorrent)).To(BeTrue())
+		})
+	})
+
+	DescribeTable("torrentReady",
+		func(when torrentv1alpha1.ReadyWhen, state string, progress float64, ready bool) {
+			torrent := &torrentv1alpha1.Torrent{Spec: torrentv1alpha1.TorrentSpec{ReadyWhen: when}}
+			got, _ := torrentReady(torrent, &qbittorrent.TorrentInfo{State: state, Progress: progress})
+			Expect(got).To(Equal(ready))
+		},
+		Entry("unset while fetching metadata", torrentv1alpha1.ReadyWhen(""), "metaDL", 0.0, true),
+		Entry("added while downloading", torrentv1alpha1.ReadyWhenAdded, "downloading", 0.3, true),
+		Entry("downloaded while fetching metadata", torrentv1alpha1.ReadyWhenDownloaded, "metaDL", 0.0, false),
+		Entry("downloaded while downloading", torrentv1alpha1.ReadyWhenDownloaded, "downloading", 0.99, false),
+		Entry("downloaded once complete and stopped", torrentv1alpha1.ReadyWhenDownloaded, "stoppedUP", 1.0, true),
+		Entry("downloaded once complete and seeding", torrentv1alpha1.ReadyWhenDownloaded, "uploading", 1.0, true),
+		Entry("seeding while downloading", torrentv1alpha1.ReadyWhenSeeding, "downloading", 0.5, false),
+		Entry("seeding once complete but queued", torrentv1alpha1.ReadyWhenSeeding, "queuedUP", 1.0, false),
+		Entry("seeding once complete but stopped", torrentv1alpha1.ReadyWhenSeeding, "stoppedUP", 1.0, false),
+		Entry("seeding while uploading", torrentv1alpha1.ReadyWhenSeeding, "uploading", 1.0, true),
+		Entry("seeding while stalled without peers", torrentv1alpha1.ReadyWhenSeeding, "stalledUP", 1.0, true),
+		Entry("seeding while forced", torrentv1alpha1.ReadyWhenSeeding, "forcedUP", 1.0, true),
+	)
+
+	Context("When exportToSecret is set", func() {
+		const resourceName = "test-torrent-export"
+		const tccName = "test-tcc-export"
+		const secretName = "test-tcc-export-creds"
+		const hash = "ee8255ecdc7ca55fb0bbf81323d87062db1f6d1c"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+		exportName := types.NamespacedName{
+			Name:      resourceName + "-torrent",
+			Namespace: "default",
+		}
+
+		var fake *fakeQBTClient
+		var controllerReconciler *TorrentReconciler
+
+		reconcileOnce := func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		countExports := func() int {
+			n := 0
+			for _, call := range fake.Calls() {
+				if call == "ExportTorrent:"+hash {
+					n++
+				}
+			}
+			return n
+		}
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, tccName, secretName)
+
+			resource := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       resourceName,
+					Namespace:  "default",
+					Finalizers: []string{TorrentFinalizer},
+				},
+				Spec: torrentv1alpha1.TorrentSpec{
+					MagnetURI:       "magnet:?xt=urn:btih:" + hash + "&dn=Big+Buck+Bunny",
+					ClientConfigRef: &torrentv1alpha1.LocalObjectReference{Name: tccName},
+					ExportToSecret:  &torrentv1alpha1.ExportToSecret{},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			fake = newFakeQBTClient()
+			controllerReconciler = &TorrentReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+		})
+
+		AfterEach(func() {
+			secret := &corev1.Secret{}
+			if err := k8sClient.Get(ctx, exportName, secret); err == nil {
+				Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+			}
+			deleteTorrent(ctx, typeNamespacedName)
+			deleteTCC(ctx, tccName, secretName)
+		})
+
+		It("should write the .torrent file into an owned Secret once metadata is received", func() {
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", State: "metaDL"})
+			reconcileOnce()
+			Expect(countExports()).To(BeZero())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, exportName, &corev1.Secret{}))).To(BeTrue())
+
+			By("receiving the metadata")
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", State: "downloading"})
+			reconcileOnce()
+			Expect(countExports()).To(Equal(1))
+
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, exportName, secret)).To(Succeed())
+			Expect(string(secret.Data[ExportedTorrentKey])).To(ContainSubstring(hash))
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(metav1.IsControlledBy(secret, torrent)).To(BeTrue())
+			Expect(torrent.Status.ExportedSecretName).To(Equal(exportName.Name))
+
+			By("not exporting again while the Secret holds the file")
+			reconcileOnce()
+			Expect(countExports()).To(Equal(1))
+		})
+
+		It("should refuse to overwrite a Secret it does not own", func() {
+			Expect(k8sClient.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: exportName.Name, Namespace: "default"},
+				Data:       map[string][]byte{"other": []byte("data")},
+			})).To(Succeed())
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", State: "downloading"})
+			reconcileOnce()
+			Expect(countExports()).To(BeZero())
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			degraded := meta.FindStatusCondition(torrent.Status.Conditions, TypeDegradedTorrent)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal("FailedToExportTorrent"))
+		})
+
+		It("should report every failed step in the Degraded condition", func() {
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			torrent.Spec.DisplayName = "Renamed"
+			Expect(k8sClient.Update(ctx, torrent)).To(Succeed())
+
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", State: "downloading", Progress: 0.5})
+			fake.renameErr = fmt.Errorf("rename refused")
+			fake.exportErr = fmt.Errorf("export refused")
+			reconcileOnce()
+
+			Expect(fake.Calls()).To(ContainElements("RenameTorrent:"+hash+":Renamed", "ExportTorrent:"+hash))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			degraded := meta.FindStatusCondition(torrent.Status.Conditions, TypeDegradedTorrent)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal(ReasonMultipleStepsFailed))
+			Expect(degraded.Message).To(Equal(
+				"FailedToRenameTorrent: rename refused; FailedToExportTorrent: export refused"))
+			// The status is still refreshed from qBittorrent
+			Expect(torrent.Status.Progress).To(Equal("50.0%"))
+
+			By("reporting the remaining failure alone once the other step recovers")
+			fake.renameErr = nil
+			reconcileOnce()
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			degraded = meta.FindStatusCondition(torrent.Status.Conditions, TypeDegradedTorrent)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal("FailedToExportTorrent"))
+			Expect(degraded.Message).To(Equal("export refused"))
+		})
+
+		It("should report an unsupported feature together with the other failed steps", func() {
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: tccName, Namespace: "default"}, tcc)).To(Succeed())
+			tcc.Status.APIVersion = "2.8.3"
+			Expect(k8sClient.Status().Update(ctx, tcc)).To(Succeed())
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			torrent.Spec.DisplayName = "Renamed"
+			Expect(k8sClient.Update(ctx, torrent)).To(Succeed())
+
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", State: "downloading", Progress: 0.5})
+			fake.renameErr = fmt.Errorf("rename refused")
+			reconcileOnce()
+
+			Expect(countExports()).To(BeZero())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			degraded := meta.FindStatusCondition(torrent.Status.Conditions, TypeDegradedTorrent)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal(ReasonMultipleStepsFailed))
+			Expect(degraded.Message).To(ContainSubstring("FailedToRenameTorrent: rename refused"))
+			Expect(degraded.Message).To(ContainSubstring(ReasonUnsupportedAPIVersion + ": exportToSecret requires"))
+			// The steps after the unsupported one still run
+			Expect(torrent.Status.Progress).To(Equal("50.0%"))
+		})
+	})
+
+	Context("When file priorities are specified", func() {
+		const resourceName = "test-torrent-file-priorities"
+		const tccName = "test-tcc-file-priorities"
+		const secretName = "test-tcc-file-priorities-creds"
+		const hash = "dd8255ecdc7ca55fb0bbf81323d87062db1f6d1c"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var fake *fakeQBTClient
+		var controllerReconciler *TorrentReconciler
+
+		reconcileOnce := func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		addPhase := func() torrentv1alpha1.AddPhase {
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			return torrent.Status.AddPhase
+		}
+
+		// createTorrent creates the Torrent, adds it to the fake qBittorrent and lets the metadata arrive
+		createTorrent := func(paused bool) {
+			resource := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       resourceName,
+					Namespace:  "default",
+					Finalizers: []string{TorrentFinalizer},
+				},
+				Spec: torrentv1alpha1.TorrentSpec{
+					MagnetURI:       "magnet:?xt=urn:btih:" + hash + "&dn=Big+Buck+Bunny",
+					ClientConfigRef: &torrentv1alpha1.LocalObjectReference{Name: tccName},
+					Paused:          &paused,
+					FilePriorities: []torrentv1alpha1.FilePriority{
+						{Match: "*/Sample/*", Priority: torrentv1alpha1.FilePrioritySkip},
+						{Match: "*/*.mkv", Priority: torrentv1alpha1.FilePriorityHigh},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			By("adding the torrent so that it stops once its metadata is received")
+			reconcileOnce()
+			Expect(fake.AddOptions(hash)).To(Equal(qbittorrent.AddTorrentOptions{
+				StopCondition: qbittorrent.StopConditionMetadataReceived,
+			}))
+			Expect(addPhase()).To(Equal(torrentv1alpha1.AddPhaseAwaitingMetadata))
+
+			By("waiting for the metadata")
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: hash, State: "metaDL"})
+			reconcileOnce()
+			Expect(fake.Calls()).NotTo(ContainElement(HavePrefix("SetFilePriority:")))
+			Expect(addPhase()).To(Equal(torrentv1alpha1.AddPhaseAwaitingMetadata))
+
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", State: "stoppedDL"})
+			fake.SetFiles(hash, []qbittorrent.TorrentFile{
+				{Name: "Big Buck Bunny/Big Buck Bunny.mkv", Priority: qbittorrent.FilePriorityNormal},
+				{Name: "Big Buck Bunny/Sample/sample.mkv", Priority: qbittorrent.FilePriorityNormal},
+				{Name: "Big Buck Bunny/Big Buck Bunny.srt", Priority: qbittorrent.FilePriorityNormal},
+			})
+		}
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, tccName, secretName)
+
+			fake = newFakeQBTClient()
+			controllerReconciler = &TorrentReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+		})
+
+		AfterEach(func() {
+			deleteTorrent(ctx, typeNamespacedName)
+			deleteTCC(ctx, tccName, secretName)
+		})
+
+		It("should select the files of the stopped torrent before starting it, once", func() {
+			createTorrent(false)
+
+			reconcileOnce()
+			Expect(fake.Calls()).To(ContainElements(
+				"SetFilePriority:"+hash+":1:0",
+				"SetFilePriority:"+hash+":0:6",
+				"ResumeTorrents:"+hash,
+			))
+			Expect(slices.Index(fake.Calls(), "ResumeTorrents:"+hash)).To(
+				BeNumerically(">", slices.Index(fake.Calls(), "SetFilePriority:"+hash+":0:6")))
+			Expect(addPhase()).To(Equal(torrentv1alpha1.AddPhaseStarted))
+
+			By("not selecting files or starting the torrent again")
+			applied := len(fake.Calls())
+			reconcileOnce()
+			Expect(fake.Calls()[applied:]).NotTo(ContainElement(HavePrefix("SetFilePriority:")))
+			Expect(fake.Calls()[applied:]).NotTo(ContainElement("ResumeTorrents:" + hash))
+		})
+
+		It("should keep the torrent stopped after selecting its files when paused", func() {
+			createTorrent(true)
+
+			reconcileOnce()
+			Expect(fake.Calls()).To(ContainElement("SetFilePriority:" + hash + ":1:0"))
+			Expect(fake.Calls()).NotTo(ContainElement("ResumeTorrents:" + hash))
+			Expect(addPhase()).To(Equal(torrentv1alpha1.AddPhaseFilesSelected))
+
+			By("starting it once paused is unset")
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			torrent.Spec.Paused = nil
+			Expect(k8sClient.Update(ctx, torrent)).To(Succeed())
+
+			applied := len(fake.Calls())
+			reconcileOnce()
+			Expect(fake.Calls()[applied:]).To(ContainElement("ResumeTorrents:" + hash))
+			Expect(fake.Calls()[applied:]).NotTo(ContainElement(HavePrefix("SetFilePriority:")))
+			Expect(addPhase()).To(Equal(torrentv1alpha1.AddPhaseStarted))
+		})
+
+		It("should match nested file paths with ** patterns", func() {
+			resource := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       resourceName,
+					Namespace:  "default",
+					Finalizers: []string{TorrentFinalizer},
+				},
+				Spec: torrentv1alpha1.TorrentSpec{
+					MagnetURI:       "magnet:?xt=urn:btih:" + hash + "&dn=Show",
+					ClientConfigRef: &torrentv1alpha1.LocalObjectReference{Name: tccName},
+					FilePriorities: []torrentv1alpha1.FilePriority{
+						{Match: "**/Sample/**", Priority: torrentv1alpha1.FilePrioritySkip},
+						{Match: "**/*.mkv", Priority: torrentv1alpha1.FilePriorityHigh},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			reconcileOnce()
+
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Show", State: "stoppedDL"})
+			fake.SetFiles(hash, []qbittorrent.TorrentFile{
+				{Name: "Show/Season 1/Disc 2/E01.mkv", Priority: qbittorrent.FilePriorityNormal},
+				{Name: "Show/Season 1/Sample/extras/sample.mkv", Priority: qbittorrent.FilePriorityNormal},
+				{Name: "Show/Season 1/E01.MKV", Priority: qbittorrent.FilePriorityNormal},
+				{Name: "Show/E00.mkv", Priority: qbittorrent.FilePriorityNormal},
+			})
+			reconcileOnce()
+
+			Expect(fake.Calls()).To(ContainElements(
+				"SetFilePriority:"+hash+":1:0",
+				"SetFilePriority:"+hash+":0|3:6",
+			))
+			By("matching the file extension case-sensitively")
+			Expect(fake.Calls()).NotTo(ContainElement(MatchRegexp(`^SetFilePriority:` + hash + `:[0-9|]*2`)))
+		})
+	})
+
+	Context("When a Torrent only fetches its metadata", func() {
+		const resourceName = "test-torrent-metadata-only"
+		const tccName = "test-tcc-metadata-only"
+		const secretName = "test-tcc-metadata-only-creds"
+		const hash = "dd8255ecdc7ca55fb0bbf81323d87062db1f6d1c"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var fake *fakeQBTClient
+		var controllerReconciler *TorrentReconciler
+
+		reconcileOnce := func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		getTorrent := func() *torrentv1alpha1.Torrent {
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			return torrent
+		}
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, tccName, secretName)
+
+			fake = newFakeQBTClient()
+			controllerReconciler = &TorrentReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+
+			metadataOnly := true
+			resource := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       resourceName,
+					Namespace:  "default",
+					Finalizers: []string{TorrentFinalizer},
+				},
+				Spec: torrentv1alpha1.TorrentSpec{
+					MagnetURI:       "magnet:?xt=urn:btih:" + hash + "&dn=Big+Buck+Bunny",
+					ClientConfigRef: &torrentv1alpha1.LocalObjectReference{Name: tccName},
+					MetadataOnly:    &metadataOnly,
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			deleteTorrent(ctx, typeNamespacedName)
+			deleteTCC(ctx, tccName, secretName)
+		})
+
+		It("should list the files of the stopped torrent, then download the selected ones once unset", func() {
+			By("adding the torrent so that it stops once its metadata is received")
+			reconcileOnce()
+			Expect(fake.AddOptions(hash)).To(Equal(qbittorrent.AddTorrentOptions{
+				StopCondition: qbittorrent.StopConditionMetadataReceived,
+			}))
+			Expect(getTorrent().Status.AddPhase).To(Equal(torrentv1alpha1.AddPhaseAwaitingMetadata))
+
+			By("not listing files before the metadata is received")
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: hash, State: "metaDL"})
+			reconcileOnce()
+			Expect(getTorrent().Status.Files).To(BeEmpty())
+
+			By("listing the files in status and keeping the torrent stopped")
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", State: "stoppedDL"})
+			fake.SetFiles(hash, []qbittorrent.TorrentFile{
+				{Name: "Big Buck Bunny/Big Buck Bunny.mkv", Size: 276134947, Priority: qbittorrent.FilePriorityNormal},
+				{Name: "Big Buck Bunny/Sample/sample.mkv", Size: 1048576, Priority: qbittorrent.FilePriorityNormal},
+			})
+			reconcileOnce()
+			reconcileOnce()
+			torrent := getTorrent()
+			Expect(torrent.Status.Files).To(Equal([]torrentv1alpha1.TorrentFileStatus{
+				{Index: 0, Name: "Big Buck Bunny/Big Buck Bunny.mkv", Size: 276134947},
+				{Index: 1, Name: "Big Buck Bunny/Sample/sample.mkv", Size: 1048576},
+			}))
+			Expect(torrent.Status.AddPhase).To(Equal(torrentv1alpha1.AddPhaseAwaitingMetadata))
+			Expect(fake.Calls()).NotTo(ContainElement(HavePrefix("SetFilePriority:")))
+			Expect(fake.Calls()).NotTo(ContainElement("ResumeTorrents:" + hash))
+
+			By("applying the chosen file priorities and starting the torrent once metadataOnly is unset")
+			torrent.Spec.MetadataOnly = nil
+			torrent.Spec.FilePriorities = []torrentv1alpha1.FilePriority{
+				{Match: "*/Sample/*", Priority: torrentv1alpha1.FilePrioritySkip},
+			}
+			Expect(k8sClient.Update(ctx, torrent)).To(Succeed())
+
+			reconcileOnce()
+			Expect(fake.Calls()).To(ContainElements(
+				"SetFilePriority:"+hash+":1:0",
+				"ResumeTorrents:"+hash,
+			))
+			torrent = getTorrent()
+			Expect(torrent.Status.AddPhase).To(Equal(torrentv1alpha1.AddPhaseStarted))
+			Expect(torrent.Status.Files).To(BeEmpty())
+			Expect(torrent.Status.FilesListed).To(BeFalse())
+		})
+
+		It("should list the files of a torrent without files only once", func() {
+			reconcileOnce()
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", State: "stoppedDL"})
+			reconcileOnce()
+			torrent := getTorrent()
+			Expect(torrent.Status.FilesListed).To(BeTrue())
+			Expect(torrent.Status.Files).To(BeEmpty())
+
+			By("not listing them again on the next reconciles")
+			fake.SetFiles(hash, []qbittorrent.TorrentFile{
+				{Name: "Big Buck Bunny/Big Buck Bunny.mkv", Size: 276134947, Priority: qbittorrent.FilePriorityNormal},
+			})
+			reconcileOnce()
+			Expect(getTorrent().Status.Files).To(BeEmpty())
+		})
+
+		It("should refuse metadataOnly when qBittorrent lacks the stop condition", func() {
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: tccName, Namespace: "default"}, tcc)).To(Succeed())
+			tcc.Status.APIVersion = "2.8.3"
+			Expect(k8sClient.Status().Update(ctx, tcc)).To(Succeed())
+
+			reconcileOnce()
+			Expect(fake.Calls()).NotTo(ContainElement(HavePrefix("AddTorrent:")))
+			condition := meta.FindStatusCondition(getTorrent().Status.Conditions, TypeDegradedTorrent)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("UnsupportedAPIVersion"))
+			Expect(condition.Message).To(ContainSubstring("metadataOnly requires"))
+		})
+	})
+
+	Context("When qBittorrent loses the settings of a Torrent", func() {
+		const resourceName = "test-torrent-settings-drift"
+		const tccName = "test-tcc-settings-drift"
+		const secretName = "test-tcc-settings-drift-creds"
+		const hash = "dd8255ecdc7ca55fb0bbf81323d87062db1f6d1c"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var fake *fakeQBTClient
+		var recorder *record.FakeRecorder
+		var controllerReconciler *TorrentReconciler
+
+		reconcileOnce := func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		// expireVerification makes the settings verification due on the next reconcile
+		expireVerification := func() {
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(torrent.Status.SettingsVerifiedAt).NotTo(BeNil())
+			verifiedAt := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+			torrent.Status.SettingsVerifiedAt = &verifiedAt
+			Expect(k8sClient.Status().Update(ctx, torrent)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, tccName, secretName)
+
+			fake = newFakeQBTClient()
+			recorder = record.NewFakeRecorder(10)
+			controllerReconciler = &TorrentReconciler{
+				Client:                 k8sClient,
+				Scheme:                 k8sClient.Scheme(),
+				ClientPool:             newFakeClientPool(fake),
+				Recorder:               recorder,
+				SettingsVerifyInterval: time.Minute,
+			}
+
+			resource := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       resourceName,
+					Namespace:  "default",
+					Finalizers: []string{TorrentFinalizer},
+				},
+				Spec: torrentv1alpha1.TorrentSpec{
+					MagnetURI:       "magnet:?xt=urn:btih:" + hash + "&dn=Big+Buck+Bunny",
+					ClientConfigRef: &torrentv1alpha1.LocalObjectReference{Name: tccName},
+					Category:        "movies",
+					FilePriorities: []torrentv1alpha1.FilePriority{
+						{Match: "*/Sample/*", Priority: torrentv1alpha1.FilePrioritySkip},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			By("adding the torrent, then selecting its files once the metadata is received")
+			reconcileOnce()
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", State: "stoppedDL", Category: "movies"})
+			fake.SetFiles(hash, []qbittorrent.TorrentFile{
+				{Name: "Big Buck Bunny/Big Buck Bunny.mkv", Priority: qbittorrent.FilePriorityNormal},
+				{Name: "Big Buck Bunny/Sample/sample.mkv", Priority: qbittorrent.FilePriorityNormal},
+			})
+			reconcileOnce()
+			Expect(fake.Calls()).To(ContainElement("SetFilePriority:" + hash + ":1:0"))
+		})
+
+		AfterEach(func() {
+			deleteTorrent(ctx, typeNamespacedName)
+			deleteTCC(ctx, tccName, secretName)
+		})
+
+		It("should apply the drifted settings again without re-adding the torrent", func() {
+			By("losing the category and file priorities across a qBittorrent restart")
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", State: "downloading"})
+			fake.SetFiles(hash, []qbittorrent.TorrentFile{
+				{Name: "Big Buck Bunny/Big Buck Bunny.mkv", Priority: qbittorrent.FilePriorityNormal},
+				{Name: "Big Buck Bunny/Sample/sample.mkv", Priority: qbittorrent.FilePriorityNormal},
+			})
+			applied := len(fake.Calls())
+
+			By("not verifying the settings on every reconcile")
+			reconcileOnce()
+			Expect(fake.Calls()[applied:]).NotTo(ContainElement(HavePrefix("SetTorrentCategory:")))
+
+			By("correcting the drift once the verification is due")
+			expireVerification()
+			reconcileOnce()
+			Expect(fake.Calls()[applied:]).To(ContainElements(
+				"SetTorrentCategory:"+hash+":movies",
+				"SetFilePriority:"+hash+":1:0",
+			))
+			Expect(fake.Calls()[applied:]).NotTo(ContainElement(HavePrefix("AddTorrent:")))
+			Expect(recorder.Events).To(Receive(And(
+				ContainSubstring("SettingsReconciled"),
+				ContainSubstring("category, filePriorities"),
+			)))
+		})
+
+		It("should not emit an event when no setting drifted", func() {
+			applied := len(fake.Calls())
+			expireVerification()
+			reconcileOnce()
+			Expect(fake.Calls()[applied:]).NotTo(ContainElement(HavePrefix("SetTorrentCategory:")))
+			Expect(fake.Calls()[applied:]).NotTo(ContainElement(HavePrefix("SetFilePriority:")))
+			Expect(recorder.Events).To(BeEmpty())
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(torrent.Status.SettingsVerifiedAt.Time).To(BeTemporally("~", time.Now(), 5*time.Second))
+		})
+
+		It("should retry a failed verification after the interval rather than on every reconcile", func() {
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", State: "downloading"})
+			fake.mu.Lock()
+			fake.setCategoryErr = fmt.Errorf("connection refused")
+			fake.mu.Unlock()
+
+			By("recording the failed verification")
+			expireVerification()
+			reconcileOnce()
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(torrent.Status.SettingsVerifiedAt.Time).To(BeTemporally("~", time.Now(), 5*time.Second))
+			degraded := meta.FindStatusCondition(torrent.Status.Conditions, TypeDegradedTorrent)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal("FailedToVerifySettings"))
+			Expect(recorder.Events).To(Receive(ContainSubstring("Warning SettingsVerificationFailed")))
+
+			By("not verifying again before the interval")
+			applied := len(fake.Calls())
+			reconcileOnce()
+			Expect(fake.Calls()[applied:]).NotTo(ContainElement(HavePrefix("SetTorrentCategory:")))
+		})
+	})
+
+	Context("When a Torrent is added to the top of the queue", func() {
+		const resourceName = "test-torrent-top-of-queue"
+		const tccName = "test-tcc-top-of-queue"
+		const secretName = "test-tcc-top-of-queue-creds"
+		const hash = "dd8255ecdc7ca55fb0bbf81323d87062db1f6d1c"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var fake *fakeQBTClient
+		var controllerReconciler *TorrentReconciler
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, tccName, secretName)
+
+			fake = newFakeQBTClient()
+			controllerReconciler = &TorrentReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+
+			addToTopOfQueue := true
+			resource := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       resourceName,
+					Namespace:  "default",
+					Finalizers: []string{TorrentFinalizer},
+				},
+				Spec: torrentv1alpha1.TorrentSpec{
+					MagnetURI:       "magnet:?xt=urn:btih:" + hash + "&dn=Big+Buck+Bunny",
+					ClientConfigRef: &torrentv1alpha1.LocalObjectReference{Name: tccName},
+					AddToTopOfQueue: &addToTopOfQueue,
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			deleteTorrent(ctx, typeNamespacedName)
+			deleteTCC(ctx, tccName, secretName)
+		})
+
+		It("should forward addToTopOfQueue to qBittorrent without a queue move", func() {
+			fake.SetPreference("queueing_enabled", true)
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fake.AddOptions(hash)).To(Equal(qbittorrent.AddTorrentOptions{AddToTopOfQueue: true}))
+			Expect(fake.Calls()).NotTo(ContainElement(HavePrefix("TopPriority:")))
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(meta.FindStatusCondition(torrent.Status.Conditions, TypeQueueingDisabledTorrent)).To(BeNil())
+		})
+
+		It("should add the torrent but report that queueing disabled ignores addToTopOfQueue", func() {
+			fake.SetPreference("queueing_enabled", false)
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fake.Calls()).To(ContainElement(HavePrefix("AddTorrent:")))
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			condition := meta.FindStatusCondition(torrent.Status.Conditions, TypeQueueingDisabledTorrent)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Message).To(ContainSubstring("addToTopOfQueue"))
+		})
+
+		It("should report an Unknown QueueingDisabled condition when the preferences cannot be read", func() {
+			fake.mu.Lock()
+			fake.preferencesErr = fmt.Errorf("connection refused")
+			fake.mu.Unlock()
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fake.Calls()).To(ContainElement(HavePrefix("AddTorrent:")))
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			condition := meta.FindStatusCondition(torrent.Status.Conditions, TypeQueueingDisabledTorrent)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionUnknown))
+			Expect(condition.Reason).To(Equal("PreferencesUnavailable"))
+			Expect(condition.Message).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Context("When qBittorrent reports the torrent as errored", func() {
+		const resourceName = "test-torrent-message"
+		const tccName = "test-tcc-message"
+		const secretName = "test-tcc-message-creds"
+		const hash = "dd8255ecdc7ca55fb0bbf81323d87062db1f6d1c"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var fake *fakeQBTClient
+		var controllerReconciler *TorrentReconciler
+
+		reconcileOnce := func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		statusMessage := func() string {
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			return torrent.Status.Message
+		}
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, tccName, secretName)
+
+			resource := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       resourceName,
+					Namespace:  "default",
+					Finalizers: []string{TorrentFinalizer},
+				},
+				Spec: torrentv1alpha1.TorrentSpec{
+					MagnetURI:       "magnet:?xt=urn:btih:" + hash + "&dn=Big+Buck+Bunny",
+					ClientConfigRef: &torrentv1alpha1.LocalObjectReference{Name: tccName},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			fake = newFakeQBTClient()
+			fake.SetLog(
+				qbittorrent.LogEntry{ID: 1, Message: `File error alert. Torrent: "Big Buck Bunny". File: "/downloads/Big Buck Bunny/Big Buck Bunny.mp4". Reason: "Big Buck Bunny file_open (/downloads/Big Buck Bunny/Big Buck Bunny.mp4) error: No space left on device"`, Type: qbittorrent.LogTypeCritical},
+				qbittorrent.LogEntry{ID: 2, Message: `File error alert. Torrent: "Sintel". File: "/downloads/Sintel/Sintel.mkv". Reason: "Permission denied"`, Type: qbittorrent.LogTypeCritical},
+			)
+			controllerReconciler = &TorrentReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+		})
+
+		AfterEach(func() {
+			deleteTorrent(ctx, typeNamespacedName)
+			deleteTCC(ctx, tccName, secretName)
+		})
+
+		It("should surface the qBittorrent error and clear it once the torrent recovers", func() {
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", State: "downloading", Progress: 0.5})
+			reconcileOnce()
+			Expect(statusMessage()).To(BeEmpty())
+			Expect(fake.Calls()).NotTo(ContainElement("GetLog"))
+
+			By("erroring the torrent")
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", State: "error", Progress: 0.5})
+			reconcileOnce()
+			message := statusMessage()
+			Expect(message).To(HavePrefix("qBittorrent reports an error for the torrent: "))
+			Expect(message).To(ContainSubstring("No space left on device"))
+			Expect(message).NotTo(ContainSubstring("Permission denied"))
+
+			By("recovering the torrent")
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", State: "downloading", Progress: 0.6})
+			reconcileOnce()
+			Expect(statusMessage()).To(BeEmpty())
+		})
+
+		It("should explain the errored state when the log does not mention the torrent", func() {
+			fake.SetLog()
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", State: "missingFiles", Progress: 1})
+			reconcileOnce()
+			Expect(statusMessage()).To(Equal("qBittorrent cannot find the torrent files"))
+		})
+	})
+
+	Context("When qBittorrent forgets a known torrent", func() {
+		const resourceName = "test-torrent-recovery"
+		const tccName = "test-tcc-recovery"
+		const secretName = "test-tcc-recovery-creds"
+		const hash = "dd8255ecdc7ca55fb0bbf81323d87062db1f6d1c"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var fake *fakeQBTClient
+		var controllerReconciler *TorrentReconciler
+
+		reconcileOnce := func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, tccName, secretName)
+
+			resource := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       resourceName,
+					Namespace:  "default",
+					Finalizers: []string{TorrentFinalizer},
+				},
+				Spec: torrentv1alpha1.TorrentSpec{
+					MagnetURI:       "magnet:?xt=urn:btih:" + hash + "&dn=Big+Buck+Bunny",
+					ClientConfigRef: &torrentv1alpha1.LocalObjectReference{Name: tccName},
+					DisplayName:     "Big Buck Bunny (2008)",
+					ContentLayout:   torrentv1alpha1.ContentLayoutSubfolder,
+					FileRenames: []torrentv1alpha1.FileRename{
+						{Match: "Big Buck Bunny/*.mp4", Rename: "movie.mp4"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			// Status left behind by the torrent qBittorrent no longer knows about
+			resource.Status.Hash = hash
+			resource.Status.SourcePinned = true
+			resource.Status.AppliedFileRenames = []torrentv1alpha1.AppliedFileRename{
+				{From: "Big Buck Bunny/Big.Buck.Bunny.1080p.mp4", To: "Big Buck Bunny/movie.mp4"},
+			}
+			Expect(k8sClient.Status().Update(ctx, resource)).To(Succeed())
+
+			fake = newFakeQBTClient()
+			controllerReconciler = &TorrentReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+		})
+
+		AfterEach(func() {
+			deleteTorrent(ctx, typeNamespacedName)
+			deleteTCC(ctx, tccName, secretName)
+		})
+
+		It("should add the torrent again and re-apply its declarative settings", func() {
+			reconcileOnce()
+			Expect(fake.Calls()).To(ContainElement(ContainSubstring("AddTorrent:")))
+			Expect(fake.AddOptions(hash).ContentLayout).To(Equal("Subfolder"))
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(torrent.Status.AppliedFileRenames).To(BeEmpty())
+			Expect(torrent.Status.SourcePinned).To(BeFalse())
+			available := meta.FindStatusCondition(torrent.Status.Conditions, TypeAvailableTorrent)
+			Expect(available).NotTo(BeNil())
+			Expect(available.Reason).To(Equal("TorrentRecovered"))
+
+			By("applying the display name and file renames once metadata is back")
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", TotalSize: 1024})
+			fake.SetFiles(hash, []qbittorrent.TorrentFile{
+				{Index: 0, Name: "Big Buck Bunny/Big.Buck.Bunny.1080p.mp4"},
+			})
+			reconcileOnce()
+
+			Expect(fake.Calls()).To(ContainElement("RenameTorrent:" + hash + ":Big Buck Bunny (2008)"))
+			Expect(fake.Calls()).To(ContainElement(
+				"RenameFile:" + hash + ":Big Buck Bunny/Big.Buck.Bunny.1080p.mp4:Big Buck Bunny/movie.mp4"))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(torrent.Status.AppliedFileRenames).To(HaveLen(1))
+		})
+	})
+
+	Context("When qBittorrent reports a transient entry without a hash", func() {
+		const resourceName = "test-torrent-empty-hash"
+		const tccName = "test-tcc-empty-hash"
+		const secretName = "test-tcc-empty-hash-creds"
+		const hash = "dd8255ecdc7ca55fb0bbf81323d87062db1f6d1c"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var fake *fakeQBTClient
+		var controllerReconciler *TorrentReconciler
+
+		reconcileOnce := func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, tccName, secretName)
+
+			resource := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       resourceName,
+					Namespace:  "default",
+					Finalizers: []string{TorrentFinalizer},
+				},
+				Spec: torrentv1alpha1.TorrentSpec{
+					MagnetURI:       "magnet:?xt=urn:btih:" + hash + "&dn=Big+Buck+Bunny",
+					ClientConfigRef: &torrentv1alpha1.LocalObjectReference{Name: tccName},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			fake = newFakeQBTClient()
+			fake.SetTorrent(qbittorrent.TorrentInfo{
+				Hash: hash, Name: "Big Buck Bunny", State: "downloading",
+				TotalSize: 1024, AmountLeft: 512, ContentPath: "/downloads/Big Buck Bunny",
+			})
+			controllerReconciler = &TorrentReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+		})
+
+		AfterEach(func() {
+			deleteTorrent(ctx, typeNamespacedName)
+			deleteTCC(ctx, tccName, secretName)
+		})
+
+		It("should keep the recorded hash and metadata fields", func() {
+			reconcileOnce()
+
+			By("listing the torrent without its hash or metadata")
+			fake.mu.Lock()
+			fake.torrents[hash] = &qbittorrent.TorrentInfo{State: "metaDL"}
+			fake.mu.Unlock()
+			reconcileOnce()
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(torrent.Status.Hash).To(Equal(hash))
+			Expect(torrent.Status.State).To(Equal("metaDL"))
+			Expect(torrent.Status.Name).To(Equal("Big Buck Bunny"))
+			Expect(torrent.Status.TotalSize).To(BeEquivalentTo(1024))
+			Expect(torrent.Status.AmountLeft).To(BeEquivalentTo(512))
+			Expect(torrent.Status.ContentPath).To(Equal("/downloads/Big Buck Bunny"))
+			Expect(torrent.Status.TotalSizeHuman).To(Equal(formatBytes(1024)))
+
+			By("deleting the torrent from qBittorrent with the kept hash")
+			Expect(k8sClient.Delete(ctx, torrent)).To(Succeed())
+			reconcileOnce()
+			Expect(fake.Calls()).To(ContainElement("DeleteTorrent:" + hash + ":true"))
+		})
+	})
+
+	Context("When a log excerpt is requested on failures", func() {
+		const resourceName = "test-torrent-log-excerpt"
+		const tccName = "test-tcc-log-excerpt"
+		const secretName = "test-tcc-log-excerpt-creds"
+		const hash = "dd8255ecdc7ca55fb0bbf81323d87062db1f6d1c"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var fake *fakeQBTClient
+		var recorder *record.FakeRecorder
+		var controllerReconciler *TorrentReconciler
+
+		reconcileOnce := func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, tccName, secretName)
+
+			resource := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       resourceName,
+					Namespace:  "default",
+					Finalizers: []string{TorrentFinalizer},
+				},
+				Spec: torrentv1alpha1.TorrentSpec{
+					MagnetURI:       "magnet:?xt=urn:btih:" + hash + "&dn=Big+Buck+Bunny",
+					ClientConfigRef: &torrentv1alpha1.LocalObjectReference{Name: tccName},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			fake = newFakeQBTClient()
+			fake.addErr = &qbittorrent.StatusError{Endpoint: "/api/v2/torrents/add", StatusCode: http.StatusInternalServerError}
+			fake.SetLog(
+				qbittorrent.LogEntry{ID: 1, Message: "qBittorrent v5.0.4 started", Type: qbittorrent.LogTypeNormal},
+				qbittorrent.LogEntry{ID: 2, Message: "UPnP/NAT-PMP: Port mapping failure", Type: qbittorrent.LogTypeWarning},
+				qbittorrent.LogEntry{ID: 3, Message: "Failed to save torrent metadata", Type: qbittorrent.LogTypeWarning},
+				qbittorrent.LogEntry{ID: 4, Message: "No space left on device", Type: qbittorrent.LogTypeCritical},
+			)
+			recorder = record.NewFakeRecorder(10)
+			controllerReconciler = &TorrentReconciler{
+				Client:          k8sClient,
+				Scheme:          k8sClient.Scheme(),
+				ClientPool:      newFakeClientPool(fake),
+				Recorder:        recorder,
+				LogExcerptLines: 2,
+			}
+		})
+
+		AfterEach(func() {
+			deleteTorrent(ctx, typeNamespacedName)
+			deleteTCC(ctx, tccName, secretName)
+		})
+
+		It("should emit the latest warning and critical lines when the Torrent becomes Degraded", func() {
+			reconcileOnce()
+
+			Expect(recorder.Events).To(HaveLen(1))
+			event := <-recorder.Events
+			Expect(event).To(HavePrefix("Warning QBittorrentLog"))
+			Expect(event).To(ContainSubstring("[warning] Failed to save torrent metadata\n[critical] No space left on device"))
+			Expect(event).NotTo(ContainSubstring("Port mapping failure"))
+			Expect(event).NotTo(ContainSubstring("started"))
+
+			By("not fetching the log again while the Degraded reason is unchanged")
+			reconcileOnce()
+			Expect(recorder.Events).To(BeEmpty())
+			logFetches := 0
+			for _, call := range fake.Calls() {
+				if call == "GetLog" {
+					logFetches++
+				}
+			}
+			Expect(logFetches).To(Equal(1))
+		})
+
+		It("should not fetch the log when disabled", func() {
+			controllerReconciler.LogExcerptLines = 0
+			reconcileOnce()
+
+			Expect(recorder.Events).To(BeEmpty())
+			Expect(fake.Calls()).NotTo(ContainElement("GetLog"))
+		})
+	})
+
+	Context("When a displayName is set", func() {
+		const resourceName = "test-torrent-display-name"
+		const tccName = "test-tcc-display-name"
+		const secretName = "test-tcc-display-name-creds"
+		const hash = "dd8255ecdc7ca55fb0bbf81323d87062db1f6d1c"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var fake *fakeQBTClient
+		var controllerReconciler *TorrentReconciler
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, tccName, secretName)
+
+			resource := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       resourceName,
+					Namespace:  "default",
+					Finalizers: []string{TorrentFinalizer},
+				},
+				Spec: torrentv1alpha1.TorrentSpec{
+					MagnetURI:       "magnet:?xt=urn:btih:" + hash + "&dn=Big+Buck+Bunny",
+					ClientConfigRef: &torrentv1alpha1.LocalObjectReference{Name: tccName},
+					DisplayName:     "Big Buck Bunny (2008)",
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			fake = newFakeQBTClient()
+			controllerReconciler = &TorrentReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+		})
+
+		AfterEach(func() {
+			deleteTorrent(ctx, typeNamespacedName)
+			deleteTCC(ctx, tccName, secretName)
+		})
+
+		It("should rename the torrent when its name drifts from displayName", func() {
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny"})
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fake.Calls()).To(ContainElement("RenameTorrent:" + hash + ":Big Buck Bunny (2008)"))
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(torrent.Status.Name).To(Equal("Big Buck Bunny (2008)"))
+		})
+
+		It("should report human-readable sizes and progress in status", func() {
+			fake.SetTorrent(qbittorrent.TorrentInfo{
+				Hash:       hash,
+				Name:       "Big Buck Bunny (2008)",
+				State:      "downloading",
+				TotalSize:  1610612736,
+				AmountLeft: 805306368,
+				Progress:   0.5,
+				DlSpeed:    1258291,
+				Ratio:      0.125,
+			})
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(torrent.Status.TotalSizeHuman).To(Equal("1.5 GiB"))
+			Expect(torrent.Status.AmountLeftHuman).To(Equal("768.0 MiB"))
+			Expect(torrent.Status.Progress).To(Equal("50.0%"))
+			Expect(torrent.Status.DownloadSpeed).To(Equal("1.2 MiB/s"))
+			Expect(torrent.Status.Ratio).To(Equal("0.12"))
+		})
+
+		It("should copy the share ratio from qBittorrent", func() {
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny (2008)", Ratio: 1.5})
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(torrent.Status.Ratio).To(Equal("1.50"))
+
+			By("tracking ratio changes on later reconciles")
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny (2008)", Ratio: 2.25})
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(torrent.Status.Ratio).To(Equal("2.25"))
+		})
+
+		It("should report swarm availability and transfer totals from the torrent properties", func() {
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny (2008)", State: "stalledDL"})
+			fake.SetProperties(hash, qbittorrent.TorrentProperties{
+				Availability:    0.875,
+				Peers:           3,
+				Seeds:           0,
+				TotalDownloaded: 734003200,
+				TotalUploaded:   104857600,
+
+				ConnectionsLimit: 100,
+			})
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(torrent.Status.Availability).To(Equal("0.88"))
+			Expect(torrent.Status.Peers).To(Equal(int32(3)))
+			Expect(torrent.Status.Seeds).To(BeZero())
+			Expect(torrent.Status.TotalDownloaded).To(Equal(int64(734003200)))
+			Expect(torrent.Status.TotalUploaded).To(Equal(int64(104857600)))
+			Expect(torrent.Status.ConnectionsLimit).To(Equal(int32(100)))
+		})
+
+		It("should accumulate transfer totals across qBittorrent counter resets", func() {
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny (2008)", State: "uploading"})
+			transfer := func(downloaded, uploaded int64) *torrentv1alpha1.Torrent {
+				fake.SetProperties(hash, qbittorrent.TorrentProperties{TotalDownloaded: downloaded, TotalUploaded: uploaded})
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				torrent := &torrentv1alpha1.Torrent{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+				return torrent
+			}
+
+			torrent := transfer(1000, 200)
+			Expect(torrent.Status.TotalDownloaded).To(Equal(int64(1000)))
+			Expect(torrent.Status.TotalUploaded).To(Equal(int64(200)))
+
+			By("adding the growth of the counters")
+			torrent = transfer(1500, 700)
+			Expect(torrent.Status.TotalDownloaded).To(Equal(int64(1500)))
+			Expect(torrent.Status.TotalUploaded).To(Equal(int64(700)))
+
+			By("counting every byte of a counter that restarted from zero")
+			torrent = transfer(300, 100)
+			Expect(torrent.Status.TotalDownloaded).To(Equal(int64(1800)))
+			Expect(torrent.Status.TotalUploaded).To(Equal(int64(800)))
+
+			torrent = transfer(400, 100)
+			Expect(torrent.Status.TotalDownloaded).To(Equal(int64(1900)))
+			Expect(torrent.Status.TotalUploaded).To(Equal(int64(800)))
+			Expect(*torrent.Status.LastSeenDownloaded).To(Equal(int64(400)))
+		})
+
+		It("should record the torrent comment and creator once metadata is available", func() {
+			props := qbittorrent.TorrentProperties{Comment: "Encoded by Blender", CreatedBy: "mktorrent 1.1"}
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny (2008)", State: "metaDL"})
+			fake.SetProperties(hash, props)
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(torrent.Status.Comment).To(BeEmpty())
+
+			By("resolving the metadata")
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny (2008)", State: "downloading"})
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(torrent.Status.Comment).To(Equal("Encoded by Blender"))
+			Expect(torrent.Status.CreatedBy).To(Equal("mktorrent 1.1"))
+
+			By("keeping the recorded values on later reconciles")
+			props.Comment = "edited"
+			fake.SetProperties(hash, props)
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(torrent.Status.Comment).To(Equal("Encoded by Blender"))
+		})
+
+		It("should not rename the torrent when its name already matches displayName", func() {
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny (2008)"})
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			for _, call := range fake.Calls() {
+				Expect(call).NotTo(HavePrefix("RenameTorrent"))
+			}
+		})
+	})
+
+	Context("When fileRenames are set", func() {
+		const resourceName = "test-torrent-file-renames"
+		const tccName = "test-tcc-file-renames"
+		const secretName = "test-tcc-file-renames-creds"
+		const hash = "dd8255ecdc7ca55fb0bbf81323d87062db1f6d1c"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var fake *fakeQBTClient
+		var controllerReconciler *TorrentReconciler
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, tccName, secretName)
+
+			resource := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       resourceName,
+					Namespace:  "default",
+					Finalizers: []string{TorrentFinalizer},
+				},
+				Spec: torrentv1alpha1.TorrentSpec{
+					MagnetURI:       "magnet:?xt=urn:btih:" + hash + "&dn=Big+Buck+Bunny",
+					ClientConfigRef: &torrentv1alpha1.LocalObjectReference{Name: tccName},
+					FileRenames: []torrentv1alpha1.FileRename{
+						{Match: "Big Buck Bunny/*.mp4", Rename: "movie.mp4"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			fake = newFakeQBTClient()
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny"})
+			controllerReconciler = &TorrentReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+		})
+
+		AfterEach(func() {
+			deleteTorrent(ctx, typeNamespacedName)
+			deleteTCC(ctx, tccName, secretName)
+		})
+
+		It("should postpone renames until metadata is available", func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			for _, call := range fake.Calls() {
+				Expect(call).NotTo(HavePrefix("RenameFile"))
+			}
+		})
+
+		It("should rename matching files and report them in status", func() {
+			fake.SetFiles(hash, []qbittorrent.TorrentFile{
+				{Index: 0, Name: "Big Buck Bunny/Big.Buck.Bunny.1080p.mp4"},
+				{Index: 1, Name: "Big Buck Bunny/poster.jpg"},
+			})
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fake.Calls()).To(ContainElement(
+				"RenameFile:" + hash + ":Big Buck Bunny/Big.Buck.Bunny.1080p.mp4:Big Buck Bunny/movie.mp4"))
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(torrent.Status.AppliedFileRenames).To(ConsistOf(torrentv1alpha1.AppliedFileRename{
+				From: "Big Buck Bunny/Big.Buck.Bunny.1080p.mp4",
+				To:   "Big Buck Bunny/movie.mp4",
+			}))
+
+			// A second reconcile must not rename again
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			renameCalls := 0
+			for _, call := range fake.Calls() {
+				if strings.HasPrefix(call, "RenameFile") {
+					renameCalls++
+				}
+			}
+			Expect(renameCalls).To(Equal(1))
+		})
+
+		It("should refuse renames colliding with existing files", func() {
+			fake.SetFiles(hash, []qbittorrent.TorrentFile{
+				{Index: 0, Name: "Big Buck Bunny/a.mp4"},
+				{Index: 1, Name: "Big Buck Bunny/b.mp4"},
+			})
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			for _, call := range fake.Calls() {
+				Expect(call).NotTo(HavePrefix("RenameFile"))
+			}
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			condition := meta.FindStatusCondition(torrent.Status.Conditions, TypeDegradedTorrent)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("FailedToRenameFiles"))
+		})
+		It("should not rename files when the qBittorrent API is too old", func() {
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: tccName, Namespace: "default"}, tcc)).To(Succeed())
+			tcc.Status.APIVersion = "2.7.0"
+			Expect(k8sClient.Status().Update(ctx, tcc)).To(Succeed())
+
+			fake.SetFiles(hash, []qbittorrent.TorrentFile{
+				{Index: 0, Name: "Big Buck Bunny/Big Buck Bunny.mp4"},
+			})
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			for _, call := range fake.Calls() {
+				Expect(call).NotTo(HavePrefix("RenameFile"))
+			}
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			degraded := meta.FindStatusCondition(torrent.Status.Conditions, TypeDegradedTorrent)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal("UnsupportedAPIVersion"))
+		})
+		It("should report unsupported file renames detected by the client", func() {
+			fake.apiVersion = "2.7.0"
+			fake.SetFiles(hash, []qbittorrent.TorrentFile{
+				{Index: 0, Name: "Big Buck Bunny/Big Buck Bunny.mp4"},
+			})
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			for _, call := range fake.Calls() {
+				Expect(call).NotTo(HavePrefix("RenameFile"))
+			}
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			degraded := meta.FindStatusCondition(torrent.Status.Conditions, TypeDegradedTorrent)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal("UnsupportedAPIVersion"))
+			Expect(degraded.Message).To(ContainSubstring("not supported"))
+		})
+	})
+
+	Context("When adding the torrent to qBittorrent fails", func() {
+		const resourceName = "test-torrent-add-failure"
+		const tccName = "test-tcc-add-failure"
+		const secretName = "test-tcc-add-failure-creds"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var fake *fakeQBTClient
+		var controllerReconciler *TorrentReconciler
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, tccName, secretName)
+
+			resource := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       resourceName,
+					Namespace:  "default",
+					Finalizers: []string{TorrentFinalizer},
+				},
+				Spec: torrentv1alpha1.TorrentSpec{
+					MagnetURI:       "magnet:?xt=urn:btih:dd8255ecdc7ca55fb0bbf81323d87062db1f6d1c&dn=Big+Buck+Bunny",
+					ClientConfigRef: &torrentv1alpha1.LocalObjectReference{Name: tccName},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			fake = newFakeQBTClient()
+			controllerReconciler = &TorrentReconciler{
+				Client:                  k8sClient,
+				Scheme:                  k8sClient.Scheme(),
+				ClientPool:              newFakeClientPool(fake),
+				TerminalRequeueInterval: 10 * time.Minute,
+			}
+		})
+
+		AfterEach(func() {
+			deleteTorrent(ctx, typeNamespacedName)
+			deleteTCC(ctx, tccName, secretName)
+		})
+
+		It("should not requeue quickly when qBittorrent rejects the torrent with a 4xx", func() {
+			fake.addErr = fmt.Errorf("failed to add torrent: %w", &qbittorrent.StatusError{
+				Endpoint:   "/api/v2/torrents/add",
+				StatusCode: http.StatusBadRequest,
+				Status:     "400 Bad Request",
+			})
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(10 * time.Minute))
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			degraded := meta.FindStatusCondition(torrent.Status.Conditions, TypeDegradedTorrent)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal("TorrentRejected"))
+		})
+
+		It("should requeue quickly when qBittorrent answers with a 5xx", func() {
+			fake.addErr = fmt.Errorf("failed to add torrent: %w", &qbittorrent.StatusError{
+				Endpoint:   "/api/v2/torrents/add",
+				StatusCode: http.StatusInternalServerError,
+				Status:     "500 Internal Server Error",
+			})
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(10 * time.Second))
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			degraded := meta.FindStatusCondition(torrent.Status.Conditions, TypeDegradedTorrent)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal("FailedToAddTorrent"))
+		})
+	})
+
+	Context("When reconciliation is paused by annotation", func() {
+		const resourceName = "test-torrent-paused"
+		const tccName = "test-tcc-torrent-paused"
+		const secretName = "test-tcc-torrent-paused-creds"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, tccName, secretName)
+
+			resource := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        resourceName,
+					Namespace:   "default",
+					Annotations: map[string]string{ReconcileAnnotation: "false"},
+				},
+				Spec: torrentv1alpha1.TorrentSpec{
+					MagnetURI:       "magnet:?xt=urn:btih:dd8255ecdc7ca55fb0bbf81323d87062db1f6d1c&dn=Big+Buck+Bunny",
+					ClientConfigRef: &torrentv1alpha1.LocalObjectReference{Name: tccName},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			deleteTorrent(ctx, typeNamespacedName)
+			deleteTCC(ctx, tccName, secretName)
+		})
+
+		It("should neither add the torrent nor mutate the resource", func() {
+			before := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, before)).To(Succeed())
+
+			fake := newFakeQBTClient()
+			controllerReconciler := &TorrentReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(reconcile.Result{}))
+			Expect(fake.Calls()).To(BeEmpty())
+
+			after := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, after)).To(Succeed())
+			Expect(after.ResourceVersion).To(Equal(before.ResourceVersion))
+			Expect(after.Finalizers).To(BeEmpty())
+			Expect(after.Status.Conditions).To(BeEmpty())
+		})
+	})
+
+	Context("When multiple magnet sources are set", func() {
+		const resourceName = "test-torrent-sources"
+		const tccName = "test-tcc-sources"
+		const secretName = "test-tcc-sources-creds"
+		const firstHash = "dd8255ecdc7ca55fb0bbf81323d87062db1f6d1c"
+		const secondHash = "08ada5a7a6183aae1e09d831df6748d566095a10"
+
+		firstMagnet := "magnet:?xt=urn:btih:" + firstHash + "&dn=Big+Buck+Bunny"
+		secondMagnet := "magnet:?xt=urn:btih:" + secondHash + "&dn=Sintel"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var fake *fakeQBTClient
+		var controllerReconciler *TorrentReconciler
+
+		reconcileOnce := func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		// expireAttempt moves the current source attempt past the metadata timeout
+		expireAttempt := func() {
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			started := metav1.NewTime(time.Now().Add(-time.Hour))
+			torrent.Status.SourceAttemptStartedAt = &started
+			Expect(k8sClient.Status().Update(ctx, torrent)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, tccName, secretName)
+
+			resource := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       resourceName,
+					Namespace:  "default",
+					Finalizers: []string{TorrentFinalizer},
+				},
+				Spec: torrentv1alpha1.TorrentSpec{
+					MagnetURIs:      []string{firstMagnet, secondMagnet},
+					ClientConfigRef: &torrentv1alpha1.LocalObjectReference{Name: tccName},
+					MetadataTimeout: &metav1.Duration{Duration: 5 * time.Minute},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			fake = newFakeQBTClient()
+			controllerReconciler = &TorrentReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+		})
+
+		AfterEach(func() {
+			deleteTorrent(ctx, typeNamespacedName)
+			deleteTCC(ctx, tccName, secretName)
+		})
+
+		It("should pin the first source once it yields metadata", func() {
+			reconcileOnce()
+			Expect(fake.Calls()).To(ContainElement("AddTorrent:" + firstMagnet))
+
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: firstHash, Name: "Big Buck Bunny", State: "downloading"})
+			reconcileOnce()
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(torrent.Status.Source).To(Equal(firstMagnet))
+			Expect(torrent.Status.SourcePinned).To(BeTrue())
+			Expect(torrent.Status.Hash).To(Equal(firstHash))
+			Expect(fake.Calls()).NotTo(ContainElement("AddTorrent:" + secondMagnet))
+		})
+
+		It("should fall back to the next source when metadata does not arrive in time", func() {
+			reconcileOnce()
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: firstHash, State: "metaDL"})
+
+			// Still within the timeout: keep waiting on the first source
+			reconcileOnce()
+			Expect(fake.Calls()).NotTo(ContainElement(HavePrefix("DeleteTorrent")))
+
+			expireAttempt()
+			reconcileOnce()
+			Expect(fake.Calls()).To(ContainElement("DeleteTorrent:" + firstHash + ":true"))
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(torrent.Status.FailedSources).To(Equal([]string{firstMagnet}))
+
+			reconcileOnce()
+			Expect(fake.Calls()).To(ContainElement("AddTorrent:" + secondMagnet))
+
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: secondHash, Name: "Sintel", State: "downloading"})
+			reconcileOnce()
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(torrent.Status.Source).To(Equal(secondMagnet))
+			Expect(torrent.Status.SourcePinned).To(BeTrue())
+			Expect(torrent.Status.Hash).To(Equal(secondHash))
+		})
+
+		It("should set Degraded when every source fails", func() {
+			reconcileOnce()
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: firstHash, State: "metaDL"})
+			expireAttempt()
+			reconcileOnce()
+
+			reconcileOnce()
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: secondHash, State: "metaDL"})
+			expireAttempt()
+			reconcileOnce()
+
+			torrent := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+			Expect(torrent.Status.FailedSources).To(Equal([]string{firstMagnet, secondMagnet}))
+			degraded := meta.FindStatusCondition(torrent.Status.Conditions, TypeDegradedTorrent)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal("AllSourcesFailed"))
+
+			// No further source is attempted
+			calls := len(fake.Calls())
+			reconcileOnce()
+			Expect(fake.Calls()[calls:]).NotTo(ContainElement(HavePrefix("AddTorrent")))
+		})
+	})
+
+	Context("When the Torrent resource is deleted", func() {
+		const resourceName = "test-torrent-on-delete"
+		const tccName = "test-tcc-on-delete"
+		const secretName = "test-tcc-on-delete-creds"
+		const hash = "dd8255ecdc7ca55fb0bbf81323d87062db1f6d1c"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var fake *fakeQBTClient
+		var controllerReconciler *TorrentReconciler
+
+		// deleteWithPolicy creates a tracked Torrent with the given policy, deletes it and reconciles the deletion
+		deleteWithPolicy := func(policy torrentv1alpha1.DeletePolicy) {
+			resource := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       resourceName,
+					Namespace:  "default",
+					Finalizers: []string{TorrentFinalizer},
+				},
+				Spec: torrentv1alpha1.TorrentSpec{
+					MagnetURI:       "magnet:?xt=urn:btih:" + hash + "&dn=Big+Buck+Bunny",
+					ClientConfigRef: &torrentv1alpha1.LocalObjectReference{Name: tccName},
+					OnDelete:        policy,
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			resource.Status.Hash = hash
+			Expect(k8sClient.Status().Update(ctx, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = k8sClient.Get(ctx, typeNamespacedName, &torrentv1alpha1.Torrent{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		}
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, tccName, secretName)
+
+			fake = newFakeQBTClient()
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny"})
+			controllerReconciler = &TorrentReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+		})
+
+		AfterEach(func() {
+			deleteTorrent(ctx, typeNamespacedName)
+			deleteTCC(ctx, tccName, secretName)
+		})
+
+		It("should remove the torrent from qBittorrent by default", func() {
+			deleteWithPolicy("")
+			Expect(fake.Calls()).To(ContainElement("DeleteTorrent:" + hash + ":true"))
+		})
+
+		It("should leave the torrent in qBittorrent in orphan mode", func() {
+			deleteWithPolicy(torrentv1alpha1.DeletePolicyOrphan)
+			Expect(fake.Calls()).NotTo(ContainElement(HavePrefix("DeleteTorrent")))
+		})
+
+		It("should keep the downloaded files when file deletion is disallowed by the operator", func() {
+			controllerReconciler.DisallowFileDeletion = true
+			deleteWithPolicy("")
+			Expect(fake.Calls()).To(ContainElement("DeleteTorrent:" + hash + ":false"))
+		})
+
+		Context("with the archive-to annotation", func() {
+			reconcileOnce := func() {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			// deleteArchived creates a tracked Torrent archived to archivePath on deletion, then deletes it
+			deleteArchived := func(archivePath string) {
+				deleteFiles := true
+				resource := &torrentv1alpha1.Torrent{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        resourceName,
+						Namespace:   "default",
+						Finalizers:  []string{TorrentFinalizer},
+						Annotations: map[string]string{ArchiveOnDeleteAnnotation: archivePath},
+					},
+					Spec: torrentv1alpha1.TorrentSpec{
+						MagnetURI:            "magnet:?xt=urn:btih:" + hash + "&dn=Big+Buck+Bunny",
+						ClientConfigRef:      &torrentv1alpha1.LocalObjectReference{Name: tccName},
+						DeleteFilesOnRemoval: &deleteFiles,
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+				resource.Status.Hash = hash
+				Expect(k8sClient.Status().Update(ctx, resource)).To(Succeed())
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+
+			It("should move the files to the archive before removing the torrent, keeping them", func() {
+				fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", SavePath: "/downloads"})
+				deleteArchived("/downloads/archive")
+
+				By("moving the files first")
+				reconcileOnce()
+				Expect(fake.Calls()).To(ContainElement("SetTorrentLocation:" + hash + ":/downloads/archive"))
+				Expect(fake.Calls()).NotTo(ContainElement(HavePrefix("DeleteTorrent")))
+
+				By("waiting while qBittorrent moves them")
+				fake.SetTorrent(qbittorrent.TorrentInfo{
+					Hash: hash, Name: "Big Buck Bunny", SavePath: "/downloads/archive/", State: "moving",
+				})
+				reconcileOnce()
+				Expect(fake.Calls()).NotTo(ContainElement(HavePrefix("DeleteTorrent")))
+				Expect(k8sClient.Get(ctx, typeNamespacedName, &torrentv1alpha1.Torrent{})).To(Succeed())
+
+				By("removing the torrent without its files once moved, despite deleteFilesOnRemoval")
+				fake.SetTorrent(qbittorrent.TorrentInfo{
+					Hash: hash, Name: "Big Buck Bunny", SavePath: "/downloads/archive/", State: "stoppedUP",
+				})
+				reconcileOnce()
+				Expect(fake.Calls()).To(ContainElement("DeleteTorrent:" + hash + ":false"))
+				err := k8sClient.Get(ctx, typeNamespacedName, &torrentv1alpha1.Torrent{})
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+			})
+
+			It("should not request the move again until qBittorrent had time to start it", func() {
+				fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", SavePath: "/downloads"})
+				deleteArchived("/downloads/archive")
+				moves := func() int {
+					count := 0
+					for _, call := range fake.Calls() {
+						if strings.HasPrefix(call, "SetTorrentLocation:") {
+							count++
+						}
+					}
+					return count
+				}
+
+				reconcileOnce()
+				Expect(moves()).To(Equal(1))
+				torrent := &torrentv1alpha1.Torrent{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+				Expect(torrent.Status.ArchiveRequestedTo).To(Equal("/downloads/archive"))
+				Expect(torrent.Status.ArchiveRequestedAt).NotTo(BeNil())
+
+				By("waiting while qBittorrent has not started the move")
+				fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Big Buck Bunny", SavePath: "/downloads"})
+				reconcileOnce()
+				Expect(moves()).To(Equal(1))
+
+				By("requesting it again once the retry interval passed")
+				Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+				requestedAt := metav1.NewTime(time.Now().Add(-2 * archiveRetryInterval))
+				torrent.Status.ArchiveRequestedAt = &requestedAt
+				Expect(k8sClient.Status().Update(ctx, torrent)).To(Succeed())
+				reconcileOnce()
+				Expect(moves()).To(Equal(2))
+				Expect(fake.Calls()).NotTo(ContainElement(HavePrefix("DeleteTorrent")))
+
+				By("removing the torrent once moved")
+				reconcileOnce()
+				Expect(fake.Calls()).To(ContainElement("DeleteTorrent:" + hash + ":false"))
+				err := k8sClient.Get(ctx, typeNamespacedName, &torrentv1alpha1.Torrent{})
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+			})
+
+			It("should keep the Torrent and report a relative archive path", func() {
+				deleteArchived("archive")
+
+				reconcileOnce()
+				Expect(fake.Calls()).NotTo(ContainElement(HavePrefix("SetTorrentLocation")))
+				Expect(fake.Calls()).NotTo(ContainElement(HavePrefix("DeleteTorrent")))
+
+				torrent := &torrentv1alpha1.Torrent{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, torrent)).To(Succeed())
+				degraded := meta.FindStatusCondition(torrent.Status.Conditions, TypeDegradedTorrent)
+				Expect(degraded).NotTo(BeNil())
+				Expect(degraded.Reason).To(Equal("FailedToArchiveTorrent"))
+				Expect(degraded.Message).To(ContainSubstring("absolute path"))
+
+				By("removing the Torrent once the annotation is dropped")
+				delete(torrent.Annotations, ArchiveOnDeleteAnnotation)
+				Expect(k8sClient.Update(ctx, torrent)).To(Succeed())
+				reconcileOnce()
+				Expect(fake.Calls()).To(ContainElement("DeleteTorrent:" + hash + ":true"))
+				err := k8sClient.Get(ctx, typeNamespacedName, &torrentv1alpha1.Torrent{})
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+			})
+		})
+	})
+
+	Context("When two Torrents resolve to the same hash", func() {
+		const firstName = "test-torrent-duplicate-first"
+		const secondName = "test-torrent-duplicate-second"
+		const tccName = "test-tcc-duplicate"
+		const secretName = "test-tcc-duplicate-creds"
+		const hash = "c9e15763f722f23e98a29decdfae341b98d53056"
+
+		ctx := context.Background()
+
+		firstNamespacedName := types.NamespacedName{Name: firstName, Namespace: "default"}
+		secondNamespacedName := types.NamespacedName{Name: secondName, Namespace: "default"}
+
+		var fake *fakeQBTClient
+		var controllerReconciler *TorrentReconciler
+
+		createTorrent := func(name, magnet string) {
+			resource := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       name,
+					Namespace:  "default",
+					Finalizers: []string{TorrentFinalizer},
+				},
+				Spec: torrentv1alpha1.TorrentSpec{
+					MagnetURI:       magnet,
+					ClientConfigRef: &torrentv1alpha1.LocalObjectReference{Name: tccName},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		}
+
+		reconcileTorrent := func(name types.NamespacedName) {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: name})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, tccName, secretName)
+			createTorrent(firstName, "magnet:?xt=urn:btih:"+hash+"&dn=Cosmos+Laundromat")
+			// Same info hash, different casing and display name
+			createTorrent(secondName, "magnet:?xt=urn:btih:"+strings.ToUpper(hash)+"&dn=Copy")
+
+			fake = newFakeQBTClient()
+			controllerReconciler = &TorrentReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+		})
+
+		AfterEach(func() {
+			deleteTorrent(ctx, firstNamespacedName)
+			deleteTorrent(ctx, secondNamespacedName)
+			deleteTCC(ctx, tccName, secretName)
+		})
+
+		It("should refuse to reconcile the Torrent that does not own the hash", func() {
+			By("letting the first Torrent take ownership of the hash")
+			reconcileTorrent(firstNamespacedName)
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: hash, Name: "Cosmos Laundromat", State: "downloading"})
+			reconcileTorrent(firstNamespacedName)
+
+			first := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, firstNamespacedName, first)).To(Succeed())
+			Expect(first.Status.Hash).To(Equal(hash))
+
+			By("reconciling the duplicate")
+			calls := len(fake.Calls())
+			reconcileTorrent(secondNamespacedName)
+			Expect(fake.Calls()[calls:]).To(BeEmpty())
+
+			second := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, secondNamespacedName, second)).To(Succeed())
+			degraded := meta.FindStatusCondition(second.Status.Conditions, TypeDegradedTorrent)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal("DuplicateHash"))
+			Expect(degraded.Message).To(ContainSubstring(firstName))
+		})
+
+		It("should not delete the torrent from qBittorrent while another Torrent shares its hash", func() {
+			for _, name := range []types.NamespacedName{firstNamespacedName, secondNamespacedName} {
+				torrent := &torrentv1alpha1.Torrent{}
+				Expect(k8sClient.Get(ctx, name, torrent)).To(Succeed())
+				torrent.Status.Hash = hash
+				Expect(k8sClient.Status().Update(ctx, torrent)).To(Succeed())
+			}
+
+			second := &torrentv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, secondNamespacedName, second)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, second)).To(Succeed())
+			reconcileTorrent(secondNamespacedName)
+
+			Expect(fake.Calls()).NotTo(ContainElement(HavePrefix("DeleteTorrent")))
+			err := k8sClient.Get(ctx, secondNamespacedName, &torrentv1alpha1.Torrent{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+	})
 })
@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"slices"
+	"time"
+
+	torrentv1alpha1 "github.com/guidonguido/qbittorrent-operator/api/v1alpha1"
+	"github.com/guidonguido/qbittorrent-operator/internal/qbittorrent"
+)
+
+// defaultMetadataTimeout is used when spec.metadataTimeout is not set
+const defaultMetadataTimeout = 10 * time.Minute
+
+// torrentSources returns the magnet URIs to try in order:
+// spec.magnet_uri followed by spec.magnetURIs, without duplicates
+func torrentSources(torrent *torrentv1alpha1.Torrent) []string {
+	var sources []string
+	for _, source := range append([]string{torrent.Spec.MagnetURI}, torrent.Spec.MagnetURIs...) {
+		if source != "" && !slices.Contains(sources, source) {
+			sources = append(sources, source)
+		}
+	}
+	return sources
+}
+
+// selectSource returns the source to reconcile: the current one while it is still listed
+// in the spec and has not failed, otherwise the first source that has not failed.
+// It returns an empty string when every source failed.
+func selectSource(torrent *torrentv1alpha1.Torrent, sources []string) string {
+	failed := torrent.Status.FailedSources
+	if current := torrent.Status.Source; current != "" &&
+		slices.Contains(sources, current) && !slices.Contains(failed, current) {
+		return current
+	}
+	for _, source := range sources {
+		if !slices.Contains(failed, source) {
+			return source
+		}
+	}
+	return ""
+}
+
+// metadataTimeout returns how long a source may take to yield metadata
+func metadataTimeout(torrent *torrentv1alpha1.Torrent) time.Duration {
+	if torrent.Spec.MetadataTimeout != nil && torrent.Spec.MetadataTimeout.Duration > 0 {
+		return torrent.Spec.MetadataTimeout.Duration
+	}
+	return defaultMetadataTimeout
+}
+
+// hasMetadata reports whether qBittorrent finished fetching the torrent metadata
+func hasMetadata(info *qbittorrent.TorrentInfo) bool {
+	return info.State != "metaDL" && info.State != "forcedMetaDL"
+}
@@ -2,7 +2,10 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -23,12 +26,32 @@ import (
 const (
 	TypeAvailableTCC = "Available"
 	TypeDegradedTCC  = "Degraded"
+	// TypeMaintenanceTCC is informational: checks are failing within spec.maintenanceWindow
+	TypeMaintenanceTCC = "Maintenance"
+	// TypeUnsupportedVersionTCC warns that the qBittorrent version is older than the supported minimum
+	TypeUnsupportedVersionTCC = "UnsupportedVersion"
+	// TypePrivacyConflictTCC warns that spec.privacy disagrees with the managing TorrentServer, whose value is enforced
+	TypePrivacyConflictTCC = "PrivacyConflict"
+	// TypeCategoryConflictTCC warns that spec.categories disagrees with the categoryPaths of the managing
+	// TorrentServer, whose save path is enforced
+	TypeCategoryConflictTCC = "CategoryConflict"
+
+	// defaultTCCFailureThreshold applies when spec.failureThreshold is unset
+	defaultTCCFailureThreshold = 3
 )
 
 type TorrentClientConfigurationReconciler struct {
 	client.Client
 	Scheme     *runtime.Scheme
 	ClientPool *qbittorrent.ClientPool
+	// BearerTokenDir is the directory spec.auth.tokenFile paths are resolved in. Empty disables token files.
+	BearerTokenDir string
+	// MinQBittorrentVersion is the oldest qBittorrent version not reported by the UnsupportedVersion condition.
+	// qbittorrent.DefaultMinQBittorrentVersion applies when empty.
+	MinQBittorrentVersion string
+
+	// now is replaced in tests to move in and out of the maintenance window
+	now func() time.Time
 }
 
 // +kubebuilder:rbac:groups=torrent.qbittorrent.io,resources=torrentclientconfigurations,verbs=get;list;watch;create;update;patch;delete
@@ -47,6 +70,12 @@ func (r *TorrentClientConfigurationReconciler) Reconcile(ctx context.Context, re
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// 1.1. Paused resources are left untouched until the reconcile annotation is removed
+	if isReconcilePaused(tcc) {
+		logger.Info("Reconciliation paused by annotation", "annotation", ReconcileAnnotation)
+		return ctrl.Result{}, nil
+	}
+
 	// 2. Parse check interval
 	checkInterval := 60 * time.Second
 	if tcc.Spec.CheckInterval != "" {
@@ -58,70 +87,65 @@ func (r *TorrentClientConfigurationReconciler) Reconcile(ctx context.Context, re
 		}
 	}
 
-	// 4. Validate the creds Secret exists and has required keys
-	secret := &corev1.Secret{}
-	if err := r.Get(ctx, types.NamespacedName{Name: tcc.Spec.CredentialsSecret.Name, Namespace: tcc.Namespace}, secret); err != nil {
-		r.setDegradedCondition(tcc, "SecretNotFound",
-			fmt.Sprintf("Credentials secret %q not found: %v", tcc.Spec.CredentialsSecret.Name, err))
-		tcc.Status.Connected = false
-		now := metav1.Now()
-		tcc.Status.LastChecked = &now
-		if statusErr := r.Status().Update(ctx, tcc); statusErr != nil {
-			logger.Error(statusErr, "Failed to update TCC status")
-		}
+	// 4-5. Validate the credentials (creds Secret keys or bearer token) and test connectivity to qBittorrent.
+	// The pooled client is the one the Torrents of this TCC reuse
+	qbtClient, err := clientForTCC(ctx, r.Client, r.ClientPool, tcc, r.BearerTokenDir)
+	switch {
+	case errors.Is(err, errCredentialsNotFound):
+		r.recordFailure(ctx, tcc, "SecretNotFound", fmt.Sprintf("Credentials not found: %v", err))
 		return ctrl.Result{RequeueAfter: checkInterval}, nil
-	}
-
-	usernameBytes, hasUsername := secret.Data["username"]
-	passwordBytes, hasPassword := secret.Data["password"]
-	if !hasUsername || !hasPassword {
-		r.setDegradedCondition(tcc, "SecretInvalid",
-			fmt.Sprintf("Credentials secret %q missing 'username' or 'password' key", tcc.Spec.CredentialsSecret.Name))
-		tcc.Status.Connected = false
-		now := metav1.Now()
-		tcc.Status.LastChecked = &now
-		if statusErr := r.Status().Update(ctx, tcc); statusErr != nil {
-			logger.Error(statusErr, "Failed to update TCC status")
-		}
+	case errors.Is(err, errCredentialsInvalid):
+		r.recordFailure(ctx, tcc, "SecretInvalid", fmt.Sprintf("Credentials invalid: %v", err))
 		return ctrl.Result{RequeueAfter: checkInterval}, nil
-	}
-
-	// 5. Test connectivity to qBittorrent
-	qbtClient, err := r.ClientPool.GetOrCreate(
-		ctx,
-		tcc.Spec.URL,
-		string(usernameBytes),
-		string(passwordBytes),
-	)
-	if err != nil {
-		r.setDegradedCondition(tcc, "ClientCreationFailed",
+	case err != nil:
+		r.recordCheckFailure(ctx, tcc, "ClientCreationFailed",
 			fmt.Sprintf("Failed to create qBittorrent client for %s: %v", tcc.Spec.URL, err))
-		tcc.Status.Connected = false
-		now := metav1.Now()
-		tcc.Status.LastChecked = &now
-		if statusErr := r.Status().Update(ctx, tcc); statusErr != nil {
-			logger.Error(statusErr, "Failed to update TCC status")
-		}
 		return ctrl.Result{RequeueAfter: checkInterval}, nil
 	}
 
 	// 6. Health check towards qBittorrent server
 	if err := qbtClient.Ping(ctx); err != nil {
-		r.setDegradedCondition(tcc, "HealthCheckFailed",
+		r.recordCheckFailure(ctx, tcc, "HealthCheckFailed",
 			fmt.Sprintf("qBittorrent health check failed at %s: %v", tcc.Spec.URL, err))
-		tcc.Status.Connected = false
-		now := metav1.Now()
-		tcc.Status.LastChecked = &now
-		if statusErr := r.Status().Update(ctx, tcc); statusErr != nil {
-			logger.Error(statusErr, "Failed to update TCC status")
-		}
 		return ctrl.Result{RequeueAfter: checkInterval}, nil
 	}
 
-	// 7. If previous checks passed, TCC is available
+	// 7. Detect the qBittorrent and WebUI API versions, keeping the previous values on failure
+	if version, err := qbtClient.GetAppVersion(ctx); err != nil {
+		logger.Error(err, "Failed to detect qBittorrent version", "url", tcc.Spec.URL)
+	} else {
+		tcc.Status.QBittorrentVersion = version
+	}
+	if apiVersion, err := qbtClient.GetAPIVersion(ctx); err != nil {
+		logger.Error(err, "Failed to detect qBittorrent API version", "url", tcc.Spec.URL)
+	} else {
+		tcc.Status.APIVersion = apiVersion
+	}
+	r.setUnsupportedVersionCondition(tcc)
+
+	// 7.1. Enforce the declared privacy settings, re-applying them when changed out-of-band.
+	// Steps 7.1 and 7.2 are independent: both run and their failures are reported together
+	var failed stepErrors
+	if err := r.reconcilePrivacy(ctx, tcc, qbtClient); err != nil {
+		failed.add("PrivacyEnforcementFailed", fmt.Errorf("failed to enforce privacy settings: %w", err))
+	}
+
+	// 7.2. Create the declared categories and edit back those whose save path drifted
+	if err := r.reconcileCategories(ctx, tcc, qbtClient); err != nil {
+		failed.add("CategoryEnforcementFailed", fmt.Errorf("failed to enforce categories: %w", err))
+	}
+	if !failed.empty() {
+		r.recordCheckFailure(ctx, tcc, failed.reason(),
+			fmt.Sprintf("Failed to enforce the configuration at %s: %s", tcc.Spec.URL, failed.message()))
+		return ctrl.Result{RequeueAfter: checkInterval}, nil
+	}
+
+	// 8. If previous checks passed, TCC is available
 	r.setAvailableCondition(tcc, "Connected",
 		fmt.Sprintf("Successfully connected to qBittorrent at %s", tcc.Spec.URL))
 	tcc.Status.Connected = true
+	tcc.Status.ConsecutiveFailures = 0
+	meta.RemoveStatusCondition(&tcc.Status.Conditions, TypeMaintenanceTCC)
 	now := metav1.Now()
 	tcc.Status.LastChecked = &now
 
@@ -134,6 +158,307 @@ func (r *TorrentClientConfigurationReconciler) Reconcile(ctx context.Context, re
 	return ctrl.Result{RequeueAfter: checkInterval}, nil
 }
 
+// Compare the declared privacy settings with the running instance and re-apply only the drifted keys.
+// The keys also enforced by the managing TorrentServer are left to it.
+func (r *TorrentClientConfigurationReconciler) reconcilePrivacy(ctx context.Context, tcc *torrentv1alpha1.TorrentClientConfiguration, qbtClient qbittorrent.QBTClient) error {
+	logger := log.FromContext(ctx)
+
+	desired := desiredPrivacyPreferences(tcc.Spec.Privacy)
+	server, serverName, err := serverPreferences(ctx, r.Client, tcc)
+	if err != nil {
+		return err
+	}
+	var conflicting []string
+	for key, value := range desired {
+		serverValue, ok := server[key]
+		if !ok {
+			continue
+		}
+		delete(desired, key)
+		if serverValue != value {
+			conflicting = append(conflicting, key)
+		}
+	}
+	r.setPrivacyConflictCondition(tcc, serverName, conflicting)
+	if len(desired) == 0 {
+		return nil
+	}
+
+	current, err := qbtClient.GetPreferences(ctx)
+	if err != nil {
+		return err
+	}
+
+	drifted := qbittorrent.DiffPreferences(current, desired)
+	if len(drifted) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(drifted))
+	for key := range drifted {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	logger.Info("Correcting drifted privacy preferences", "keys", keys)
+	return qbtClient.SetPreferences(ctx, drifted)
+}
+
+// Report the spec.privacy keys whose value differs from the one enforced by the managing TorrentServer,
+// removing the condition when they agree
+func (r *TorrentClientConfigurationReconciler) setPrivacyConflictCondition(tcc *torrentv1alpha1.TorrentClientConfiguration, serverName string, conflicting []string) {
+	if len(conflicting) == 0 {
+		meta.RemoveStatusCondition(&tcc.Status.Conditions, TypePrivacyConflictTCC)
+		return
+	}
+	sort.Strings(conflicting)
+	condition := metav1.Condition{
+		Type:   TypePrivacyConflictTCC,
+		Status: metav1.ConditionTrue,
+		Reason: "DeclaredByTorrentServer",
+		Message: fmt.Sprintf("%s also declared by TorrentServer %s with another value: the TorrentServer value is enforced",
+			strings.Join(conflicting, ", "), serverName),
+		ObservedGeneration: tcc.Generation,
+		LastTransitionTime: metav1.NewTime(time.Now()),
+	}
+	meta.SetStatusCondition(&tcc.Status.Conditions, condition)
+}
+
+// Warn while the detected qBittorrent version is older than the supported minimum, without blocking anything
+func (r *TorrentClientConfigurationReconciler) setUnsupportedVersionCondition(tcc *torrentv1alpha1.TorrentClientConfiguration) {
+	condition := unsupportedVersionCondition(tcc.Status.QBittorrentVersion, r.MinQBittorrentVersion, tcc.Generation)
+	if condition == nil {
+		meta.RemoveStatusCondition(&tcc.Status.Conditions, TypeUnsupportedVersionTCC)
+		return
+	}
+	meta.SetStatusCondition(&tcc.Status.Conditions, *condition)
+}
+
+// unsupportedVersionCondition returns the UnsupportedVersion condition for a qBittorrent version older than
+// minimum, or qbittorrent.DefaultMinQBittorrentVersion when empty. It returns nil for a supported or unknown version.
+func unsupportedVersionCondition(version, minimum string, generation int64) *metav1.Condition {
+	if minimum == "" {
+		minimum = qbittorrent.DefaultMinQBittorrentVersion
+	}
+	if !qbittorrent.AppVersionBelow(version, minimum) {
+		return nil
+	}
+	return &metav1.Condition{
+		Type:   TypeUnsupportedVersionTCC,
+		Status: metav1.ConditionTrue,
+		Reason: "VersionBelowMinimum",
+		Message: fmt.Sprintf("qBittorrent %s is older than %s, the oldest version supported by the operator: "+
+			"upgrade qBittorrent, as credentials and some features may not work", version, minimum),
+		ObservedGeneration: generation,
+		LastTransitionTime: metav1.NewTime(time.Now()),
+	}
+}
+
+// Compare the declared categories with the running instance, creating the missing ones and
+// editing the ones whose save path differs. Undeclared categories are never touched, and the
+// categories also declared in the categoryPaths of the managing TorrentServer are left to it.
+func (r *TorrentClientConfigurationReconciler) reconcileCategories(ctx context.Context, tcc *torrentv1alpha1.TorrentClientConfiguration, qbtClient qbittorrent.QBTClient) error {
+	server, serverName, err := serverCategoryPaths(ctx, r.Client, tcc)
+	if err != nil {
+		return err
+	}
+	categories := make([]torrentv1alpha1.CategorySpec, 0, len(tcc.Spec.Categories))
+	var conflicting []string
+	for _, category := range tcc.Spec.Categories {
+		serverPath, ok := server[category.Name]
+		if !ok {
+			categories = append(categories, category)
+			continue
+		}
+		if serverPath != category.SavePath {
+			conflicting = append(conflicting, category.Name)
+		}
+	}
+	r.setCategoryConflictCondition(tcc, serverName, conflicting)
+
+	_, err = enforceCategories(ctx, qbtClient, categories)
+	return err
+}
+
+// Report the spec.categories whose save path differs from the categoryPaths of the managing TorrentServer,
+// removing the condition when they agree
+func (r *TorrentClientConfigurationReconciler) setCategoryConflictCondition(tcc *torrentv1alpha1.TorrentClientConfiguration, serverName string, conflicting []string) {
+	if len(conflicting) == 0 {
+		meta.RemoveStatusCondition(&tcc.Status.Conditions, TypeCategoryConflictTCC)
+		return
+	}
+	sort.Strings(conflicting)
+	condition := metav1.Condition{
+		Type:   TypeCategoryConflictTCC,
+		Status: metav1.ConditionTrue,
+		Reason: "DeclaredByTorrentServer",
+		Message: fmt.Sprintf("%s also declared by TorrentServer %s with another save path: the TorrentServer save path is enforced",
+			strings.Join(conflicting, ", "), serverName),
+		ObservedGeneration: tcc.Generation,
+		LastTransitionTime: metav1.NewTime(time.Now()),
+	}
+	meta.SetStatusCondition(&tcc.Status.Conditions, condition)
+}
+
+// enforceCategories creates the missing categories and edits back the drifted save paths,
+// returning the names of the categories it changed
+func enforceCategories(ctx context.Context, qbtClient qbittorrent.QBTClient, categories []torrentv1alpha1.CategorySpec) ([]string, error) {
+	logger := log.FromContext(ctx)
+
+	if len(categories) == 0 {
+		return nil, nil
+	}
+
+	current, err := qbtClient.GetCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for _, category := range categories {
+		existing, ok := current[category.Name]
+		switch {
+		case !ok:
+			logger.Info("Creating missing category", "category", category.Name, "savePath", category.SavePath)
+			if err := qbtClient.CreateCategory(ctx, category.Name, category.SavePath); err != nil {
+				return changed, err
+			}
+		case existing.SavePath != category.SavePath:
+			logger.Info("Correcting drifted category save path", "category", category.Name,
+				"savePath", category.SavePath, "currentSavePath", existing.SavePath)
+			if err := qbtClient.EditCategory(ctx, category.Name, category.SavePath); err != nil {
+				return changed, err
+			}
+		default:
+			continue
+		}
+		changed = append(changed, category.Name)
+	}
+	return changed, nil
+}
+
+// desiredPrivacyPreferences maps the set privacy fields to their qBittorrent preference keys
+func desiredPrivacyPreferences(privacy *torrentv1alpha1.PrivacySpec) map[string]any {
+	desired := make(map[string]any, 3)
+	if privacy == nil {
+		return desired
+	}
+	if privacy.DHT != nil {
+		desired[qbittorrent.PreferenceDHT] = *privacy.DHT
+	}
+	if privacy.PeX != nil {
+		desired[qbittorrent.PreferencePeX] = *privacy.PeX
+	}
+	if privacy.LSD != nil {
+		desired[qbittorrent.PreferenceLSD] = *privacy.LSD
+	}
+	return desired
+}
+
+// Record a failed check against qBittorrent. Within spec.maintenanceWindow the failure only sets
+// the Maintenance condition, leaving Available/Degraded and the failure count untouched.
+func (r *TorrentClientConfigurationReconciler) recordCheckFailure(ctx context.Context, tcc *torrentv1alpha1.TorrentClientConfiguration, reason, message string) {
+	logger := log.FromContext(ctx)
+
+	end, inWindow, err := maintenanceWindowEnd(tcc.Spec.MaintenanceWindow, r.clock())
+	if err != nil {
+		logger.Error(err, "Invalid maintenanceWindow, ignoring it")
+	}
+	if !inWindow {
+		r.recordFailure(ctx, tcc, reason, message)
+		return
+	}
+
+	logger.Info("TCC check failed within the maintenance window", "reason", reason, "windowEnd", end)
+	meta.SetStatusCondition(&tcc.Status.Conditions, metav1.Condition{
+		Type:               TypeMaintenanceTCC,
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		Message:            fmt.Sprintf("%s (maintenance window until %s)", message, end.Format(time.RFC3339)),
+		LastTransitionTime: metav1.NewTime(time.Now()),
+	})
+
+	tcc.Status.Connected = false
+	now := metav1.Now()
+	tcc.Status.LastChecked = &now
+	if err := r.Status().Update(ctx, tcc); err != nil {
+		logger.Error(err, "Failed to update TCC status")
+	}
+}
+
+func (r *TorrentClientConfigurationReconciler) clock() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+// maintenanceWindowEnd reports whether now falls within the daily window and when that window closes.
+// The window opened today or, when it spans midnight, yesterday.
+func maintenanceWindowEnd(window *torrentv1alpha1.MaintenanceWindow, now time.Time) (time.Time, bool, error) {
+	if window == nil {
+		return time.Time{}, false, nil
+	}
+
+	start, err := time.Parse("15:04", window.Start)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid start %q: %w", window.Start, err)
+	}
+	duration, err := time.ParseDuration(window.Duration)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid duration %q: %w", window.Duration, err)
+	}
+	if duration <= 0 || duration > 24*time.Hour {
+		return time.Time{}, false, fmt.Errorf("invalid duration %q: must be positive and at most 24h", window.Duration)
+	}
+	location := time.UTC
+	if window.TimeZone != "" {
+		if location, err = time.LoadLocation(window.TimeZone); err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid timeZone %q: %w", window.TimeZone, err)
+		}
+	}
+
+	local := now.In(location)
+	for _, day := range []int{0, -1} {
+		opens := time.Date(local.Year(), local.Month(), local.Day()+day, start.Hour(), start.Minute(), 0, 0, location)
+		closes := opens.Add(duration)
+		if !local.Before(opens) && local.Before(closes) {
+			return closes, true, nil
+		}
+	}
+	return time.Time{}, false, nil
+}
+
+// Record a failed check. An Available TCC only turns Degraded after spec.failureThreshold
+// consecutive failures; a TCC that is not Available yet is marked Degraded right away.
+func (r *TorrentClientConfigurationReconciler) recordFailure(ctx context.Context, tcc *torrentv1alpha1.TorrentClientConfiguration, reason, message string) {
+	logger := log.FromContext(ctx)
+
+	threshold := tcc.Spec.FailureThreshold
+	if threshold < 1 {
+		threshold = defaultTCCFailureThreshold
+	}
+
+	tcc.Status.ConsecutiveFailures++
+	if !meta.IsStatusConditionTrue(tcc.Status.Conditions, TypeAvailableTCC) || tcc.Status.ConsecutiveFailures >= threshold {
+		r.setDegradedCondition(tcc, reason, message)
+	} else {
+		logger.Info("TCC check failed, keeping Available within the failure threshold",
+			"reason", reason,
+			"consecutiveFailures", tcc.Status.ConsecutiveFailures,
+			"failureThreshold", threshold,
+		)
+	}
+
+	meta.RemoveStatusCondition(&tcc.Status.Conditions, TypeMaintenanceTCC)
+	tcc.Status.Connected = false
+	now := metav1.Now()
+	tcc.Status.LastChecked = &now
+	if err := r.Status().Update(ctx, tcc); err != nil {
+		logger.Error(err, "Failed to update TCC status")
+	}
+}
+
 func (r *TorrentClientConfigurationReconciler) setAvailableCondition(tcc *torrentv1alpha1.TorrentClientConfiguration, reason, message string) {
 	condition := metav1.Condition{
 		Type:               TypeAvailableTCC,
@@ -174,7 +499,7 @@ func (r *TorrentClientConfigurationReconciler) findTCCForSecret(ctx context.Cont
 
 	var requests []reconcile.Request
 	for _, tcc := range tccList.Items {
-		if tcc.Spec.CredentialsSecret.Name == secret.Name {
+		if tcc.Spec.CredentialsSecret != nil && tcc.Spec.CredentialsSecret.Name == secret.Name {
 			requests = append(requests, reconcile.Request{
 				NamespacedName: types.NamespacedName{
 					Name:      tcc.Name,
@@ -187,6 +512,7 @@ func (r *TorrentClientConfigurationReconciler) findTCCForSecret(ctx context.Cont
 }
 
 func (r *TorrentClientConfigurationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	registerDegradedTCCMetric(mgr.GetCache())
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&torrentv1alpha1.TorrentClientConfiguration{}).
 		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.findTCCForSecret)).
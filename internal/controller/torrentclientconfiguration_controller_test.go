@@ -2,16 +2,22 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	torrentv1alpha1 "github.com/guidonguido/qbittorrent-operator/api/v1alpha1"
+	"github.com/guidonguido/qbittorrent-operator/internal/qbittorrent"
 )
 
 var _ = Describe("TorrentClientConfiguration Controller", func() {
@@ -37,7 +43,7 @@ var _ = Describe("TorrentClientConfiguration Controller", func() {
 					},
 					Spec: torrentv1alpha1.TorrentClientConfigurationSpec{
 						URL: "http://qbittorrent:8080",
-						CredentialsSecret: torrentv1alpha1.SecretReference{
+						CredentialsSecret: &torrentv1alpha1.SecretReference{
 							Name: "nonexistent-secret",
 						},
 					},
@@ -112,7 +118,7 @@ var _ = Describe("TorrentClientConfiguration Controller", func() {
 					},
 					Spec: torrentv1alpha1.TorrentClientConfigurationSpec{
 						URL: "http://qbittorrent:8080",
-						CredentialsSecret: torrentv1alpha1.SecretReference{
+						CredentialsSecret: &torrentv1alpha1.SecretReference{
 							Name: secretName,
 						},
 					},
@@ -150,4 +156,709 @@ var _ = Describe("TorrentClientConfiguration Controller", func() {
 			Expect(tcc.Status.Conditions[0].Reason).To(Equal("SecretInvalid"))
 		})
 	})
+
+	Context("When qBittorrent is reachable", func() {
+		const resourceName = "test-tcc-versions"
+		const secretName = "test-tcc-versions-creds"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, resourceName, secretName)
+		})
+
+		AfterEach(func() {
+			deleteTCC(ctx, resourceName, secretName)
+		})
+
+		It("should report the qBittorrent and WebUI API versions in status", func() {
+			fake := newFakeQBTClient()
+			fake.appVersion = "v4.6.2"
+			fake.apiVersion = "2.9.3"
+			controllerReconciler := &TorrentClientConfigurationReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tcc)).To(Succeed())
+			Expect(tcc.Status.Connected).To(BeTrue())
+			Expect(tcc.Status.QBittorrentVersion).To(Equal("v4.6.2"))
+			Expect(tcc.Status.APIVersion).To(Equal("2.9.3"))
+		})
+
+		It("should warn without blocking while qBittorrent is older than the supported minimum", func() {
+			fake := newFakeQBTClient()
+			fake.appVersion = "v4.1.9"
+			controllerReconciler := &TorrentClientConfigurationReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+			reconcileOnce := func() *torrentv1alpha1.TorrentClientConfiguration {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, tcc)).To(Succeed())
+				return tcc
+			}
+
+			tcc := reconcileOnce()
+			unsupported := meta.FindStatusCondition(tcc.Status.Conditions, TypeUnsupportedVersionTCC)
+			Expect(unsupported).NotTo(BeNil())
+			Expect(unsupported.Status).To(Equal(metav1.ConditionTrue))
+			Expect(unsupported.Reason).To(Equal("VersionBelowMinimum"))
+			Expect(unsupported.Message).To(ContainSubstring("v4.1.9"))
+			Expect(unsupported.Message).To(ContainSubstring(qbittorrent.DefaultMinQBittorrentVersion))
+			Expect(meta.IsStatusConditionTrue(tcc.Status.Conditions, TypeAvailableTCC)).To(BeTrue())
+
+			By("removing the warning once qBittorrent is upgraded")
+			fake.mu.Lock()
+			fake.appVersion = "v4.6.2"
+			fake.mu.Unlock()
+			tcc = reconcileOnce()
+			Expect(meta.FindStatusCondition(tcc.Status.Conditions, TypeUnsupportedVersionTCC)).To(BeNil())
+
+			By("honouring a configured minimum")
+			controllerReconciler.MinQBittorrentVersion = "5.0.0"
+			tcc = reconcileOnce()
+			Expect(meta.IsStatusConditionTrue(tcc.Status.Conditions, TypeUnsupportedVersionTCC)).To(BeTrue())
+		})
+
+		It("should prime the pooled session its Torrents reuse", func() {
+			fake := newFakeQBTClient()
+			pool := newFakeClientPool(fake)
+			tccReconciler := &TorrentClientConfigurationReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: pool,
+			}
+			_, err := tccReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fake.Calls()).To(ContainElement("Login:admin"))
+			primed := len(fake.Calls())
+
+			torrentName := types.NamespacedName{Name: "test-tcc-versions-torrent", Namespace: "default"}
+			torrent := &torrentv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{Name: torrentName.Name, Namespace: "default"},
+				Spec: torrentv1alpha1.TorrentSpec{
+					MagnetURI:       "magnet:?xt=urn:btih:dd8255ecdc7ca55fb0bbf81323d87062db1f6d1c",
+					ClientConfigRef: &torrentv1alpha1.LocalObjectReference{Name: resourceName},
+				},
+			}
+			Expect(k8sClient.Create(ctx, torrent)).To(Succeed())
+			defer deleteTorrent(ctx, torrentName)
+
+			torrentReconciler := &TorrentReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: pool,
+			}
+			for range 2 {
+				_, err = torrentReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: torrentName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			torrentCalls := fake.Calls()[primed:]
+			Expect(torrentCalls).To(ContainElement(HavePrefix("AddTorrent:")))
+			Expect(torrentCalls).NotTo(ContainElement(HavePrefix("Login:")))
+		})
+	})
+
+	Context("When bearer authentication is declared", func() {
+		const resourceName = "test-tcc-bearer"
+		const secretName = "test-tcc-bearer-creds"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		setAuth := func(auth *torrentv1alpha1.ClientAuthSpec) {
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tcc)).To(Succeed())
+			tcc.Spec.Auth = auth
+			Expect(k8sClient.Update(ctx, tcc)).To(Succeed())
+		}
+
+		// A newly created TCC has no Available condition, so a single failure turns it Degraded
+		clearAvailable := func() {
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tcc)).To(Succeed())
+			tcc.Status.Conditions = nil
+			Expect(k8sClient.Status().Update(ctx, tcc)).To(Succeed())
+		}
+
+		reconcileWith := func(fake *fakeQBTClient, tokenDir string) *torrentv1alpha1.TorrentClientConfiguration {
+			controllerReconciler := &TorrentClientConfigurationReconciler{
+				Client:         k8sClient,
+				Scheme:         k8sClient.Scheme(),
+				ClientPool:     newFakeClientPool(fake),
+				BearerTokenDir: tokenDir,
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tcc)).To(Succeed())
+			return tcc
+		}
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, resourceName, secretName)
+		})
+
+		AfterEach(func() {
+			deleteTCC(ctx, resourceName, secretName)
+		})
+
+		It("should send the token key of the credentials secret instead of logging in", func() {
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: secretName, Namespace: "default"}, secret)).To(Succeed())
+			secret.Data["token"] = []byte("secret-token\n")
+			Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+			setAuth(&torrentv1alpha1.ClientAuthSpec{Type: torrentv1alpha1.ClientAuthBearer})
+
+			fake := newFakeQBTClient()
+			tcc := reconcileWith(fake, "")
+			Expect(tcc.Status.Connected).To(BeTrue())
+			Expect(fake.Calls()).To(ContainElement("SetBearerToken:secret-token"))
+			Expect(fake.Calls()).NotTo(ContainElement(HavePrefix("Login:")))
+		})
+
+		It("should read the token file from the bearer token directory", func() {
+			tokenDir := GinkgoT().TempDir()
+			Expect(os.WriteFile(filepath.Join(tokenDir, "qbittorrent"), []byte("file-token"), 0o600)).To(Succeed())
+			setAuth(&torrentv1alpha1.ClientAuthSpec{Type: torrentv1alpha1.ClientAuthBearer, TokenFile: "qbittorrent"})
+
+			fake := newFakeQBTClient()
+			tcc := reconcileWith(fake, tokenDir)
+			Expect(tcc.Status.Connected).To(BeTrue())
+			Expect(fake.Calls()).To(ContainElement("SetBearerToken:file-token"))
+		})
+
+		It("should read the token file when no credentials secret is referenced", func() {
+			tokenDir := GinkgoT().TempDir()
+			Expect(os.WriteFile(filepath.Join(tokenDir, "qbittorrent"), []byte("file-token"), 0o600)).To(Succeed())
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tcc)).To(Succeed())
+			tcc.Spec.CredentialsSecret = nil
+			tcc.Spec.Auth = &torrentv1alpha1.ClientAuthSpec{Type: torrentv1alpha1.ClientAuthBearer, TokenFile: "qbittorrent"}
+			Expect(k8sClient.Update(ctx, tcc)).To(Succeed())
+
+			fake := newFakeQBTClient()
+			tcc = reconcileWith(fake, tokenDir)
+			Expect(tcc.Spec.CredentialsSecret).To(BeNil())
+			Expect(tcc.Status.Connected).To(BeTrue())
+			Expect(fake.Calls()).To(ContainElement("SetBearerToken:file-token"))
+		})
+
+		It("should refuse a token file outside the bearer token directory", func() {
+			tokenDir := GinkgoT().TempDir()
+			setAuth(&torrentv1alpha1.ClientAuthSpec{
+				Type:      torrentv1alpha1.ClientAuthBearer,
+				TokenFile: "../../var/run/secrets/kubernetes.io/serviceaccount/token",
+			})
+			clearAvailable()
+
+			fake := newFakeQBTClient()
+			tcc := reconcileWith(fake, tokenDir)
+			degraded := meta.FindStatusCondition(tcc.Status.Conditions, TypeDegradedTCC)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal("SecretInvalid"))
+			Expect(fake.Calls()).NotTo(ContainElement(HavePrefix("SetBearerToken:")))
+		})
+
+		It("should refuse a token file when no bearer token directory is configured", func() {
+			setAuth(&torrentv1alpha1.ClientAuthSpec{Type: torrentv1alpha1.ClientAuthBearer, TokenFile: "qbittorrent"})
+			clearAvailable()
+
+			tcc := reconcileWith(newFakeQBTClient(), "")
+			degraded := meta.FindStatusCondition(tcc.Status.Conditions, TypeDegradedTCC)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal("SecretInvalid"))
+		})
+	})
+
+	Context("When privacy settings are declared", func() {
+		const resourceName = "test-tcc-privacy"
+		const secretName = "test-tcc-privacy-creds"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var (
+			fake                 *fakeQBTClient
+			controllerReconciler *TorrentClientConfigurationReconciler
+		)
+
+		reconcileTCC := func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, resourceName, secretName)
+
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tcc)).To(Succeed())
+			disabled := false
+			tcc.Spec.Privacy = &torrentv1alpha1.PrivacySpec{
+				DHT: &disabled,
+				PeX: &disabled,
+			}
+			Expect(k8sClient.Update(ctx, tcc)).To(Succeed())
+
+			fake = newFakeQBTClient()
+			fake.SetPreference("dht", true)
+			fake.SetPreference("pex", true)
+			fake.SetPreference("lsd", true)
+			controllerReconciler = &TorrentClientConfigurationReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+		})
+
+		AfterEach(func() {
+			deleteTCC(ctx, resourceName, secretName)
+		})
+
+		It("should apply only the declared privacy preferences", func() {
+			reconcileTCC()
+
+			Expect(fake.Calls()).To(ContainElement("SetPreferences:dht|pex"))
+			Expect(fake.Preference("dht")).To(Equal(false))
+			Expect(fake.Preference("pex")).To(Equal(false))
+			Expect(fake.Preference("lsd")).To(Equal(true))
+
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tcc)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(tcc.Status.Conditions, TypeAvailableTCC)).To(BeTrue())
+		})
+
+		It("should re-enforce a privacy preference changed out-of-band", func() {
+			reconcileTCC()
+			applied := len(fake.Calls())
+			reconcileTCC()
+			Expect(fake.Calls()[applied:]).NotTo(ContainElement(HavePrefix("SetPreferences:")))
+
+			fake.SetPreference("dht", true)
+			reconcileTCC()
+
+			Expect(fake.Calls()).To(ContainElement("SetPreferences:dht"))
+			Expect(fake.Preference("dht")).To(Equal(false))
+		})
+
+		It("should leave the keys declared by the managing TorrentServer to it", func() {
+			enabled, disabled := true, false
+			deleteServer := manageTCCByServer(ctx, resourceName, torrentv1alpha1.TorrentServerSpec{
+				BitTorrent: &torrentv1alpha1.BitTorrentSpec{EnableDHT: &enabled, EnablePEX: &disabled},
+			})
+			defer deleteServer()
+
+			reconcileTCC()
+			Expect(fake.Calls()).NotTo(ContainElement(HavePrefix("SetPreferences:")))
+			Expect(fake.Preference("dht")).To(Equal(true))
+
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tcc)).To(Succeed())
+			conflict := meta.FindStatusCondition(tcc.Status.Conditions, TypePrivacyConflictTCC)
+			Expect(conflict).NotTo(BeNil())
+			Expect(conflict.Reason).To(Equal("DeclaredByTorrentServer"))
+			Expect(conflict.Message).To(HavePrefix("dht also declared by TorrentServer " + resourceName + "-server"))
+			Expect(meta.IsStatusConditionTrue(tcc.Status.Conditions, TypeAvailableTCC)).To(BeTrue())
+
+			By("removing the condition once both agree")
+			tcc.Spec.Privacy.DHT = &enabled
+			Expect(k8sClient.Update(ctx, tcc)).To(Succeed())
+			reconcileTCC()
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tcc)).To(Succeed())
+			Expect(meta.FindStatusCondition(tcc.Status.Conditions, TypePrivacyConflictTCC)).To(BeNil())
+		})
+	})
+
+	Context("When categories are declared", func() {
+		const resourceName = "test-tcc-categories"
+		const secretName = "test-tcc-categories-creds"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var (
+			fake                 *fakeQBTClient
+			controllerReconciler *TorrentClientConfigurationReconciler
+		)
+
+		reconcileTCC := func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, resourceName, secretName)
+
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tcc)).To(Succeed())
+			tcc.Spec.Categories = []torrentv1alpha1.CategorySpec{
+				{Name: "movies", SavePath: "/downloads/movies"},
+				{Name: "tv", SavePath: "/downloads/tv"},
+			}
+			Expect(k8sClient.Update(ctx, tcc)).To(Succeed())
+
+			fake = newFakeQBTClient()
+			fake.SetCategory("tv", "/downloads/tv")
+			fake.SetCategory("manual", "/downloads/manual")
+			controllerReconciler = &TorrentClientConfigurationReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+		})
+
+		AfterEach(func() {
+			deleteTCC(ctx, resourceName, secretName)
+		})
+
+		It("should create a missing category with its save path", func() {
+			reconcileTCC()
+
+			Expect(fake.Calls()).To(ContainElement("CreateCategory:movies:/downloads/movies"))
+			Expect(fake.Calls()).NotTo(ContainElement(HavePrefix("CreateCategory:tv:")))
+			Expect(fake.Calls()).NotTo(ContainElement(HavePrefix("EditCategory:")))
+			savePath, ok := fake.CategoryPath("movies")
+			Expect(ok).To(BeTrue())
+			Expect(savePath).To(Equal("/downloads/movies"))
+
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tcc)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(tcc.Status.Conditions, TypeAvailableTCC)).To(BeTrue())
+		})
+
+		It("should edit a category whose save path drifted", func() {
+			reconcileTCC()
+			applied := len(fake.Calls())
+			reconcileTCC()
+			Expect(fake.Calls()[applied:]).NotTo(ContainElement(HavePrefix("EditCategory:")))
+
+			fake.SetCategory("tv", "/mnt/elsewhere")
+			reconcileTCC()
+
+			Expect(fake.Calls()).To(ContainElement("EditCategory:tv:/downloads/tv"))
+			savePath, _ := fake.CategoryPath("tv")
+			Expect(savePath).To(Equal("/downloads/tv"))
+			// Undeclared categories are left untouched
+			savePath, _ = fake.CategoryPath("manual")
+			Expect(savePath).To(Equal("/downloads/manual"))
+		})
+
+		It("should leave the categories declared by the managing TorrentServer to it", func() {
+			deleteServer := manageTCCByServer(ctx, resourceName, torrentv1alpha1.TorrentServerSpec{
+				CategoryPaths: map[string]string{"movies": "/downloads/movies", "tv": "/downloads/series"},
+			})
+			defer deleteServer()
+
+			reconcileTCC()
+			Expect(fake.Calls()).NotTo(ContainElement(HavePrefix("CreateCategory:")))
+			Expect(fake.Calls()).NotTo(ContainElement(HavePrefix("EditCategory:")))
+
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tcc)).To(Succeed())
+			conflict := meta.FindStatusCondition(tcc.Status.Conditions, TypeCategoryConflictTCC)
+			Expect(conflict).NotTo(BeNil())
+			Expect(conflict.Reason).To(Equal("DeclaredByTorrentServer"))
+			Expect(conflict.Message).To(HavePrefix("tv also declared by TorrentServer " + resourceName + "-server"))
+			Expect(meta.IsStatusConditionTrue(tcc.Status.Conditions, TypeAvailableTCC)).To(BeTrue())
+
+			By("removing the condition once both agree")
+			tcc.Spec.Categories[1].SavePath = "/downloads/series"
+			Expect(k8sClient.Update(ctx, tcc)).To(Succeed())
+			reconcileTCC()
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tcc)).To(Succeed())
+			Expect(meta.FindStatusCondition(tcc.Status.Conditions, TypeCategoryConflictTCC)).To(BeNil())
+		})
+
+		It("should report both privacy and category failures together", func() {
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tcc)).To(Succeed())
+			disabled := false
+			tcc.Spec.Privacy = &torrentv1alpha1.PrivacySpec{DHT: &disabled}
+			tcc.Spec.FailureThreshold = 1
+			Expect(k8sClient.Update(ctx, tcc)).To(Succeed())
+
+			fake.SetPreference("dht", true)
+			fake.setPreferencesErr = fmt.Errorf("preferences refused")
+			fake.categoryErr = fmt.Errorf("category refused")
+			reconcileTCC()
+
+			Expect(fake.Calls()).To(ContainElements("SetPreferences:dht", "CreateCategory:movies:/downloads/movies"))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tcc)).To(Succeed())
+			degraded := meta.FindStatusCondition(tcc.Status.Conditions, TypeDegradedTCC)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal(ReasonMultipleStepsFailed))
+			Expect(degraded.Message).To(ContainSubstring(
+				"PrivacyEnforcementFailed: failed to enforce privacy settings: preferences refused; " +
+					"CategoryEnforcementFailed: failed to enforce categories: category refused"))
+		})
+	})
+
+	Context("When an Available qBittorrent starts failing health checks", func() {
+		const resourceName = "test-tcc-grace"
+		const secretName = "test-tcc-grace-creds"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var fake *fakeQBTClient
+		var controllerReconciler *TorrentClientConfigurationReconciler
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, resourceName, secretName)
+
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tcc)).To(Succeed())
+			tcc.Spec.FailureThreshold = 2
+			Expect(k8sClient.Update(ctx, tcc)).To(Succeed())
+
+			fake = newFakeQBTClient()
+			fake.pingErr = fmt.Errorf("connection refused")
+			controllerReconciler = &TorrentClientConfigurationReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+		})
+
+		AfterEach(func() {
+			deleteTCC(ctx, resourceName, secretName)
+		})
+
+		reconcileTCC := func() *torrentv1alpha1.TorrentClientConfiguration {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tcc)).To(Succeed())
+			return tcc
+		}
+
+		It("should stay Available after a single failure", func() {
+			tcc := reconcileTCC()
+			Expect(tcc.Status.Connected).To(BeFalse())
+			Expect(tcc.Status.ConsecutiveFailures).To(Equal(int32(1)))
+			Expect(meta.IsStatusConditionTrue(tcc.Status.Conditions, TypeAvailableTCC)).To(BeTrue())
+			Expect(meta.FindStatusCondition(tcc.Status.Conditions, TypeDegradedTCC)).To(BeNil())
+		})
+
+		It("should turn Degraded after failureThreshold consecutive failures", func() {
+			reconcileTCC()
+			tcc := reconcileTCC()
+			Expect(tcc.Status.ConsecutiveFailures).To(Equal(int32(2)))
+			Expect(meta.FindStatusCondition(tcc.Status.Conditions, TypeAvailableTCC)).To(BeNil())
+			degraded := meta.FindStatusCondition(tcc.Status.Conditions, TypeDegradedTCC)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal("HealthCheckFailed"))
+		})
+
+		It("should reset the failure count and stay Available on success", func() {
+			reconcileTCC()
+			fake.pingErr = nil
+			tcc := reconcileTCC()
+			Expect(tcc.Status.Connected).To(BeTrue())
+			Expect(tcc.Status.ConsecutiveFailures).To(BeZero())
+
+			fake.pingErr = fmt.Errorf("connection refused")
+			tcc = reconcileTCC()
+			Expect(meta.IsStatusConditionTrue(tcc.Status.Conditions, TypeAvailableTCC)).To(BeTrue())
+		})
+	})
+
+	Context("When health checks fail around a maintenance window", func() {
+		const resourceName = "test-tcc-maintenance"
+		const secretName = "test-tcc-maintenance-creds"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var fake *fakeQBTClient
+		var controllerReconciler *TorrentClientConfigurationReconciler
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, resourceName, secretName)
+
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tcc)).To(Succeed())
+			tcc.Spec.FailureThreshold = 1
+			tcc.Spec.MaintenanceWindow = &torrentv1alpha1.MaintenanceWindow{
+				Start:    "23:30",
+				Duration: "1h",
+			}
+			Expect(k8sClient.Update(ctx, tcc)).To(Succeed())
+
+			fake = newFakeQBTClient()
+			fake.pingErr = fmt.Errorf("connection refused")
+			controllerReconciler = &TorrentClientConfigurationReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+		})
+
+		AfterEach(func() {
+			deleteTCC(ctx, resourceName, secretName)
+		})
+
+		reconcileAt := func(now time.Time) *torrentv1alpha1.TorrentClientConfiguration {
+			controllerReconciler.now = func() time.Time { return now }
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tcc)).To(Succeed())
+			return tcc
+		}
+
+		It("should set Maintenance instead of Degraded within the window", func() {
+			// The window opened the day before and spans midnight
+			tcc := reconcileAt(time.Date(2026, 3, 10, 0, 15, 0, 0, time.UTC))
+			Expect(tcc.Status.Connected).To(BeFalse())
+			Expect(tcc.Status.ConsecutiveFailures).To(BeZero())
+			Expect(meta.IsStatusConditionTrue(tcc.Status.Conditions, TypeAvailableTCC)).To(BeTrue())
+			Expect(meta.FindStatusCondition(tcc.Status.Conditions, TypeDegradedTCC)).To(BeNil())
+			maintenance := meta.FindStatusCondition(tcc.Status.Conditions, TypeMaintenanceTCC)
+			Expect(maintenance).NotTo(BeNil())
+			Expect(maintenance.Reason).To(Equal("HealthCheckFailed"))
+			Expect(maintenance.Message).To(ContainSubstring("2026-03-10T00:30:00Z"))
+		})
+
+		It("should turn Degraded outside the window", func() {
+			reconcileAt(time.Date(2026, 3, 10, 0, 15, 0, 0, time.UTC))
+			tcc := reconcileAt(time.Date(2026, 3, 10, 0, 30, 0, 0, time.UTC))
+			Expect(meta.FindStatusCondition(tcc.Status.Conditions, TypeMaintenanceTCC)).To(BeNil())
+			degraded := meta.FindStatusCondition(tcc.Status.Conditions, TypeDegradedTCC)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal("HealthCheckFailed"))
+		})
+
+		It("should clear Maintenance once the check passes again", func() {
+			reconcileAt(time.Date(2026, 3, 9, 23, 45, 0, 0, time.UTC))
+			fake.pingErr = nil
+			tcc := reconcileAt(time.Date(2026, 3, 9, 23, 50, 0, 0, time.UTC))
+			Expect(tcc.Status.Connected).To(BeTrue())
+			Expect(meta.FindStatusCondition(tcc.Status.Conditions, TypeMaintenanceTCC)).To(BeNil())
+		})
+	})
+
+	DescribeTable("maintenanceWindowEnd",
+		func(window torrentv1alpha1.MaintenanceWindow, now time.Time, inWindow bool) {
+			_, got, err := maintenanceWindowEnd(&window, now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(got).To(Equal(inWindow))
+		},
+		Entry("before the window", torrentv1alpha1.MaintenanceWindow{Start: "03:00", Duration: "30m"},
+			time.Date(2026, 3, 10, 2, 59, 0, 0, time.UTC), false),
+		Entry("at the window start", torrentv1alpha1.MaintenanceWindow{Start: "03:00", Duration: "30m"},
+			time.Date(2026, 3, 10, 3, 0, 0, 0, time.UTC), true),
+		Entry("at the window end", torrentv1alpha1.MaintenanceWindow{Start: "03:00", Duration: "30m"},
+			time.Date(2026, 3, 10, 3, 30, 0, 0, time.UTC), false),
+		Entry("after midnight in a window opened the day before", torrentv1alpha1.MaintenanceWindow{Start: "23:00", Duration: "2h"},
+			time.Date(2026, 3, 10, 0, 30, 0, 0, time.UTC), true),
+		Entry("in the window time zone", torrentv1alpha1.MaintenanceWindow{Start: "03:00", Duration: "30m", TimeZone: "Europe/Rome"},
+			time.Date(2026, 3, 10, 2, 10, 0, 0, time.UTC), true),
+	)
+
+	It("should reject an invalid maintenance window", func() {
+		_, inWindow, err := maintenanceWindowEnd(&torrentv1alpha1.MaintenanceWindow{Start: "03:00", Duration: "48h"}, time.Now())
+		Expect(err).To(HaveOccurred())
+		Expect(inWindow).To(BeFalse())
+	})
+
+	Context("When reconciliation is paused by annotation", func() {
+		const resourceName = "test-tcc-paused"
+		const secretName = "test-tcc-paused-creds"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			createAvailableTCC(ctx, resourceName, secretName)
+
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tcc)).To(Succeed())
+			tcc.Annotations = map[string]string{ReconcileAnnotation: "false"}
+			Expect(k8sClient.Update(ctx, tcc)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			deleteTCC(ctx, resourceName, secretName)
+		})
+
+		It("should neither contact qBittorrent nor update status", func() {
+			before := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, before)).To(Succeed())
+
+			fake := newFakeQBTClient()
+			controllerReconciler := &TorrentClientConfigurationReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(reconcile.Result{}))
+			Expect(fake.Calls()).To(BeEmpty())
+
+			after := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, after)).To(Succeed())
+			Expect(after.ResourceVersion).To(Equal(before.ResourceVersion))
+			Expect(after.Status.LastChecked).To(BeNil())
+		})
+	})
 })
@@ -4,34 +4,108 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	torrentv1alpha1 "github.com/guidonguido/qbittorrent-operator/api/v1alpha1"
+	"github.com/guidonguido/qbittorrent-operator/internal/configinit"
+	"github.com/guidonguido/qbittorrent-operator/internal/qbittorrent"
 )
 
 const (
 	TypeAvailableTorrentServer = "Available"
 	TypeDegradedTorrentServer  = "Degraded"
+	// TypeHostNetworkTorrentServer warns that the pod runs on the node network
+	TypeHostNetworkTorrentServer = "HostNetwork"
+	// TypeConflictingEnvTorrentServer warns that spec.env overrides settings managed by the operator
+	TypeConflictingEnvTorrentServer = "ConflictingEnv"
+	// TypeResourcesReadyTorrentServer summarizes the health of every managed child resource
+	TypeResourcesReadyTorrentServer = "ResourcesReady"
+	// TypeQueueingDisabledTorrentServer warns that the declared active torrent limits are not enforced
+	TypeQueueingDisabledTorrentServer = "QueueingDisabled"
+	// TypeSessionTimeoutMisalignedTorrentServer warns that the declared WebUI session timeout
+	// expires sessions the client pool may still reuse
+	TypeSessionTimeoutMisalignedTorrentServer = "SessionTimeoutMisaligned"
+	// TypeLowDiskSpaceTorrentServer warns that the free space on disk is below spec.lowDiskSpaceThreshold
+	TypeLowDiskSpaceTorrentServer = "LowDiskSpace"
+	// TypeProgressingTorrentServer reports whether the Deployment is rolling out a new revision, e.g. a new image
+	TypeProgressingTorrentServer = "Progressing"
+	// TypeMultipleReplicasTorrentServer warns that several qBittorrent replicas share the config volume
+	TypeMultipleReplicasTorrentServer = "MultipleReplicas"
+	// TypeUnsupportedVersionTorrentServer warns that the managed TCC detected a qBittorrent version older than the supported minimum
+	TypeUnsupportedVersionTorrentServer = "UnsupportedVersion"
 )
 
+// ResetCredentialsAnnotation makes config-init rewrite the qBittorrent credentials from the credentials Secret
+// on every pod start while set, and lets a deleted managed Secret be recreated with a new password.
+// Changing its value restarts qBittorrent.
+const ResetCredentialsAnnotation = "torrent.qbittorrent.io/reset-credentials"
+
+// errCredentialsSecretDeleted reports a managed credentials Secret deleted while the instance runs
+var errCredentialsSecretDeleted = errors.New("managed credentials secret was deleted")
+
+// defaultLowDiskSpaceThreshold applies when spec.lowDiskSpaceThreshold is unset
+var defaultLowDiskSpaceThreshold = resource.MustParse("5Gi")
+
+const (
+	// incompleteVolumeName is the emptyDir volume holding incomplete torrents
+	incompleteVolumeName = "incomplete"
+	// DefaultIncompleteMountPath is where the incomplete torrents volume is mounted by default
+	DefaultIncompleteMountPath = "/incomplete"
+	// ipFilterVolumeName is the ConfigMap or PVC volume holding the IP filter file
+	ipFilterVolumeName = "ip-filter"
+	// ipFilterMountPath is where the IP filter volume is mounted
+	ipFilterMountPath = "/ip-filter"
+)
+
+// errWaitingForStorage reports download PVCs that are not Bound yet
+var errWaitingForStorage = errors.New("waiting for download volumes to be bound")
+
 type TorrentServerReconciler struct {
 	client.Client
 	Scheme        *runtime.Scheme
 	OperatorImage string // operator image for init containers, set from OPERATOR_IMAGE env var
+	// ClientPool is used to verify the WebUI answers through the Service once replicas are ready.
+	// The check is skipped when nil.
+	ClientPool *qbittorrent.ClientPool
+	// Recorder emits events, e.g. when drifted preferences are corrected. Events are skipped when nil.
+	Recorder record.EventRecorder
+	// ResyncInterval is how long to wait before reconciling a TorrentServer whose resources are all reconciled,
+	// to check the WebUI, preferences and free disk space again. Zero requeues only when a watched resource changes.
+	ResyncInterval time.Duration
+	// DefaultImage is the qBittorrent image of TorrentServers without spec.image, e.g. an internal mirror.
+	// The latest tested linuxserver image is used when empty.
+	DefaultImage string
+	// ImageRegistryPrefix replaces the registry of spec.image and of the built-in default image,
+	// e.g. "mirror.internal:5000/cache" in air-gapped clusters. Images are used as written when empty.
+	ImageRegistryPrefix string
+	// MinQBittorrentVersion is the oldest qBittorrent version not reported by the UnsupportedVersion condition.
+	// qbittorrent.DefaultMinQBittorrentVersion applies when empty.
+	MinQBittorrentVersion string
 }
 
 // +kubebuilder:rbac:groups=torrent.qbittorrent.io,resources=torrentservers,verbs=get;list;watch;create;update;patch;delete
@@ -42,6 +116,10 @@ type TorrentServerReconciler struct {
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways,verbs=get
 
 func (r *TorrentServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
@@ -54,6 +132,12 @@ func (r *TorrentServerReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// 1.1. Paused resources are left untouched until the reconcile annotation is removed
+	if isReconcilePaused(ts) {
+		logger.Info("Reconciliation paused by annotation", "annotation", ReconcileAnnotation)
+		return ctrl.Result{}, nil
+	}
+
 	// 2. Handle deletion
 	if !ts.DeletionTimestamp.IsZero() {
 		// No cleanup needed for now since all owned resources
@@ -64,68 +148,132 @@ func (r *TorrentServerReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, nil
 	}
 
-	// 3. Reconcile TS.spec.credentialsSecret.name
-	// If no TCC is referred, create a new one
-	secretName, err := r.ensureCredentialsSecret(ctx, ts)
-	if err != nil {
-		r.setDegradedCondition(ts, "CredentialsSecretError", err.Error())
-		if statusErr := r.Status().Update(ctx, ts); statusErr != nil {
-			logger.Error(statusErr, "Failed to update TorrentServer status")
+	// 2.1. Look the Gateway API up once, for both the HTTPRoute and its status condition
+	gatewayAPI := r.lookupGatewayAPI(ts)
+
+	// 3. Rebuild the child resources, unless the spec did not change since they were last
+	// fully reconciled and they still exist: then only the status is refreshed
+	children, upToDate := r.observedChildren(ctx, ts)
+	if !upToDate {
+		var reason string
+		var err error
+		children, reason, err = r.reconcileChildren(ctx, ts, gatewayAPI)
+		if errors.Is(err, errWaitingForStorage) {
+			logger.Info("Waiting for download volumes before creating the Deployment", "reason", err.Error())
+			r.setWaitingForStorageCondition(ts, err.Error())
+			if statusErr := r.Status().Update(ctx, ts); statusErr != nil {
+				logger.Error(statusErr, "Failed to update TorrentServer status")
+			}
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 		}
-		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		if err != nil {
+			r.setDegradedCondition(ts, reason, err.Error())
+			if statusErr := r.Status().Update(ctx, ts); statusErr != nil {
+				logger.Error(statusErr, "Failed to update TorrentServer status")
+			}
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+	} else {
+		logger.V(1).Info("Spec unchanged and child resources present, refreshing status only",
+			"generation", ts.Generation,
+		)
 	}
+	serviceURL := children.serviceURL
+	secretName := children.secretName
 
-	// 4. Reconcile config PVC
-	pvcName, err := r.ensureConfigPVC(ctx, ts)
-	if err != nil {
-		r.setDegradedCondition(ts, "ConfigPVCError", err.Error())
-		if statusErr := r.Status().Update(ctx, ts); statusErr != nil {
-			logger.Error(statusErr, "Failed to update TorrentServer status")
-		}
-		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	// 4. Update status. The child resources now reflect the current generation
+	deployment := &appsv1.Deployment{}
+	deploymentErr := r.Get(ctx, types.NamespacedName{Name: children.deploymentName, Namespace: ts.Namespace}, deployment)
+	if deploymentErr == nil {
+		ts.Status.ReadyReplicas = deployment.Status.ReadyReplicas
 	}
+	ts.Status.ObservedGeneration = ts.Generation
+	ts.Status.CredentialsSecretName = secretName
+	r.setConfigSeeded(ctx, ts)
+	ts.Status.DeploymentName = children.deploymentName
+	ts.Status.ServiceName = children.serviceName
+	ts.Status.ExternalServiceName = children.externalServiceName
+	ts.Status.HTTPRouteName = children.httpRouteName
+	ts.Status.HTTPRouteURL = children.httpRouteURL
+	ts.Status.ConfigPVCName = children.pvcName
+	ts.Status.ClientConfigurationName = children.tccName
+	ts.Status.URL = serviceURL
 
-	// 6. Reconcile qBittorrent Deployment
-	deploymentName, err := r.ensureDeployment(ctx, ts, pvcName, secretName)
-	if err != nil {
-		r.setDegradedCondition(ts, "DeploymentError", err.Error())
+	r.setHostNetworkCondition(ts)
+	r.setMultipleReplicasCondition(ts)
+	r.setUnsupportedVersionCondition(ctx, ts, children.tccName)
+	r.setConflictingEnvCondition(ts)
+	r.setGatewayAPICondition(ts, gatewayAPI)
+	r.setResourcesReadyCondition(ctx, ts, children, deployment, deploymentErr)
+	r.setProgressingCondition(ts, deployment, deploymentErr)
+
+	// 4.1. Readiness must not be reported from replicas of a previous revision while the Deployment rolls out
+	if deploymentErr != nil {
+		r.setRolloutInProgressCondition(ts, fmt.Sprintf("waiting for Deployment %s: %v", children.deploymentName, deploymentErr))
 		if statusErr := r.Status().Update(ctx, ts); statusErr != nil {
 			logger.Error(statusErr, "Failed to update TorrentServer status")
 		}
 		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 	}
-
-	// 7. Reconcile Service
-	serviceName, err := r.ensureService(ctx, ts)
-	if err != nil {
-		r.setDegradedCondition(ts, "ServiceError", err.Error())
+	if rolledOut, message := deploymentRolledOut(deployment); !rolledOut {
+		logger.V(1).Info("Deployment rollout in progress", "deployment", deployment.Name, "status", message)
+		r.setRolloutInProgressCondition(ts, message)
 		if statusErr := r.Status().Update(ctx, ts); statusErr != nil {
 			logger.Error(statusErr, "Failed to update TorrentServer status")
 		}
 		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 	}
 
-	// 8. Reconcile TorrentClientConfiguration containing qBittorrent service URL and credential secret reference
-	serviceURL := fmt.Sprintf("http://%s.%s.svc.cluster.local:%d", serviceName, ts.Namespace, ts.Spec.WebUIPort)
-	tccName, err := r.ensureTorrentClientConfiguration(ctx, ts, serviceURL, secretName)
-	if err != nil {
-		r.setDegradedCondition(ts, "ClientConfigError", err.Error())
-		if statusErr := r.Status().Update(ctx, ts); statusErr != nil {
-			logger.Error(statusErr, "Failed to update TorrentServer status")
+	// 5. Once replicas are ready, verify the WebUI actually answers through the Service
+	if ts.Status.ReadyReplicas > 0 && r.ClientPool != nil {
+		qbtClient, err := r.checkWebUI(ctx, ts, serviceURL, secretName)
+		if err != nil {
+			logger.Error(err, "qBittorrent WebUI is unreachable", "url", serviceURL)
+			r.setDegradedCondition(ts, "WebUIUnreachable", err.Error())
+			if statusErr := r.Status().Update(ctx, ts); statusErr != nil {
+				logger.Error(statusErr, "Failed to update TorrentServer status")
+			}
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 		}
-		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
-	}
 
-	// 9. Update status
-	deployment := &appsv1.Deployment{}
-	if err := r.Get(ctx, types.NamespacedName{Name: deploymentName, Namespace: ts.Namespace}, deployment); err == nil {
-		ts.Status.ReadyReplicas = deployment.Status.ReadyReplicas
+		// 6. Re-apply declared preferences that drifted from the running instance
+		if err := r.reconcilePreferences(ctx, ts, qbtClient); err != nil {
+			logger.Error(err, "Failed to reconcile qBittorrent preferences")
+			r.setDegradedCondition(ts, "PreferencesError", err.Error())
+			if statusErr := r.Status().Update(ctx, ts); statusErr != nil {
+				logger.Error(statusErr, "Failed to update TorrentServer status")
+			}
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+
+		// 6.1. Create the categories of spec.categoryPaths and edit back their drifted save paths.
+		// Steps 6.1 to 6.3 are independent: all run and their failures are reported together
+		var failed stepErrors
+		if err := r.reconcileCategoryPaths(ctx, ts, qbtClient); err != nil {
+			logger.Error(err, "Failed to reconcile qBittorrent categories")
+			failed.add("CategoriesError", err)
+		}
+
+		// 6.2. Report the free disk space, keeping the previous value on failure
+		if mainData, err := qbtClient.GetMainData(ctx); err != nil {
+			logger.Error(err, "Failed to read qBittorrent free disk space")
+		} else {
+			r.setFreeSpace(ts, mainData.ServerState.FreeSpaceOnDisk)
+		}
+
+		// 6.3. Stop or restart the torrents for spec.suspend
+		if err := r.reconcileSuspend(ctx, ts, qbtClient); err != nil {
+			logger.Error(err, "Failed to suspend or resume qBittorrent torrents")
+			failed.add("SuspendError", err)
+		}
+		if !failed.empty() {
+			r.setDegradedCondition(ts, failed.reason(), failed.message())
+			if statusErr := r.Status().Update(ctx, ts); statusErr != nil {
+				logger.Error(statusErr, "Failed to update TorrentServer status")
+			}
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
 	}
-	ts.Status.DeploymentName = deploymentName
-	ts.Status.ServiceName = serviceName
-	ts.Status.ConfigPVCName = pvcName
-	ts.Status.ClientConfigurationName = tccName
-	ts.Status.URL = serviceURL
 
 	r.setAvailableCondition(ts, "Reconciled", "All resources are reconciled")
 	if err := r.Status().Update(ctx, ts); err != nil {
@@ -133,7 +281,318 @@ func (r *TorrentServerReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	return ctrl.Result{RequeueAfter: r.ResyncInterval}, nil
+}
+
+// torrentServerChildren holds the names of the resources managed for a TorrentServer
+type torrentServerChildren struct {
+	secretName          string
+	pvcName             string
+	deploymentName      string
+	serviceName         string
+	externalServiceName string
+	httpRouteName       string
+	httpRouteURL        string
+	tccName             string
+	serviceURL          string
+}
+
+// Create or update every child resource, returning the Degraded reason on failure
+func (r *TorrentServerReconciler) reconcileChildren(ctx context.Context, ts *torrentv1alpha1.TorrentServer, gatewayAPI gatewayAPILookup) (torrentServerChildren, string, error) {
+	var children torrentServerChildren
+	var err error
+
+	// 1. Reconcile TS.spec.credentialsSecret.name
+	// If no TCC is referred, create a new one
+	if children.secretName, err = r.ensureCredentialsSecret(ctx, ts); err != nil {
+		if errors.Is(err, errCredentialsSecretDeleted) {
+			return children, "CredentialsSecretDeleted", err
+		}
+		return children, "CredentialsSecretError", err
+	}
+
+	// 2. Reconcile config PVC
+	if children.pvcName, err = r.ensureConfigPVC(ctx, ts); err != nil {
+		return children, "ConfigPVCError", err
+	}
+
+	// 2.1. Replicas sharing the config volume must all be able to mount it
+	if err := r.checkReplicasAccessMode(ctx, ts, children.pvcName); err != nil {
+		return children, "ReplicasRequireReadWriteMany", err
+	}
+
+	// 2.2. Optionally hold the Deployment back until the download PVCs are bound
+	if ts.Spec.WaitForDownloadVolumes != nil && *ts.Spec.WaitForDownloadVolumes {
+		pending, err := r.pendingDownloadVolumes(ctx, ts)
+		if err != nil {
+			return children, "DownloadVolumeError", err
+		}
+		if len(pending) > 0 {
+			return children, "", fmt.Errorf("%w: %s", errWaitingForStorage, strings.Join(pending, ", "))
+		}
+	}
+
+	// 3. Reconcile qBittorrent Deployment
+	if children.deploymentName, err = r.ensureDeployment(ctx, ts, children.pvcName, children.secretName); err != nil {
+		return children, "DeploymentError", err
+	}
+
+	// 4. Reconcile Service, unless the user brings their own
+	if children.serviceName, err = r.ensureService(ctx, ts); err != nil {
+		return children, "ServiceError", err
+	}
+
+	// 4.1. Reconcile the optional external Service
+	if children.externalServiceName, err = r.ensureExternalService(ctx, ts); err != nil {
+		return children, "ExternalServiceError", err
+	}
+
+	// 4.2. Reconcile the optional Gateway API HTTPRoute
+	if children.httpRouteName, children.httpRouteURL, err = r.ensureHTTPRoute(ctx, ts, children.serviceName, gatewayAPI); err != nil {
+		return children, "HTTPRouteError", err
+	}
+
+	// 5. Reconcile TorrentClientConfiguration containing qBittorrent service URL and credential secret reference.
+	// Without a managed Service, the URL of the user's own Service or route is used
+	if children.serviceName != "" {
+		children.serviceURL = fmt.Sprintf("http://%s.%s.svc.cluster.local:%d", children.serviceName, ts.Namespace, ts.Spec.WebUIPort)
+	} else {
+		children.serviceURL = ts.Spec.Service.URL
+	}
+	if children.serviceURL == "" {
+		return children, "ServiceURLMissing", fmt.Errorf("spec.service.url is required when spec.service.enabled is false")
+	}
+	if children.tccName, err = r.ensureTorrentClientConfiguration(ctx, ts, children.serviceURL, children.secretName); err != nil {
+		return children, "ClientConfigError", err
+	}
+
+	return children, "", nil
+}
+
+// Return the child resources recorded in status when they were already reconciled
+// for the current generation and still exist
+func (r *TorrentServerReconciler) observedChildren(ctx context.Context, ts *torrentv1alpha1.TorrentServer) (torrentServerChildren, bool) {
+	children := torrentServerChildren{
+		secretName:          ts.Status.CredentialsSecretName,
+		pvcName:             ts.Status.ConfigPVCName,
+		deploymentName:      ts.Status.DeploymentName,
+		serviceName:         ts.Status.ServiceName,
+		externalServiceName: ts.Status.ExternalServiceName,
+		httpRouteName:       ts.Status.HTTPRouteName,
+		httpRouteURL:        ts.Status.HTTPRouteURL,
+		tccName:             ts.Status.ClientConfigurationName,
+		serviceURL:          ts.Status.URL,
+	}
+
+	if ts.Generation == 0 || ts.Status.ObservedGeneration != ts.Generation {
+		return children, false
+	}
+	if children.secretName == "" || children.deploymentName == "" || children.tccName == "" {
+		return children, false
+	}
+	if children.serviceName == "" && serviceEnabled(ts) {
+		return children, false
+	}
+	// Keep trying to create a missing route, e.g. once the Gateway API CRDs are installed
+	if (ts.Spec.HTTPRoute != nil) != (children.httpRouteName != "") {
+		return children, false
+	}
+
+	type namedChild struct {
+		name string
+		obj  client.Object
+	}
+	tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+	deployment := &appsv1.Deployment{}
+	required := []namedChild{
+		{children.secretName, &corev1.Secret{}},
+		{children.deploymentName, deployment},
+		{children.tccName, tcc},
+	}
+	if children.serviceName != "" {
+		required = append(required, namedChild{children.serviceName, &corev1.Service{}})
+	}
+	if children.pvcName != "" {
+		required = append(required, namedChild{children.pvcName, &corev1.PersistentVolumeClaim{}})
+	}
+	if children.externalServiceName != "" {
+		required = append(required, namedChild{children.externalServiceName, &corev1.Service{}})
+	}
+	if children.httpRouteName != "" {
+		required = append(required, namedChild{children.httpRouteName, newGatewayAPIObject(httpRouteGVK)})
+	}
+	for _, child := range required {
+		if err := r.Get(ctx, types.NamespacedName{Name: child.name, Namespace: ts.Namespace}, child.obj); err != nil {
+			return children, false
+		}
+	}
+
+	// A TCC edited by hand no longer points to this qBittorrent: rebuild the children to correct it
+	if len(tccDrift(tcc, children.serviceURL, children.secretName)) > 0 {
+		return children, false
+	}
+	// The reset-credentials annotation is not part of the spec: roll the Deployment when it changes
+	if r.OperatorImage != "" &&
+		configInitEnvValue(deployment, configinit.EnvResetCredentials) != ts.Annotations[ResetCredentialsAnnotation] {
+		return children, false
+	}
+	return children, true
+}
+
+// Ping the qBittorrent WebUI through the managed Service with the managed credentials,
+// returning the client used so later steps can reuse the session
+func (r *TorrentServerReconciler) checkWebUI(ctx context.Context, ts *torrentv1alpha1.TorrentServer, serviceURL, secretName string) (qbittorrent.QBTClient, error) {
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: secretName, Namespace: ts.Namespace}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get credentials secret %q: %w", secretName, err)
+	}
+
+	qbtClient, err := r.ClientPool.GetOrCreate(
+		ctx,
+		serviceURL,
+		string(secret.Data["username"]),
+		string(secret.Data["password"]),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to log in to qBittorrent WebUI at %s: %w", serviceURL, err)
+	}
+
+	if err := qbtClient.Ping(ctx); err != nil {
+		return nil, fmt.Errorf("qBittorrent WebUI at %s did not answer: %w", serviceURL, err)
+	}
+	return qbtClient, nil
+}
+
+// Compare the declared preferences with the running instance and re-apply only the drifted keys
+func (r *TorrentServerReconciler) reconcilePreferences(ctx context.Context, ts *torrentv1alpha1.TorrentServer, qbtClient qbittorrent.QBTClient) error {
+	logger := log.FromContext(ctx)
+
+	desired, err := desiredPreferences(ts)
+	if err != nil {
+		return err
+	}
+
+	current, err := qbtClient.GetPreferences(ctx)
+	if err != nil {
+		return err
+	}
+	r.setQueueingDisabledCondition(ts, desired, current)
+	r.alignSessionTimeout(ts, desired, current)
+
+	drifted := qbittorrent.DiffPreferences(current, desired)
+	if len(drifted) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(drifted))
+	for key := range drifted {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	logger.Info("Correcting drifted qBittorrent preferences", "keys", keys)
+	if err := qbtClient.SetPreferences(ctx, drifted); err != nil {
+		return err
+	}
+
+	if r.Recorder != nil {
+		r.Recorder.Eventf(ts, corev1.EventTypeNormal, "PreferencesReconciled",
+			"Re-applied drifted preferences: %s", strings.Join(keys, ", "))
+	}
+	return nil
+}
+
+// Create the categories declared in spec.categoryPaths that are missing and edit back the drifted save paths
+func (r *TorrentServerReconciler) reconcileCategoryPaths(ctx context.Context, ts *torrentv1alpha1.TorrentServer, qbtClient qbittorrent.QBTClient) error {
+	names := make([]string, 0, len(ts.Spec.CategoryPaths))
+	for name := range ts.Spec.CategoryPaths {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	categories := make([]torrentv1alpha1.CategorySpec, 0, len(names))
+	for _, name := range names {
+		categories = append(categories, torrentv1alpha1.CategorySpec{Name: name, SavePath: ts.Spec.CategoryPaths[name]})
+	}
+
+	changed, err := enforceCategories(ctx, qbtClient, categories)
+	if err != nil {
+		return fmt.Errorf("failed to enforce category paths: %w", err)
+	}
+	if len(changed) > 0 && r.Recorder != nil {
+		r.Recorder.Eventf(ts, corev1.EventTypeNormal, "CategoriesReconciled",
+			"Created or corrected categories: %s", strings.Join(changed, ", "))
+	}
+	return nil
+}
+
+// desiredPreferences returns the preferences derived from the typed spec fields,
+// overridden by the raw spec.preferences entries
+func desiredPreferences(ts *torrentv1alpha1.TorrentServer) (map[string]any, error) {
+	desired := make(map[string]any, len(ts.Spec.Preferences))
+	if savePath := defaultSavePath(ts); savePath != "" {
+		desired["save_path"] = savePath
+	}
+	if bt := ts.Spec.BitTorrent; bt != nil {
+		if bt.UseRandomPort {
+			desired["random_port"] = true
+		} else if bt.Port != nil {
+			desired["random_port"] = false
+			desired["listen_port"] = *bt.Port
+		}
+		if bt.EnableDHT != nil {
+			desired[qbittorrent.PreferenceDHT] = *bt.EnableDHT
+		}
+		if bt.EnableLSD != nil {
+			desired[qbittorrent.PreferenceLSD] = *bt.EnableLSD
+		}
+		if bt.EnablePEX != nil {
+			desired[qbittorrent.PreferencePeX] = *bt.EnablePEX
+		}
+	}
+	if q := ts.Spec.Queueing; q != nil {
+		if q.Enabled != nil {
+			desired[qbittorrent.PreferenceQueueingEnabled] = *q.Enabled
+		}
+		if q.MaxActiveDownloads != nil {
+			desired[qbittorrent.PreferenceMaxActiveDownloads] = *q.MaxActiveDownloads
+		}
+		if q.MaxActiveUploads != nil {
+			desired[qbittorrent.PreferenceMaxActiveUploads] = *q.MaxActiveUploads
+		}
+		if q.MaxActiveTorrents != nil {
+			desired[qbittorrent.PreferenceMaxActiveTorrents] = *q.MaxActiveTorrents
+		}
+	}
+	if scheduler := ts.Spec.Scheduler; scheduler != nil {
+		if err := addSchedulerPreferences(desired, scheduler); err != nil {
+			return nil, err
+		}
+	}
+	if incomplete := ts.Spec.IncompleteStorage; incomplete != nil {
+		desired[qbittorrent.PreferenceTempPathEnabled] = true
+		desired[qbittorrent.PreferenceTempPath] = incompleteMountPath(incomplete)
+	}
+	if filter := ts.Spec.IPFilter; filter != nil {
+		desired[qbittorrent.PreferenceIPFilterEnabled] = filter.Enabled == nil || *filter.Enabled
+		desired[qbittorrent.PreferenceIPFilterPath] = ipFilterPath(filter)
+	}
+	// Removing spec.alternativeWebUI switches back to the built-in WebUI: the init
+	// container leaves an existing config untouched, so it is disabled through the API
+	if alt := ts.Spec.AlternativeWebUI; alt != nil {
+		desired[qbittorrent.PreferenceAlternativeWebUIEnabled] = true
+		desired[qbittorrent.PreferenceAlternativeWebUIPath] = alt.RootFolder
+	} else {
+		desired[qbittorrent.PreferenceAlternativeWebUIEnabled] = false
+	}
+	for key, raw := range ts.Spec.Preferences {
+		var value any
+		if err := json.Unmarshal(raw.Raw, &value); err != nil {
+			return nil, fmt.Errorf("invalid value for preference %q: %w", key, err)
+		}
+		desired[key] = value
+	}
+	return desired, nil
 }
 
 func (r *TorrentServerReconciler) ensureCredentialsSecret(ctx context.Context, ts *torrentv1alpha1.TorrentServer) (string, error) {
@@ -159,12 +618,29 @@ func (r *TorrentServerReconciler) ensureCredentialsSecret(ctx context.Context, t
 	secretName := ts.Name + "-credentials"
 
 	// Check if secret already exists
-	if err := r.Get(ctx, types.NamespacedName{Name: secretName, Namespace: ts.Namespace}, secret); err == nil {
+	err := r.Get(ctx, types.NamespacedName{Name: secretName, Namespace: ts.Namespace}, secret)
+	if err == nil {
 		// Secret already exists, return its name
 
 		logger.V(1).Info("Credentials secret ensured; using existing secret", "name", secretName)
 		return secretName, nil
 	}
+	if !apierrors.IsNotFound(err) {
+		return "", fmt.Errorf("failed to get credentials secret %q: %w", secretName, err)
+	}
+
+	password, err := generateRandomPassword(16)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate password: %w", err)
+	}
+
+	// A Secret deleted after it was created must not hand out a password the running qBittorrent
+	// does not know: set it on the instance first, unless the credentials are reset on restart
+	if ts.Status.CredentialsSecretName == secretName && ts.Annotations[ResetCredentialsAnnotation] == "" {
+		if err := r.resetInstanceCredentials(ctx, ts, secretName, managedUsername, password); err != nil {
+			return "", err
+		}
+	}
 
 	// Create new secret with generated credentials
 	secret = &corev1.Secret{
@@ -180,12 +656,8 @@ func (r *TorrentServerReconciler) ensureCredentialsSecret(ctx context.Context, t
 			return err
 		}
 
-		password, err := generateRandomPassword(16)
-		if err != nil {
-			return fmt.Errorf("failed to generate password: %w", err)
-		}
 		secret.Data = map[string][]byte{
-			"username": []byte("admin"),
+			"username": []byte(managedUsername),
 			"password": []byte(password),
 		}
 		logger.V(1).Info("Generated credentials secret", "name", secretName)
@@ -200,8 +672,55 @@ func (r *TorrentServerReconciler) ensureCredentialsSecret(ctx context.Context, t
 	return secretName, nil
 }
 
+// managedUsername is the WebUI username of the generated credentials Secret
+const managedUsername = "admin"
+
+// Set new credentials on the running qBittorrent through a session still cached by the client pool,
+// the previous password being lost with the deleted Secret
+func (r *TorrentServerReconciler) resetInstanceCredentials(ctx context.Context, ts *torrentv1alpha1.TorrentServer, secretName, username, password string) error {
+	logger := log.FromContext(ctx)
+
+	var qbtClient qbittorrent.QBTClient
+	var cached bool
+	if r.ClientPool != nil && ts.Status.URL != "" {
+		qbtClient, cached = r.ClientPool.Cached(ts.Status.URL)
+	}
+	if !cached {
+		return fmt.Errorf("%w: no live qBittorrent session can set a new password on the running instance; "+
+			"recreate Secret %q with the previous credentials, or set the %s annotation to generate new ones "+
+			"and restart qBittorrent with them", errCredentialsSecretDeleted, secretName, ResetCredentialsAnnotation)
+	}
+
+	logger.Info("Managed credentials secret was deleted, setting a new password on the running instance",
+		"name", secretName)
+	err := qbtClient.SetPreferences(ctx, map[string]any{
+		qbittorrent.PreferenceWebUIUsername: username,
+		qbittorrent.PreferenceWebUIPassword: password,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: failed to set a new password on the running instance: %w", errCredentialsSecretDeleted, err)
+	}
+	if r.Recorder != nil {
+		r.Recorder.Eventf(ts, corev1.EventTypeWarning, "CredentialsSecretRecreated",
+			"Credentials secret %s was deleted; a new password was set on the running instance and stored in a recreated secret",
+			secretName)
+	}
+	return nil
+}
+
 func (r *TorrentServerReconciler) ensureConfigPVC(ctx context.Context, ts *torrentv1alpha1.TorrentServer) (string, error) {
 	logger := log.FromContext(ctx)
+
+	// An existing claim is only validated, the operator does not own or modify it
+	if ts.Spec.ConfigStorage != nil && ts.Spec.ConfigStorage.ExistingClaimName != "" {
+		claimName := ts.Spec.ConfigStorage.ExistingClaimName
+		if err := r.Get(ctx, types.NamespacedName{Name: claimName, Namespace: ts.Namespace}, &corev1.PersistentVolumeClaim{}); err != nil {
+			return "", fmt.Errorf("failed to get existing config PVC %q: %w", claimName, err)
+		}
+		logger.V(1).Info("Using existing config PVC", "name", claimName)
+		return claimName, nil
+	}
+
 	pvcName := ts.Name + "-config"
 
 	storageSize := "1Gi"
@@ -268,11 +787,7 @@ func (r *TorrentServerReconciler) ensureDeployment(ctx context.Context, ts *torr
 		port = 8080
 	}
 
-	image := ts.Spec.Image
-	if image == "" {
-		// Default image to latest tested qBittorrent version if not specified
-		image = "lscr.io/linuxserver/qbittorrent:amd64-5.1.4"
-	}
+	image := r.resolveImage(ts)
 
 	volumes := []corev1.Volume{
 		{
@@ -308,6 +823,23 @@ func (r *TorrentServerReconciler) ensureDeployment(ctx context.Context, ts *torr
 		})
 	}
 
+	// Keep incomplete torrents on an ephemeral volume, qBittorrent moves them to their save path once completed
+	if incomplete := ts.Spec.IncompleteStorage; incomplete != nil {
+		volumes = append(volumes, corev1.Volume{
+			Name: incompleteVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					Medium:    incomplete.Medium,
+					SizeLimit: incomplete.SizeLimit,
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      incompleteVolumeName,
+			MountPath: incompleteMountPath(incomplete),
+		})
+	}
+
 	// Init conainer is used to initialize qBittorrent.config credentials
 	// with the TCC-referenced secret data
 	var initContainers []corev1.Container
@@ -325,6 +857,7 @@ func (r *TorrentServerReconciler) ensureDeployment(ctx context.Context, ts *torr
 			},
 		})
 		readOnlyRootFilesystem := true
+		configInitEnv := configInitEnvForTorrentServer(ts)
 		initContainers = []corev1.Container{
 			{
 				Name:  "config-init",
@@ -336,18 +869,104 @@ func (r *TorrentServerReconciler) ensureDeployment(ctx context.Context, ts *torr
 					// Mount credentials secret to /credentials as read-only
 					{Name: "credentials", MountPath: "/credentials", ReadOnly: true},
 				},
+				Env:       configInitEnv,
+				Resources: configInitResources(ts),
 				SecurityContext: &corev1.SecurityContext{
 					RunAsUser:                &[]int64{0}[0], // Must run as root to create config file with correct permissions
 					AllowPrivilegeEscalation: &[]bool{false}[0],
-					Capabilities: &corev1.Capabilities{
-						Drop: []corev1.Capability{"ALL"},
-					},
-					ReadOnlyRootFilesystem: &readOnlyRootFilesystem,
+					Capabilities:             configInitCapabilities(configInitEnv),
+					ReadOnlyRootFilesystem:   &readOnlyRootFilesystem,
+				},
+			},
+		}
+	}
+
+	// Mount the alternative WebUI files from a ConfigMap when requested
+	if alt := ts.Spec.AlternativeWebUI; alt != nil && alt.ConfigMapName != "" {
+		volumes = append(volumes, corev1.Volume{
+			Name: "alternative-webui",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: alt.ConfigMapName},
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "alternative-webui",
+			MountPath: alt.RootFolder,
+			ReadOnly:  true,
+		})
+	}
+
+	// Mount the IP filter file read-only from a ConfigMap or an existing PVC
+	if filter := ts.Spec.IPFilter; filter != nil {
+		source := corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: filter.ClaimName, ReadOnly: true},
+		}
+		if filter.ConfigMapName != "" {
+			source = corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: filter.ConfigMapName},
+				},
+			}
+		}
+		volumes = append(volumes, corev1.Volume{Name: ipFilterVolumeName, VolumeSource: source})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      ipFilterVolumeName,
+			MountPath: ipFilterMountPath,
+			ReadOnly:  true,
+		})
+	}
+
+	// Append user-provided extra volumes, rejecting names already used by managed volumes
+	for _, extraVolume := range ts.Spec.ExtraVolumes {
+		for _, v := range volumes {
+			if v.Name == extraVolume.Name {
+				return "", fmt.Errorf("extra volume %q collides with a volume managed by the operator", extraVolume.Name)
+			}
+		}
+		volumes = append(volumes, extraVolume)
+	}
+	// Append user-provided extra mounts, rejecting paths that would shadow or nest in a managed mount
+	managedMountPaths := make([]string, 0, len(volumeMounts)+1)
+	for _, mount := range volumeMounts {
+		managedMountPaths = append(managedMountPaths, mount.MountPath)
+	}
+	if r.OperatorImage != "" {
+		managedMountPaths = append(managedMountPaths, "/credentials")
+	}
+	for _, extraMount := range ts.Spec.ExtraVolumeMounts {
+		for _, managedPath := range managedMountPaths {
+			if mountPathsOverlap(extraMount.MountPath, managedPath) {
+				return "", fmt.Errorf("extra volume mount %q at %s collides with the %s mount managed by the operator",
+					extraMount.Name, extraMount.MountPath, managedPath)
+			}
+		}
+		volumeMounts = append(volumeMounts, extraMount)
+	}
+
+	// Conservative startup probe tolerating slow resume data restores
+	startupProbe := ts.Spec.StartupProbe
+	if startupProbe == nil {
+		startupProbe = &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path: "/",
+					Port: intstr.FromString("webui"),
 				},
 			},
+			PeriodSeconds:    10,
+			TimeoutSeconds:   5,
+			FailureThreshold: 30,
 		}
 	}
 
+	// Pods on the host network need ClusterFirstWithHostNet to keep resolving cluster DNS names
+	dnsPolicy := corev1.DNSClusterFirst
+	if ts.Spec.HostNetwork {
+		dnsPolicy = corev1.DNSClusterFirstWithHostNet
+	}
+
 	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      deploymentName,
@@ -376,20 +995,21 @@ func (r *TorrentServerReconciler) ensureDeployment(ctx context.Context, ts *torr
 							Name:            "qbittorrent",
 							Image:           image,
 							ImagePullPolicy: corev1.PullAlways,
-							Ports: []corev1.ContainerPort{
-								{
-									Name:          "webui",
-									ContainerPort: port,
-									Protocol:      corev1.ProtocolTCP,
-								},
-							},
-							Env:          ts.Spec.Env,
-							VolumeMounts: volumeMounts,
-							Resources:    ts.Spec.Resources,
+							Command:         ts.Spec.Command,
+							Args:            ts.Spec.Args,
+							Ports:           containerPortsForTorrentServer(ts, port),
+							Env:             envForTorrentServer(ts),
+							VolumeMounts:    volumeMounts,
+							Resources:       ts.Spec.Resources,
+							StartupProbe:    startupProbe,
 						},
 					},
-					Volumes:       volumes,
-					RestartPolicy: corev1.RestartPolicyAlways,
+					Volumes:                   volumes,
+					RestartPolicy:             corev1.RestartPolicyAlways,
+					HostNetwork:               ts.Spec.HostNetwork,
+					DNSPolicy:                 dnsPolicy,
+					PriorityClassName:         ts.Spec.PriorityClassName,
+					TopologySpreadConstraints: ts.Spec.TopologySpreadConstraints,
 				},
 			},
 		}
@@ -403,10 +1023,31 @@ func (r *TorrentServerReconciler) ensureDeployment(ctx context.Context, ts *torr
 	return deploymentName, nil
 }
 
+// serviceEnabled reports whether the operator manages the "<name>" WebUI Service
+func serviceEnabled(ts *torrentv1alpha1.TorrentServer) bool {
+	return ts.Spec.Service == nil || ts.Spec.Service.Enabled == nil || *ts.Spec.Service.Enabled
+}
+
+// Create the "<name>" Service, deleting it once spec.service.enabled is false
 func (r *TorrentServerReconciler) ensureService(ctx context.Context, ts *torrentv1alpha1.TorrentServer) (string, error) {
 	logger := log.FromContext(ctx)
 	serviceName := ts.Name
 
+	if !serviceEnabled(ts) {
+		existing := &corev1.Service{}
+		if err := r.Get(ctx, types.NamespacedName{Name: serviceName, Namespace: ts.Namespace}, existing); err != nil {
+			return "", client.IgnoreNotFound(err)
+		}
+		if !metav1.IsControlledBy(existing, ts) {
+			return "", nil
+		}
+		if err := r.Delete(ctx, existing); client.IgnoreNotFound(err) != nil {
+			return "", fmt.Errorf("failed to delete service: %w", err)
+		}
+		logger.Info("Service deleted", "name", serviceName)
+		return "", nil
+	}
+
 	port := ts.Spec.WebUIPort
 	if port == 0 {
 		port = 8080
@@ -432,14 +1073,7 @@ func (r *TorrentServerReconciler) ensureService(ctx context.Context, ts *torrent
 		svc.Spec = corev1.ServiceSpec{
 			Type:     serviceType,
 			Selector: labelsForTorrentServer(ts.Name),
-			Ports: []corev1.ServicePort{
-				{
-					Name:       "webui",
-					Port:       port,
-					TargetPort: intstr.FromString("webui"),
-					Protocol:   corev1.ProtocolTCP,
-				},
-			},
+			Ports:    servicePortsForTorrentServer(ts, port, nil),
 		}
 		return nil
 	})
@@ -451,63 +1085,753 @@ func (r *TorrentServerReconciler) ensureService(ctx context.Context, ts *torrent
 	return serviceName, nil
 }
 
-func (r *TorrentServerReconciler) ensureTorrentClientConfiguration(ctx context.Context, ts *torrentv1alpha1.TorrentServer, serviceURL, secretName string) (string, error) {
+// Create the "<name>-external" Service when spec.externalService is set, deleting it once unset
+func (r *TorrentServerReconciler) ensureExternalService(ctx context.Context, ts *torrentv1alpha1.TorrentServer) (string, error) {
 	logger := log.FromContext(ctx)
-	tccName := ts.Name + "-client-config"
+	serviceName := ts.Name + "-external"
 
-	tcc := &torrentv1alpha1.TorrentClientConfiguration{
+	svc := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      tccName,
+			Name:      serviceName,
 			Namespace: ts.Namespace,
 		},
 	}
 
-	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, tcc, func() error {
-		if err := controllerutil.SetControllerReference(ts, tcc, r.Scheme); err != nil {
-			return err
+	if ts.Spec.ExternalService == nil {
+		existing := &corev1.Service{}
+		if err := r.Get(ctx, types.NamespacedName{Name: serviceName, Namespace: ts.Namespace}, existing); err != nil {
+			return "", client.IgnoreNotFound(err)
 		}
-		tcc.Labels = labelsForTorrentServer(ts.Name)
-		tcc.Labels["torrent.qbittorrent.io/managed-by"] = ts.Name
-		tcc.Spec = torrentv1alpha1.TorrentClientConfigurationSpec{
-			URL: serviceURL,
-			CredentialsSecret: torrentv1alpha1.SecretReference{
-				Name: secretName,
-			},
+		if !metav1.IsControlledBy(existing, ts) {
+			return "", nil
+		}
+		if err := r.Delete(ctx, existing); client.IgnoreNotFound(err) != nil {
+			return "", fmt.Errorf("failed to delete external service: %w", err)
+		}
+		logger.Info("External Service deleted", "name", serviceName)
+		return "", nil
+	}
+
+	port := ts.Spec.WebUIPort
+	if port == 0 {
+		port = 8080
+	}
+
+	serviceType := ts.Spec.ExternalService.Type
+	if serviceType == "" {
+		serviceType = corev1.ServiceTypeLoadBalancer
+	}
+
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, svc, func() error {
+		if err := controllerutil.SetControllerReference(ts, svc, r.Scheme); err != nil {
+			return err
+		}
+		svc.Labels = labelsForTorrentServer(ts.Name)
+		svc.Annotations = ts.Spec.ExternalService.Annotations
+		svc.Spec.Type = serviceType
+		svc.Spec.Selector = labelsForTorrentServer(ts.Name)
+		// Keep the allocated node ports, so updates do not move NodePort/LoadBalancer traffic
+		var existing []corev1.ServicePort
+		if serviceType != corev1.ServiceTypeClusterIP {
+			existing = svc.Spec.Ports
+		}
+		svc.Spec.Ports = servicePortsForTorrentServer(ts, port, existing)
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to ensure external service: %w", err)
+	}
+	logger.V(1).Info("External Service ensured", "name", serviceName, "result", result)
+
+	return serviceName, nil
+}
+
+func (r *TorrentServerReconciler) ensureTorrentClientConfiguration(ctx context.Context, ts *torrentv1alpha1.TorrentServer, serviceURL, secretName string) (string, error) {
+	logger := log.FromContext(ctx)
+	tccName := ts.Name + "-client-config"
+
+	tcc := &torrentv1alpha1.TorrentClientConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      tccName,
+			Namespace: ts.Namespace,
+		},
+	}
+
+	// Only the connection fields are owned: the check interval and failure threshold stay user-tunable
+	var drifted []string
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, tcc, func() error {
+		if err := controllerutil.SetControllerReference(ts, tcc, r.Scheme); err != nil {
+			return err
+		}
+		if tcc.ResourceVersion != "" {
+			drifted = tccDrift(tcc, serviceURL, secretName)
+		}
+		tcc.Labels = labelsForTorrentServer(ts.Name)
+		tcc.Labels["torrent.qbittorrent.io/managed-by"] = ts.Name
+		tcc.Spec.URL = serviceURL
+		tcc.Spec.CredentialsSecret = &torrentv1alpha1.SecretReference{
+			Name: secretName,
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to ensure TorrentClientConfiguration: %w", err)
+	}
+	logger.V(1).Info("TorrentClientConfiguration ensured", "name", tccName, "result", result)
+
+	if len(drifted) > 0 {
+		logger.Info("Corrected drifted TorrentClientConfiguration", "name", tccName, "fields", drifted)
+		if r.Recorder != nil {
+			r.Recorder.Eventf(ts, corev1.EventTypeWarning, "TCCDriftCorrected",
+				"Reverted manual edits to TorrentClientConfiguration %s: %s", tccName, strings.Join(drifted, ", "))
 		}
-		return nil
-	})
-	if err != nil {
-		return "", fmt.Errorf("failed to ensure TorrentClientConfiguration: %w", err)
 	}
-	logger.V(1).Info("TorrentClientConfiguration ensured", "name", tccName, "result", result)
 
 	return tccName, nil
 }
 
+// tccDrift returns the TCC spec fields that differ from the values derived from the TorrentServer
+func tccDrift(tcc *torrentv1alpha1.TorrentClientConfiguration, serviceURL, secretName string) []string {
+	var drifted []string
+	if tcc.Spec.URL != serviceURL {
+		drifted = append(drifted, "url")
+	}
+	if tcc.Spec.CredentialsSecret == nil || tcc.Spec.CredentialsSecret.Name != secretName {
+		drifted = append(drifted, "credentialsSecret")
+	}
+	return drifted
+}
+
+// serverPreferences returns the preferences enforced by the TorrentServer managing the TCC, with its name.
+// The TorrentServer owns these keys: the TCC and its Torrents leave them alone. It returns nil for a TCC
+// not managed by a TorrentServer.
+func serverPreferences(ctx context.Context, c client.Client, tcc *torrentv1alpha1.TorrentClientConfiguration) (map[string]any, string, error) {
+	ts, err := controllingServer(ctx, c, tcc)
+	if err != nil || ts == nil {
+		return nil, "", err
+	}
+	desired, err := desiredPreferences(ts)
+	if err != nil {
+		return nil, "", err
+	}
+	return desired, ts.Name, nil
+}
+
+// serverCategoryPaths returns the spec.categoryPaths of the TorrentServer managing the TCC, with its name.
+// The TorrentServer owns these categories: the TCC leaves them alone. It returns nil for a TCC
+// without a managing TorrentServer
+func serverCategoryPaths(ctx context.Context, c client.Client, tcc *torrentv1alpha1.TorrentClientConfiguration) (map[string]string, string, error) {
+	ts, err := controllingServer(ctx, c, tcc)
+	if err != nil || ts == nil {
+		return nil, "", err
+	}
+	return ts.Spec.CategoryPaths, ts.Name, nil
+}
+
+// controllingServer returns the TorrentServer controlling the TCC, or nil when there is none or it is gone
+func controllingServer(ctx context.Context, c client.Client, tcc *torrentv1alpha1.TorrentClientConfiguration) (*torrentv1alpha1.TorrentServer, error) {
+	owner := metav1.GetControllerOf(tcc)
+	if owner == nil || owner.Kind != "TorrentServer" {
+		return nil, nil
+	}
+	ts := &torrentv1alpha1.TorrentServer{}
+	if err := c.Get(ctx, types.NamespacedName{Name: owner.Name, Namespace: tcc.Namespace}, ts); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get TorrentServer %q: %w", owner.Name, err)
+	}
+	return ts, nil
+}
+
 func (r *TorrentServerReconciler) setAvailableCondition(ts *torrentv1alpha1.TorrentServer, reason, message string) {
 	condition := metav1.Condition{
 		Type:               TypeAvailableTorrentServer,
 		Status:             metav1.ConditionTrue,
 		Reason:             reason,
 		Message:            message,
+		ObservedGeneration: ts.Generation,
+		LastTransitionTime: metav1.NewTime(time.Now()),
+	}
+	meta.SetStatusCondition(&ts.Status.Conditions, condition)
+	meta.RemoveStatusCondition(&ts.Status.Conditions, TypeDegradedTorrentServer)
+}
+
+// A rollout is expected progress rather than a failure: report not Available without Degraded
+func (r *TorrentServerReconciler) setRolloutInProgressCondition(ts *torrentv1alpha1.TorrentServer, message string) {
+	condition := metav1.Condition{
+		Type:               TypeAvailableTorrentServer,
+		Status:             metav1.ConditionFalse,
+		Reason:             "RolloutInProgress",
+		Message:            message,
+		ObservedGeneration: ts.Generation,
 		LastTransitionTime: metav1.NewTime(time.Now()),
 	}
 	meta.SetStatusCondition(&ts.Status.Conditions, condition)
 	meta.RemoveStatusCondition(&ts.Status.Conditions, TypeDegradedTorrentServer)
 }
 
+// Pending storage is expected progress rather than a failure: report not Available without Degraded
+func (r *TorrentServerReconciler) setWaitingForStorageCondition(ts *torrentv1alpha1.TorrentServer, message string) {
+	condition := metav1.Condition{
+		Type:               TypeAvailableTorrentServer,
+		Status:             metav1.ConditionFalse,
+		Reason:             "WaitingForStorage",
+		Message:            message,
+		ObservedGeneration: ts.Generation,
+		LastTransitionTime: metav1.NewTime(time.Now()),
+	}
+	meta.SetStatusCondition(&ts.Status.Conditions, condition)
+	meta.RemoveStatusCondition(&ts.Status.Conditions, TypeDegradedTorrentServer)
+}
+
+// List the download PVCs that do not exist or are not Bound yet, e.g. while dynamic provisioning runs
+func (r *TorrentServerReconciler) pendingDownloadVolumes(ctx context.Context, ts *torrentv1alpha1.TorrentServer) ([]string, error) {
+	var pending []string
+	for _, dv := range ts.Spec.DownloadVolumes {
+		pvc := &corev1.PersistentVolumeClaim{}
+		err := r.Get(ctx, types.NamespacedName{Name: dv.ClaimName, Namespace: ts.Namespace}, pvc)
+		switch {
+		case apierrors.IsNotFound(err):
+			pending = append(pending, dv.ClaimName+" (not found)")
+		case err != nil:
+			return nil, fmt.Errorf("failed to get download PVC %q: %w", dv.ClaimName, err)
+		case pvc.Status.Phase != corev1.ClaimBound:
+			pending = append(pending, fmt.Sprintf("%s (%s)", dv.ClaimName, pvc.Status.Phase))
+		}
+	}
+	return pending, nil
+}
+
+// Report whether the Deployment controller observed the latest Deployment spec and every desired
+// replica runs it and is ready, describing the pending step otherwise
+func deploymentRolledOut(deployment *appsv1.Deployment) (bool, string) {
+	desired := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
+	}
+	status := deployment.Status
+
+	switch {
+	case status.ObservedGeneration < deployment.Generation:
+		return false, fmt.Sprintf("waiting for Deployment %s generation %d to be observed", deployment.Name, deployment.Generation)
+	case status.UpdatedReplicas < desired:
+		return false, fmt.Sprintf("%d of %d replicas updated", status.UpdatedReplicas, desired)
+	case status.Replicas > status.UpdatedReplicas:
+		return false, fmt.Sprintf("%d old replicas pending termination", status.Replicas-status.UpdatedReplicas)
+	case status.ReadyReplicas < desired:
+		return false, fmt.Sprintf("%d of %d updated replicas ready", status.ReadyReplicas, desired)
+	}
+	return true, ""
+}
+
+// Report the outcome of the latest successful config-init run of the TorrentServer pods,
+// keeping the previous value while no run is observed, e.g. between pod restarts
+func (r *TorrentServerReconciler) setConfigSeeded(ctx context.Context, ts *torrentv1alpha1.TorrentServer) {
+	if r.OperatorImage == "" {
+		// Without OperatorImage there is no config-init container
+		ts.Status.ConfigSeeded = nil
+		return
+	}
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(ts.Namespace), client.MatchingLabels(labelsForTorrentServer(ts.Name))); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list TorrentServer pods to read the config-init outcome")
+		return
+	}
+
+	var latest *corev1.ContainerStateTerminated
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.InitContainerStatuses {
+			terminated := status.State.Terminated
+			if status.Name != "config-init" || terminated == nil || terminated.ExitCode != 0 {
+				continue
+			}
+			if latest == nil || terminated.FinishedAt.After(latest.FinishedAt.Time) {
+				latest = terminated
+			}
+		}
+	}
+	if latest == nil {
+		return
+	}
+	if seeded, ok := configinit.SeededFromTerminationMessage(latest.Message); ok {
+		ts.Status.ConfigSeeded = &seeded
+	}
+}
+
+// Progressing is True while the Deployment rolls out a new revision and False once it completed,
+// or when the Deployment controller gave up after progressDeadlineSeconds
+func (r *TorrentServerReconciler) setProgressingCondition(ts *torrentv1alpha1.TorrentServer, deployment *appsv1.Deployment, deploymentErr error) {
+	condition := metav1.Condition{
+		Type:               TypeProgressingTorrentServer,
+		Status:             metav1.ConditionFalse,
+		Reason:             "RolloutComplete",
+		Message:            "Deployment rolled out",
+		ObservedGeneration: ts.Generation,
+		LastTransitionTime: metav1.NewTime(time.Now()),
+	}
+
+	rolledOut, message := false, "waiting for the Deployment to be created"
+	if deploymentErr == nil {
+		rolledOut, message = deploymentRolledOut(deployment)
+		condition.Message = fmt.Sprintf("Deployment rolled out image %s", qbittorrentImage(deployment))
+	}
+	stalled := deploymentProgressDeadlineExceeded(deployment)
+	switch {
+	case rolledOut:
+	case stalled != nil:
+		condition.Reason = "ProgressDeadlineExceeded"
+		condition.Message = stalled.Message
+	default:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "RolloutInProgress"
+		condition.Message = message
+		if deploymentErr == nil {
+			condition.Message = fmt.Sprintf("rolling out image %s: %s", qbittorrentImage(deployment), message)
+		}
+	}
+	meta.SetStatusCondition(&ts.Status.Conditions, condition)
+}
+
+// Return the Deployment Progressing condition when the Deployment controller stopped waiting for the rollout
+func deploymentProgressDeadlineExceeded(deployment *appsv1.Deployment) *appsv1.DeploymentCondition {
+	for i, c := range deployment.Status.Conditions {
+		if c.Type == appsv1.DeploymentProgressing && c.Status == corev1.ConditionFalse && c.Reason == "ProgressDeadlineExceeded" {
+			return &deployment.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// qbittorrentImage returns the image of the qbittorrent container of the Deployment pod template
+func qbittorrentImage(deployment *appsv1.Deployment) string {
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if container.Name == "qbittorrent" {
+			return container.Image
+		}
+	}
+	return ""
+}
+
+// The Service keeps working with hostNetwork, but peers and the WebUI are also
+// reachable directly on the node IP, so surface it as an explicit warning
+func (r *TorrentServerReconciler) setHostNetworkCondition(ts *torrentv1alpha1.TorrentServer) {
+	if !ts.Spec.HostNetwork {
+		meta.RemoveStatusCondition(&ts.Status.Conditions, TypeHostNetworkTorrentServer)
+		return
+	}
+	condition := metav1.Condition{
+		Type:               TypeHostNetworkTorrentServer,
+		Status:             metav1.ConditionTrue,
+		Reason:             "HostNetworkEnabled",
+		Message:            "qBittorrent runs on the node network and bypasses the ClusterIP Service for peer traffic",
+		ObservedGeneration: ts.Generation,
+		LastTransitionTime: metav1.NewTime(time.Now()),
+	}
+	meta.SetStatusCondition(&ts.Status.Conditions, condition)
+}
+
+// Refuse more than one replica unless the config PVC, managed or existing, is ReadWriteMany.
+// The API rejects it for the managed PVC already; an existing claim is only known at runtime.
+func (r *TorrentServerReconciler) checkReplicasAccessMode(ctx context.Context, ts *torrentv1alpha1.TorrentServer, pvcName string) error {
+	if ts.Spec.Replicas == nil || *ts.Spec.Replicas <= 1 {
+		return nil
+	}
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.Get(ctx, types.NamespacedName{Name: pvcName, Namespace: ts.Namespace}, pvc); err != nil {
+		return fmt.Errorf("failed to get config PVC %q: %w", pvcName, err)
+	}
+	if slices.Contains(pvc.Spec.AccessModes, corev1.ReadWriteMany) {
+		return nil
+	}
+	return fmt.Errorf("spec.replicas %d requires the config PVC %s to be ReadWriteMany, found %v",
+		*ts.Spec.Replicas, pvcName, pvc.Spec.AccessModes)
+}
+
+// qBittorrent is not highly available: replicas sharing a config do not coordinate, so warn while there are several
+func (r *TorrentServerReconciler) setMultipleReplicasCondition(ts *torrentv1alpha1.TorrentServer) {
+	if ts.Spec.Replicas == nil || *ts.Spec.Replicas <= 1 {
+		meta.RemoveStatusCondition(&ts.Status.Conditions, TypeMultipleReplicasTorrentServer)
+		return
+	}
+	condition := metav1.Condition{
+		Type:   TypeMultipleReplicasTorrentServer,
+		Status: metav1.ConditionTrue,
+		Reason: "NotHighlyAvailable",
+		Message: fmt.Sprintf("%d qBittorrent replicas share the config volume without coordinating: "+
+			"keep a single one active, or they download the same torrents into the same files", *ts.Spec.Replicas),
+		ObservedGeneration: ts.Generation,
+		LastTransitionTime: metav1.NewTime(time.Now()),
+	}
+	meta.SetStatusCondition(&ts.Status.Conditions, condition)
+}
+
+// Mirror the qBittorrent version check of the managed TCC, keeping the condition while the TCC cannot be read
+func (r *TorrentServerReconciler) setUnsupportedVersionCondition(ctx context.Context, ts *torrentv1alpha1.TorrentServer, tccName string) {
+	if tccName == "" {
+		return
+	}
+	tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+	if err := r.Get(ctx, types.NamespacedName{Name: tccName, Namespace: ts.Namespace}, tcc); err != nil {
+		log.FromContext(ctx).V(1).Info("Failed to read the detected qBittorrent version", "tcc", tccName, "error", err.Error())
+		return
+	}
+	condition := unsupportedVersionCondition(tcc.Status.QBittorrentVersion, r.MinQBittorrentVersion, ts.Generation)
+	if condition == nil {
+		meta.RemoveStatusCondition(&ts.Status.Conditions, TypeUnsupportedVersionTorrentServer)
+		return
+	}
+	condition.Type = TypeUnsupportedVersionTorrentServer
+	meta.SetStatusCondition(&ts.Status.Conditions, *condition)
+}
+
+func (r *TorrentServerReconciler) setConflictingEnvCondition(ts *torrentv1alpha1.TorrentServer) {
+	conflicts := envConflicts(ts)
+	if len(conflicts) == 0 {
+		meta.RemoveStatusCondition(&ts.Status.Conditions, TypeConflictingEnvTorrentServer)
+		return
+	}
+	condition := metav1.Condition{
+		Type:               TypeConflictingEnvTorrentServer,
+		Status:             metav1.ConditionTrue,
+		Reason:             "ConflictingEnv",
+		Message:            "spec.env overrides settings managed by the operator: " + strings.Join(conflicts, ", "),
+		ObservedGeneration: ts.Generation,
+		LastTransitionTime: metav1.NewTime(time.Now()),
+	}
+	meta.SetStatusCondition(&ts.Status.Conditions, condition)
+}
+
+// List the spec.env entries setting a variable the operator derives from a typed field to another value.
+// The linuxserver image reads WEBUI_PORT and TORRENTING_PORT at startup: overriding them moves qBittorrent
+// away from the ports the probes, Services and TCC use. PUID, PGID and TZ from spec.env take precedence
+// over the typed fields, which are then silently ignored.
+func envConflicts(ts *torrentv1alpha1.TorrentServer) []string {
+	type managedEnv struct {
+		field string
+		value string
+	}
+	managed := map[string]managedEnv{}
+	if ts.Spec.WebUIPort != 0 {
+		managed["WEBUI_PORT"] = managedEnv{"spec.webUIPort", strconv.Itoa(int(ts.Spec.WebUIPort))}
+	}
+	if bt := ts.Spec.BitTorrent; bt != nil && bt.Port != nil {
+		managed["TORRENTING_PORT"] = managedEnv{"spec.bittorrent.port", strconv.Itoa(int(*bt.Port))}
+	}
+	if ts.Spec.PUID != nil {
+		managed["PUID"] = managedEnv{"spec.puid", strconv.FormatInt(*ts.Spec.PUID, 10)}
+	}
+	if ts.Spec.PGID != nil {
+		managed["PGID"] = managedEnv{"spec.pgid", strconv.FormatInt(*ts.Spec.PGID, 10)}
+	}
+	if ts.Spec.Timezone != "" {
+		managed["TZ"] = managedEnv{"spec.timezone", ts.Spec.Timezone}
+	}
+
+	var conflicts []string
+	for _, env := range ts.Spec.Env {
+		m, ok := managed[env.Name]
+		switch {
+		case !ok:
+		case env.ValueFrom != nil:
+			conflicts = append(conflicts, fmt.Sprintf("%s from a reference (%s is %s)", env.Name, m.field, m.value))
+		case env.Value != m.value:
+			conflicts = append(conflicts, fmt.Sprintf("%s=%s (%s is %s)", env.Name, env.Value, m.field, m.value))
+		}
+	}
+	return conflicts
+}
+
+// Active torrent limits are silently ignored by qBittorrent while its queue is disabled,
+// whether through the declared preferences or the running instance when not declared
+func (r *TorrentServerReconciler) setQueueingDisabledCondition(ts *torrentv1alpha1.TorrentServer, desired, current map[string]any) {
+	var limits []string
+	for _, key := range []string{
+		qbittorrent.PreferenceMaxActiveDownloads,
+		qbittorrent.PreferenceMaxActiveUploads,
+		qbittorrent.PreferenceMaxActiveTorrents,
+	} {
+		if _, ok := desired[key]; ok {
+			limits = append(limits, key)
+		}
+	}
+
+	enabled, declared := desired[qbittorrent.PreferenceQueueingEnabled]
+	if !declared {
+		enabled = current[qbittorrent.PreferenceQueueingEnabled]
+	}
+	if len(limits) == 0 || enabled == true {
+		meta.RemoveStatusCondition(&ts.Status.Conditions, TypeQueueingDisabledTorrentServer)
+		return
+	}
+	condition := metav1.Condition{
+		Type:               TypeQueueingDisabledTorrentServer,
+		Status:             metav1.ConditionTrue,
+		Reason:             "QueueingDisabled",
+		Message:            "queueing_enabled is false, so qBittorrent does not enforce " + strings.Join(limits, ", "),
+		ObservedGeneration: ts.Generation,
+		LastTransitionTime: metav1.NewTime(time.Now()),
+	}
+	meta.SetStatusCondition(&ts.Status.Conditions, condition)
+}
+
+// addSchedulerPreferences sets the scheduler preferences, splitting the HH:MM times into hours and minutes
+func addSchedulerPreferences(desired map[string]any, scheduler *torrentv1alpha1.SchedulerSpec) error {
+	from, err := time.Parse("15:04", scheduler.From)
+	if err != nil {
+		return fmt.Errorf("invalid scheduler from %q: must be HH:MM", scheduler.From)
+	}
+	to, err := time.Parse("15:04", scheduler.To)
+	if err != nil {
+		return fmt.Errorf("invalid scheduler to %q: must be HH:MM", scheduler.To)
+	}
+	days := scheduler.Days
+	if days == "" {
+		days = "EveryDay"
+	}
+	schedulerDays, ok := qbittorrent.SchedulerDays[days]
+	if !ok {
+		return fmt.Errorf("invalid scheduler days %q", scheduler.Days)
+	}
+
+	desired[qbittorrent.PreferenceSchedulerEnabled] = scheduler.Enabled == nil || *scheduler.Enabled
+	desired[qbittorrent.PreferenceScheduleFromHour] = from.Hour()
+	desired[qbittorrent.PreferenceScheduleFromMin] = from.Minute()
+	desired[qbittorrent.PreferenceScheduleToHour] = to.Hour()
+	desired[qbittorrent.PreferenceScheduleToMin] = to.Minute()
+	desired[qbittorrent.PreferenceSchedulerDays] = schedulerDays
+	return nil
+}
+
+// Raise an undeclared WebUI session timeout that would expire sessions the client pool may still reuse,
+// and report a declared one that does, leaving it as declared
+func (r *TorrentServerReconciler) alignSessionTimeout(ts *torrentv1alpha1.TorrentServer, desired, current map[string]any) {
+	minTimeout := r.ClientPool.MinSessionTimeout()
+	minSeconds := int64(math.Ceil(minTimeout.Seconds()))
+
+	if value, declared := desired[qbittorrent.PreferenceWebUISessionTimeout]; declared {
+		seconds, ok := preferenceSeconds(value)
+		if !ok || seconds >= minSeconds {
+			meta.RemoveStatusCondition(&ts.Status.Conditions, TypeSessionTimeoutMisalignedTorrentServer)
+			return
+		}
+		condition := metav1.Condition{
+			Type:   TypeSessionTimeoutMisalignedTorrentServer,
+			Status: metav1.ConditionTrue,
+			Reason: "SessionTimeoutTooShort",
+			Message: fmt.Sprintf("%s is %ds, but the operator reuses WebUI sessions for up to %s: "+
+				"requests may fail with 403 until it logs in again", qbittorrent.PreferenceWebUISessionTimeout, seconds, minTimeout),
+			ObservedGeneration: ts.Generation,
+			LastTransitionTime: metav1.NewTime(time.Now()),
+		}
+		meta.SetStatusCondition(&ts.Status.Conditions, condition)
+		return
+	}
+
+	meta.RemoveStatusCondition(&ts.Status.Conditions, TypeSessionTimeoutMisalignedTorrentServer)
+	if seconds, ok := preferenceSeconds(current[qbittorrent.PreferenceWebUISessionTimeout]); ok && seconds < minSeconds {
+		desired[qbittorrent.PreferenceWebUISessionTimeout] = minSeconds
+	}
+}
+
+// preferenceSeconds reads a numeric preference, as decoded from the WebUI API or from spec.preferences
+func preferenceSeconds(value any) (int64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return int64(v), true
+	case int64:
+		return v, true
+	case int:
+		return int64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// stoppedTorrentStates are the states of torrents that are not running, left stopped when resuming
+var stoppedTorrentStates = map[string]bool{
+	"pausedDL":  true,
+	"pausedUP":  true,
+	"stoppedDL": true,
+	"stoppedUP": true,
+}
+
+// Stop every torrent when spec.suspend is set, recording the running ones, and restart only those
+// once it is unset, so that torrents stopped on purpose stay stopped. The running torrents are
+// persisted before stopping them: a status update lost afterwards must not lose which ones to restart.
+func (r *TorrentServerReconciler) reconcileSuspend(ctx context.Context, ts *torrentv1alpha1.TorrentServer, qbtClient qbittorrent.QBTClient) error {
+	logger := log.FromContext(ctx)
+	suspend := ts.Spec.Suspend != nil && *ts.Spec.Suspend
+
+	switch {
+	case suspend && !ts.Status.Suspended:
+		// A list persisted by a previous attempt is kept: its torrents may already be stopped
+		if ts.Status.SuspendedTorrents == nil {
+			torrents, err := qbtClient.GetTorrentsInfo(ctx)
+			if err != nil {
+				return err
+			}
+			running := make([]string, 0, len(torrents))
+			for _, torrent := range torrents {
+				if !stoppedTorrentStates[torrent.State] {
+					running = append(running, torrent.Hash)
+				}
+			}
+			sort.Strings(running)
+			ts.Status.SuspendedTorrents = running
+			if err := r.Status().Update(ctx, ts); err != nil {
+				return fmt.Errorf("failed to record the running torrents: %w", err)
+			}
+		}
+
+		logger.Info("Suspending qBittorrent, stopping every torrent", "running", len(ts.Status.SuspendedTorrents))
+		if err := qbtClient.PauseAll(ctx); err != nil {
+			return err
+		}
+		ts.Status.Suspended = true
+		if r.Recorder != nil {
+			r.Recorder.Eventf(ts, corev1.EventTypeNormal, "Suspended",
+				"Stopped every torrent, %d were running", len(ts.Status.SuspendedTorrents))
+		}
+
+	// A suspend unset before its PauseAll succeeded still restarts the recorded torrents
+	case !suspend && (ts.Status.Suspended || len(ts.Status.SuspendedTorrents) > 0):
+		logger.Info("Resuming qBittorrent, restarting the torrents stopped by suspend",
+			"torrents", len(ts.Status.SuspendedTorrents))
+		if len(ts.Status.SuspendedTorrents) > 0 {
+			if err := qbtClient.ResumeTorrents(ctx, ts.Status.SuspendedTorrents); err != nil {
+				return err
+			}
+		}
+		if r.Recorder != nil {
+			r.Recorder.Eventf(ts, corev1.EventTypeNormal, "Resumed",
+				"Restarted the %d torrents stopped by suspend", len(ts.Status.SuspendedTorrents))
+		}
+		ts.Status.Suspended = false
+		ts.Status.SuspendedTorrents = nil
+	}
+	return nil
+}
+
+// Record the free space on disk and set LowDiskSpace while it is below the threshold,
+// emitting a Warning event when the condition appears
+func (r *TorrentServerReconciler) setFreeSpace(ts *torrentv1alpha1.TorrentServer, freeSpace int64) {
+	ts.Status.FreeSpaceOnDisk = &freeSpace
+	ts.Status.FreeSpaceOnDiskHuman = formatBytes(freeSpace)
+
+	threshold := defaultLowDiskSpaceThreshold
+	if ts.Spec.LowDiskSpaceThreshold != nil {
+		threshold = *ts.Spec.LowDiskSpaceThreshold
+	}
+	if freeSpace >= threshold.Value() {
+		meta.RemoveStatusCondition(&ts.Status.Conditions, TypeLowDiskSpaceTorrentServer)
+		return
+	}
+
+	message := fmt.Sprintf("%s free on disk, below the %s threshold", formatBytes(freeSpace), threshold.String())
+	if r.Recorder != nil && !meta.IsStatusConditionTrue(ts.Status.Conditions, TypeLowDiskSpaceTorrentServer) {
+		r.Recorder.Event(ts, corev1.EventTypeWarning, "LowDiskSpace", message)
+	}
+	condition := metav1.Condition{
+		Type:               TypeLowDiskSpaceTorrentServer,
+		Status:             metav1.ConditionTrue,
+		Reason:             "LowDiskSpace",
+		Message:            message,
+		ObservedGeneration: ts.Generation,
+		LastTransitionTime: metav1.NewTime(time.Now()),
+	}
+	meta.SetStatusCondition(&ts.Status.Conditions, condition)
+}
+
+func (r *TorrentServerReconciler) setResourcesReadyCondition(ctx context.Context, ts *torrentv1alpha1.TorrentServer, children torrentServerChildren, deployment *appsv1.Deployment, deploymentErr error) {
+	condition := metav1.Condition{
+		Type:               TypeResourcesReadyTorrentServer,
+		Status:             metav1.ConditionTrue,
+		Reason:             "AllResourcesReady",
+		Message:            "All managed resources exist and are healthy",
+		ObservedGeneration: ts.Generation,
+		LastTransitionTime: metav1.NewTime(time.Now()),
+	}
+	if unhealthy := r.unhealthyChildren(ctx, ts, children, deployment, deploymentErr); len(unhealthy) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "ResourcesNotReady"
+		condition.Message = strings.Join(unhealthy, "; ")
+	}
+	meta.SetStatusCondition(&ts.Status.Conditions, condition)
+}
+
+// Describe every managed child that is missing or unhealthy: the Deployment must be rolled out,
+// the TCC Available and the config PVC not Lost; the Secret and Services only need to exist
+func (r *TorrentServerReconciler) unhealthyChildren(ctx context.Context, ts *torrentv1alpha1.TorrentServer, children torrentServerChildren, deployment *appsv1.Deployment, deploymentErr error) []string {
+	var unhealthy []string
+	get := func(kind, name string, obj client.Object) bool {
+		if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: ts.Namespace}, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				unhealthy = append(unhealthy, fmt.Sprintf("%s %s is missing", kind, name))
+			} else {
+				unhealthy = append(unhealthy, fmt.Sprintf("%s %s: %v", kind, name, err))
+			}
+			return false
+		}
+		return true
+	}
+
+	get("Secret", children.secretName, &corev1.Secret{})
+
+	if children.pvcName != "" {
+		pvc := &corev1.PersistentVolumeClaim{}
+		if get("PersistentVolumeClaim", children.pvcName, pvc) && pvc.Status.Phase == corev1.ClaimLost {
+			unhealthy = append(unhealthy, fmt.Sprintf("PersistentVolumeClaim %s lost its volume", children.pvcName))
+		}
+	}
+
+	switch {
+	case apierrors.IsNotFound(deploymentErr):
+		unhealthy = append(unhealthy, fmt.Sprintf("Deployment %s is missing", children.deploymentName))
+	case deploymentErr != nil:
+		unhealthy = append(unhealthy, fmt.Sprintf("Deployment %s: %v", children.deploymentName, deploymentErr))
+	default:
+		if rolledOut, message := deploymentRolledOut(deployment); !rolledOut {
+			unhealthy = append(unhealthy, fmt.Sprintf("Deployment %s is not ready: %s", children.deploymentName, message))
+		}
+	}
+
+	if children.serviceName != "" {
+		get("Service", children.serviceName, &corev1.Service{})
+	}
+	if children.externalServiceName != "" {
+		get("Service", children.externalServiceName, &corev1.Service{})
+	}
+	if children.httpRouteName != "" {
+		get("HTTPRoute", children.httpRouteName, newGatewayAPIObject(httpRouteGVK))
+	}
+
+	tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+	if get("TorrentClientConfiguration", children.tccName, tcc) &&
+		!meta.IsStatusConditionTrue(tcc.Status.Conditions, TypeAvailableTCC) {
+		unhealthy = append(unhealthy, fmt.Sprintf("TorrentClientConfiguration %s is not Available", children.tccName))
+	}
+	return unhealthy
+}
+
 func (r *TorrentServerReconciler) setDegradedCondition(ts *torrentv1alpha1.TorrentServer, reason, message string) {
 	condition := metav1.Condition{
 		Type:               TypeDegradedTorrentServer,
 		Status:             metav1.ConditionTrue,
 		Reason:             reason,
 		Message:            message,
+		ObservedGeneration: ts.Generation,
 		LastTransitionTime: metav1.NewTime(time.Now()),
 	}
 	meta.SetStatusCondition(&ts.Status.Conditions, condition)
 	meta.RemoveStatusCondition(&ts.Status.Conditions, TypeAvailableTorrentServer)
 }
 
+// ManagedPodSelector selects the pods of every TorrentServer, so that the manager only caches those
+func ManagedPodSelector() labels.Selector {
+	return labels.SelectorFromSet(labels.Set{"app.kubernetes.io/managed-by": "qbittorrent-operator"})
+}
+
 func labelsForTorrentServer(name string) map[string]string {
 	return map[string]string{
 		"app.kubernetes.io/name":       "qbittorrent",
@@ -516,6 +1840,189 @@ func labelsForTorrentServer(name string) map[string]string {
 	}
 }
 
+// Build the qBittorrent container environment: typed PUID/PGID/TZ fields first,
+// with entries of the same name in spec.env overriding them
+func envForTorrentServer(ts *torrentv1alpha1.TorrentServer) []corev1.EnvVar {
+	var typed []corev1.EnvVar
+	if ts.Spec.PUID != nil {
+		typed = append(typed, corev1.EnvVar{Name: "PUID", Value: strconv.FormatInt(*ts.Spec.PUID, 10)})
+	}
+	if ts.Spec.PGID != nil {
+		typed = append(typed, corev1.EnvVar{Name: "PGID", Value: strconv.FormatInt(*ts.Spec.PGID, 10)})
+	}
+	if ts.Spec.Timezone != "" {
+		typed = append(typed, corev1.EnvVar{Name: "TZ", Value: ts.Spec.Timezone})
+	}
+	if len(typed) == 0 {
+		return ts.Spec.Env
+	}
+
+	overridden := make(map[string]bool, len(ts.Spec.Env))
+	for _, env := range ts.Spec.Env {
+		overridden[env.Name] = true
+	}
+
+	env := make([]corev1.EnvVar, 0, len(typed)+len(ts.Spec.Env))
+	for _, e := range typed {
+		if !overridden[e.Name] {
+			env = append(env, e)
+		}
+	}
+	return append(env, ts.Spec.Env...)
+}
+
+// bitTorrentPort returns the fixed peer port, or 0 when qBittorrent picks it
+func bitTorrentPort(ts *torrentv1alpha1.TorrentServer) int32 {
+	bt := ts.Spec.BitTorrent
+	if bt == nil || bt.Port == nil || bt.UseRandomPort {
+		return 0
+	}
+	return *bt.Port
+}
+
+// containerPortsForTorrentServer exposes the WebUI and, when fixed, the peer port over TCP and UDP
+func containerPortsForTorrentServer(ts *torrentv1alpha1.TorrentServer, webUIPort int32) []corev1.ContainerPort {
+	ports := []corev1.ContainerPort{
+		{
+			Name:          "webui",
+			ContainerPort: webUIPort,
+			Protocol:      corev1.ProtocolTCP,
+		},
+	}
+	if peerPort := bitTorrentPort(ts); peerPort != 0 {
+		ports = append(ports,
+			corev1.ContainerPort{Name: "bittorrent-tcp", ContainerPort: peerPort, Protocol: corev1.ProtocolTCP},
+			corev1.ContainerPort{Name: "bittorrent-udp", ContainerPort: peerPort, Protocol: corev1.ProtocolUDP},
+		)
+	}
+	return ports
+}
+
+// servicePortsForTorrentServer mirrors containerPortsForTorrentServer, keeping the node ports
+// already allocated to the existing ports of the same name
+func servicePortsForTorrentServer(ts *torrentv1alpha1.TorrentServer, webUIPort int32, existing []corev1.ServicePort) []corev1.ServicePort {
+	var ports []corev1.ServicePort
+	for _, cp := range containerPortsForTorrentServer(ts, webUIPort) {
+		sp := corev1.ServicePort{
+			Name:       cp.Name,
+			Port:       cp.ContainerPort,
+			TargetPort: intstr.FromString(cp.Name),
+			Protocol:   cp.Protocol,
+		}
+		for _, e := range existing {
+			if e.Name == sp.Name {
+				sp.NodePort = e.NodePort
+			}
+		}
+		ports = append(ports, sp)
+	}
+	return ports
+}
+
+// defaultSavePath returns spec.defaultSavePath, falling back to the first download volume so
+// new torrents land on a mounted PVC. It is empty when neither is set.
+func defaultSavePath(ts *torrentv1alpha1.TorrentServer) string {
+	if ts.Spec.DefaultSavePath != "" {
+		return ts.Spec.DefaultSavePath
+	}
+	if len(ts.Spec.DownloadVolumes) > 0 {
+		return ts.Spec.DownloadVolumes[0].MountPath
+	}
+	return ""
+}
+
+// mountPathsOverlap reports whether two mount paths are equal or one is nested in the other
+func mountPathsOverlap(a, b string) bool {
+	a, b = path.Clean(a), path.Clean(b)
+	return a == b || strings.HasPrefix(a, strings.TrimSuffix(b, "/")+"/") || strings.HasPrefix(b, strings.TrimSuffix(a, "/")+"/")
+}
+
+// incompleteMountPath returns where the incomplete torrents volume is mounted, defaulting to DefaultIncompleteMountPath
+func incompleteMountPath(incomplete *torrentv1alpha1.IncompleteStorageSpec) string {
+	if incomplete.MountPath != "" {
+		return incomplete.MountPath
+	}
+	return DefaultIncompleteMountPath
+}
+
+// ipFilterPath returns the path of the IP filter file in the qBittorrent container, defaulting to ipfilter.dat
+func ipFilterPath(filter *torrentv1alpha1.IPFilterSpec) string {
+	fileName := filter.FileName
+	if fileName == "" {
+		fileName = "ipfilter.dat"
+	}
+	return path.Join(ipFilterMountPath, fileName)
+}
+
+// defaultConfigInitResources sizes config-init when spec.initResources is unset: it only rewrites
+// qBittorrent.conf and hashes the password once, so small values are enough
+var defaultConfigInitResources = corev1.ResourceRequirements{
+	Requests: corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("10m"),
+		corev1.ResourceMemory: resource.MustParse("32Mi"),
+	},
+	Limits: corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("200m"),
+		corev1.ResourceMemory: resource.MustParse("64Mi"),
+	},
+}
+
+// configInitResources returns spec.initResources, falling back to defaultConfigInitResources
+func configInitResources(ts *torrentv1alpha1.TorrentServer) corev1.ResourceRequirements {
+	if ts.Spec.InitResources != nil {
+		return *ts.Spec.InitResources
+	}
+	return *defaultConfigInitResources.DeepCopy()
+}
+
+// configInitEnvForTorrentServer passes the settings written to qBittorrent.conf to the config-init container
+func configInitEnvForTorrentServer(ts *torrentv1alpha1.TorrentServer) []corev1.EnvVar {
+	var env []corev1.EnvVar
+	if alt := ts.Spec.AlternativeWebUI; alt != nil {
+		env = append(env, corev1.EnvVar{Name: configinit.EnvAlternativeWebUIRootFolder, Value: alt.RootFolder})
+	}
+	if reset := ts.Annotations[ResetCredentialsAnnotation]; reset != "" {
+		env = append(env, corev1.EnvVar{Name: configinit.EnvResetCredentials, Value: reset})
+	}
+	// The config is handed over to the user and group qBittorrent runs as
+	for _, e := range envForTorrentServer(ts) {
+		if e.Name == configinit.EnvPUID || e.Name == configinit.EnvPGID {
+			env = append(env, e)
+		}
+	}
+	return env
+}
+
+// configInitEnvValue returns the value of an environment variable of the config-init container, empty when unset
+func configInitEnvValue(deployment *appsv1.Deployment, name string) string {
+	for _, container := range deployment.Spec.Template.Spec.InitContainers {
+		if container.Name != "config-init" {
+			continue
+		}
+		for _, env := range container.Env {
+			if env.Name == name {
+				return env.Value
+			}
+		}
+	}
+	return ""
+}
+
+// configInitCapabilities keeps config-init capability-free, unless it has to hand the config
+// over to PUID/PGID: changing ownership and later rewriting the handed-over file need CHOWN and DAC_OVERRIDE
+func configInitCapabilities(env []corev1.EnvVar) *corev1.Capabilities {
+	capabilities := &corev1.Capabilities{
+		Drop: []corev1.Capability{"ALL"},
+	}
+	for _, e := range env {
+		if e.Name == configinit.EnvPUID || e.Name == configinit.EnvPGID {
+			capabilities.Add = []corev1.Capability{"CHOWN", "DAC_OVERRIDE"}
+			break
+		}
+	}
+	return capabilities
+}
+
 func generateRandomPassword(length int) (string, error) {
 	bytes := make([]byte, length)
 	if _, err := rand.Read(bytes); err != nil {
@@ -525,13 +2032,25 @@ func generateRandomPassword(length int) (string, error) {
 }
 
 func (r *TorrentServerReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&torrentv1alpha1.TorrentServer{}).
 		// Watch for owned resorces changes to trigger reconciliation
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.Service{}).
 		Owns(&corev1.PersistentVolumeClaim{}).
-		Owns(&torrentv1alpha1.TorrentClientConfiguration{}).
+		Owns(&torrentv1alpha1.TorrentClientConfiguration{})
+
+	// HTTPRoutes can only be watched when the Gateway API CRDs are installed at startup:
+	// otherwise the route is still created on a later resync, but its changes are not watched
+	installed, err := gatewayAPIInstalled(mgr.GetRESTMapper())
+	if err != nil {
+		return fmt.Errorf("failed to look up the Gateway API: %w", err)
+	}
+	if installed {
+		builder = builder.Owns(newGatewayAPIObject(httpRouteGVK))
+	}
+
+	return builder.
 		Named("torrentserver").
 		Complete(r)
 }
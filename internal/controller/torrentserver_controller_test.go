@@ -2,17 +2,33 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"time"
 
+	"github.com/go-logr/logr/funcr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	torrentv1alpha1 "github.com/guidonguido/qbittorrent-operator/api/v1alpha1"
+	"github.com/guidonguido/qbittorrent-operator/internal/configinit"
+	"github.com/guidonguido/qbittorrent-operator/internal/qbittorrent"
 )
 
 var _ = Describe("TorrentServer Controller", func() {
@@ -85,6 +101,14 @@ var _ = Describe("TorrentServer Controller", func() {
 			// Verify no init containers when OperatorImage is not set
 			Expect(deployment.Spec.Template.Spec.InitContainers).To(BeEmpty())
 
+			// Verify default startup probe targets the WebUI port
+			startupProbe := deployment.Spec.Template.Spec.Containers[0].StartupProbe
+			Expect(startupProbe).NotTo(BeNil())
+			Expect(startupProbe.HTTPGet).NotTo(BeNil())
+			Expect(startupProbe.HTTPGet.Port).To(Equal(intstr.FromString("webui")))
+			Expect(deployment.Spec.Template.Spec.Containers[0].Ports[0].Name).To(Equal("webui"))
+			Expect(startupProbe.FailureThreshold).To(BeNumerically(">=", 30))
+
 			// Verify Service was created
 			svc := &corev1.Service{}
 			Expect(k8sClient.Get(ctx, types.NamespacedName{
@@ -146,6 +170,8 @@ var _ = Describe("TorrentServer Controller", func() {
 			Expect(initContainer.Name).To(Equal("config-init"))
 			Expect(initContainer.Image).To(Equal("ghcr.io/guidonguido/qbittorrent-operator:test"))
 			Expect(initContainer.Command).To(Equal([]string{"/manager", "config-init"}))
+			Expect(initContainer.Resources.Requests.Cpu().String()).To(Equal("10m"))
+			Expect(initContainer.Resources.Limits.Memory().String()).To(Equal("64Mi"))
 
 			// Verify credentials volume is present
 			volumeNames := make([]string, len(deployment.Spec.Template.Spec.Volumes))
@@ -155,4 +181,2837 @@ var _ = Describe("TorrentServer Controller", func() {
 			Expect(volumeNames).To(ContainElement("credentials"))
 		})
 	})
-})
+
+	Context("When init container resources are specified", func() {
+		const resourceName = "test-torrentserver-init-resources"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		AfterEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+			deployment := &appsv1.Deployment{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, deployment); err == nil {
+				Expect(k8sClient.Delete(ctx, deployment)).To(Succeed())
+			}
+		})
+
+		It("should give the configured resources to the config-init container", func() {
+			initResources := corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("50m"),
+					corev1.ResourceMemory: resource.MustParse("48Mi"),
+				},
+				Limits: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("128Mi"),
+				},
+			}
+			ts := &torrentv1alpha1.TorrentServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: torrentv1alpha1.TorrentServerSpec{
+					InitResources: &initResources,
+				},
+			}
+			Expect(k8sClient.Create(ctx, ts)).To(Succeed())
+
+			controllerReconciler := &TorrentServerReconciler{
+				Client:        k8sClient,
+				Scheme:        k8sClient.Scheme(),
+				OperatorImage: "ghcr.io/guidonguido/qbittorrent-operator:test",
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.InitContainers).To(HaveLen(1))
+			got := deployment.Spec.Template.Spec.InitContainers[0].Resources
+			Expect(got.Requests.Cpu().Cmp(resource.MustParse("50m"))).To(Equal(0))
+			Expect(got.Requests.Memory().Cmp(resource.MustParse("48Mi"))).To(Equal(0))
+			Expect(got.Limits.Memory().Cmp(resource.MustParse("128Mi"))).To(Equal(0))
+			Expect(got.Limits).NotTo(HaveKey(corev1.ResourceCPU))
+		})
+	})
+
+	Context("When extra volumes and mounts are specified", func() {
+		const resourceName = "test-torrentserver-extra-volumes"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		AfterEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+			deployment := &appsv1.Deployment{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, deployment); err == nil {
+				Expect(k8sClient.Delete(ctx, deployment)).To(Succeed())
+			}
+		})
+
+		It("should add extra volumes to the pod and extra mounts to the qBittorrent container", func() {
+			resource := &torrentv1alpha1.TorrentServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: torrentv1alpha1.TorrentServerSpec{
+					ExtraVolumes: []corev1.Volume{
+						{
+							Name: "tmp",
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory},
+							},
+						},
+					},
+					ExtraVolumeMounts: []corev1.VolumeMount{
+						{Name: "tmp", MountPath: "/tmp"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			controllerReconciler := &TorrentServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deployment)).To(Succeed())
+
+			volumeNames := make([]string, len(deployment.Spec.Template.Spec.Volumes))
+			for i, v := range deployment.Spec.Template.Spec.Volumes {
+				volumeNames[i] = v.Name
+			}
+			Expect(volumeNames).To(ContainElements("config", "tmp"))
+
+			container := deployment.Spec.Template.Spec.Containers[0]
+			Expect(container.VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: "tmp", MountPath: "/tmp"}))
+			Expect(container.VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: "config", MountPath: "/config"}))
+		})
+
+		It("should set Degraded condition when an extra volume collides with a managed volume", func() {
+			resource := &torrentv1alpha1.TorrentServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: torrentv1alpha1.TorrentServerSpec{
+					ExtraVolumes: []corev1.Volume{
+						{
+							Name: "config",
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{},
+							},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			controllerReconciler := &TorrentServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			condition := meta.FindStatusCondition(ts.Status.Conditions, TypeDegradedTorrentServer)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("DeploymentError"))
+			Expect(condition.Message).To(ContainSubstring("collides"))
+		})
+
+		It("should set Degraded condition when an extra mount collides with a managed mount", func() {
+			resource := &torrentv1alpha1.TorrentServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: torrentv1alpha1.TorrentServerSpec{
+					IncompleteStorage: &torrentv1alpha1.IncompleteStorageSpec{},
+					ExtraVolumes: []corev1.Volume{
+						{
+							Name: "scratch",
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{},
+							},
+						},
+					},
+					ExtraVolumeMounts: []corev1.VolumeMount{
+						{Name: "scratch", MountPath: "/incomplete/"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			controllerReconciler := &TorrentServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			condition := meta.FindStatusCondition(ts.Status.Conditions, TypeDegradedTorrentServer)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("DeploymentError"))
+			Expect(condition.Message).To(ContainSubstring(`extra volume mount "scratch" at /incomplete/ collides with the /incomplete mount`))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, &appsv1.Deployment{})).NotTo(Succeed())
+		})
+	})
+
+	Context("When hostNetwork is enabled", func() {
+		const resourceName = "test-torrentserver-hostnetwork"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: torrentv1alpha1.TorrentServerSpec{
+					HostNetwork: true,
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+		})
+
+		It("should propagate hostNetwork to the pod spec and warn via condition", func() {
+			controllerReconciler := &TorrentServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.HostNetwork).To(BeTrue())
+			Expect(deployment.Spec.Template.Spec.DNSPolicy).To(Equal(corev1.DNSClusterFirstWithHostNet))
+
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			condition := meta.FindStatusCondition(ts.Status.Conditions, TypeHostNetworkTorrentServer)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		})
+	})
+
+	Context("When more than one replica is requested", func() {
+		ctx := context.Background()
+
+		// reconcileReplicas creates a TorrentServer with the given replicas and config access modes, then reconciles it
+		reconcileReplicas := func(name string, replicas int32, accessModes ...corev1.PersistentVolumeAccessMode) *torrentv1alpha1.TorrentServer {
+			resource := &torrentv1alpha1.TorrentServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "default",
+				},
+				Spec: torrentv1alpha1.TorrentServerSpec{
+					Replicas:      &replicas,
+					ConfigStorage: &torrentv1alpha1.StorageSpec{AccessModes: accessModes},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			})
+
+			controllerReconciler := &TorrentServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: name, Namespace: "default"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, ts)).To(Succeed())
+			return ts
+		}
+
+		It("should run the replicas on a ReadWriteMany config volume and warn via condition", func() {
+			ts := reconcileReplicas("test-torrentserver-replicas-rwx", 2, corev1.ReadWriteMany)
+
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: ts.Name, Namespace: "default"}, deployment)).To(Succeed())
+			Expect(*deployment.Spec.Replicas).To(Equal(int32(2)))
+
+			condition := meta.FindStatusCondition(ts.Status.Conditions, TypeMultipleReplicasTorrentServer)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("NotHighlyAvailable"))
+		})
+
+		It("should refuse the replicas on a ReadWriteOnce config volume", func() {
+			ts := reconcileReplicas("test-torrentserver-replicas-rwo", 2, corev1.ReadWriteOnce)
+
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: ts.Name, Namespace: "default"}, &appsv1.Deployment{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			degraded := meta.FindStatusCondition(ts.Status.Conditions, TypeDegradedTorrentServer)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal("ReplicasRequireReadWriteMany"))
+			Expect(degraded.Message).To(ContainSubstring("ReadWriteMany"))
+		})
+
+		It("should not warn about a single replica", func() {
+			ts := reconcileReplicas("test-torrentserver-replicas-single", 1, corev1.ReadWriteOnce)
+			Expect(meta.FindStatusCondition(ts.Status.Conditions, TypeMultipleReplicasTorrentServer)).To(BeNil())
+		})
+	})
+
+	Context("When scheduling options are specified", func() {
+		const resourceName = "test-torrentserver-scheduling"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		constraint := corev1.TopologySpreadConstraint{
+			MaxSkew:           1,
+			TopologyKey:       "kubernetes.io/hostname",
+			WhenUnsatisfiable: corev1.ScheduleAnyway,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app.kubernetes.io/name": "qbittorrent"},
+			},
+		}
+
+		BeforeEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: torrentv1alpha1.TorrentServerSpec{
+					PriorityClassName:         "seeding-critical",
+					TopologySpreadConstraints: []corev1.TopologySpreadConstraint{constraint},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+		})
+
+		It("should forward priorityClassName and topologySpreadConstraints to the Deployment", func() {
+			controllerReconciler := &TorrentServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.PriorityClassName).To(Equal("seeding-critical"))
+			Expect(deployment.Spec.Template.Spec.TopologySpreadConstraints).To(Equal([]corev1.TopologySpreadConstraint{constraint}))
+		})
+	})
+
+	Context("When command and args overrides are specified", func() {
+		const resourceName = "test-torrentserver-command"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: torrentv1alpha1.TorrentServerSpec{
+					Command: []string{"/bin/sh", "-c"},
+					Args:    []string{"sleep infinity"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+		})
+
+		It("should forward command and args to the qBittorrent container", func() {
+			controllerReconciler := &TorrentServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deployment)).To(Succeed())
+			container := deployment.Spec.Template.Spec.Containers[0]
+			Expect(container.Command).To(Equal([]string{"/bin/sh", "-c"}))
+			Expect(container.Args).To(Equal([]string{"sleep infinity"}))
+		})
+	})
+
+	Context("When the operator configures the qBittorrent image", func() {
+		const resourceName = "test-torrentserver-image"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		// deployedImage creates the TorrentServer with the given spec.image, reconciles it and returns the container image
+		deployedImage := func(controllerReconciler *TorrentServerReconciler, image string) string {
+			resource := &torrentv1alpha1.TorrentServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: torrentv1alpha1.TorrentServerSpec{Image: image},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			controllerReconciler.Client = k8sClient
+			controllerReconciler.Scheme = k8sClient.Scheme()
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deployment)).To(Succeed())
+			return deployment.Spec.Template.Spec.Containers[0].Image
+		}
+
+		AfterEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+		})
+
+		It("should use the latest tested image without spec.image or an operator default", func() {
+			Expect(deployedImage(&TorrentServerReconciler{}, "")).To(Equal(defaultQBittorrentImage))
+		})
+
+		It("should use the operator default image as given when spec.image is empty", func() {
+			controllerReconciler := &TorrentServerReconciler{
+				DefaultImage:        "registry.internal/qbittorrent:5.1.4",
+				ImageRegistryPrefix: "mirror.internal:5000/cache",
+			}
+			Expect(deployedImage(controllerReconciler, "")).To(Equal("registry.internal/qbittorrent:5.1.4"))
+		})
+
+		It("should keep spec.image over the operator default image", func() {
+			controllerReconciler := &TorrentServerReconciler{DefaultImage: "registry.internal/qbittorrent:5.1.4"}
+			Expect(deployedImage(controllerReconciler, "lscr.io/linuxserver/qbittorrent:5.0.0")).To(
+				Equal("lscr.io/linuxserver/qbittorrent:5.0.0"))
+		})
+
+		It("should rewrite the registry of spec.image to the operator registry prefix", func() {
+			controllerReconciler := &TorrentServerReconciler{ImageRegistryPrefix: "mirror.internal:5000/cache"}
+			Expect(deployedImage(controllerReconciler, "lscr.io/linuxserver/qbittorrent:5.0.0")).To(
+				Equal("mirror.internal:5000/cache/linuxserver/qbittorrent:5.0.0"))
+		})
+
+		It("should rewrite the registry of the built-in default image", func() {
+			controllerReconciler := &TorrentServerReconciler{ImageRegistryPrefix: "mirror.internal:5000/cache/"}
+			Expect(deployedImage(controllerReconciler, "")).To(
+				Equal("mirror.internal:5000/cache/linuxserver/qbittorrent:amd64-5.1.4"))
+		})
+
+		DescribeTable("rewriteImageRegistry",
+			func(image, prefix, expected string) {
+				Expect(rewriteImageRegistry(image, prefix)).To(Equal(expected))
+			},
+			Entry("no prefix", "lscr.io/linuxserver/qbittorrent:5.1.4", "", "lscr.io/linuxserver/qbittorrent:5.1.4"),
+			Entry("registry with a dot", "lscr.io/linuxserver/qbittorrent:5.1.4", "mirror.internal",
+				"mirror.internal/linuxserver/qbittorrent:5.1.4"),
+			Entry("registry with a port", "registry:5000/qbittorrent:5.1.4", "mirror.internal",
+				"mirror.internal/qbittorrent:5.1.4"),
+			Entry("localhost registry", "localhost/qbittorrent", "mirror.internal", "mirror.internal/qbittorrent"),
+			Entry("Docker Hub repository", "qbittorrentofficial/qbittorrent-nox:5.0.0", "mirror.internal/hub",
+				"mirror.internal/hub/qbittorrentofficial/qbittorrent-nox:5.0.0"),
+			Entry("Docker Hub official image", "qbittorrent:5.0.0", "mirror.internal", "mirror.internal/library/qbittorrent:5.0.0"),
+			Entry("digest", "ghcr.io/hotio/qbittorrent@sha256:0123abcd", "mirror.internal",
+				"mirror.internal/hotio/qbittorrent@sha256:0123abcd"),
+			Entry("already mirrored", "mirror.internal/linuxserver/qbittorrent:5.1.4", "mirror.internal",
+				"mirror.internal/linuxserver/qbittorrent:5.1.4"),
+		)
+	})
+
+	Context("When puid, pgid and timezone are specified", func() {
+		const resourceName = "test-torrentserver-ids"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			id := int64(1000)
+			resource := &torrentv1alpha1.TorrentServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: torrentv1alpha1.TorrentServerSpec{
+					PUID:     &id,
+					PGID:     &id,
+					Timezone: "Europe/Rome",
+					Env: []corev1.EnvVar{
+						{Name: "PGID", Value: "100"},
+						{Name: "UMASK", Value: "022"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+		})
+
+		It("should generate env vars from the typed fields, letting spec.env override them", func() {
+			controllerReconciler := &TorrentServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.Containers[0].Env).To(Equal([]corev1.EnvVar{
+				{Name: "PUID", Value: "1000"},
+				{Name: "TZ", Value: "Europe/Rome"},
+				{Name: "PGID", Value: "100"},
+				{Name: "UMASK", Value: "022"},
+			}))
+		})
+
+		It("should hand the config over to the effective ids from config-init", func() {
+			controllerReconciler := &TorrentServerReconciler{
+				Client:        k8sClient,
+				Scheme:        k8sClient.Scheme(),
+				OperatorImage: "ghcr.io/guidonguido/qbittorrent-operator:test",
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deployment)).To(Succeed())
+			initContainer := deployment.Spec.Template.Spec.InitContainers[0]
+			Expect(initContainer.Env).To(Equal([]corev1.EnvVar{
+				{Name: "PUID", Value: "1000"},
+				{Name: "PGID", Value: "100"},
+			}))
+			Expect(initContainer.SecurityContext.Capabilities.Drop).To(Equal([]corev1.Capability{"ALL"}))
+			Expect(initContainer.SecurityContext.Capabilities.Add).To(ConsistOf(corev1.Capability("CHOWN"), corev1.Capability("DAC_OVERRIDE")))
+		})
+	})
+
+	Context("When spec.env conflicts with managed settings", func() {
+		const resourceName = "test-torrentserver-conflicting-env"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			id := int64(1000)
+			resource := &torrentv1alpha1.TorrentServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: torrentv1alpha1.TorrentServerSpec{
+					WebUIPort: 8080,
+					PUID:      &id,
+					Timezone:  "Europe/Rome",
+					Env: []corev1.EnvVar{
+						{Name: "WEBUI_PORT", Value: "9090"},
+						{Name: "PUID", Value: "1000"},
+						{Name: "TZ", ValueFrom: &corev1.EnvVarSource{
+							ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "host-settings"},
+								Key:                  "timezone",
+							},
+						}},
+						{Name: "UMASK", Value: "022"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+		})
+
+		It("should report the conflicting entries and clear the condition once they are removed", func() {
+			controllerReconciler := &TorrentServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			condition := meta.FindStatusCondition(ts.Status.Conditions, TypeConflictingEnvTorrentServer)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("ConflictingEnv"))
+			Expect(condition.Message).To(ContainSubstring("WEBUI_PORT=9090 (spec.webUIPort is 8080)"))
+			Expect(condition.Message).To(ContainSubstring("TZ from a reference (spec.timezone is Europe/Rome)"))
+			Expect(condition.Message).NotTo(ContainSubstring("PUID"))
+			Expect(condition.Message).NotTo(ContainSubstring("UMASK"))
+
+			By("removing the conflicting entries")
+			ts.Spec.Env = []corev1.EnvVar{{Name: "UMASK", Value: "022"}}
+			Expect(k8sClient.Update(ctx, ts)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			Expect(meta.FindStatusCondition(ts.Status.Conditions, TypeConflictingEnvTorrentServer)).To(BeNil())
+		})
+	})
+
+	Context("When reconciliation is paused by annotation", func() {
+		const resourceName = "test-torrentserver-paused"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        resourceName,
+					Namespace:   "default",
+					Annotations: map[string]string{ReconcileAnnotation: "false"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+		})
+
+		It("should not create or mutate any resource", func() {
+			before := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, before)).To(Succeed())
+
+			controllerReconciler := &TorrentServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(reconcile.Result{}))
+
+			after := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, after)).To(Succeed())
+			Expect(after.ResourceVersion).To(Equal(before.ResourceVersion))
+			Expect(after.Status.Conditions).To(BeEmpty())
+
+			err = k8sClient.Get(ctx, typeNamespacedName, &appsv1.Deployment{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-credentials", Namespace: "default"}, &corev1.Secret{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	Context("When the spec is unchanged since the last reconcile", func() {
+		const resourceName = "test-torrentserver-generation"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		AfterEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+			deployment := &appsv1.Deployment{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, deployment); err == nil {
+				Expect(k8sClient.Delete(ctx, deployment)).To(Succeed())
+			}
+			svc := &corev1.Service{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, svc); err == nil {
+				Expect(k8sClient.Delete(ctx, svc)).To(Succeed())
+			}
+		})
+
+		It("should not write child resources until the generation changes", func() {
+			resource := &torrentv1alpha1.TorrentServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			counting := &writeCountingClient{Client: k8sClient}
+			controllerReconciler := &TorrentServerReconciler{
+				Client: counting,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counting.Writes()).NotTo(BeZero())
+
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			Expect(ts.Status.ObservedGeneration).To(Equal(ts.Generation))
+			Expect(ts.Status.CredentialsSecretName).To(Equal(resourceName + "-credentials"))
+
+			By("reconciling again with the same generation")
+			counting.Reset()
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counting.Writes()).To(BeZero())
+
+			By("changing the spec")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			ts.Spec.ServiceType = corev1.ServiceTypeNodePort
+			Expect(k8sClient.Update(ctx, ts)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counting.Writes()).NotTo(BeZero())
+
+			svc := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, svc)).To(Succeed())
+			Expect(svc.Spec.Type).To(Equal(corev1.ServiceTypeNodePort))
+		})
+	})
+
+	Context("When deriving the default save path", func() {
+		downloads := []torrentv1alpha1.DownloadVolumeSpec{
+			{ClaimName: "movies", MountPath: "/downloads/movies"},
+			{ClaimName: "tv", MountPath: "/downloads/tv"},
+		}
+
+		DescribeTable("defaultSavePath",
+			func(spec torrentv1alpha1.TorrentServerSpec, expected string) {
+				ts := &torrentv1alpha1.TorrentServer{Spec: spec}
+				Expect(defaultSavePath(ts)).To(Equal(expected))
+
+				desired, err := desiredPreferences(ts)
+				Expect(err).NotTo(HaveOccurred())
+				if expected == "" {
+					Expect(desired).NotTo(HaveKey("save_path"))
+				} else {
+					Expect(desired).To(HaveKeyWithValue("save_path", expected))
+				}
+			},
+			Entry("no download volumes", torrentv1alpha1.TorrentServerSpec{}, ""),
+			Entry("first download volume", torrentv1alpha1.TorrentServerSpec{DownloadVolumes: downloads}, "/downloads/movies"),
+			Entry("explicit defaultSavePath", torrentv1alpha1.TorrentServerSpec{
+				DownloadVolumes: downloads,
+				DefaultSavePath: "/downloads/tv/incoming",
+			}, "/downloads/tv/incoming"),
+		)
+
+		It("should let spec.preferences override the derived save path", func() {
+			ts := &torrentv1alpha1.TorrentServer{
+				Spec: torrentv1alpha1.TorrentServerSpec{
+					DownloadVolumes: downloads,
+					Preferences: map[string]apiextensionsv1.JSON{
+						"save_path": {Raw: []byte(`"/data"`)},
+					},
+				},
+			}
+			desired, err := desiredPreferences(ts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(desired).To(HaveKeyWithValue("save_path", "/data"))
+		})
+	})
+
+	Context("When bittorrent settings are specified", func() {
+		const resourceName = "test-torrentserver-bittorrent"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		AfterEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+			deployment := &appsv1.Deployment{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, deployment); err == nil {
+				Expect(k8sClient.Delete(ctx, deployment)).To(Succeed())
+			}
+			svc := &corev1.Service{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, svc); err == nil {
+				Expect(k8sClient.Delete(ctx, svc)).To(Succeed())
+			}
+		})
+
+		It("should map the fields to qBittorrent preferences", func() {
+			port := int32(51413)
+			enabled, disabled := true, false
+			ts := &torrentv1alpha1.TorrentServer{
+				Spec: torrentv1alpha1.TorrentServerSpec{
+					BitTorrent: &torrentv1alpha1.BitTorrentSpec{
+						Port:      &port,
+						EnableDHT: &disabled,
+						EnableLSD: &disabled,
+						EnablePEX: &enabled,
+					},
+				},
+			}
+
+			desired, err := desiredPreferences(ts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(desired).To(HaveKeyWithValue("listen_port", int32(51413)))
+			Expect(desired).To(HaveKeyWithValue("random_port", false))
+			Expect(desired).To(HaveKeyWithValue("dht", false))
+			Expect(desired).To(HaveKeyWithValue("lsd", false))
+			Expect(desired).To(HaveKeyWithValue("pex", true))
+
+			By("using a random port instead")
+			ts.Spec.BitTorrent = &torrentv1alpha1.BitTorrentSpec{UseRandomPort: true}
+			desired, err = desiredPreferences(ts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(desired).To(HaveKeyWithValue("random_port", true))
+			Expect(desired).NotTo(HaveKey("listen_port"))
+			Expect(desired).NotTo(HaveKey("dht"))
+		})
+
+		It("should expose the fixed port on the container and the Service", func() {
+			port := int32(51413)
+			resource := &torrentv1alpha1.TorrentServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: torrentv1alpha1.TorrentServerSpec{
+					BitTorrent: &torrentv1alpha1.BitTorrentSpec{Port: &port},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			controllerReconciler := &TorrentServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.Containers[0].Ports).To(ContainElements(
+				corev1.ContainerPort{Name: "bittorrent-tcp", ContainerPort: 51413, Protocol: corev1.ProtocolTCP},
+				corev1.ContainerPort{Name: "bittorrent-udp", ContainerPort: 51413, Protocol: corev1.ProtocolUDP},
+			))
+
+			svc := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, svc)).To(Succeed())
+			Expect(svc.Spec.Ports).To(ContainElements(
+				corev1.ServicePort{Name: "bittorrent-tcp", Port: 51413, TargetPort: intstr.FromString("bittorrent-tcp"), Protocol: corev1.ProtocolTCP},
+				corev1.ServicePort{Name: "bittorrent-udp", Port: 51413, TargetPort: intstr.FromString("bittorrent-udp"), Protocol: corev1.ProtocolUDP},
+			))
+		})
+	})
+
+	Context("When an external Service is requested", func() {
+		const resourceName = "test-torrentserver-external-svc"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+		externalName := types.NamespacedName{
+			Name:      resourceName + "-external",
+			Namespace: "default",
+		}
+
+		AfterEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+			for _, name := range []types.NamespacedName{typeNamespacedName, externalName} {
+				svc := &corev1.Service{}
+				if err := k8sClient.Get(ctx, name, svc); err == nil {
+					Expect(k8sClient.Delete(ctx, svc)).To(Succeed())
+				}
+			}
+		})
+
+		It("should create both Services with distinct types and keep the TCC on the internal one", func() {
+			resource := &torrentv1alpha1.TorrentServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: torrentv1alpha1.TorrentServerSpec{
+					ServiceType: corev1.ServiceTypeClusterIP,
+					WebUIPort:   8080,
+					ExternalService: &torrentv1alpha1.ExternalServiceSpec{
+						Type:        corev1.ServiceTypeLoadBalancer,
+						Annotations: map[string]string{"metallb.universe.tf/address-pool": "home"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			controllerReconciler := &TorrentServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			internal := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, internal)).To(Succeed())
+			Expect(internal.Spec.Type).To(Equal(corev1.ServiceTypeClusterIP))
+
+			external := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, externalName, external)).To(Succeed())
+			Expect(external.Spec.Type).To(Equal(corev1.ServiceTypeLoadBalancer))
+			Expect(external.Annotations).To(HaveKeyWithValue("metallb.universe.tf/address-pool", "home"))
+			Expect(external.Spec.Selector).To(Equal(internal.Spec.Selector))
+
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			Expect(ts.Status.ExternalServiceName).To(Equal(resourceName + "-external"))
+
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: ts.Status.ClientConfigurationName, Namespace: "default"}, tcc)).To(Succeed())
+			Expect(tcc.Spec.URL).To(HavePrefix("http://" + resourceName + ".default.svc"))
+
+			By("removing spec.externalService")
+			ts.Spec.ExternalService = nil
+			Expect(k8sClient.Update(ctx, ts)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, externalName, &corev1.Service{}))).To(BeTrue())
+		})
+	})
+
+	Context("When the managed Service is disabled", func() {
+		const resourceName = "test-torrentserver-byo-svc"
+		const ownURL = "http://qbittorrent.example.internal:8080"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var controllerReconciler *TorrentServerReconciler
+
+		createTorrentServer := func(service *torrentv1alpha1.ServiceSpec) {
+			resource := &torrentv1alpha1.TorrentServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: torrentv1alpha1.TorrentServerSpec{
+					WebUIPort: 8080,
+					Service:   service,
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		}
+
+		reconcileOnce := func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			controllerReconciler = &TorrentServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+		})
+
+		AfterEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+			svc := &corev1.Service{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, svc); err == nil {
+				Expect(k8sClient.Delete(ctx, svc)).To(Succeed())
+			}
+		})
+
+		It("should not create a Service and point the TCC to the given URL", func() {
+			disabled := false
+			createTorrentServer(&torrentv1alpha1.ServiceSpec{Enabled: &disabled, URL: ownURL})
+			reconcileOnce()
+			reconcileOnce()
+
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, &corev1.Service{}))).To(BeTrue())
+
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			Expect(ts.Status.ServiceName).To(BeEmpty())
+			Expect(ts.Status.URL).To(Equal(ownURL))
+			Expect(meta.FindStatusCondition(ts.Status.Conditions, TypeDegradedTorrentServer)).To(BeNil())
+
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: ts.Status.ClientConfigurationName, Namespace: "default"}, tcc)).To(Succeed())
+			Expect(tcc.Spec.URL).To(Equal(ownURL))
+		})
+
+		It("should delete the managed Service once disabled", func() {
+			createTorrentServer(nil)
+			reconcileOnce()
+			Expect(k8sClient.Get(ctx, typeNamespacedName, &corev1.Service{})).To(Succeed())
+
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			disabled := false
+			ts.Spec.Service = &torrentv1alpha1.ServiceSpec{Enabled: &disabled, URL: ownURL}
+			Expect(k8sClient.Update(ctx, ts)).To(Succeed())
+			reconcileOnce()
+
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, &corev1.Service{}))).To(BeTrue())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			Expect(ts.Status.ServiceName).To(BeEmpty())
+			Expect(ts.Status.URL).To(Equal(ownURL))
+		})
+
+		It("should set Degraded when no URL replaces the Service", func() {
+			disabled := false
+			createTorrentServer(&torrentv1alpha1.ServiceSpec{Enabled: &disabled})
+			reconcileOnce()
+
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			degraded := meta.FindStatusCondition(ts.Status.Conditions, TypeDegradedTorrentServer)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal("ServiceURLMissing"))
+		})
+	})
+
+	Context("When an HTTPRoute is requested", func() {
+		const resourceName = "test-torrentserver-httproute"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		httpRoute := func() *torrentv1alpha1.HTTPRouteSpec {
+			return &torrentv1alpha1.HTTPRouteSpec{
+				ParentRef: torrentv1alpha1.GatewayReference{Name: "public", Namespace: "gateways", SectionName: "https"},
+				Hostname:  "qbittorrent.example.com",
+				Path:      "/qbittorrent",
+			}
+		}
+
+		AfterEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+		})
+
+		It("should route the path prefix to the WebUI Service through the parent Gateway", func() {
+			ts := &torrentv1alpha1.TorrentServer{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec:       torrentv1alpha1.TorrentServerSpec{WebUIPort: 8090, HTTPRoute: httpRoute()},
+			}
+			Expect(httpRouteSpecForTorrentServer(ts, resourceName)).To(Equal(map[string]interface{}{
+				"parentRefs": []interface{}{
+					map[string]interface{}{"name": "public", "namespace": "gateways", "sectionName": "https"},
+				},
+				"hostnames": []interface{}{"qbittorrent.example.com"},
+				"rules": []interface{}{
+					map[string]interface{}{
+						"matches": []interface{}{
+							map[string]interface{}{"path": map[string]interface{}{"type": "PathPrefix", "value": "/qbittorrent"}},
+						},
+						"backendRefs": []interface{}{
+							map[string]interface{}{"name": resourceName, "port": int64(8090)},
+						},
+					},
+				},
+			}))
+
+			By("defaulting the path and leaving the hostnames to the Gateway")
+			ts.Spec.HTTPRoute = &torrentv1alpha1.HTTPRouteSpec{ParentRef: torrentv1alpha1.GatewayReference{Name: "public"}}
+			spec := httpRouteSpecForTorrentServer(ts, resourceName)
+			Expect(spec).NotTo(HaveKey("hostnames"))
+			Expect(spec["parentRefs"]).To(Equal([]interface{}{map[string]interface{}{"name": "public"}}))
+			Expect(spec["rules"]).To(HaveExactElements(HaveKeyWithValue("matches", ContainElement(
+				HaveKeyWithValue("path", HaveKeyWithValue("value", "/"))))))
+		})
+
+		It("should report the URL with the scheme and port of the listener", func() {
+			route := httpRoute()
+			Expect(gatewayListenerURL(route, "HTTPS", 443)).To(Equal("https://qbittorrent.example.com/qbittorrent"))
+			Expect(gatewayListenerURL(route, "HTTPS", 8443)).To(Equal("https://qbittorrent.example.com:8443/qbittorrent"))
+			Expect(gatewayListenerURL(route, "HTTP", 80)).To(Equal("http://qbittorrent.example.com/qbittorrent"))
+			Expect(gatewayListenerURL(route, "HTTP", 0)).To(Equal("http://qbittorrent.example.com/qbittorrent"))
+		})
+
+		It("should report the missing Gateway API instead of creating the route", func() {
+			resource := &torrentv1alpha1.TorrentServer{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec:       torrentv1alpha1.TorrentServerSpec{WebUIPort: 8080, HTTPRoute: httpRoute()},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			controllerReconciler := &TorrentServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			Expect(ts.Status.HTTPRouteName).To(BeEmpty())
+			Expect(ts.Status.HTTPRouteURL).To(BeEmpty())
+			Expect(ts.Status.ServiceName).To(Equal(resourceName))
+			unavailable := meta.FindStatusCondition(ts.Status.Conditions, TypeGatewayAPIUnavailableTorrentServer)
+			Expect(unavailable).NotTo(BeNil())
+			Expect(unavailable.Reason).To(Equal("GatewayAPINotInstalled"))
+			Expect(meta.FindStatusCondition(ts.Status.Conditions, TypeDegradedTorrentServer)).To(BeNil())
+
+			By("clearing the condition once spec.httpRoute is removed")
+			ts.Spec.HTTPRoute = nil
+			Expect(k8sClient.Update(ctx, ts)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			Expect(meta.FindStatusCondition(ts.Status.Conditions, TypeGatewayAPIUnavailableTorrentServer)).To(BeNil())
+		})
+	})
+
+	Context("When the managed TCC is edited by hand", func() {
+		const resourceName = "test-torrentserver-tcc-drift"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+		tccName := types.NamespacedName{
+			Name:      resourceName + "-client-config",
+			Namespace: "default",
+		}
+
+		AfterEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+			if err := k8sClient.Get(ctx, tccName, tcc); err == nil {
+				Expect(k8sClient.Delete(ctx, tcc)).To(Succeed())
+			}
+		})
+
+		It("should revert the URL and credentials secret and record an event", func() {
+			resource := &torrentv1alpha1.TorrentServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: torrentv1alpha1.TorrentServerSpec{
+					WebUIPort: 8080,
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler := &TorrentServerReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: recorder,
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive())
+
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, tccName, tcc)).To(Succeed())
+			derivedURL := tcc.Spec.URL
+
+			By("editing the TCC without changing the TorrentServer spec")
+			tcc.Spec.URL = "http://elsewhere:8080"
+			tcc.Spec.CredentialsSecret.Name = "other-credentials"
+			tcc.Spec.CheckInterval = "5m"
+			Expect(k8sClient.Update(ctx, tcc)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, tccName, tcc)).To(Succeed())
+			Expect(tcc.Spec.URL).To(Equal(derivedURL))
+			Expect(tcc.Spec.CredentialsSecret.Name).To(Equal(resourceName + "-credentials"))
+			Expect(tcc.Spec.CheckInterval).To(Equal("5m"))
+			Expect(recorder.Events).To(Receive(And(
+				ContainSubstring("TCCDriftCorrected"),
+				ContainSubstring("url, credentialsSecret"),
+			)))
+		})
+	})
+
+	Context("When an alternative WebUI is configured", func() {
+		const resourceName = "test-torrentserver-alt-webui"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		AfterEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+			deployment := &appsv1.Deployment{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, deployment); err == nil {
+				Expect(k8sClient.Delete(ctx, deployment)).To(Succeed())
+			}
+		})
+
+		It("should pass the root folder to config-init and mount the ConfigMap there", func() {
+			resource := &torrentv1alpha1.TorrentServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: torrentv1alpha1.TorrentServerSpec{
+					AlternativeWebUI: &torrentv1alpha1.AlternativeWebUISpec{
+						RootFolder:    "/vuetorrent",
+						ConfigMapName: "vuetorrent",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			controllerReconciler := &TorrentServerReconciler{
+				Client:        k8sClient,
+				Scheme:        k8sClient.Scheme(),
+				OperatorImage: "ghcr.io/guidonguido/qbittorrent-operator:test",
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deployment)).To(Succeed())
+
+			initContainer := deployment.Spec.Template.Spec.InitContainers[0]
+			Expect(initContainer.Env).To(ContainElement(corev1.EnvVar{
+				Name:  "ALTERNATIVE_WEBUI_ROOT_FOLDER",
+				Value: "/vuetorrent",
+			}))
+
+			var altVolume *corev1.Volume
+			for i, v := range deployment.Spec.Template.Spec.Volumes {
+				if v.Name == "alternative-webui" {
+					altVolume = &deployment.Spec.Template.Spec.Volumes[i]
+				}
+			}
+			Expect(altVolume).NotTo(BeNil())
+			Expect(altVolume.ConfigMap).NotTo(BeNil())
+			Expect(altVolume.ConfigMap.Name).To(Equal("vuetorrent"))
+
+			container := deployment.Spec.Template.Spec.Containers[0]
+			Expect(container.VolumeMounts).To(ContainElement(corev1.VolumeMount{
+				Name:      "alternative-webui",
+				MountPath: "/vuetorrent",
+				ReadOnly:  true,
+			}))
+		})
+
+		It("should derive the alternative WebUI preferences, letting spec.preferences override them", func() {
+			ts := &torrentv1alpha1.TorrentServer{
+				Spec: torrentv1alpha1.TorrentServerSpec{
+					AlternativeWebUI: &torrentv1alpha1.AlternativeWebUISpec{RootFolder: "/vuetorrent"},
+					Preferences: map[string]apiextensionsv1.JSON{
+						"alternative_webui_path": {Raw: []byte(`"/custom"`)},
+					},
+				},
+			}
+
+			desired, err := desiredPreferences(ts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(desired).To(HaveKeyWithValue("alternative_webui_enabled", true))
+			Expect(desired).To(HaveKeyWithValue("alternative_webui_path", "/custom"))
+		})
+
+		It("should disable the alternative WebUI when spec.alternativeWebUI is unset", func() {
+			desired, err := desiredPreferences(&torrentv1alpha1.TorrentServer{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(desired).To(HaveKeyWithValue("alternative_webui_enabled", false))
+			Expect(desired).NotTo(HaveKey("alternative_webui_path"))
+		})
+	})
+
+	Context("When an IP filter is configured", func() {
+		const resourceName = "test-torrentserver-ip-filter"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		AfterEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+			deployment := &appsv1.Deployment{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, deployment); err == nil {
+				Expect(k8sClient.Delete(ctx, deployment)).To(Succeed())
+			}
+		})
+
+		It("should mount the filter ConfigMap read-only", func() {
+			resource := &torrentv1alpha1.TorrentServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: torrentv1alpha1.TorrentServerSpec{
+					IPFilter: &torrentv1alpha1.IPFilterSpec{ConfigMapName: "blocklist"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			controllerReconciler := &TorrentServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deployment)).To(Succeed())
+
+			var filterVolume *corev1.Volume
+			for i, v := range deployment.Spec.Template.Spec.Volumes {
+				if v.Name == "ip-filter" {
+					filterVolume = &deployment.Spec.Template.Spec.Volumes[i]
+				}
+			}
+			Expect(filterVolume).NotTo(BeNil())
+			Expect(filterVolume.ConfigMap).NotTo(BeNil())
+			Expect(filterVolume.ConfigMap.Name).To(Equal("blocklist"))
+
+			container := deployment.Spec.Template.Spec.Containers[0]
+			Expect(container.VolumeMounts).To(ContainElement(corev1.VolumeMount{
+				Name:      "ip-filter",
+				MountPath: "/ip-filter",
+				ReadOnly:  true,
+			}))
+		})
+
+		It("should mount a filter PVC read-only and point the preferences at the file", func() {
+			enabled := false
+			ts := &torrentv1alpha1.TorrentServer{
+				Spec: torrentv1alpha1.TorrentServerSpec{
+					IPFilter: &torrentv1alpha1.IPFilterSpec{ClaimName: "blocklists", FileName: "lists/level1.p2p"},
+				},
+			}
+
+			desired, err := desiredPreferences(ts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(desired).To(HaveKeyWithValue("ip_filter_enabled", true))
+			Expect(desired).To(HaveKeyWithValue("ip_filter_path", "/ip-filter/lists/level1.p2p"))
+
+			ts.Spec.IPFilter.Enabled = &enabled
+			ts.Spec.IPFilter.FileName = ""
+			desired, err = desiredPreferences(ts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(desired).To(HaveKeyWithValue("ip_filter_enabled", false))
+			Expect(desired).To(HaveKeyWithValue("ip_filter_path", "/ip-filter/ipfilter.dat"))
+
+			desired, err = desiredPreferences(&torrentv1alpha1.TorrentServer{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(desired).NotTo(HaveKey("ip_filter_enabled"))
+
+			By("mounting the PVC read-only")
+			ts.ObjectMeta = metav1.ObjectMeta{Name: resourceName, Namespace: "default"}
+			Expect(k8sClient.Create(ctx, ts)).To(Succeed())
+			controllerReconciler := &TorrentServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
+				Name: "ip-filter",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "blocklists", ReadOnly: true},
+				},
+			}))
+		})
+	})
+
+	Context("When config-init reports its outcome", func() {
+		const resourceName = "test-torrentserver-config-seeded"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var controllerReconciler *TorrentServerReconciler
+
+		// createConfigInitPod creates a TorrentServer pod whose config-init container exited with the message
+		createConfigInitPod := func(name, message string, finishedAt time.Time) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "default",
+					Labels:    labelsForTorrentServer(resourceName),
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "qbittorrent", Image: "lscr.io/linuxserver/qbittorrent:latest"}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+			pod.Status.InitContainerStatuses = []corev1.ContainerStatus{{
+				Name: "config-init",
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+					ExitCode:   0,
+					Message:    message,
+					FinishedAt: metav1.NewTime(finishedAt),
+				}},
+			}}
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+		}
+
+		reconcileConfigSeeded := func() *bool {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			return ts.Status.ConfigSeeded
+		}
+
+		BeforeEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			controllerReconciler = &TorrentServerReconciler{
+				Client:        k8sClient,
+				Scheme:        k8sClient.Scheme(),
+				OperatorImage: "ghcr.io/guidonguido/qbittorrent-operator:test",
+			}
+		})
+
+		AfterEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+			deployment := &appsv1.Deployment{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, deployment); err == nil {
+				Expect(k8sClient.Delete(ctx, deployment)).To(Succeed())
+			}
+			Expect(k8sClient.DeleteAllOf(ctx, &corev1.Pod{}, client.InNamespace("default"),
+				client.MatchingLabels(labelsForTorrentServer(resourceName)))).To(Succeed())
+		})
+
+		It("should leave configSeeded unset until config-init ran", func() {
+			Expect(reconcileConfigSeeded()).To(BeNil())
+		})
+
+		It("should report a pre-existing config as not seeded", func() {
+			createConfigInitPod(resourceName+"-imported", "config-init succeeded: seeded=false\n", time.Now())
+
+			configSeeded := reconcileConfigSeeded()
+			Expect(configSeeded).NotTo(BeNil())
+			Expect(*configSeeded).To(BeFalse())
+		})
+
+		It("should report the outcome of the latest config-init run", func() {
+			createConfigInitPod(resourceName+"-old", "config-init succeeded: seeded=false\n", time.Now().Add(-time.Hour))
+			createConfigInitPod(resourceName+"-new", "config-init succeeded: seeded=true\n", time.Now())
+
+			configSeeded := reconcileConfigSeeded()
+			Expect(configSeeded).NotTo(BeNil())
+			Expect(*configSeeded).To(BeTrue())
+
+			By("keeping the outcome while no pod reports one")
+			Expect(k8sClient.DeleteAllOf(ctx, &corev1.Pod{}, client.InNamespace("default"),
+				client.MatchingLabels(labelsForTorrentServer(resourceName)))).To(Succeed())
+			configSeeded = reconcileConfigSeeded()
+			Expect(configSeeded).NotTo(BeNil())
+			Expect(*configSeeded).To(BeTrue())
+		})
+	})
+
+	Context("When incomplete storage is configured", func() {
+		const resourceName = "test-torrentserver-incomplete"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		AfterEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+			deployment := &appsv1.Deployment{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, deployment); err == nil {
+				Expect(k8sClient.Delete(ctx, deployment)).To(Succeed())
+			}
+		})
+
+		It("should mount a size-limited tmpfs emptyDir at the incomplete path", func() {
+			sizeLimit := resource.MustParse("2Gi")
+			resource := &torrentv1alpha1.TorrentServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: torrentv1alpha1.TorrentServerSpec{
+					IncompleteStorage: &torrentv1alpha1.IncompleteStorageSpec{
+						MountPath: "/scratch",
+						Medium:    corev1.StorageMediumMemory,
+						SizeLimit: &sizeLimit,
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			controllerReconciler := &TorrentServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deployment)).To(Succeed())
+
+			var incompleteVolume *corev1.Volume
+			for i, v := range deployment.Spec.Template.Spec.Volumes {
+				if v.Name == "incomplete" {
+					incompleteVolume = &deployment.Spec.Template.Spec.Volumes[i]
+				}
+			}
+			Expect(incompleteVolume).NotTo(BeNil())
+			Expect(incompleteVolume.EmptyDir).NotTo(BeNil())
+			Expect(incompleteVolume.EmptyDir.Medium).To(Equal(corev1.StorageMediumMemory))
+			Expect(incompleteVolume.EmptyDir.SizeLimit.Cmp(sizeLimit)).To(Equal(0))
+
+			container := deployment.Spec.Template.Spec.Containers[0]
+			Expect(container.VolumeMounts).To(ContainElement(corev1.VolumeMount{
+				Name:      "incomplete",
+				MountPath: "/scratch",
+			}))
+		})
+
+		It("should keep incomplete torrents in the mount path", func() {
+			ts := &torrentv1alpha1.TorrentServer{
+				Spec: torrentv1alpha1.TorrentServerSpec{
+					IncompleteStorage: &torrentv1alpha1.IncompleteStorageSpec{},
+				},
+			}
+
+			desired, err := desiredPreferences(ts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(desired).To(HaveKeyWithValue("temp_path_enabled", true))
+			Expect(desired).To(HaveKeyWithValue("temp_path", "/incomplete"))
+
+			desired, err = desiredPreferences(&torrentv1alpha1.TorrentServer{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(desired).NotTo(HaveKey("temp_path_enabled"))
+		})
+
+		It("should map the scheduler window to the scheduler preferences", func() {
+			ts := &torrentv1alpha1.TorrentServer{
+				Spec: torrentv1alpha1.TorrentServerSpec{
+					Scheduler: &torrentv1alpha1.SchedulerSpec{From: "23:30", To: "07:05", Days: "Weekdays"},
+				},
+			}
+
+			desired, err := desiredPreferences(ts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(desired).To(HaveKeyWithValue("scheduler_enabled", true))
+			Expect(desired).To(HaveKeyWithValue("schedule_from_hour", 23))
+			Expect(desired).To(HaveKeyWithValue("schedule_from_min", 30))
+			Expect(desired).To(HaveKeyWithValue("schedule_to_hour", 7))
+			Expect(desired).To(HaveKeyWithValue("schedule_to_min", 5))
+			Expect(desired).To(HaveKeyWithValue("scheduler_days", 1))
+
+			By("defaulting the days and honouring a disabled scheduler")
+			disabled := false
+			ts.Spec.Scheduler = &torrentv1alpha1.SchedulerSpec{Enabled: &disabled, From: "01:00", To: "02:00"}
+			desired, err = desiredPreferences(ts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(desired).To(HaveKeyWithValue("scheduler_enabled", false))
+			Expect(desired).To(HaveKeyWithValue("scheduler_days", 0))
+
+			By("rejecting a malformed time")
+			ts.Spec.Scheduler = &torrentv1alpha1.SchedulerSpec{From: "25:00", To: "02:00"}
+			_, err = desiredPreferences(ts)
+			Expect(err).To(MatchError(ContainSubstring("invalid scheduler from")))
+
+			desired, err = desiredPreferences(&torrentv1alpha1.TorrentServer{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(desired).NotTo(HaveKey("scheduler_enabled"))
+		})
+	})
+
+	Context("When replicas are ready", func() {
+		const resourceName = "test-torrentserver-webui"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var fake *fakeQBTClient
+		var controllerReconciler *TorrentServerReconciler
+
+		BeforeEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			fake = newFakeQBTClient()
+			controllerReconciler = &TorrentServerReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				ClientPool: newFakeClientPool(fake),
+			}
+
+			By("reconciling once to create the Deployment and marking it ready")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			setDeploymentRollout(ctx, typeNamespacedName, 1, 1, 1)
+		})
+
+		AfterEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+		})
+
+		It("should set WebUIUnreachable when the WebUI does not answer", func() {
+			fake.pingErr = fmt.Errorf("connection refused")
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			Expect(meta.FindStatusCondition(ts.Status.Conditions, TypeAvailableTorrentServer)).To(BeNil())
+			degraded := meta.FindStatusCondition(ts.Status.Conditions, TypeDegradedTorrentServer)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal("WebUIUnreachable"))
+		})
+
+		It("should not report Available while a new revision rolls out", func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("changing the spec so that the Deployment gets a new revision")
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			ts.Spec.Timezone = "Europe/Rome"
+			Expect(k8sClient.Update(ctx, ts)).To(Succeed())
+
+			By("leaving the old replica ready while the new one starts")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deployment)).To(Succeed())
+			deployment.Status.ObservedGeneration = deployment.Generation
+			deployment.Status.Replicas = 2
+			deployment.Status.UpdatedReplicas = 1
+			deployment.Status.ReadyReplicas = 1
+			Expect(k8sClient.Status().Update(ctx, deployment)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			Expect(ts.Status.ObservedGeneration).To(Equal(ts.Generation))
+			available := meta.FindStatusCondition(ts.Status.Conditions, TypeAvailableTorrentServer)
+			Expect(available).NotTo(BeNil())
+			Expect(available.Status).To(Equal(metav1.ConditionFalse))
+			Expect(available.Reason).To(Equal("RolloutInProgress"))
+			Expect(available.ObservedGeneration).To(Equal(ts.Generation))
+			Expect(meta.FindStatusCondition(ts.Status.Conditions, TypeDegradedTorrentServer)).To(BeNil())
+
+			By("completing the rollout")
+			setDeploymentRollout(ctx, typeNamespacedName, 1, 1, 1)
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			available = meta.FindStatusCondition(ts.Status.Conditions, TypeAvailableTorrentServer)
+			Expect(available.Status).To(Equal(metav1.ConditionTrue))
+			Expect(available.ObservedGeneration).To(Equal(ts.Generation))
+		})
+
+		It("should report Progressing while a new image rolls out", func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			progressing := meta.FindStatusCondition(ts.Status.Conditions, TypeProgressingTorrentServer)
+			Expect(progressing).NotTo(BeNil())
+			Expect(progressing.Status).To(Equal(metav1.ConditionFalse))
+			Expect(progressing.Reason).To(Equal("RolloutComplete"))
+
+			By("bumping the qBittorrent image")
+			ts.Spec.Image = "lscr.io/linuxserver/qbittorrent:5.1.0"
+			Expect(k8sClient.Update(ctx, ts)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("leaving the Deployment mid-rollout")
+			setDeploymentRollout(ctx, typeNamespacedName, 2, 1, 1)
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			progressing = meta.FindStatusCondition(ts.Status.Conditions, TypeProgressingTorrentServer)
+			Expect(progressing.Status).To(Equal(metav1.ConditionTrue))
+			Expect(progressing.Reason).To(Equal("RolloutInProgress"))
+			Expect(progressing.Message).To(ContainSubstring("lscr.io/linuxserver/qbittorrent:5.1.0"))
+			Expect(progressing.ObservedGeneration).To(Equal(ts.Generation))
+
+			By("exceeding the Deployment progress deadline")
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deployment)).To(Succeed())
+			deployment.Status.Conditions = []appsv1.DeploymentCondition{{
+				Type:    appsv1.DeploymentProgressing,
+				Status:  corev1.ConditionFalse,
+				Reason:  "ProgressDeadlineExceeded",
+				Message: "ReplicaSet has timed out progressing.",
+			}}
+			Expect(k8sClient.Status().Update(ctx, deployment)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			progressing = meta.FindStatusCondition(ts.Status.Conditions, TypeProgressingTorrentServer)
+			Expect(progressing.Status).To(Equal(metav1.ConditionFalse))
+			Expect(progressing.Reason).To(Equal("ProgressDeadlineExceeded"))
+
+			By("completing the rollout")
+			setDeploymentRollout(ctx, typeNamespacedName, 1, 1, 1)
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			progressing = meta.FindStatusCondition(ts.Status.Conditions, TypeProgressingTorrentServer)
+			Expect(progressing.Status).To(Equal(metav1.ConditionFalse))
+			Expect(progressing.Reason).To(Equal("RolloutComplete"))
+		})
+
+		It("should set Available once the WebUI answers", func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fake.Calls()).To(ContainElement("Ping"))
+
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			Expect(ts.Status.ReadyReplicas).To(Equal(int32(1)))
+			Expect(meta.IsStatusConditionTrue(ts.Status.Conditions, TypeAvailableTorrentServer)).To(BeTrue())
+		})
+
+		It("should requeue a healthy instance after the resync interval", func() {
+			controllerReconciler.ResyncInterval = 7 * time.Minute
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(7 * time.Minute))
+
+			By("keeping the fast retry when the WebUI does not answer")
+			fake.pingErr = fmt.Errorf("connection refused")
+			result, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(10 * time.Second))
+		})
+
+		It("should re-apply IP filter preferences changed out-of-band", func() {
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			ts.Spec.IPFilter = &torrentv1alpha1.IPFilterSpec{ConfigMapName: "blocklist"}
+			Expect(k8sClient.Update(ctx, ts)).To(Succeed())
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("rolling out the Deployment mounting the filter")
+			setDeploymentRollout(ctx, typeNamespacedName, 1, 1, 1)
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fake.Preference("ip_filter_enabled")).To(BeTrue())
+			Expect(fake.Preference("ip_filter_path")).To(Equal("/ip-filter/ipfilter.dat"))
+
+			By("disabling the filter in the WebUI")
+			fake.SetPreference("ip_filter_enabled", false)
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fake.Calls()).To(ContainElement("SetPreferences:ip_filter_enabled"))
+			Expect(fake.Preference("ip_filter_enabled")).To(BeTrue())
+		})
+
+		It("should apply the queue limits and enable queueing", func() {
+			enabled := true
+			downloads, uploads, active := int32(2), int32(3), int32(4)
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			ts.Spec.Queueing = &torrentv1alpha1.QueueingSpec{
+				Enabled:            &enabled,
+				MaxActiveDownloads: &downloads,
+				MaxActiveUploads:   &uploads,
+				MaxActiveTorrents:  &active,
+			}
+			Expect(k8sClient.Update(ctx, ts)).To(Succeed())
+			fake.SetPreference("queueing_enabled", false)
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fake.Calls()).To(ContainElement(
+				"SetPreferences:max_active_downloads|max_active_torrents|max_active_uploads|queueing_enabled"))
+			Expect(fake.Preference("queueing_enabled")).To(BeTrue())
+			Expect(fake.Preference("max_active_downloads")).To(BeEquivalentTo(2))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			Expect(meta.FindStatusCondition(ts.Status.Conditions, TypeQueueingDisabledTorrentServer)).To(BeNil())
+		})
+
+		It("should report active limits that queueing disabled on the instance leaves unenforced", func() {
+			downloads := int32(2)
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			ts.Spec.Queueing = &torrentv1alpha1.QueueingSpec{MaxActiveDownloads: &downloads}
+			Expect(k8sClient.Update(ctx, ts)).To(Succeed())
+			fake.SetPreference("queueing_enabled", false)
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fake.Calls()).To(ContainElement("SetPreferences:max_active_downloads"))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			queueing := meta.FindStatusCondition(ts.Status.Conditions, TypeQueueingDisabledTorrentServer)
+			Expect(queueing).NotTo(BeNil())
+			Expect(queueing.Status).To(Equal(metav1.ConditionTrue))
+			Expect(queueing.Message).To(ContainSubstring("max_active_downloads"))
+			Expect(meta.IsStatusConditionTrue(ts.Status.Conditions, TypeAvailableTorrentServer)).To(BeTrue())
+
+			By("enabling queueing on the instance")
+			fake.SetPreference("queueing_enabled", true)
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			Expect(meta.FindStatusCondition(ts.Status.Conditions, TypeQueueingDisabledTorrentServer)).To(BeNil())
+		})
+
+		It("should report the free disk space and warn when it drops below the threshold", func() {
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler.Recorder = recorder
+			threshold := resource.MustParse("10Gi")
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			ts.Spec.LowDiskSpaceThreshold = &threshold
+			Expect(k8sClient.Update(ctx, ts)).To(Succeed())
+			fake.freeSpace = 20 << 30
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fake.Calls()).To(ContainElement("GetMainData"))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			Expect(ts.Status.FreeSpaceOnDiskHuman).To(Equal("20.0 GiB"))
+			Expect(meta.FindStatusCondition(ts.Status.Conditions, TypeLowDiskSpaceTorrentServer)).To(BeNil())
+
+			By("filling up the disk")
+			fake.freeSpace = 2 << 30
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			Expect(ts.Status.FreeSpaceOnDisk).To(HaveValue(Equal(int64(2 << 30))))
+			Expect(ts.Status.FreeSpaceOnDiskHuman).To(Equal("2.0 GiB"))
+			lowDisk := meta.FindStatusCondition(ts.Status.Conditions, TypeLowDiskSpaceTorrentServer)
+			Expect(lowDisk).NotTo(BeNil())
+			Expect(lowDisk.Status).To(Equal(metav1.ConditionTrue))
+			Expect(lowDisk.Message).To(ContainSubstring("10Gi"))
+			Expect(lowDisk.ObservedGeneration).To(Equal(ts.Generation))
+			Expect(meta.IsStatusConditionTrue(ts.Status.Conditions, TypeAvailableTorrentServer)).To(BeTrue())
+			Expect(recorder.Events).To(Receive(ContainSubstring("Warning LowDiskSpace")))
+
+			By("staying below the threshold without repeating the event")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive())
+		})
+
+		It("should stop every torrent on suspend and restart only the running ones on resume", func() {
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: "running", State: "downloading"})
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: "stopped", State: "stoppedUP"})
+			suspend := true
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			ts.Spec.Suspend = &suspend
+			Expect(k8sClient.Update(ctx, ts)).To(Succeed())
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fake.Calls()).To(ContainElement("PauseAll"))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			Expect(ts.Status.Suspended).To(BeTrue())
+			Expect(ts.Status.SuspendedTorrents).To(Equal([]string{"running"}))
+
+			By("reconciling while suspended without pausing again")
+			suspended := len(fake.Calls())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fake.Calls()[suspended:]).NotTo(ContainElement("PauseAll"))
+
+			By("resuming the instance")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			ts.Spec.Suspend = nil
+			Expect(k8sClient.Update(ctx, ts)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fake.Calls()).To(ContainElement("ResumeTorrents:running"))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			Expect(ts.Status.Suspended).To(BeFalse())
+			Expect(ts.Status.SuspendedTorrents).To(BeEmpty())
+		})
+
+		It("should report a failure to suspend the instance", func() {
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: "running", State: "downloading"})
+			fake.pauseAllErr = fmt.Errorf("stop refused")
+			suspend := true
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			ts.Spec.Suspend = &suspend
+			Expect(k8sClient.Update(ctx, ts)).To(Succeed())
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			Expect(ts.Status.Suspended).To(BeFalse())
+			degraded := meta.FindStatusCondition(ts.Status.Conditions, TypeDegradedTorrentServer)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal("SuspendError"))
+			Expect(degraded.Message).To(ContainSubstring("stop refused"))
+
+			By("having recorded the running torrents before stopping them")
+			Expect(ts.Status.SuspendedTorrents).To(Equal([]string{"running"}))
+
+			By("keeping the recorded torrents when retrying")
+			fake.pauseAllErr = nil
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: "late", State: "downloading"})
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			Expect(ts.Status.Suspended).To(BeTrue())
+			Expect(ts.Status.SuspendedTorrents).To(Equal([]string{"running"}))
+		})
+
+		It("should restart the recorded torrents when suspend is unset before stopping them", func() {
+			fake.SetTorrent(qbittorrent.TorrentInfo{Hash: "running", State: "downloading"})
+			fake.pauseAllErr = fmt.Errorf("stop refused")
+			suspend := true
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			ts.Spec.Suspend = &suspend
+			Expect(k8sClient.Update(ctx, ts)).To(Succeed())
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			ts.Spec.Suspend = nil
+			Expect(k8sClient.Update(ctx, ts)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fake.Calls()).To(ContainElement("ResumeTorrents:running"))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			Expect(ts.Status.SuspendedTorrents).To(BeEmpty())
+		})
+
+		It("should align the WebUI session timeout with the client pool", func() {
+			By("raising an undeclared timeout shorter than the pool reuses sessions for")
+			fake.SetPreference(qbittorrent.PreferenceWebUISessionTimeout, float64(120))
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fake.Calls()).To(ContainElement("SetPreferences:web_ui_session_timeout"))
+			Expect(fake.Preference(qbittorrent.PreferenceWebUISessionTimeout)).To(BeEquivalentTo(360))
+
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			Expect(meta.FindStatusCondition(ts.Status.Conditions, TypeSessionTimeoutMisalignedTorrentServer)).To(BeNil())
+
+			By("keeping a declared timeout that is too short and warning about it")
+			ts.Spec.Preferences = map[string]apiextensionsv1.JSON{
+				qbittorrent.PreferenceWebUISessionTimeout: {Raw: []byte("60")},
+			}
+			Expect(k8sClient.Update(ctx, ts)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fake.Preference(qbittorrent.PreferenceWebUISessionTimeout)).To(BeEquivalentTo(60))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			misaligned := meta.FindStatusCondition(ts.Status.Conditions, TypeSessionTimeoutMisalignedTorrentServer)
+			Expect(misaligned).NotTo(BeNil())
+			Expect(misaligned.Status).To(Equal(metav1.ConditionTrue))
+			Expect(misaligned.Reason).To(Equal("SessionTimeoutTooShort"))
+			Expect(misaligned.Message).To(ContainSubstring("6m0s"))
+			Expect(meta.IsStatusConditionTrue(ts.Status.Conditions, TypeAvailableTorrentServer)).To(BeTrue())
+
+			By("declaring a long enough timeout")
+			ts.Spec.Preferences = map[string]apiextensionsv1.JSON{
+				qbittorrent.PreferenceWebUISessionTimeout: {Raw: []byte("3600")},
+			}
+			Expect(k8sClient.Update(ctx, ts)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			Expect(meta.FindStatusCondition(ts.Status.Conditions, TypeSessionTimeoutMisalignedTorrentServer)).To(BeNil())
+		})
+
+		It("should set a new password on the running instance when the managed Secret is deleted", func() {
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler.Recorder = recorder
+			By("reconciling once so that the client pool holds a live session")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			secretKey := types.NamespacedName{Name: resourceName + "-credentials", Namespace: "default"}
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, secretKey, secret)).To(Succeed())
+			oldPassword := string(secret.Data["password"])
+			Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fake.Calls()).To(ContainElement("SetPreferences:web_ui_password|web_ui_username"))
+
+			Expect(k8sClient.Get(ctx, secretKey, secret)).To(Succeed())
+			Expect(string(secret.Data["username"])).To(Equal("admin"))
+			Expect(string(secret.Data["password"])).NotTo(Equal(oldPassword))
+			Expect(fake.Preference(qbittorrent.PreferenceWebUIPassword)).To(Equal(string(secret.Data["password"])))
+			Expect(recorder.Events).To(Receive(ContainSubstring("Warning CredentialsSecretRecreated")))
+
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			Expect(meta.FindStatusCondition(ts.Status.Conditions, TypeDegradedTorrentServer)).To(BeNil())
+			Expect(meta.IsStatusConditionTrue(ts.Status.Conditions, TypeAvailableTorrentServer)).To(BeTrue())
+		})
+
+		It("should not log the new password set on the running instance", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/api/v2/auth/login" {
+					http.SetCookie(w, &http.Cookie{Name: "SID", Value: "sid"})
+					_, _ = w.Write([]byte("Ok."))
+				}
+			}))
+			defer server.Close()
+
+			By("holding a live session with a real client")
+			pool := qbittorrent.NewClientPool(5 * time.Minute)
+			_, err := pool.GetOrCreate(ctx, server.URL, "admin", "previous")
+			Expect(err).NotTo(HaveOccurred())
+			controllerReconciler.ClientPool = pool
+
+			var lines []string
+			logger := funcr.New(func(prefix, args string) {
+				lines = append(lines, prefix+" "+args)
+			}, funcr.Options{Verbosity: 1})
+			ts := &torrentv1alpha1.TorrentServer{Status: torrentv1alpha1.TorrentServerStatus{URL: server.URL}}
+			const password = "n3w-Secret-Passw0rd"
+			Expect(controllerReconciler.resetInstanceCredentials(log.IntoContext(ctx, logger), ts,
+				resourceName+"-credentials", "admin", password)).To(Succeed())
+
+			logged := strings.Join(lines, "\n")
+			Expect(logged).To(ContainSubstring(qbittorrent.PreferenceWebUIPassword))
+			Expect(logged).NotTo(ContainSubstring(password))
+		})
+
+		It("should not mint a conflicting password for a deleted Secret without a live session", func() {
+			By("reconciling with a client pool that holds no session")
+			controllerReconciler.ClientPool = newFakeClientPool(fake)
+			controllerReconciler.OperatorImage = "qbittorrent-operator:test"
+			secretKey := types.NamespacedName{Name: resourceName + "-credentials", Namespace: "default"}
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, secretKey, secret)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, secretKey, secret))).To(BeTrue())
+			Expect(fake.Calls()).NotTo(ContainElement("SetPreferences:web_ui_password|web_ui_username"))
+
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			degraded := meta.FindStatusCondition(ts.Status.Conditions, TypeDegradedTorrentServer)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal("CredentialsSecretDeleted"))
+			Expect(degraded.Message).To(ContainSubstring(ResetCredentialsAnnotation))
+
+			By("resetting the credentials through the annotation")
+			ts.Annotations = map[string]string{ResetCredentialsAnnotation: "1"}
+			Expect(k8sClient.Update(ctx, ts)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, secretKey, secret)).To(Succeed())
+			Expect(secret.Data["password"]).NotTo(BeEmpty())
+
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deployment)).To(Succeed())
+			Expect(configInitEnvValue(deployment, configinit.EnvResetCredentials)).To(Equal("1"))
+		})
+
+		It("should roll the Deployment when the reset-credentials annotation changes", func() {
+			controllerReconciler.OperatorImage = "qbittorrent-operator:test"
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			ts.Annotations = map[string]string{ResetCredentialsAnnotation: "2026-10-17"}
+			Expect(k8sClient.Update(ctx, ts)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deployment)).To(Succeed())
+			Expect(configInitEnvValue(deployment, configinit.EnvResetCredentials)).To(Equal("2026-10-17"))
+		})
+
+		It("should re-apply only drifted preferences and leave unmanaged keys untouched", func() {
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler.Recorder = recorder
+
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			ts.Spec.Preferences = map[string]apiextensionsv1.JSON{
+				"max_active_downloads": {Raw: []byte(`5`)},
+				"dht":                  {Raw: []byte(`false`)},
+			}
+			Expect(k8sClient.Update(ctx, ts)).To(Succeed())
+
+			By("simulating an out-of-band edit of a managed key")
+			fake.SetPreference("max_active_downloads", float64(3))
+			fake.SetPreference("dht", false)
+			fake.SetPreference("web_ui_port", float64(8080))
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fake.Calls()).To(ContainElement("SetPreferences:max_active_downloads"))
+			Expect(fake.Preference("max_active_downloads")).To(BeEquivalentTo(5))
+			Expect(fake.Preference("web_ui_port")).To(BeEquivalentTo(8080))
+			Expect(recorder.Events).To(Receive(ContainSubstring("PreferencesReconciled")))
+
+			By("reconciling again without drift")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			setCalls := 0
+			for _, call := range fake.Calls() {
+				if strings.HasPrefix(call, "SetPreferences") {
+					setCalls++
+				}
+			}
+			Expect(setCalls).To(Equal(1))
+			Expect(recorder.Events).NotTo(Receive())
+		})
+
+		It("should warn while the managed TCC detected an unsupported qBittorrent version", func() {
+			tccName := types.NamespacedName{Name: resourceName + "-client-config", Namespace: "default"}
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, tccName, tcc)).To(Succeed())
+			tcc.Status.QBittorrentVersion = "v4.1.9"
+			Expect(k8sClient.Status().Update(ctx, tcc)).To(Succeed())
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			unsupported := meta.FindStatusCondition(ts.Status.Conditions, TypeUnsupportedVersionTorrentServer)
+			Expect(unsupported).NotTo(BeNil())
+			Expect(unsupported.Reason).To(Equal("VersionBelowMinimum"))
+			Expect(unsupported.Message).To(ContainSubstring("v4.1.9"))
+			Expect(meta.IsStatusConditionTrue(ts.Status.Conditions, TypeAvailableTorrentServer)).To(BeTrue())
+
+			By("removing the warning once qBittorrent is upgraded")
+			Expect(k8sClient.Get(ctx, tccName, tcc)).To(Succeed())
+			tcc.Status.QBittorrentVersion = "v5.1.4"
+			Expect(k8sClient.Status().Update(ctx, tcc)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			Expect(meta.FindStatusCondition(ts.Status.Conditions, TypeUnsupportedVersionTorrentServer)).To(BeNil())
+		})
+
+		It("should create the categories of spec.categoryPaths and edit back drifted save paths", func() {
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler.Recorder = recorder
+
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			ts.Spec.CategoryPaths = map[string]string{
+				"tv":     "/downloads/tv",
+				"movies": "/downloads/movies",
+			}
+			Expect(k8sClient.Update(ctx, ts)).To(Succeed())
+
+			By("keeping a category that already has the declared save path")
+			fake.SetCategory("movies", "/downloads/movies")
+			fake.SetCategory("music", "/downloads/music")
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fake.Calls()).To(ContainElement("CreateCategory:tv:/downloads/tv"))
+			Expect(fake.Calls()).NotTo(ContainElement(HavePrefix("CreateCategory:movies:")))
+			Expect(fake.Calls()).NotTo(ContainElement(HavePrefix("EditCategory:")))
+			Expect(recorder.Events).To(Receive(ContainSubstring("CategoriesReconciled")))
+
+			By("editing back a save path changed in the WebUI")
+			fake.SetCategory("tv", "/mnt/tv")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fake.Calls()).To(ContainElement("EditCategory:tv:/downloads/tv"))
+			savePath, _ := fake.CategoryPath("tv")
+			Expect(savePath).To(Equal("/downloads/tv"))
+			Expect(fake.Calls()).NotTo(ContainElement(HavePrefix("EditCategory:music:")))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(ts.Status.Conditions, TypeAvailableTorrentServer)).To(BeTrue())
+		})
+
+		It("should report Degraded when a category cannot be created", func() {
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			ts.Spec.CategoryPaths = map[string]string{"tv": "/downloads/tv"}
+			Expect(k8sClient.Update(ctx, ts)).To(Succeed())
+			fake.categoryErr = fmt.Errorf("forbidden")
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(10 * time.Second))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			degraded := meta.FindStatusCondition(ts.Status.Conditions, TypeDegradedTorrentServer)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal("CategoriesError"))
+			Expect(degraded.Message).To(ContainSubstring("forbidden"))
+		})
+
+		It("should still report the free space and suspend the torrents when a category fails", func() {
+			suspend := true
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			ts.Spec.CategoryPaths = map[string]string{"tv": "/downloads/tv"}
+			ts.Spec.Suspend = &suspend
+			Expect(k8sClient.Update(ctx, ts)).To(Succeed())
+			fake.categoryErr = fmt.Errorf("forbidden")
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fake.Calls()).To(ContainElement("PauseAll"))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			Expect(ts.Status.FreeSpaceOnDisk).NotTo(BeNil())
+			Expect(ts.Status.Suspended).To(BeTrue())
+			degraded := meta.FindStatusCondition(ts.Status.Conditions, TypeDegradedTorrentServer)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal("CategoriesError"))
+		})
+	})
+
+	Context("When summarizing the health of the child resources", func() {
+		const resourceName = "test-torrentserver-resources"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var controllerReconciler *TorrentServerReconciler
+
+		reconcileTS := func() *torrentv1alpha1.TorrentServer {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			return ts
+		}
+
+		BeforeEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			controllerReconciler = &TorrentServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+		})
+
+		AfterEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+		})
+
+		It("should list the unready children until all of them are healthy", func() {
+			ts := reconcileTS()
+			resourcesReady := meta.FindStatusCondition(ts.Status.Conditions, TypeResourcesReadyTorrentServer)
+			Expect(resourcesReady).NotTo(BeNil())
+			Expect(resourcesReady.Status).To(Equal(metav1.ConditionFalse))
+			Expect(resourcesReady.Reason).To(Equal("ResourcesNotReady"))
+			Expect(resourcesReady.Message).To(ContainSubstring("Deployment " + ts.Status.DeploymentName + " is not ready"))
+			Expect(resourcesReady.Message).To(ContainSubstring(
+				"TorrentClientConfiguration " + ts.Status.ClientConfigurationName + " is not Available"))
+
+			By("rolling out the Deployment while the TCC is still not Available")
+			setDeploymentRollout(ctx, typeNamespacedName, 1, 1, 1)
+			ts = reconcileTS()
+			resourcesReady = meta.FindStatusCondition(ts.Status.Conditions, TypeResourcesReadyTorrentServer)
+			Expect(resourcesReady.Status).To(Equal(metav1.ConditionFalse))
+			Expect(resourcesReady.Message).To(Equal(
+				"TorrentClientConfiguration " + ts.Status.ClientConfigurationName + " is not Available"))
+
+			By("marking the TCC Available")
+			tcc := &torrentv1alpha1.TorrentClientConfiguration{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: ts.Status.ClientConfigurationName, Namespace: "default"}, tcc)).To(Succeed())
+			meta.SetStatusCondition(&tcc.Status.Conditions, metav1.Condition{
+				Type:   TypeAvailableTCC,
+				Status: metav1.ConditionTrue,
+				Reason: "Connected",
+			})
+			Expect(k8sClient.Status().Update(ctx, tcc)).To(Succeed())
+
+			ts = reconcileTS()
+			resourcesReady = meta.FindStatusCondition(ts.Status.Conditions, TypeResourcesReadyTorrentServer)
+			Expect(resourcesReady.Status).To(Equal(metav1.ConditionTrue))
+			Expect(resourcesReady.Reason).To(Equal("AllResourcesReady"))
+			Expect(resourcesReady.ObservedGeneration).To(Equal(ts.Generation))
+		})
+	})
+
+	Context("When an existing config PVC is referenced", func() {
+		const resourceName = "test-torrentserver-existing-config"
+		const claimName = "legacy-qbittorrent-config"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      claimName,
+					Namespace: "default",
+				},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceStorage: resource.MustParse("1Gi"),
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pvc)).To(Succeed())
+
+			resource := &torrentv1alpha1.TorrentServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: torrentv1alpha1.TorrentServerSpec{
+					ConfigStorage: &torrentv1alpha1.StorageSpec{
+						ExistingClaimName: claimName,
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+			pvc := &corev1.PersistentVolumeClaim{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: claimName, Namespace: "default"}, pvc); err == nil {
+				Expect(k8sClient.Delete(ctx, pvc)).To(Succeed())
+			}
+		})
+
+		It("should mount the existing claim without creating a config PVC", func() {
+			controllerReconciler := &TorrentServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-config", Namespace: "default"}, &corev1.PersistentVolumeClaim{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			pvc := &corev1.PersistentVolumeClaim{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: claimName, Namespace: "default"}, pvc)).To(Succeed())
+			Expect(pvc.OwnerReferences).To(BeEmpty())
+
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deployment)).To(Succeed())
+			var configClaim string
+			for _, volume := range deployment.Spec.Template.Spec.Volumes {
+				if volume.Name == "config" {
+					configClaim = volume.PersistentVolumeClaim.ClaimName
+				}
+			}
+			Expect(configClaim).To(Equal(claimName))
+
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			Expect(ts.Status.ConfigPVCName).To(Equal(claimName))
+		})
+
+		It("should set Degraded when the existing claim does not exist", func() {
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			ts.Spec.ConfigStorage.ExistingClaimName = "missing-claim"
+			Expect(k8sClient.Update(ctx, ts)).To(Succeed())
+
+			controllerReconciler := &TorrentServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			degraded := meta.FindStatusCondition(ts.Status.Conditions, TypeDegradedTorrentServer)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal("ConfigPVCError"))
+		})
+	})
+
+	Context("When waiting for download volumes to bind", func() {
+		const resourceName = "test-torrentserver-wait-storage"
+		const claimName = "test-torrentserver-wait-storage-downloads"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+		claimNamespacedName := types.NamespacedName{
+			Name:      claimName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      claimName,
+					Namespace: "default",
+				},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceStorage: resource.MustParse("100Gi"),
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pvc)).To(Succeed())
+			pvc.Status.Phase = corev1.ClaimPending
+			Expect(k8sClient.Status().Update(ctx, pvc)).To(Succeed())
+
+			wait := true
+			resource := &torrentv1alpha1.TorrentServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: torrentv1alpha1.TorrentServerSpec{
+					DownloadVolumes: []torrentv1alpha1.DownloadVolumeSpec{
+						{ClaimName: claimName, MountPath: "/downloads"},
+					},
+					WaitForDownloadVolumes: &wait,
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			resource := &torrentv1alpha1.TorrentServer{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+			for _, obj := range []client.Object{&appsv1.Deployment{}, &corev1.Service{}} {
+				if err := k8sClient.Get(ctx, typeNamespacedName, obj); err == nil {
+					Expect(k8sClient.Delete(ctx, obj)).To(Succeed())
+				}
+			}
+			pvc := &corev1.PersistentVolumeClaim{}
+			if err := k8sClient.Get(ctx, claimNamespacedName, pvc); err == nil {
+				Expect(k8sClient.Delete(ctx, pvc)).To(Succeed())
+			}
+		})
+
+		It("should hold the Deployment back until the download PVC is Bound", func() {
+			controllerReconciler := &TorrentServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(10 * time.Second))
+
+			err = k8sClient.Get(ctx, typeNamespacedName, &appsv1.Deployment{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			ts := &torrentv1alpha1.TorrentServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			available := meta.FindStatusCondition(ts.Status.Conditions, TypeAvailableTorrentServer)
+			Expect(available).NotTo(BeNil())
+			Expect(available.Status).To(Equal(metav1.ConditionFalse))
+			Expect(available.Reason).To(Equal("WaitingForStorage"))
+			Expect(available.Message).To(ContainSubstring(claimName + " (Pending)"))
+			Expect(meta.FindStatusCondition(ts.Status.Conditions, TypeDegradedTorrentServer)).To(BeNil())
+
+			By("binding the download PVC")
+			pvc := &corev1.PersistentVolumeClaim{}
+			Expect(k8sClient.Get(ctx, claimNamespacedName, pvc)).To(Succeed())
+			pvc.Status.Phase = corev1.ClaimBound
+			Expect(k8sClient.Status().Update(ctx, pvc)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, &appsv1.Deployment{})).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ts)).To(Succeed())
+			available = meta.FindStatusCondition(ts.Status.Conditions, TypeAvailableTorrentServer)
+			Expect(available).NotTo(BeNil())
+			Expect(available.Reason).NotTo(Equal("WaitingForStorage"))
+		})
+	})
+})
+
+// setDeploymentRollout records a Deployment status whose controller observed the latest spec
+func setDeploymentRollout(ctx context.Context, name types.NamespacedName, replicas, updated, ready int32) {
+	deployment := &appsv1.Deployment{}
+	Expect(k8sClient.Get(ctx, name, deployment)).To(Succeed())
+	deployment.Status.ObservedGeneration = deployment.Generation
+	deployment.Status.Replicas = replicas
+	deployment.Status.UpdatedReplicas = updated
+	deployment.Status.ReadyReplicas = ready
+	deployment.Status.AvailableReplicas = ready
+	Expect(k8sClient.Status().Update(ctx, deployment)).To(Succeed())
+}
+
+// writeCountingClient counts the Create, Update and Patch calls made on non-status resources
+type writeCountingClient struct {
+	client.Client
+	writes atomic.Int32
+}
+
+func (c *writeCountingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	c.writes.Add(1)
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *writeCountingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	c.writes.Add(1)
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *writeCountingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	c.writes.Add(1)
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+// Writes returns the number of writes since the last Reset
+func (c *writeCountingClient) Writes() int32 {
+	return c.writes.Load()
+}
+
+// Reset clears the write counter
+func (c *writeCountingClient) Reset() {
+	c.writes.Store(0)
+}
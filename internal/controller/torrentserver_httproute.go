@@ -0,0 +1,234 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	torrentv1alpha1 "github.com/guidonguido/qbittorrent-operator/api/v1alpha1"
+)
+
+// TypeGatewayAPIUnavailableTorrentServer warns that spec.httpRoute is set but the Gateway API CRDs are not installed
+const TypeGatewayAPIUnavailableTorrentServer = "GatewayAPIUnavailable"
+
+// The Gateway API types are handled as unstructured objects,
+// so that the operator runs on clusters without the Gateway API CRDs
+var (
+	httpRouteGVK = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"}
+	gatewayGVK   = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "Gateway"}
+)
+
+func newGatewayAPIObject(gvk schema.GroupVersionKind) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	return obj
+}
+
+// gatewayAPIInstalled reports whether the cluster serves the HTTPRoute kind
+func gatewayAPIInstalled(mapper meta.RESTMapper) (bool, error) {
+	_, err := mapper.RESTMapping(httpRouteGVK.GroupKind(), httpRouteGVK.Version)
+	if meta.IsNoMatchError(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// gatewayAPILookup is the result of the single Gateway API lookup of a reconcile,
+// shared by the HTTPRoute and the GatewayAPIUnavailable condition
+type gatewayAPILookup struct {
+	installed bool
+	err       error
+}
+
+// Look the Gateway API up only when spec.httpRoute is set,
+// so clusters without the Gateway API are never queried otherwise
+func (r *TorrentServerReconciler) lookupGatewayAPI(ts *torrentv1alpha1.TorrentServer) gatewayAPILookup {
+	if ts.Spec.HTTPRoute == nil {
+		return gatewayAPILookup{}
+	}
+	installed, err := gatewayAPIInstalled(r.RESTMapper())
+	return gatewayAPILookup{installed: installed, err: err}
+}
+
+// Create the "<name>" HTTPRoute when spec.httpRoute is set and the Gateway API is installed,
+// deleting it once unset. Returns the route name and the URL it exposes the WebUI at
+func (r *TorrentServerReconciler) ensureHTTPRoute(ctx context.Context, ts *torrentv1alpha1.TorrentServer, serviceName string, gatewayAPI gatewayAPILookup) (string, string, error) {
+	logger := log.FromContext(ctx)
+	routeName := ts.Name
+
+	if ts.Spec.HTTPRoute == nil {
+		// Only a route recorded in status may exist, so clusters without the Gateway API are never queried
+		if ts.Status.HTTPRouteName == "" {
+			return "", "", nil
+		}
+		existing := newGatewayAPIObject(httpRouteGVK)
+		if err := r.Get(ctx, types.NamespacedName{Name: ts.Status.HTTPRouteName, Namespace: ts.Namespace}, existing); err != nil {
+			if meta.IsNoMatchError(err) {
+				return "", "", nil
+			}
+			return "", "", client.IgnoreNotFound(err)
+		}
+		if !metav1.IsControlledBy(existing, ts) {
+			return "", "", nil
+		}
+		if err := r.Delete(ctx, existing); client.IgnoreNotFound(err) != nil {
+			return "", "", fmt.Errorf("failed to delete HTTPRoute: %w", err)
+		}
+		logger.Info("HTTPRoute deleted", "name", ts.Status.HTTPRouteName)
+		return "", "", nil
+	}
+
+	if gatewayAPI.err != nil {
+		return "", "", fmt.Errorf("failed to look up the Gateway API: %w", gatewayAPI.err)
+	}
+	if !gatewayAPI.installed {
+		// Reported through the GatewayAPIUnavailable condition
+		return "", "", nil
+	}
+	if serviceName == "" {
+		return "", "", fmt.Errorf("spec.httpRoute targets the managed Service, which spec.service.enabled disables")
+	}
+
+	route := newGatewayAPIObject(httpRouteGVK)
+	route.SetName(routeName)
+	route.SetNamespace(ts.Namespace)
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, route, func() error {
+		if err := controllerutil.SetControllerReference(ts, route, r.Scheme); err != nil {
+			return err
+		}
+		route.SetLabels(labelsForTorrentServer(ts.Name))
+		route.Object["spec"] = httpRouteSpecForTorrentServer(ts, serviceName)
+		return nil
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to ensure HTTPRoute: %w", err)
+	}
+	logger.V(1).Info("HTTPRoute ensured", "name", routeName, "result", result)
+
+	return routeName, r.httpRouteURL(ctx, ts), nil
+}
+
+// httpRouteSpecForTorrentServer builds the HTTPRoute spec routing spec.httpRoute.path to the WebUI Service
+func httpRouteSpecForTorrentServer(ts *torrentv1alpha1.TorrentServer, serviceName string) map[string]interface{} {
+	route := ts.Spec.HTTPRoute
+
+	port := ts.Spec.WebUIPort
+	if port == 0 {
+		port = 8080
+	}
+
+	parentRef := map[string]interface{}{"name": route.ParentRef.Name}
+	if route.ParentRef.Namespace != "" {
+		parentRef["namespace"] = route.ParentRef.Namespace
+	}
+	if route.ParentRef.SectionName != "" {
+		parentRef["sectionName"] = route.ParentRef.SectionName
+	}
+
+	spec := map[string]interface{}{
+		"parentRefs": []interface{}{parentRef},
+		"rules": []interface{}{
+			map[string]interface{}{
+				"matches": []interface{}{
+					map[string]interface{}{
+						"path": map[string]interface{}{"type": "PathPrefix", "value": httpRoutePath(route)},
+					},
+				},
+				"backendRefs": []interface{}{
+					map[string]interface{}{"name": serviceName, "port": int64(port)},
+				},
+			},
+		},
+	}
+	if route.Hostname != "" {
+		spec["hostnames"] = []interface{}{route.Hostname}
+	}
+	return spec
+}
+
+func httpRoutePath(route *torrentv1alpha1.HTTPRouteSpec) string {
+	if route.Path == "" {
+		return "/"
+	}
+	return route.Path
+}
+
+// httpRouteURL returns the URL the route exposes the WebUI at, taking the scheme and port from the
+// parent Gateway listener when it can be read. Routes without a concrete hostname have no URL
+func (r *TorrentServerReconciler) httpRouteURL(ctx context.Context, ts *torrentv1alpha1.TorrentServer) string {
+	route := ts.Spec.HTTPRoute
+	if route.Hostname == "" || strings.HasPrefix(route.Hostname, "*") {
+		return ""
+	}
+
+	protocol, port := "HTTP", int64(0)
+	namespace := route.ParentRef.Namespace
+	if namespace == "" {
+		namespace = ts.Namespace
+	}
+	gateway := newGatewayAPIObject(gatewayGVK)
+	if err := r.Get(ctx, types.NamespacedName{Name: route.ParentRef.Name, Namespace: namespace}, gateway); err == nil {
+		listeners, _, _ := unstructured.NestedSlice(gateway.Object, "spec", "listeners")
+		for _, item := range listeners {
+			listener, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if name, _, _ := unstructured.NestedString(listener, "name"); route.ParentRef.SectionName != "" && name != route.ParentRef.SectionName {
+				continue
+			}
+			protocol, _, _ = unstructured.NestedString(listener, "protocol")
+			port, _, _ = unstructured.NestedInt64(listener, "port")
+			break
+		}
+	}
+	return gatewayListenerURL(route, protocol, port)
+}
+
+// gatewayListenerURL formats the URL of the route hostname and path on a listener, omitting default ports
+func gatewayListenerURL(route *torrentv1alpha1.HTTPRouteSpec, protocol string, port int64) string {
+	scheme := "http"
+	if protocol == "HTTPS" {
+		scheme = "https"
+	}
+	host := route.Hostname
+	if port != 0 && !(scheme == "http" && port == 80) && !(scheme == "https" && port == 443) {
+		host = net.JoinHostPort(host, strconv.FormatInt(port, 10))
+	}
+	return scheme + "://" + host + httpRoutePath(route)
+}
+
+func (r *TorrentServerReconciler) setGatewayAPICondition(ts *torrentv1alpha1.TorrentServer, gatewayAPI gatewayAPILookup) {
+	if ts.Spec.HTTPRoute == nil {
+		meta.RemoveStatusCondition(&ts.Status.Conditions, TypeGatewayAPIUnavailableTorrentServer)
+		return
+	}
+	if gatewayAPI.err != nil {
+		return
+	}
+	if gatewayAPI.installed {
+		meta.RemoveStatusCondition(&ts.Status.Conditions, TypeGatewayAPIUnavailableTorrentServer)
+		return
+	}
+	condition := metav1.Condition{
+		Type:               TypeGatewayAPIUnavailableTorrentServer,
+		Status:             metav1.ConditionTrue,
+		Reason:             "GatewayAPINotInstalled",
+		Message:            "spec.httpRoute is set but the gateway.networking.k8s.io/v1 HTTPRoute CRD is not installed: no HTTPRoute is created",
+		ObservedGeneration: ts.Generation,
+		LastTransitionTime: metav1.NewTime(time.Now()),
+	}
+	meta.SetStatusCondition(&ts.Status.Conditions, condition)
+}
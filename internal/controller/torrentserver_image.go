@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"strings"
+
+	torrentv1alpha1 "github.com/guidonguido/qbittorrent-operator/api/v1alpha1"
+)
+
+// defaultQBittorrentImage is the latest tested qBittorrent image, used when neither spec.image
+// nor the operator DefaultImage is set
+const defaultQBittorrentImage = "lscr.io/linuxserver/qbittorrent:amd64-5.1.4"
+
+// resolveImage returns the qBittorrent image of the TorrentServer: spec.image with its registry rewritten
+// to ImageRegistryPrefix, else DefaultImage as given, else the rewritten built-in default
+func (r *TorrentServerReconciler) resolveImage(ts *torrentv1alpha1.TorrentServer) string {
+	switch {
+	case ts.Spec.Image != "":
+		return rewriteImageRegistry(ts.Spec.Image, r.ImageRegistryPrefix)
+	case r.DefaultImage != "":
+		return r.DefaultImage
+	default:
+		return rewriteImageRegistry(defaultQBittorrentImage, r.ImageRegistryPrefix)
+	}
+}
+
+// rewriteImageRegistry replaces the registry of image with prefix, e.g. "lscr.io/linuxserver/qbittorrent:5.1.4"
+// becomes "mirror.internal/cache/linuxserver/qbittorrent:5.1.4". As in Docker, the first path component is
+// a registry only when it contains "." or ":" or is "localhost"; other images are Docker Hub images.
+// Images already under prefix are returned unchanged, as is every image when prefix is empty.
+func rewriteImageRegistry(image, prefix string) string {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if prefix == "" || strings.HasPrefix(image, prefix+"/") {
+		return image
+	}
+
+	repository := image
+	first, rest, found := strings.Cut(image, "/")
+	switch {
+	case !found:
+		// Docker Hub official images live under library/
+		repository = "library/" + image
+	case strings.ContainsAny(first, ".:") || first == "localhost":
+		repository = rest
+	}
+	return prefix + "/" + repository
+}
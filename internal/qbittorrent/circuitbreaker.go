@@ -0,0 +1,126 @@
+package qbittorrent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Circuit breaker states
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half-open"
+)
+
+// CircuitBreaker short-circuits the requests towards a qBittorrent instance that keeps failing.
+// After threshold consecutive failures it opens, failing every request fast for the cool-down.
+// The first request after the cool-down is let through as a probe: its success closes the
+// breaker, its failure opens it again. Requests issued while the probe runs fail fast.
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	// now is replaced in tests to move through the cool-down
+	now func() time.Time
+
+	state     string
+	failures  int
+	openUntil time.Time
+}
+
+// NewCircuitBreaker builds a closed breaker opening after threshold consecutive failures
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+		state:     CircuitClosed,
+	}
+}
+
+// State returns the current state, reporting an open breaker past its cool-down as half-open
+func (b *CircuitBreaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && !b.now().Before(b.openUntil) {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+// Allow reports whether a request may be sent, turning an open breaker past its cool-down
+// half-open and letting the calling request through as the probe
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case CircuitOpen:
+		if b.now().Before(b.openUntil) {
+			return fmt.Errorf("%w until %s", ErrCircuitOpen, b.openUntil.Format(time.RFC3339))
+		}
+		b.state = CircuitHalfOpen
+		return nil
+	case CircuitHalfOpen:
+		return fmt.Errorf("%w while a probe request is in flight", ErrCircuitOpen)
+	}
+	return nil
+}
+
+// Success closes the breaker and resets the failure count
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = CircuitClosed
+	b.failures = 0
+}
+
+// Failure counts a failed request, opening the breaker at the threshold or after a failed probe
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.threshold {
+		if b.state != CircuitOpen {
+			circuitBreakerOpens.Inc()
+		}
+		b.state = CircuitOpen
+		b.openUntil = b.now().Add(b.cooldown)
+	}
+}
+
+// Cancel releases a probe that did not complete, without counting a success or a failure
+func (b *CircuitBreaker) Cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitHalfOpen {
+		b.state = CircuitOpen
+	}
+}
+
+// circuitBreakerTransport guards every outbound request with the breaker of the qBittorrent URL.
+// Transport errors and 5xx responses are failures: the instance or the proxy in front of it is down.
+type circuitBreakerTransport struct {
+	next    http.RoundTripper
+	breaker *CircuitBreaker
+}
+
+func (t *circuitBreakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.breaker.Allow(); err != nil {
+		return nil, err
+	}
+	resp, err := t.next.RoundTrip(req)
+	switch {
+	case err != nil && (errors.Is(err, context.Canceled) || errors.Is(err, ErrRateLimited)):
+		// Cancelled by the caller or never sent: nothing is known about the instance, let the next request probe it
+		t.breaker.Cancel()
+	case err != nil || resp.StatusCode >= http.StatusInternalServerError:
+		t.breaker.Failure()
+	default:
+		t.breaker.Success()
+	}
+	return resp, err
+}
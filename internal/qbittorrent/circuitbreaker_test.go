@@ -0,0 +1,195 @@
+package qbittorrent
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestBreaker returns a breaker whose clock only moves through the returned function
+func newTestBreaker(threshold int, cooldown time.Duration) (*CircuitBreaker, func(time.Duration)) {
+	breaker := NewCircuitBreaker(threshold, cooldown)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	breaker.now = func() time.Time { return now }
+	return breaker, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestCircuitBreaker_OpensAfterThreshold(t *testing.T) {
+	breaker, _ := newTestBreaker(3, time.Minute)
+
+	for range 2 {
+		if err := breaker.Allow(); err != nil {
+			t.Fatalf("expected closed breaker to allow requests, got %v", err)
+		}
+		breaker.Failure()
+	}
+	if got := breaker.State(); got != CircuitClosed {
+		t.Fatalf("expected breaker to stay closed below the threshold, got %s", got)
+	}
+
+	breaker.Failure()
+	if got := breaker.State(); got != CircuitOpen {
+		t.Fatalf("expected breaker to open at the threshold, got %s", got)
+	}
+	if err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen from an open breaker, got %v", err)
+	}
+}
+
+func TestCircuitBreaker_SuccessResetsFailures(t *testing.T) {
+	breaker, _ := newTestBreaker(2, time.Minute)
+
+	breaker.Failure()
+	breaker.Success()
+	breaker.Failure()
+	if got := breaker.State(); got != CircuitClosed {
+		t.Errorf("expected non-consecutive failures to keep the breaker closed, got %s", got)
+	}
+}
+
+func TestCircuitBreaker_HalfOpenProbe(t *testing.T) {
+	breaker, advance := newTestBreaker(1, time.Minute)
+	breaker.Failure()
+
+	advance(30 * time.Second)
+	if err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected requests to fail fast during the cool-down, got %v", err)
+	}
+
+	advance(30 * time.Second)
+	if got := breaker.State(); got != CircuitHalfOpen {
+		t.Fatalf("expected breaker to be half-open after the cool-down, got %s", got)
+	}
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("expected the probe request to be allowed, got %v", err)
+	}
+	if err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected concurrent requests to fail fast while probing, got %v", err)
+	}
+
+	t.Run("failed probe reopens", func(t *testing.T) {
+		breaker.Failure()
+		if got := breaker.State(); got != CircuitOpen {
+			t.Fatalf("expected failed probe to reopen the breaker, got %s", got)
+		}
+		if err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+			t.Errorf("expected a new cool-down after the failed probe, got %v", err)
+		}
+	})
+
+	t.Run("successful probe closes", func(t *testing.T) {
+		advance(time.Minute)
+		if err := breaker.Allow(); err != nil {
+			t.Fatalf("expected the probe request to be allowed, got %v", err)
+		}
+		breaker.Success()
+		if got := breaker.State(); got != CircuitClosed {
+			t.Fatalf("expected successful probe to close the breaker, got %s", got)
+		}
+		if err := breaker.Allow(); err != nil {
+			t.Errorf("expected closed breaker to allow requests, got %v", err)
+		}
+	})
+}
+
+func TestCircuitBreaker_CancelledProbe(t *testing.T) {
+	breaker, advance := newTestBreaker(1, time.Minute)
+	breaker.Failure()
+	advance(time.Minute)
+
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("expected the probe request to be allowed, got %v", err)
+	}
+	breaker.Cancel()
+	if err := breaker.Allow(); err != nil {
+		t.Errorf("expected a cancelled probe to let the next request probe, got %v", err)
+	}
+}
+
+func TestGetOrCreate_CircuitBreakerFailsFast(t *testing.T) {
+	var requests atomic.Int32
+	healthy := atomic.Bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "SID", Value: "sid"})
+		_, _ = w.Write([]byte("Ok."))
+	}))
+	t.Cleanup(server.Close)
+
+	pool := NewClientPoolWithOptions(ClientPoolOptions{
+		TTL:            5 * time.Minute,
+		CircuitBreaker: CircuitBreakerOptions{Threshold: 2, Cooldown: time.Minute},
+	})
+	breaker := pool.Breaker(server.URL)
+	now := time.Now()
+	breaker.now = func() time.Time { return now }
+
+	for range 2 {
+		if _, err := pool.GetOrCreate(context.Background(), server.URL, "admin", "pass"); err == nil {
+			t.Fatal("expected login to fail against a failing server")
+		}
+	}
+	_, err := pool.GetOrCreate(context.Background(), server.URL, "admin", "pass")
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen once the breaker opened, got %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("expected the open breaker to skip the server, got %d requests", got)
+	}
+
+	healthy.Store(true)
+	now = now.Add(time.Minute)
+	if _, err := pool.GetOrCreate(context.Background(), server.URL, "admin", "pass"); err != nil {
+		t.Fatalf("expected the probe login to succeed, got %v", err)
+	}
+	if got := breaker.State(); got != CircuitClosed {
+		t.Errorf("expected the successful probe to close the breaker, got %s", got)
+	}
+}
+
+func TestNewClientPoolWithOptions_CircuitBreakerDisabled(t *testing.T) {
+	pool := NewClientPoolWithOptions(ClientPoolOptions{TTL: time.Minute})
+	if breaker := pool.Breaker("http://qbittorrent:8080"); breaker != nil {
+		t.Errorf("expected no circuit breaker without a threshold, got %+v", breaker)
+	}
+}
+
+func TestCleanup_PrunesBreakersOfUnusedURLs(t *testing.T) {
+	pool := NewClientPoolWithOptions(ClientPoolOptions{
+		TTL:            time.Second,
+		CircuitBreaker: CircuitBreakerOptions{Threshold: 1, Cooldown: time.Minute},
+	})
+	pool.clients["live"] = &poolEntry{client: &Client{}, url: "http://live:8080", lastUsed: time.Now()}
+	pool.clients["gone"] = &poolEntry{client: &Client{}, url: "http://gone:8080", lastUsed: time.Now().Add(-time.Minute)}
+	live := pool.Breaker("http://live:8080")
+	pool.Breaker("http://gone:8080")
+	failing := pool.Breaker("http://failing:8080")
+	now := time.Now()
+	failing.now = func() time.Time { return now }
+	failing.Failure()
+
+	pool.Cleanup()
+	if pool.Breaker("http://live:8080") != live {
+		t.Error("expected the breaker of a cached URL to be kept")
+	}
+	if _, ok := pool.breakers["http://gone:8080"]; ok {
+		t.Error("expected the breaker of an expired URL to be pruned")
+	}
+	if _, ok := pool.breakers["http://failing:8080"]; !ok {
+		t.Fatal("expected an open breaker to be kept during its cool-down")
+	}
+
+	now = now.Add(time.Minute)
+	pool.Remove("live")
+	if len(pool.breakers) != 0 {
+		t.Errorf("expected every breaker to be pruned, got %v", pool.breakers)
+	}
+}
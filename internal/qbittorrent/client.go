@@ -5,12 +5,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"mime/multipart"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/go-logr/logr"
+	"golang.org/x/time/rate"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -19,20 +24,134 @@ type Client struct {
 	baseURL    string
 	httpClient *http.Client
 	sessionID  string // SID obtained from login
+	// bearerToken replaces the session cookie when set, see SetBearerToken
+	bearerToken string
+
+	mu         sync.RWMutex
+	apiVersion string // WebUI API version detected by GetAPIVersion
+}
+
+// AddTorrentOptions tunes how /api/v2/torrents/add adds a torrent
+type AddTorrentOptions struct {
+	// SkipChecking skips the hash recheck of data already on disk
+	SkipChecking bool
+	// ContentLayout is one of Original, Subfolder or NoSubfolder.
+	// Empty leaves the choice to the qBittorrent torrent_content_layout preference.
+	ContentLayout string
+	// Paused adds the torrent stopped. A stopped magnet link does not fetch its metadata.
+	Paused bool
+	// StopCondition stops the torrent once it is reached, e.g. StopConditionMetadataReceived.
+	// Empty leaves the torrent running.
+	StopCondition string
+	// Category assigns the torrent to a category. Empty adds it uncategorized.
+	Category string
+	// AddToTopOfQueue adds the torrent at the top of the queue instead of the bottom.
+	// Ignored by qBittorrent while queueing is disabled.
+	AddToTopOfQueue bool
 }
 
+// StopConditionMetadataReceived stops a torrent added from a magnet link as soon as
+// its metadata is received, before any piece is downloaded
+const StopConditionMetadataReceived = "MetadataReceived"
+
+// File download priorities accepted by /api/v2/torrents/filePrio
+const (
+	FilePriorityDoNotDownload = 0
+	FilePriorityNormal        = 1
+	FilePriorityHigh          = 6
+	FilePriorityMaximum       = 7
+)
+
 // DTO returned by qBittorrent /api/v2/torrents/info API
 type TorrentInfo struct {
-	AddedOn     int64  `json:"added_on"`
-	AmountLeft  int64  `json:"amount_left"`
-	ContentPath string `json:"content_path"`
-	Hash        string `json:"hash"`
-	MagnetURI   string `json:"magnet_uri"`
-	Name        string `json:"name"`
-	Size        int64  `json:"size"`
-	State       string `json:"state"`
-	TotalSize   int64  `json:"total_size"`
-	TimeActive  int64  `json:"time_active"`
+	AddedOn     int64   `json:"added_on"`
+	AmountLeft  int64   `json:"amount_left"`
+	Category    string  `json:"category"`
+	ContentPath string  `json:"content_path"`
+	DlSpeed     int64   `json:"dlspeed"`
+	Hash        string  `json:"hash"`
+	MagnetURI   string  `json:"magnet_uri"`
+	Name        string  `json:"name"`
+	Priority    int     `json:"priority"` // Queue position, <= 0 when queueing is disabled or the torrent seeds
+	Progress    float64 `json:"progress"`
+	Ratio       float64 `json:"ratio"`
+	SavePath    string  `json:"save_path"`
+	Size        int64   `json:"size"`
+	State       string  `json:"state"`
+	TotalSize   int64   `json:"total_size"`
+	TimeActive  int64   `json:"time_active"`
+}
+
+// DTO returned by qBittorrent /api/v2/torrents/files API
+type TorrentFile struct {
+	Index    int     `json:"index"`
+	Name     string  `json:"name"`
+	Size     int64   `json:"size"`
+	Progress float64 `json:"progress"`
+	Priority int     `json:"priority"`
+}
+
+// DTO returned by qBittorrent /api/v2/torrents/properties API
+type TorrentProperties struct {
+	// Availability is the number of distributed copies of the torrent in the swarm
+	Availability    float64 `json:"availability"`
+	Peers           int     `json:"peers"`
+	PeersTotal      int     `json:"peers_total"`
+	Seeds           int     `json:"seeds"`
+	SeedsTotal      int     `json:"seeds_total"`
+	TotalDownloaded int64   `json:"total_downloaded"`
+	TotalUploaded   int64   `json:"total_uploaded"`
+	PiecesHave      int     `json:"pieces_have"`
+	PiecesNum       int     `json:"pieces_num"`
+	// ConnectionsLimit is the effective peer connection limit, -1 when unlimited
+	ConnectionsLimit int `json:"nb_connections_limit"`
+	// Comment and CreatedBy are embedded in the torrent metadata, empty until it is retrieved
+	Comment   string `json:"comment"`
+	CreatedBy string `json:"created_by"`
+}
+
+// DTO returned by qBittorrent /api/v2/torrents/categories API
+type Category struct {
+	Name string `json:"name"`
+	// SavePath is where the torrents of the category are saved, empty for the default save path
+	SavePath string `json:"savePath"`
+}
+
+// DTO returned by qBittorrent /api/v2/sync/maindata API, limited to the fields the operator reads
+type MainData struct {
+	ServerState ServerState `json:"server_state"`
+}
+
+// Global transfer and disk state reported in the server_state field of /api/v2/sync/maindata
+type ServerState struct {
+	// FreeSpaceOnDisk is the free space in bytes on the volume of the default save path
+	FreeSpaceOnDisk int64 `json:"free_space_on_disk"`
+}
+
+// Log entry types reported by /api/v2/log/main
+const (
+	LogTypeNormal   = 1
+	LogTypeInfo     = 2
+	LogTypeWarning  = 4
+	LogTypeCritical = 8
+)
+
+// DTO returned by qBittorrent /api/v2/log/main API
+type LogEntry struct {
+	ID        int    `json:"id"`
+	Message   string `json:"message"`
+	Timestamp int64  `json:"timestamp"`
+	Type      int    `json:"type"`
+}
+
+// LogOptions selects the entries returned by GetLog
+type LogOptions struct {
+	Normal   bool
+	Info     bool
+	Warning  bool
+	Critical bool
+	// LastKnownID skips the entries up to this ID, -1 returns every entry
+	LastKnownID int
 }
 
 func NewClient(baseURL string) *Client {
@@ -43,11 +162,41 @@ func NewClientWithTimeout(baseURL string, timeout time.Duration) *Client {
 	return &Client{
 		baseURL: strings.TrimSuffix(baseURL, "/"),
 		httpClient: &http.Client{
-			Timeout: timeout,
+			Timeout:   timeout,
+			Transport: &userAgentTransport{next: http.DefaultTransport, userAgent: DefaultUserAgent()},
 		},
 	}
 }
 
+// SetUserAgent overrides the User-Agent header sent with every request. It wraps the current
+// transport, so the rate limiter, breaker and trace logger already set are kept
+func (c *Client) SetUserAgent(userAgent string) {
+	c.httpClient.Transport = &userAgentTransport{next: c.httpClient.Transport, userAgent: userAgent}
+}
+
+// SetRateLimiter throttles every request with the limiter, shared by the clients of the same URL
+func (c *Client) SetRateLimiter(limiter *rate.Limiter) {
+	c.httpClient.Transport = &rateLimitTransport{next: c.httpClient.Transport, limiter: limiter}
+}
+
+// SetCircuitBreaker guards every request with the breaker, shared by the clients of the same URL.
+// Call it after SetUserAgent and SetRateLimiter, so that an open breaker fails fast without waiting
+func (c *Client) SetCircuitBreaker(breaker *CircuitBreaker) {
+	c.httpClient.Transport = &circuitBreakerTransport{next: c.httpClient.Transport, breaker: breaker}
+}
+
+// SetTraceLogger logs every request, its status and duration to logger, redacting credentials and cookies.
+// Call it last, so that the trace also covers the requests failed fast by the breaker or the rate limiter
+func (c *Client) SetTraceLogger(logger logr.Logger) {
+	c.httpClient.Transport = &traceTransport{next: c.httpClient.Transport, logger: logger}
+}
+
+// SetBearerToken authenticates every request with an "Authorization: Bearer" header instead of
+// the session cookie, e.g. for qBittorrent behind an OIDC proxy. Login is then not needed
+func (c *Client) SetBearerToken(token string) {
+	c.bearerToken = token
+}
+
 func (c *Client) Ping(ctx context.Context) error {
 	_, err := c.GetTorrentsInfo(ctx)
 	return err
@@ -120,11 +269,8 @@ func (c *Client) GetTorrentsInfo(ctx context.Context) ([]TorrentInfo, error) {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// The request must contain the session ID cookie for authentication
-	req.AddCookie(&http.Cookie{
-		Name:  "SID",
-		Value: c.sessionID,
-	})
+	// The request must contain the session ID cookie or the bearer token for authentication
+	c.authorize(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -165,6 +311,13 @@ func (c *Client) GetTorrentsInfo(ctx context.Context) ([]TorrentInfo, error) {
 	return torrentsInfo, nil
 }
 
+// GetTorrentInfo returns the torrent identified by hash.
+// The contract distinguishes absence from failures:
+//   - (info, nil) when the torrent exists in qBittorrent
+//   - (nil, nil) when qBittorrent answered but the torrent is not present
+//   - (nil, err) when the API call failed (transport, auth, decoding errors)
+//
+// Callers must not treat a nil error as "torrent present" without checking info.
 func (c *Client) GetTorrentInfo(ctx context.Context, hash string) (*TorrentInfo, error) {
 	logger := log.FromContext(ctx).WithName("qbittorrent-client")
 
@@ -184,32 +337,48 @@ func (c *Client) GetTorrentInfo(ctx context.Context, hash string) (*TorrentInfo,
 	return nil, nil
 }
 
-func (c *Client) AddTorrent(ctx context.Context, magnetURI string) error {
+func (c *Client) AddTorrent(ctx context.Context, magnetURI string, opts AddTorrentOptions) error {
 	logger := log.FromContext(ctx).WithName("qbittorrent-client")
 	torrentsAddURL := c.baseURL + "/api/v2/torrents/add"
 
 	logger.Info("Adding torrent to qbittorrent",
 		"URL", torrentsAddURL,
 		"magnetURI", magnetURI,
+		"options", opts,
 	)
 
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
 
-	if err := writer.WriteField("urls", magnetURI); err != nil {
-		logger.Error(err, "Failed to write form field")
-		return fmt.Errorf("failed to write form field: %w", err)
+	fields := [][2]string{{"urls", magnetURI}}
+	if opts.SkipChecking {
+		fields = append(fields, [2]string{"skip_checking", "true"})
 	}
-
-	urlFields, err := writer.CreateFormField("urls")
-	if err != nil {
-		logger.Error(err, "Failed to create form field")
-		return fmt.Errorf("failed to create form field: %w", err)
+	if opts.ContentLayout != "" {
+		fields = append(fields, [2]string{"contentLayout", opts.ContentLayout})
 	}
-
-	if _, err := urlFields.Write([]byte(magnetURI)); err != nil {
-		logger.Error(err, "Failed to write form field")
-		return fmt.Errorf("failed to write form field: %w", err)
+	if opts.Paused {
+		// qBittorrent 5 (API 2.11.0) renamed the paused field to stopped
+		field := "paused"
+		if c.Capabilities().StopStart {
+			field = "stopped"
+		}
+		fields = append(fields, [2]string{field, "true"})
+	}
+	if opts.StopCondition != "" {
+		fields = append(fields, [2]string{"stopCondition", opts.StopCondition})
+	}
+	if opts.Category != "" {
+		fields = append(fields, [2]string{"category", opts.Category})
+	}
+	if opts.AddToTopOfQueue {
+		fields = append(fields, [2]string{"addToTopOfQueue", "true"})
+	}
+	for _, field := range fields {
+		if err := writer.WriteField(field[0], field[1]); err != nil {
+			logger.Error(err, "Failed to write form field", "field", field[0])
+			return fmt.Errorf("failed to write form field %s: %w", field[0], err)
+		}
 	}
 
 	if err := writer.Close(); err != nil {
@@ -217,42 +386,23 @@ func (c *Client) AddTorrent(ctx context.Context, magnetURI string) error {
 		return fmt.Errorf("failed to close writer: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", torrentsAddURL, body)
+	req, err := http.NewRequestWithContext(ctx, "POST", torrentsAddURL, body)
 	if err != nil {
 		logger.Error(err, "Failed to create request")
 		return fmt.Errorf("failed to create request: %w", err)
 	}
-
 	req.Header.Set("Content-Type", writer.FormDataContentType())
-	req.AddCookie(&http.Cookie{
-		Name:  "SID",
-		Value: c.sessionID,
-	})
 
-	resp, err := c.httpClient.Do(req)
+	respBody, err := c.do(ctx, req)
 	if err != nil {
 		logger.Error(err, "Failed to add torrent")
 		return fmt.Errorf("failed to add torrent: %w", err)
 	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			logger.Error(err, "Failed to close response body")
-		}
-	}()
 
-	if resp.StatusCode != http.StatusOK {
-		logger.Error(nil, "Failed to add torrent",
-			"status", resp.StatusCode)
-
-		if resp.StatusCode == http.StatusUnauthorized {
-			if resp.StatusCode == http.StatusUnauthorized {
-				logger.Error(nil, "Unauthorized access to qbittorrent",
-					"status", resp.StatusCode)
-				return fmt.Errorf("unauthorized access to qbittorrent")
-			}
-
-			return fmt.Errorf("failed to add torrent. Status: %s", resp.Status)
-		}
+	// qBittorrent answers 200 "Fails." when none of the provided URLs could be added
+	if strings.TrimSpace(string(respBody)) == "Fails." {
+		logger.Error(ErrTorrentRejected, "Failed to add torrent")
+		return fmt.Errorf("failed to add torrent: %w", ErrTorrentRejected)
 	}
 
 	logger.Info("Successfully added torrent",
@@ -283,10 +433,7 @@ func (c *Client) DeleteTorrent(ctx context.Context, hash string, deleteFiles boo
 	}
 
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(&http.Cookie{
-		Name:  "SID",
-		Value: c.sessionID,
-	})
+	c.authorize(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -317,3 +464,613 @@ func (c *Client) DeleteTorrent(ctx context.Context, hash string, deleteFiles boo
 	)
 	return nil
 }
+
+// Rename a torrent, changing the name displayed by qBittorrent
+func (c *Client) RenameTorrent(ctx context.Context, hash, name string) error {
+	logger := log.FromContext(ctx).WithName("qbittorrent-client")
+
+	logger.Info("Renaming torrent",
+		"hash", hash,
+		"name", name,
+	)
+
+	data := url.Values{}
+	data.Set("hash", hash)
+	data.Set("name", name)
+
+	if _, err := c.postForm(ctx, "/api/v2/torrents/rename", data); err != nil {
+		logger.Error(err, "Failed to rename torrent")
+		return fmt.Errorf("failed to rename torrent: %w", err)
+	}
+
+	logger.Info("Successfully renamed torrent",
+		"hash", hash,
+	)
+	return nil
+}
+
+// Move the torrents with the given hashes to category, which must exist on the instance
+func (c *Client) SetTorrentCategory(ctx context.Context, hashes []string, category string) error {
+	logger := log.FromContext(ctx).WithName("qbittorrent-client")
+
+	logger.Info("Setting torrent category",
+		"hashes", hashes,
+		"category", category,
+	)
+
+	data := url.Values{}
+	data.Set("hashes", strings.Join(hashes, "|"))
+	data.Set("category", category)
+
+	if _, err := c.postForm(ctx, "/api/v2/torrents/setCategory", data); err != nil {
+		logger.Error(err, "Failed to set torrent category")
+		return fmt.Errorf("failed to set torrent category: %w", err)
+	}
+	return nil
+}
+
+// Move the files of the torrents with the given hashes to location. qBittorrent moves them in the background,
+// reporting the torrents in the moving state meanwhile
+func (c *Client) SetTorrentLocation(ctx context.Context, hashes []string, location string) error {
+	logger := log.FromContext(ctx).WithName("qbittorrent-client")
+
+	logger.Info("Setting torrent location",
+		"hashes", hashes,
+		"location", location,
+	)
+
+	data := url.Values{}
+	data.Set("hashes", strings.Join(hashes, "|"))
+	data.Set("location", location)
+
+	if _, err := c.postForm(ctx, "/api/v2/torrents/setLocation", data); err != nil {
+		logger.Error(err, "Failed to set torrent location")
+		return fmt.Errorf("failed to set torrent location: %w", err)
+	}
+	return nil
+}
+
+// AllHashes selects every torrent in bulk operations such as PauseTorrents
+const AllHashes = "all"
+
+// Pause (stop) the torrents with the given hashes, or every torrent when hashes is [AllHashes]
+func (c *Client) PauseTorrents(ctx context.Context, hashes []string) error {
+	logger := log.FromContext(ctx).WithName("qbittorrent-client")
+
+	// qBittorrent 5 (API 2.11.0) renamed torrents/pause to torrents/stop
+	endpoint := "/api/v2/torrents/pause"
+	if c.Capabilities().StopStart {
+		endpoint = "/api/v2/torrents/stop"
+	}
+
+	logger.Info("Pausing torrents",
+		"hashes", hashes,
+	)
+
+	data := url.Values{}
+	data.Set("hashes", strings.Join(hashes, "|"))
+
+	if _, err := c.postForm(ctx, endpoint, data); err != nil {
+		logger.Error(err, "Failed to pause torrents")
+		return fmt.Errorf("failed to pause torrents: %w", err)
+	}
+	return nil
+}
+
+// Move the torrents with the given hashes to the top of the queue
+func (c *Client) TopPriority(ctx context.Context, hashes []string) error {
+	return c.moveInQueue(ctx, "/api/v2/torrents/topPrio", "top", hashes)
+}
+
+// Move the torrents with the given hashes to the bottom of the queue
+func (c *Client) BottomPriority(ctx context.Context, hashes []string) error {
+	return c.moveInQueue(ctx, "/api/v2/torrents/bottomPrio", "bottom", hashes)
+}
+
+// Move the torrents with the given hashes one position up in the queue
+func (c *Client) IncreasePriority(ctx context.Context, hashes []string) error {
+	return c.moveInQueue(ctx, "/api/v2/torrents/increasePrio", "up", hashes)
+}
+
+// Move the torrents with the given hashes one position down in the queue
+func (c *Client) DecreasePriority(ctx context.Context, hashes []string) error {
+	return c.moveInQueue(ctx, "/api/v2/torrents/decreasePrio", "down", hashes)
+}
+
+// qBittorrent answers 409 on the queue endpoints when torrent queueing is disabled
+func (c *Client) moveInQueue(ctx context.Context, endpoint, direction string, hashes []string) error {
+	logger := log.FromContext(ctx).WithName("qbittorrent-client")
+
+	logger.Info("Moving torrents in the queue",
+		"direction", direction,
+		"hashes", hashes,
+	)
+
+	data := url.Values{}
+	data.Set("hashes", strings.Join(hashes, "|"))
+
+	if _, err := c.postForm(ctx, endpoint, data); err != nil {
+		logger.Error(err, "Failed to move torrents in the queue", "direction", direction)
+		return fmt.Errorf("failed to move torrents %s in the queue: %w", direction, err)
+	}
+	return nil
+}
+
+// Resume (start) the torrents with the given hashes, or every torrent when hashes is [AllHashes]
+func (c *Client) ResumeTorrents(ctx context.Context, hashes []string) error {
+	logger := log.FromContext(ctx).WithName("qbittorrent-client")
+
+	// qBittorrent 5 (API 2.11.0) renamed torrents/resume to torrents/start
+	endpoint := "/api/v2/torrents/resume"
+	if c.Capabilities().StopStart {
+		endpoint = "/api/v2/torrents/start"
+	}
+
+	logger.Info("Resuming torrents",
+		"hashes", hashes,
+	)
+
+	data := url.Values{}
+	data.Set("hashes", strings.Join(hashes, "|"))
+
+	if _, err := c.postForm(ctx, endpoint, data); err != nil {
+		logger.Error(err, "Failed to resume torrents")
+		return fmt.Errorf("failed to resume torrents: %w", err)
+	}
+	return nil
+}
+
+// Pause (stop) every torrent on the instance
+func (c *Client) PauseAll(ctx context.Context) error {
+	return c.PauseTorrents(ctx, []string{AllHashes})
+}
+
+// Pause every torrent in the given category. It is a no-op when the category is empty
+func (c *Client) PauseCategory(ctx context.Context, category string) error {
+	hashes, err := c.getCategoryHashes(ctx, category)
+	if err != nil || len(hashes) == 0 {
+		return err
+	}
+	return c.PauseTorrents(ctx, hashes)
+}
+
+// Resume every torrent in the given category. It is a no-op when the category is empty
+func (c *Client) ResumeCategory(ctx context.Context, category string) error {
+	hashes, err := c.getCategoryHashes(ctx, category)
+	if err != nil || len(hashes) == 0 {
+		return err
+	}
+	return c.ResumeTorrents(ctx, hashes)
+}
+
+// List the hashes of the torrents in a category, the pause/resume endpoints do not filter by category
+func (c *Client) getCategoryHashes(ctx context.Context, category string) ([]string, error) {
+	logger := log.FromContext(ctx).WithName("qbittorrent-client")
+
+	query := url.Values{}
+	query.Set("category", category)
+
+	body, err := c.get(ctx, "/api/v2/torrents/info", query)
+	if err != nil {
+		logger.Error(err, "Failed to list torrents by category", "category", category)
+		return nil, fmt.Errorf("failed to list torrents in category %q: %w", category, err)
+	}
+
+	var torrentsInfo []TorrentInfo
+	if err := json.Unmarshal(body, &torrentsInfo); err != nil {
+		logger.Error(err, "Failed to parse torrents info list")
+		return nil, fmt.Errorf("failed to parse torrents info list: %w", err)
+	}
+
+	hashes := make([]string, 0, len(torrentsInfo))
+	for _, info := range torrentsInfo {
+		hashes = append(hashes, info.Hash)
+	}
+	return hashes, nil
+}
+
+// List the categories, keyed by category name
+func (c *Client) GetCategories(ctx context.Context) (map[string]Category, error) {
+	logger := log.FromContext(ctx).WithName("qbittorrent-client")
+
+	body, err := c.get(ctx, "/api/v2/torrents/categories", nil)
+	if err != nil {
+		logger.Error(err, "Failed to get categories")
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+
+	var categories map[string]Category
+	if err := json.Unmarshal(body, &categories); err != nil {
+		logger.Error(err, "Failed to parse categories")
+		return nil, fmt.Errorf("failed to parse categories: %w", err)
+	}
+
+	return categories, nil
+}
+
+// Create a category. qBittorrent rejects the request with 409 if the category already exists
+func (c *Client) CreateCategory(ctx context.Context, name, savePath string) error {
+	logger := log.FromContext(ctx).WithName("qbittorrent-client")
+
+	logger.Info("Creating category",
+		"category", name,
+		"savePath", savePath,
+	)
+
+	data := url.Values{}
+	data.Set("category", name)
+	data.Set("savePath", savePath)
+
+	if _, err := c.postForm(ctx, "/api/v2/torrents/createCategory", data); err != nil {
+		logger.Error(err, "Failed to create category")
+		return fmt.Errorf("failed to create category %q: %w", name, err)
+	}
+	return nil
+}
+
+// Set the save path of an existing category, an empty path restores the default save path
+func (c *Client) EditCategory(ctx context.Context, name, savePath string) error {
+	logger := log.FromContext(ctx).WithName("qbittorrent-client")
+
+	logger.Info("Editing category",
+		"category", name,
+		"savePath", savePath,
+	)
+
+	data := url.Values{}
+	data.Set("category", name)
+	data.Set("savePath", savePath)
+
+	if _, err := c.postForm(ctx, "/api/v2/torrents/editCategory", data); err != nil {
+		logger.Error(err, "Failed to edit category")
+		return fmt.Errorf("failed to edit category %q: %w", name, err)
+	}
+	return nil
+}
+
+// List the files of a torrent. The list is empty until metadata is received
+func (c *Client) GetTorrentFiles(ctx context.Context, hash string) ([]TorrentFile, error) {
+	logger := log.FromContext(ctx).WithName("qbittorrent-client")
+
+	logger.V(1).Info("Getting torrent files",
+		"hash", hash,
+	)
+
+	query := url.Values{}
+	query.Set("hash", hash)
+
+	body, err := c.get(ctx, "/api/v2/torrents/files", query)
+	if err != nil {
+		logger.Error(err, "Failed to get torrent files")
+		return nil, fmt.Errorf("failed to get torrent files: %w", err)
+	}
+
+	var files []TorrentFile
+	if err := json.Unmarshal(body, &files); err != nil {
+		logger.Error(err, "Failed to parse torrent files")
+		return nil, fmt.Errorf("failed to parse torrent files: %w", err)
+	}
+
+	return files, nil
+}
+
+// Get the generic properties of a torrent, such as swarm availability and cumulative transfer totals
+func (c *Client) GetTorrentProperties(ctx context.Context, hash string) (*TorrentProperties, error) {
+	logger := log.FromContext(ctx).WithName("qbittorrent-client")
+
+	logger.V(1).Info("Getting torrent properties",
+		"hash", hash,
+	)
+
+	query := url.Values{}
+	query.Set("hash", hash)
+
+	body, err := c.get(ctx, "/api/v2/torrents/properties", query)
+	if err != nil {
+		logger.Error(err, "Failed to get torrent properties")
+		return nil, fmt.Errorf("failed to get torrent properties: %w", err)
+	}
+
+	var properties TorrentProperties
+	if err := json.Unmarshal(body, &properties); err != nil {
+		logger.Error(err, "Failed to parse torrent properties")
+		return nil, fmt.Errorf("failed to parse torrent properties: %w", err)
+	}
+
+	return &properties, nil
+}
+
+// Get the entries of the qBittorrent main log matching the given types, oldest first
+func (c *Client) GetLog(ctx context.Context, opts LogOptions) ([]LogEntry, error) {
+	logger := log.FromContext(ctx).WithName("qbittorrent-client")
+
+	logger.V(1).Info("Getting qbittorrent main log",
+		"options", opts,
+	)
+
+	// qBittorrent enables every type left out of the query, so all of them are sent explicitly
+	query := url.Values{}
+	query.Set("normal", strconv.FormatBool(opts.Normal))
+	query.Set("info", strconv.FormatBool(opts.Info))
+	query.Set("warning", strconv.FormatBool(opts.Warning))
+	query.Set("critical", strconv.FormatBool(opts.Critical))
+	query.Set("last_known_id", strconv.Itoa(opts.LastKnownID))
+
+	body, err := c.get(ctx, "/api/v2/log/main", query)
+	if err != nil {
+		logger.Error(err, "Failed to get qbittorrent log")
+		return nil, fmt.Errorf("failed to get qbittorrent log: %w", err)
+	}
+
+	var entries []LogEntry
+	if err := json.Unmarshal(body, &entries); err != nil {
+		logger.Error(err, "Failed to parse qbittorrent log")
+		return nil, fmt.Errorf("failed to parse qbittorrent log: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Set the download priority of the files of a torrent, identified by their index in the file list
+func (c *Client) SetFilePriority(ctx context.Context, hash string, indexes []int, priority int) error {
+	logger := log.FromContext(ctx).WithName("qbittorrent-client")
+
+	logger.Info("Setting torrent file priority",
+		"hash", hash,
+		"indexes", indexes,
+		"priority", priority,
+	)
+
+	ids := make([]string, len(indexes))
+	for i, index := range indexes {
+		ids[i] = strconv.Itoa(index)
+	}
+
+	data := url.Values{}
+	data.Set("hash", hash)
+	data.Set("id", strings.Join(ids, "|"))
+	data.Set("priority", strconv.Itoa(priority))
+
+	if _, err := c.postForm(ctx, "/api/v2/torrents/filePrio", data); err != nil {
+		logger.Error(err, "Failed to set torrent file priority")
+		return fmt.Errorf("failed to set torrent file priority: %w", err)
+	}
+
+	return nil
+}
+
+// Rename a file inside a torrent. Paths are relative to the torrent root
+func (c *Client) RenameFile(ctx context.Context, hash, oldPath, newPath string) error {
+	logger := log.FromContext(ctx).WithName("qbittorrent-client")
+
+	logger.Info("Renaming torrent file",
+		"hash", hash,
+		"oldPath", oldPath,
+		"newPath", newPath,
+	)
+
+	if !c.Capabilities().RenameFile {
+		logger.Error(ErrUnsupportedFeature, "Failed to rename torrent file", "apiVersion", c.APIVersion())
+		return fmt.Errorf("failed to rename torrent file: %w", ErrUnsupportedFeature)
+	}
+
+	data := url.Values{}
+	data.Set("hash", hash)
+	data.Set("oldPath", oldPath)
+	data.Set("newPath", newPath)
+
+	if _, err := c.postForm(ctx, "/api/v2/torrents/renameFile", data); err != nil {
+		logger.Error(err, "Failed to rename torrent file")
+		return fmt.Errorf("failed to rename torrent file: %w", err)
+	}
+
+	return nil
+}
+
+// Export the .torrent file of a torrent. qBittorrent rejects the request until metadata is received
+func (c *Client) ExportTorrent(ctx context.Context, hash string) ([]byte, error) {
+	logger := log.FromContext(ctx).WithName("qbittorrent-client")
+
+	logger.V(1).Info("Exporting torrent file",
+		"hash", hash,
+	)
+
+	if !c.Capabilities().Export {
+		logger.Error(ErrUnsupportedFeature, "Failed to export torrent file", "apiVersion", c.APIVersion())
+		return nil, fmt.Errorf("failed to export torrent file: %w", ErrUnsupportedFeature)
+	}
+
+	query := url.Values{}
+	query.Set("hash", hash)
+
+	body, err := c.get(ctx, "/api/v2/torrents/export", query)
+	if err != nil {
+		logger.Error(err, "Failed to export torrent file")
+		return nil, fmt.Errorf("failed to export torrent file: %w", err)
+	}
+
+	return body, nil
+}
+
+// Get a full snapshot of the sync main data. Only the server state is decoded
+func (c *Client) GetMainData(ctx context.Context) (*MainData, error) {
+	logger := log.FromContext(ctx).WithName("qbittorrent-client")
+
+	// rid=0 requests a full snapshot instead of the changes since a previous response
+	query := url.Values{}
+	query.Set("rid", "0")
+
+	body, err := c.get(ctx, "/api/v2/sync/maindata", query)
+	if err != nil {
+		logger.Error(err, "Failed to get qbittorrent main data")
+		return nil, fmt.Errorf("failed to get qbittorrent main data: %w", err)
+	}
+
+	var mainData MainData
+	if err := json.Unmarshal(body, &mainData); err != nil {
+		logger.Error(err, "Failed to parse qbittorrent main data")
+		return nil, fmt.Errorf("failed to parse qbittorrent main data: %w", err)
+	}
+
+	return &mainData, nil
+}
+
+// Get the application preferences, keyed by qBittorrent preference name (e.g. "save_path")
+func (c *Client) GetPreferences(ctx context.Context) (map[string]any, error) {
+	logger := log.FromContext(ctx).WithName("qbittorrent-client")
+
+	body, err := c.get(ctx, "/api/v2/app/preferences", nil)
+	if err != nil {
+		logger.Error(err, "Failed to get qbittorrent preferences")
+		return nil, fmt.Errorf("failed to get qbittorrent preferences: %w", err)
+	}
+
+	var preferences map[string]any
+	if err := json.Unmarshal(body, &preferences); err != nil {
+		logger.Error(err, "Failed to parse qbittorrent preferences")
+		return nil, fmt.Errorf("failed to parse qbittorrent preferences: %w", err)
+	}
+
+	return preferences, nil
+}
+
+// Set the given application preferences, leaving the other preferences untouched
+func (c *Client) SetPreferences(ctx context.Context, preferences map[string]any) error {
+	logger := log.FromContext(ctx).WithName("qbittorrent-client")
+
+	encoded, err := json.Marshal(preferences)
+	if err != nil {
+		return fmt.Errorf("failed to encode qbittorrent preferences: %w", err)
+	}
+
+	// Only the keys are logged, the values may hold secrets such as proxy_password
+	logger.Info("Setting qbittorrent preferences",
+		"keys", PreferenceKeys(preferences),
+	)
+
+	data := url.Values{}
+	data.Set("json", string(encoded))
+
+	if _, err := c.postForm(ctx, "/api/v2/app/setPreferences", data); err != nil {
+		logger.Error(err, "Failed to set qbittorrent preferences")
+		return fmt.Errorf("failed to set qbittorrent preferences: %w", err)
+	}
+	return nil
+}
+
+// Get the qBittorrent application version, e.g. "v4.6.2"
+func (c *Client) GetAppVersion(ctx context.Context) (string, error) {
+	logger := log.FromContext(ctx).WithName("qbittorrent-client")
+
+	body, err := c.get(ctx, "/api/v2/app/version", nil)
+	if err != nil {
+		logger.Error(err, "Failed to get qbittorrent version")
+		return "", fmt.Errorf("failed to get qbittorrent version: %w", err)
+	}
+
+	return strings.TrimSpace(string(body)), nil
+}
+
+// Get the WebUI API version, e.g. "2.9.3", which gates the availability of newer endpoints
+func (c *Client) GetAPIVersion(ctx context.Context) (string, error) {
+	logger := log.FromContext(ctx).WithName("qbittorrent-client")
+
+	body, err := c.get(ctx, "/api/v2/app/webapiVersion", nil)
+	if err != nil {
+		logger.Error(err, "Failed to get qbittorrent API version")
+		return "", fmt.Errorf("failed to get qbittorrent API version: %w", err)
+	}
+
+	apiVersion := strings.TrimSpace(string(body))
+	c.mu.Lock()
+	c.apiVersion = apiVersion
+	c.mu.Unlock()
+
+	return apiVersion, nil
+}
+
+// APIVersion returns the WebUI API version detected by the last GetAPIVersion call,
+// or an empty string if it has not been detected yet
+func (c *Client) APIVersion() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.apiVersion
+}
+
+// Capabilities returns the features supported by the detected WebUI API version.
+// All features are assumed available until the version has been detected.
+func (c *Client) Capabilities() Capabilities {
+	return CapabilitiesFor(c.APIVersion())
+}
+
+// Send an authenticated GET request to a qBittorrent API endpoint and return the response body
+func (c *Client) get(ctx context.Context, endpoint string, query url.Values) ([]byte, error) {
+	requestURL := c.baseURL + endpoint
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	return c.do(ctx, req)
+}
+
+// Send an authenticated form-encoded POST request to a qBittorrent API endpoint
+// and return the response body
+func (c *Client) postForm(ctx context.Context, endpoint string, data url.Values) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+endpoint, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return c.do(ctx, req)
+}
+
+// authorize adds the bearer token when set, and the session ID cookie otherwise
+func (c *Client) authorize(req *http.Request) {
+	if c.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+		return
+	}
+	req.AddCookie(&http.Cookie{
+		Name:  "SID",
+		Value: c.sessionID,
+	})
+}
+
+// Send an authenticated request carrying the session cookie, or the bearer token, and return the response body.
+// Non-200 responses are returned as errors
+func (c *Client) do(ctx context.Context, req *http.Request) ([]byte, error) {
+	logger := log.FromContext(ctx).WithName("qbittorrent-client")
+
+	// The request must contain the session ID cookie or the bearer token for authentication
+	c.authorize(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request to %s failed: %w", req.URL.Path, err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logger.Error(err, "Failed to close response body")
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{
+			Endpoint:   req.URL.Path,
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+		}
+	}
+
+	return body, nil
+}
@@ -1,10 +1,21 @@
 package qbittorrent
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 func TestClient(t *testing.T) {
@@ -32,3 +43,881 @@ func TestNewClient_TrimsTrailingSlash(t *testing.T) {
 		t.Errorf("Expected trailing slash to be trimmed, got '%s'", client.baseURL)
 	}
 }
+
+func newTorrentsInfoServer(t *testing.T, status int, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/torrents/info" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetTorrentInfo_Present(t *testing.T) {
+	server := newTorrentsInfoServer(t, http.StatusOK,
+		`[{"hash":"aaaa","name":"first"},{"hash":"bbbb","name":"second","priority":3}]`)
+	client := NewClient(server.URL)
+
+	info, err := client.GetTorrentInfo(context.Background(), "bbbb")
+	if err != nil {
+		t.Fatalf("GetTorrentInfo returned error: %v", err)
+	}
+	if info == nil || info.Name != "second" {
+		t.Fatalf("expected torrent 'second', got %+v", info)
+	}
+	if info.Priority != 3 {
+		t.Errorf("expected queue position 3, got %d", info.Priority)
+	}
+}
+
+func TestGetTorrentInfo_AbsentReturnsNilNil(t *testing.T) {
+	server := newTorrentsInfoServer(t, http.StatusOK, `[{"hash":"aaaa","name":"first"}]`)
+	client := NewClient(server.URL)
+
+	info, err := client.GetTorrentInfo(context.Background(), "cccc")
+	if err != nil {
+		t.Fatalf("expected nil error for absent torrent, got %v", err)
+	}
+	if info != nil {
+		t.Errorf("expected nil info for absent torrent, got %+v", info)
+	}
+}
+
+func TestGetTorrentInfo_APIErrorReturnsError(t *testing.T) {
+	server := newTorrentsInfoServer(t, http.StatusInternalServerError, "")
+	client := NewClient(server.URL)
+
+	info, err := client.GetTorrentInfo(context.Background(), "aaaa")
+	if err == nil {
+		t.Fatal("expected error for failing API")
+	}
+	if info != nil {
+		t.Errorf("expected nil info on error, got %+v", info)
+	}
+}
+
+func TestGetTorrentInfo_TransportErrorReturnsError(t *testing.T) {
+	server := newTorrentsInfoServer(t, http.StatusOK, "[]")
+	client := NewClient(server.URL)
+	// Close the server so requests fail at the transport level
+	server.Close()
+
+	info, err := client.GetTorrentInfo(context.Background(), "aaaa")
+	if err == nil {
+		t.Fatal("expected error for unreachable server")
+	}
+	if info != nil {
+		t.Errorf("expected nil info on error, got %+v", info)
+	}
+}
+
+// recordedRequest captures what the fake qBittorrent server received
+type recordedRequest struct {
+	Method string
+	Path   string
+	Form   url.Values
+	Header http.Header
+}
+
+// newRecordingServer returns a fake qBittorrent server replying with status and body
+// to every request, recording each received request
+func newRecordingServer(t *testing.T, status int, body string) (*httptest.Server, *[]recordedRequest) {
+	t.Helper()
+	var mu sync.Mutex
+	requests := &[]recordedRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			_ = r.ParseForm()
+		}
+		mu.Lock()
+		*requests = append(*requests, recordedRequest{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Form:   r.Form,
+			Header: r.Header.Clone(),
+		})
+		mu.Unlock()
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func TestBearerToken_ReplacesSessionCookie(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK, "[]")
+	client := NewClient(server.URL)
+	client.SetBearerToken("eyJhbGciOi.payload.signature")
+
+	if err := client.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping returned error: %v", err)
+	}
+	req := (*requests)[0]
+	if got := req.Header.Get("Authorization"); got != "Bearer eyJhbGciOi.payload.signature" {
+		t.Errorf("got Authorization %q, want the bearer token", got)
+	}
+	if cookie := req.Header.Get("Cookie"); cookie != "" {
+		t.Errorf("expected no session cookie, got %q", cookie)
+	}
+}
+
+func TestRenameTorrent(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK, "")
+	client := NewClient(server.URL)
+
+	if err := client.RenameTorrent(context.Background(), "aaaa", "New Name"); err != nil {
+		t.Fatalf("RenameTorrent returned error: %v", err)
+	}
+
+	if len(*requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(*requests))
+	}
+	req := (*requests)[0]
+	if req.Method != http.MethodPost || req.Path != "/api/v2/torrents/rename" {
+		t.Errorf("unexpected request %s %s", req.Method, req.Path)
+	}
+	if req.Form.Get("hash") != "aaaa" || req.Form.Get("name") != "New Name" {
+		t.Errorf("unexpected form %v", req.Form)
+	}
+}
+
+func TestRenameTorrent_Error(t *testing.T) {
+	server, _ := newRecordingServer(t, http.StatusConflict, "")
+	client := NewClient(server.URL)
+
+	if err := client.RenameTorrent(context.Background(), "aaaa", "New Name"); err == nil {
+		t.Fatal("expected error on non-200 response")
+	}
+}
+
+func TestSetTorrentCategory(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK, "")
+	client := NewClient(server.URL)
+
+	if err := client.SetTorrentCategory(context.Background(), []string{"aaaa", "bbbb"}, "movies"); err != nil {
+		t.Fatalf("SetTorrentCategory returned error: %v", err)
+	}
+
+	req := (*requests)[0]
+	if req.Method != http.MethodPost || req.Path != "/api/v2/torrents/setCategory" {
+		t.Errorf("unexpected request %s %s", req.Method, req.Path)
+	}
+	if req.Form.Get("hashes") != "aaaa|bbbb" || req.Form.Get("category") != "movies" {
+		t.Errorf("unexpected form %v", req.Form)
+	}
+}
+
+func TestSetTorrentLocation(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK, "")
+	client := NewClient(server.URL)
+
+	if err := client.SetTorrentLocation(context.Background(), []string{"aaaa"}, "/downloads/archive"); err != nil {
+		t.Fatalf("SetTorrentLocation returned error: %v", err)
+	}
+
+	req := (*requests)[0]
+	if req.Method != http.MethodPost || req.Path != "/api/v2/torrents/setLocation" {
+		t.Errorf("unexpected request %s %s", req.Method, req.Path)
+	}
+	if req.Form.Get("hashes") != "aaaa" || req.Form.Get("location") != "/downloads/archive" {
+		t.Errorf("unexpected form %v", req.Form)
+	}
+}
+
+func TestGetTorrentFiles(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK,
+		`[{"index":0,"name":"dir/a.mkv","size":10,"progress":0.5,"priority":1}]`)
+	client := NewClient(server.URL)
+
+	files, err := client.GetTorrentFiles(context.Background(), "aaaa")
+	if err != nil {
+		t.Fatalf("GetTorrentFiles returned error: %v", err)
+	}
+	if len(files) != 1 || files[0].Name != "dir/a.mkv" || files[0].Priority != 1 {
+		t.Errorf("unexpected files %+v", files)
+	}
+	req := (*requests)[0]
+	if req.Path != "/api/v2/torrents/files" || req.Form.Get("hash") != "aaaa" {
+		t.Errorf("unexpected request %s %v", req.Path, req.Form)
+	}
+}
+
+func TestRenameFile(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK, "")
+	client := NewClient(server.URL)
+
+	if err := client.RenameFile(context.Background(), "aaaa", "dir/a.mkv", "dir/b.mkv"); err != nil {
+		t.Fatalf("RenameFile returned error: %v", err)
+	}
+	req := (*requests)[0]
+	if req.Path != "/api/v2/torrents/renameFile" {
+		t.Errorf("unexpected path %s", req.Path)
+	}
+	if req.Form.Get("oldPath") != "dir/a.mkv" || req.Form.Get("newPath") != "dir/b.mkv" {
+		t.Errorf("unexpected form %v", req.Form)
+	}
+}
+
+func TestGetAPIVersion(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK, "2.9.3\n")
+	client := NewClient(server.URL)
+
+	version, err := client.GetAPIVersion(context.Background())
+	if err != nil {
+		t.Fatalf("GetAPIVersion returned error: %v", err)
+	}
+	if version != "2.9.3" {
+		t.Errorf("expected version 2.9.3, got %q", version)
+	}
+	if got := (*requests)[0]; got.Method != http.MethodGet || got.Path != "/api/v2/app/webapiVersion" {
+		t.Errorf("unexpected request %s %s", got.Method, got.Path)
+	}
+}
+
+func TestGetAppVersion(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK, "v4.6.2")
+	client := NewClient(server.URL)
+
+	version, err := client.GetAppVersion(context.Background())
+	if err != nil {
+		t.Fatalf("GetAppVersion returned error: %v", err)
+	}
+	if version != "v4.6.2" {
+		t.Errorf("expected version v4.6.2, got %q", version)
+	}
+	if got := (*requests)[0].Path; got != "/api/v2/app/version" {
+		t.Errorf("unexpected request path %s", got)
+	}
+}
+
+func TestGetAPIVersion_Error(t *testing.T) {
+	server, _ := newRecordingServer(t, http.StatusInternalServerError, "")
+	client := NewClient(server.URL)
+
+	if _, err := client.GetAPIVersion(context.Background()); err == nil {
+		t.Fatal("expected an error on a 500 response")
+	}
+}
+
+func TestRenameFile_UnsupportedOnOldAPI(t *testing.T) {
+	var renameCalls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/app/webapiVersion", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("2.7.0"))
+	})
+	mux.HandleFunc("/api/v2/torrents/renameFile", func(w http.ResponseWriter, r *http.Request) {
+		renameCalls.Add(1)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	ctx := context.Background()
+
+	if _, err := client.GetAPIVersion(ctx); err != nil {
+		t.Fatalf("GetAPIVersion returned error: %v", err)
+	}
+	if client.Capabilities().RenameFile {
+		t.Fatal("expected RenameFile to be unsupported on API 2.7.0")
+	}
+
+	err := client.RenameFile(ctx, "abc", "a.mkv", "b.mkv")
+	if !errors.Is(err, ErrUnsupportedFeature) {
+		t.Fatalf("expected ErrUnsupportedFeature, got %v", err)
+	}
+	if renameCalls.Load() != 0 {
+		t.Errorf("expected no renameFile request, got %d", renameCalls.Load())
+	}
+}
+
+func TestPauseAndResumeTorrents(t *testing.T) {
+	tests := []struct {
+		name       string
+		apiVersion string
+		pause      bool
+		hashes     []string
+		wantPath   string
+		wantHashes string
+	}{
+		{"pause selected on qBittorrent 5", "2.11.2", true, []string{"aaa", "bbb"}, "/api/v2/torrents/stop", "aaa|bbb"},
+		{"pause all on qBittorrent 5", "2.11.2", true, []string{AllHashes}, "/api/v2/torrents/stop", "all"},
+		{"resume all on qBittorrent 5", "2.11.2", false, []string{AllHashes}, "/api/v2/torrents/start", "all"},
+		{"pause selected on qBittorrent 4", "2.8.3", true, []string{"aaa"}, "/api/v2/torrents/pause", "aaa"},
+		{"resume selected on qBittorrent 4", "2.8.3", false, []string{"aaa", "bbb"}, "/api/v2/torrents/resume", "aaa|bbb"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newRecordingServer(t, http.StatusOK, "")
+			client := NewClient(server.URL)
+			client.apiVersion = tt.apiVersion
+
+			var err error
+			if tt.pause {
+				err = client.PauseTorrents(context.Background(), tt.hashes)
+			} else {
+				err = client.ResumeTorrents(context.Background(), tt.hashes)
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := (*requests)[0]
+			if got.Path != tt.wantPath {
+				t.Errorf("expected path %s, got %s", tt.wantPath, got.Path)
+			}
+			if hashes := got.Form.Get("hashes"); hashes != tt.wantHashes {
+				t.Errorf("expected hashes %q, got %q", tt.wantHashes, hashes)
+			}
+		})
+	}
+}
+
+func TestPauseAll(t *testing.T) {
+	tests := []struct {
+		name       string
+		apiVersion string
+		call       func(*Client, context.Context) error
+		wantPath   string
+	}{
+		{"pause all on qBittorrent 5", "2.11.2", (*Client).PauseAll, "/api/v2/torrents/stop"},
+		{"pause all on qBittorrent 4", "2.8.3", (*Client).PauseAll, "/api/v2/torrents/pause"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newRecordingServer(t, http.StatusOK, "")
+			client := NewClient(server.URL)
+			client.apiVersion = tt.apiVersion
+
+			if err := tt.call(client, context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := (*requests)[0]
+			if got.Path != tt.wantPath {
+				t.Errorf("expected path %s, got %s", tt.wantPath, got.Path)
+			}
+			if hashes := got.Form.Get("hashes"); hashes != AllHashes {
+				t.Errorf("expected hashes %q, got %q", AllHashes, hashes)
+			}
+		})
+	}
+}
+
+func TestQueuePriority(t *testing.T) {
+	tests := []struct {
+		name     string
+		move     func(*Client, context.Context, []string) error
+		wantPath string
+	}{
+		{"top", (*Client).TopPriority, "/api/v2/torrents/topPrio"},
+		{"bottom", (*Client).BottomPriority, "/api/v2/torrents/bottomPrio"},
+		{"up", (*Client).IncreasePriority, "/api/v2/torrents/increasePrio"},
+		{"down", (*Client).DecreasePriority, "/api/v2/torrents/decreasePrio"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newRecordingServer(t, http.StatusOK, "")
+			client := NewClient(server.URL)
+
+			if err := tt.move(client, context.Background(), []string{"aaa", "bbb"}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := (*requests)[0]
+			if got.Method != http.MethodPost || got.Path != tt.wantPath {
+				t.Errorf("expected POST %s, got %s %s", tt.wantPath, got.Method, got.Path)
+			}
+			if hashes := got.Form.Get("hashes"); hashes != "aaa|bbb" {
+				t.Errorf("expected hashes %q, got %q", "aaa|bbb", hashes)
+			}
+		})
+	}
+}
+
+func TestPauseCategory(t *testing.T) {
+	var mu sync.Mutex
+	var categoryQuery, pausedHashes string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/torrents/info", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		categoryQuery = r.URL.Query().Get("category")
+		mu.Unlock()
+		_, _ = w.Write([]byte(`[{"hash":"aaa"},{"hash":"bbb"}]`))
+	})
+	mux.HandleFunc("/api/v2/torrents/stop", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		mu.Lock()
+		pausedHashes = r.PostForm.Get("hashes")
+		mu.Unlock()
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	if err := client.PauseCategory(context.Background(), "movies"); err != nil {
+		t.Fatalf("PauseCategory returned error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if categoryQuery != "movies" {
+		t.Errorf("expected category filter movies, got %q", categoryQuery)
+	}
+	if pausedHashes != "aaa|bbb" {
+		t.Errorf("expected hashes aaa|bbb, got %q", pausedHashes)
+	}
+}
+
+func TestResumeCategory_Empty(t *testing.T) {
+	var resumeCalls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/torrents/info", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[]`))
+	})
+	mux.HandleFunc("/api/v2/torrents/start", func(w http.ResponseWriter, r *http.Request) {
+		resumeCalls.Add(1)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	if err := client.ResumeCategory(context.Background(), "movies"); err != nil {
+		t.Fatalf("ResumeCategory returned error: %v", err)
+	}
+	if resumeCalls.Load() != 0 {
+		t.Errorf("expected no resume request for an empty category, got %d", resumeCalls.Load())
+	}
+}
+
+func TestGetPreferences(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK, `{"max_active_downloads":3,"dht":true}`)
+	client := NewClient(server.URL)
+
+	preferences, err := client.GetPreferences(context.Background())
+	if err != nil {
+		t.Fatalf("GetPreferences returned error: %v", err)
+	}
+	if got := (*requests)[0].Path; got != "/api/v2/app/preferences" {
+		t.Errorf("expected path /api/v2/app/preferences, got %s", got)
+	}
+	if preferences["max_active_downloads"] != float64(3) || preferences["dht"] != true {
+		t.Errorf("unexpected preferences: %v", preferences)
+	}
+}
+
+func TestSetPreferences(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK, "")
+	client := NewClient(server.URL)
+
+	if err := client.SetPreferences(context.Background(), map[string]any{"dht": false}); err != nil {
+		t.Fatalf("SetPreferences returned error: %v", err)
+	}
+
+	got := (*requests)[0]
+	if got.Path != "/api/v2/app/setPreferences" {
+		t.Errorf("expected path /api/v2/app/setPreferences, got %s", got.Path)
+	}
+	if encoded := got.Form.Get("json"); encoded != `{"dht":false}` {
+		t.Errorf("expected json form field {\"dht\":false}, got %q", encoded)
+	}
+}
+
+func TestSetPreferences_LogsKeysOnly(t *testing.T) {
+	server, _ := newRecordingServer(t, http.StatusOK, "")
+	client := NewClient(server.URL)
+	logger, lines := newTraceRecorder()
+
+	err := client.SetPreferences(log.IntoContext(context.Background(), logger), map[string]any{
+		"proxy_password":  "proxy-secret",
+		"web_ui_password": "hunter2",
+		"dht":             false,
+	})
+	if err != nil {
+		t.Fatalf("SetPreferences returned error: %v", err)
+	}
+
+	got := strings.Join(lines(), "\n")
+	for _, secret := range []string{"proxy-secret", "hunter2"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("log leaks %q: %s", secret, got)
+		}
+	}
+	if want := `"keys"=["dht" "proxy_password" "web_ui_password"]`; !strings.Contains(got, want) {
+		t.Errorf("expected %s in the log: %s", want, got)
+	}
+}
+
+func TestGetTorrentProperties(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK,
+		`{"availability":1.25,"peers":4,"peers_total":12,"seeds":2,"seeds_total":30,"total_downloaded":1048576,"total_uploaded":524288,"pieces_have":10,"pieces_num":40,"nb_connections_limit":100,"comment":"Encoded by Blender","created_by":"mktorrent 1.1"}`)
+	client := NewClient(server.URL)
+
+	props, err := client.GetTorrentProperties(context.Background(), "abc")
+	if err != nil {
+		t.Fatalf("GetTorrentProperties returned error: %v", err)
+	}
+
+	got := (*requests)[0]
+	if got.Path != "/api/v2/torrents/properties" {
+		t.Errorf("expected path /api/v2/torrents/properties, got %s", got.Path)
+	}
+	if hash := got.Form.Get("hash"); hash != "abc" {
+		t.Errorf("expected hash abc, got %q", hash)
+	}
+	want := TorrentProperties{
+		Availability:    1.25,
+		Peers:           4,
+		PeersTotal:      12,
+		Seeds:           2,
+		SeedsTotal:      30,
+		TotalDownloaded: 1048576,
+		TotalUploaded:   524288,
+		PiecesHave:      10,
+		PiecesNum:       40,
+
+		ConnectionsLimit: 100,
+		Comment:          "Encoded by Blender",
+		CreatedBy:        "mktorrent 1.1",
+	}
+	if *props != want {
+		t.Errorf("expected %+v, got %+v", want, *props)
+	}
+}
+
+func TestAddTorrent_ContentLayout(t *testing.T) {
+	tests := []struct {
+		name string
+		opts AddTorrentOptions
+		want []string
+	}{
+		{"qBittorrent default", AddTorrentOptions{}, nil},
+		{"subfolder", AddTorrentOptions{ContentLayout: "Subfolder"}, []string{"Subfolder"}},
+		{"no subfolder", AddTorrentOptions{ContentLayout: "NoSubfolder"}, []string{"NoSubfolder"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newRecordingServer(t, http.StatusOK, "Ok.")
+			client := NewClient(server.URL)
+
+			if err := client.AddTorrent(context.Background(), "magnet:?xt=urn:btih:aaaa", tt.opts); err != nil {
+				t.Fatalf("AddTorrent returned error: %v", err)
+			}
+			if got := (*requests)[0].Form["contentLayout"]; !slices.Equal(got, tt.want) {
+				t.Errorf("expected contentLayout %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAddTorrent_Category(t *testing.T) {
+	tests := []struct {
+		name string
+		opts AddTorrentOptions
+		want []string
+	}{
+		{"uncategorized", AddTorrentOptions{}, nil},
+		{"category", AddTorrentOptions{Category: "movies"}, []string{"movies"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newRecordingServer(t, http.StatusOK, "Ok.")
+			client := NewClient(server.URL)
+
+			if err := client.AddTorrent(context.Background(), "magnet:?xt=urn:btih:aaaa", tt.opts); err != nil {
+				t.Fatalf("AddTorrent returned error: %v", err)
+			}
+			if got := (*requests)[0].Form["category"]; !slices.Equal(got, tt.want) {
+				t.Errorf("expected category %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAddTorrent_AddToTopOfQueue(t *testing.T) {
+	tests := []struct {
+		name string
+		opts AddTorrentOptions
+		want []string
+	}{
+		{"bottom of queue", AddTorrentOptions{}, nil},
+		{"top of queue", AddTorrentOptions{AddToTopOfQueue: true}, []string{"true"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newRecordingServer(t, http.StatusOK, "Ok.")
+			client := NewClient(server.URL)
+
+			if err := client.AddTorrent(context.Background(), "magnet:?xt=urn:btih:aaaa", tt.opts); err != nil {
+				t.Fatalf("AddTorrent returned error: %v", err)
+			}
+			if got := (*requests)[0].Form["addToTopOfQueue"]; !slices.Equal(got, tt.want) {
+				t.Errorf("expected addToTopOfQueue %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAddTorrent_SkipChecking(t *testing.T) {
+	tests := []struct {
+		name string
+		opts AddTorrentOptions
+		want string
+	}{
+		{"recheck by default", AddTorrentOptions{}, ""},
+		{"skip checking", AddTorrentOptions{SkipChecking: true}, "true"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newRecordingServer(t, http.StatusOK, "Ok.")
+			client := NewClient(server.URL)
+
+			if err := client.AddTorrent(context.Background(), "magnet:?xt=urn:btih:aaaa", tt.opts); err != nil {
+				t.Fatalf("AddTorrent returned error: %v", err)
+			}
+			got := (*requests)[0]
+			if got.Path != "/api/v2/torrents/add" {
+				t.Errorf("expected path /api/v2/torrents/add, got %s", got.Path)
+			}
+			if skip := got.Form.Get("skip_checking"); skip != tt.want {
+				t.Errorf("expected skip_checking %q, got %q", tt.want, skip)
+			}
+		})
+	}
+}
+
+func TestAddTorrent_PausedAndStopCondition(t *testing.T) {
+	tests := []struct {
+		name       string
+		apiVersion string
+		opts       AddTorrentOptions
+		want       map[string]string
+	}{
+		{"running by default", "2.11.2", AddTorrentOptions{}, map[string]string{"stopped": "", "paused": "", "stopCondition": ""}},
+		{"stopped on qBittorrent 5", "2.11.2", AddTorrentOptions{Paused: true}, map[string]string{"stopped": "true", "paused": ""}},
+		{"paused before qBittorrent 5", "2.9.3", AddTorrentOptions{Paused: true}, map[string]string{"paused": "true", "stopped": ""}},
+		{"stopped on metadata", "2.9.3", AddTorrentOptions{StopCondition: StopConditionMetadataReceived},
+			map[string]string{"stopCondition": "MetadataReceived", "paused": ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newRecordingServer(t, http.StatusOK, tt.apiVersion)
+			client := NewClient(server.URL)
+			if _, err := client.GetAPIVersion(context.Background()); err != nil {
+				t.Fatalf("GetAPIVersion returned error: %v", err)
+			}
+
+			if err := client.AddTorrent(context.Background(), "magnet:?xt=urn:btih:aaaa", tt.opts); err != nil {
+				t.Fatalf("AddTorrent returned error: %v", err)
+			}
+			got := (*requests)[1]
+			for field, want := range tt.want {
+				if value := got.Form.Get(field); value != want {
+					t.Errorf("expected %s %q, got %q", field, want, value)
+				}
+			}
+		})
+	}
+}
+
+func TestSetFilePriority(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK, "")
+	client := NewClient(server.URL)
+
+	if err := client.SetFilePriority(context.Background(), "aaaa", []int{0, 2}, FilePriorityDoNotDownload); err != nil {
+		t.Fatalf("SetFilePriority returned error: %v", err)
+	}
+	req := (*requests)[0]
+	if req.Path != "/api/v2/torrents/filePrio" {
+		t.Errorf("unexpected path %s", req.Path)
+	}
+	if req.Form.Get("hash") != "aaaa" || req.Form.Get("id") != "0|2" || req.Form.Get("priority") != "0" {
+		t.Errorf("unexpected form %v", req.Form)
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK, "[]")
+	client := NewClient(server.URL)
+
+	// Login goes through http.Client.PostForm, the other calls through Client.do
+	_ = client.Login(context.Background(), "admin", "adminadmin")
+	if _, err := client.GetTorrentsInfo(context.Background()); err != nil {
+		t.Fatalf("GetTorrentsInfo returned error: %v", err)
+	}
+	if _, err := client.GetAppVersion(context.Background()); err != nil {
+		t.Fatalf("GetAppVersion returned error: %v", err)
+	}
+
+	want := "qbittorrent-operator/" + Version
+	for _, got := range *requests {
+		if ua := got.Header.Get("User-Agent"); ua != want {
+			t.Errorf("expected User-Agent %q on %s, got %q", want, got.Path, ua)
+		}
+	}
+
+	client.SetUserAgent("media-stack/1.0")
+	if err := client.AddTorrent(context.Background(), "magnet:?xt=urn:btih:aaaa", AddTorrentOptions{}); err != nil {
+		t.Fatalf("AddTorrent returned error: %v", err)
+	}
+	last := (*requests)[len(*requests)-1]
+	if ua := last.Header.Get("User-Agent"); ua != "media-stack/1.0" {
+		t.Errorf("expected overridden User-Agent media-stack/1.0, got %q", ua)
+	}
+}
+
+func TestGetLog(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK,
+		`[{"id":7,"message":"File error alert. Torrent: \"Sintel\". Reason: No space left on device","timestamp":1700000000,"type":8},`+
+			`{"id":8,"message":"UPnP/NAT-PMP: Port mapping failure","timestamp":1700000060,"type":4}]`)
+	client := NewClient(server.URL)
+
+	entries, err := client.GetLog(context.Background(), LogOptions{Warning: true, Critical: true, LastKnownID: 6})
+	if err != nil {
+		t.Fatalf("GetLog returned error: %v", err)
+	}
+
+	got := (*requests)[0]
+	if got.Method != http.MethodGet || got.Path != "/api/v2/log/main" {
+		t.Errorf("expected GET /api/v2/log/main, got %s %s", got.Method, got.Path)
+	}
+	wantQuery := map[string]string{
+		"normal": "false", "info": "false", "warning": "true", "critical": "true", "last_known_id": "6",
+	}
+	for key, want := range wantQuery {
+		if value := got.Form.Get(key); value != want {
+			t.Errorf("expected %s=%s, got %q", key, want, value)
+		}
+	}
+
+	want := []LogEntry{
+		{ID: 7, Message: `File error alert. Torrent: "Sintel". Reason: No space left on device`, Timestamp: 1700000000, Type: LogTypeCritical},
+		{ID: 8, Message: "UPnP/NAT-PMP: Port mapping failure", Timestamp: 1700000060, Type: LogTypeWarning},
+	}
+	if !slices.Equal(entries, want) {
+		t.Errorf("expected %+v, got %+v", want, entries)
+	}
+}
+
+func TestExportTorrent(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK, "d8:announce0:e")
+	client := NewClient(server.URL)
+
+	data, err := client.ExportTorrent(context.Background(), "aaaa")
+	if err != nil {
+		t.Fatalf("ExportTorrent returned error: %v", err)
+	}
+	if string(data) != "d8:announce0:e" {
+		t.Errorf("unexpected exported data %q", data)
+	}
+	req := (*requests)[0]
+	if req.Method != http.MethodGet || req.Path != "/api/v2/torrents/export" || req.Form.Get("hash") != "aaaa" {
+		t.Errorf("unexpected request %s %s %v", req.Method, req.Path, req.Form)
+	}
+}
+
+func TestExportTorrent_MetadataNotReceived(t *testing.T) {
+	server, _ := newRecordingServer(t, http.StatusConflict, "")
+	client := NewClient(server.URL)
+
+	_, err := client.ExportTorrent(context.Background(), "aaaa")
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusConflict {
+		t.Fatalf("expected a 409 StatusError, got %v", err)
+	}
+}
+
+func TestGetCategories(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK,
+		`{"movies":{"name":"movies","savePath":"/downloads/movies"},"misc":{"name":"misc","savePath":""}}`)
+	client := NewClient(server.URL)
+
+	categories, err := client.GetCategories(context.Background())
+	if err != nil {
+		t.Fatalf("GetCategories returned error: %v", err)
+	}
+	if req := (*requests)[0]; req.Method != http.MethodGet || req.Path != "/api/v2/torrents/categories" {
+		t.Errorf("unexpected request %s %s", req.Method, req.Path)
+	}
+	want := map[string]Category{
+		"movies": {Name: "movies", SavePath: "/downloads/movies"},
+		"misc":   {Name: "misc"},
+	}
+	if !reflect.DeepEqual(categories, want) {
+		t.Errorf("got categories %+v, want %+v", categories, want)
+	}
+}
+
+func TestCreateAndEditCategory(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK, "")
+	client := NewClient(server.URL)
+
+	if err := client.CreateCategory(context.Background(), "movies", "/downloads/movies"); err != nil {
+		t.Fatalf("CreateCategory returned error: %v", err)
+	}
+	if err := client.EditCategory(context.Background(), "movies", "/mnt/movies"); err != nil {
+		t.Fatalf("EditCategory returned error: %v", err)
+	}
+
+	for i, want := range []struct{ path, savePath string }{
+		{"/api/v2/torrents/createCategory", "/downloads/movies"},
+		{"/api/v2/torrents/editCategory", "/mnt/movies"},
+	} {
+		req := (*requests)[i]
+		if req.Method != http.MethodPost || req.Path != want.path {
+			t.Errorf("unexpected request %s %s, want POST %s", req.Method, req.Path, want.path)
+		}
+		if req.Form.Get("category") != "movies" || req.Form.Get("savePath") != want.savePath {
+			t.Errorf("unexpected form %v, want category movies and savePath %s", req.Form, want.savePath)
+		}
+	}
+}
+
+func TestCreateCategory_Conflict(t *testing.T) {
+	server, _ := newRecordingServer(t, http.StatusConflict, "")
+	client := NewClient(server.URL)
+
+	if err := client.CreateCategory(context.Background(), "movies", "/downloads/movies"); err == nil {
+		t.Fatal("expected an error for an existing category")
+	}
+}
+
+func TestGetMainData(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK,
+		`{"rid":1,"full_update":true,"torrents":{},"server_state":{"free_space_on_disk":53687091200,"dl_info_speed":0}}`)
+	client := NewClient(server.URL)
+
+	mainData, err := client.GetMainData(context.Background())
+	if err != nil {
+		t.Fatalf("GetMainData returned error: %v", err)
+	}
+	req := (*requests)[0]
+	if req.Method != http.MethodGet || req.Path != "/api/v2/sync/maindata" || req.Form.Get("rid") != "0" {
+		t.Errorf("unexpected request %s %s %v", req.Method, req.Path, req.Form)
+	}
+	if mainData.ServerState.FreeSpaceOnDisk != 53687091200 {
+		t.Errorf("got free space %d, want 53687091200", mainData.ServerState.FreeSpaceOnDisk)
+	}
+}
+
+func TestSetUserAgent_KeepsTransportChain(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK, "[]")
+	logger, lines := newTraceRecorder()
+	client := NewClient(server.URL)
+	client.SetTraceLogger(logger)
+	client.SetUserAgent("media-stack/1.0")
+
+	if _, err := client.GetTorrentsInfo(context.Background()); err != nil {
+		t.Fatalf("GetTorrentsInfo returned error: %v", err)
+	}
+	if ua := (*requests)[0].Header.Get("User-Agent"); ua != "media-stack/1.0" {
+		t.Errorf("expected overridden User-Agent media-stack/1.0, got %q", ua)
+	}
+	if len(lines()) != 1 {
+		t.Errorf("expected the request to be traced after SetUserAgent, got %v", lines())
+	}
+}
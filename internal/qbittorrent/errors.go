@@ -0,0 +1,53 @@
+package qbittorrent
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrTorrentRejected is returned when qBittorrent refuses to add a torrent (invalid magnet/URL)
+var ErrTorrentRejected = errors.New("torrent rejected by qbittorrent")
+
+// ErrUnsupportedFeature is returned when the detected WebUI API version does not support a feature
+var ErrUnsupportedFeature = errors.New("feature not supported by this qbittorrent version")
+
+// ErrCircuitOpen is returned without contacting qBittorrent while the circuit breaker of its URL is open
+var ErrCircuitOpen = errors.New("qbittorrent circuit breaker open")
+
+// ErrRateLimited is returned without contacting qBittorrent when the context ends
+// before the rate limit of its URL lets the request through
+var ErrRateLimited = errors.New("qbittorrent rate limit wait aborted")
+
+// StatusError is returned when qBittorrent answers with a non-200 status code
+type StatusError struct {
+	Endpoint   string
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	if e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden {
+		return "unauthorized access to qbittorrent"
+	}
+	return fmt.Sprintf("request to %s failed. Status: %s", e.Endpoint, e.Status)
+}
+
+// IsTerminalError reports whether retrying the same request cannot succeed,
+// e.g. qBittorrent rejected the input as invalid.
+// Transport errors, authentication errors, throttling and 5xx responses are transient.
+func IsTerminalError(err error) bool {
+	if errors.Is(err, ErrTorrentRejected) {
+		return true
+	}
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	switch statusErr.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return false
+	}
+	return statusErr.StatusCode >= 400 && statusErr.StatusCode < 500
+}
@@ -0,0 +1,68 @@
+package qbittorrent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestIsTerminalError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"rejected torrent", fmt.Errorf("wrap: %w", ErrTorrentRejected), true},
+		{"bad request", &StatusError{StatusCode: http.StatusBadRequest}, true},
+		{"unsupported media type", &StatusError{StatusCode: http.StatusUnsupportedMediaType}, true},
+		{"unauthorized", &StatusError{StatusCode: http.StatusUnauthorized}, false},
+		{"forbidden", &StatusError{StatusCode: http.StatusForbidden}, false},
+		{"too many requests", &StatusError{StatusCode: http.StatusTooManyRequests}, false},
+		{"internal server error", &StatusError{StatusCode: http.StatusInternalServerError}, false},
+		{"bad gateway", fmt.Errorf("wrap: %w", &StatusError{StatusCode: http.StatusBadGateway}), false},
+		{"transport error", errors.New("connection refused"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTerminalError(tt.err); got != tt.want {
+				t.Errorf("IsTerminalError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddTorrent_ErrorClassification(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		wantErr      bool
+		wantTerminal bool
+	}{
+		{"accepted", http.StatusOK, "Ok.", false, false},
+		{"rejected with Fails.", http.StatusOK, "Fails.", true, true},
+		{"invalid torrent 400", http.StatusBadRequest, "", true, true},
+		{"invalid torrent 415", http.StatusUnsupportedMediaType, "Fails.", true, true},
+		{"server error 500", http.StatusInternalServerError, "", true, false},
+		{"unavailable 503", http.StatusServiceUnavailable, "", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newRecordingServer(t, tt.status, tt.body)
+			client := NewClient(server.URL)
+
+			err := client.AddTorrent(context.Background(), "magnet:?xt=urn:btih:aaaa", AddTorrentOptions{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AddTorrent error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && IsTerminalError(err) != tt.wantTerminal {
+				t.Errorf("IsTerminalError(%v) = %v, want %v", err, !tt.wantTerminal, tt.wantTerminal)
+			}
+			if got := (*requests)[0].Form["urls"]; len(got) != 1 {
+				t.Errorf("expected a single urls field, got %v", got)
+			}
+		})
+	}
+}
@@ -5,8 +5,36 @@ import "context"
 type QBTClient interface {
 	Login(ctx context.Context, username, password string) error
 	GetTorrentsInfo(ctx context.Context) ([]TorrentInfo, error)
+	// GetTorrentInfo returns (nil, nil) when the torrent is absent, see Client.GetTorrentInfo
 	GetTorrentInfo(ctx context.Context, hash string) (*TorrentInfo, error)
-	AddTorrent(ctx context.Context, magnetURI string) error
+	AddTorrent(ctx context.Context, magnetURI string, opts AddTorrentOptions) error
 	DeleteTorrent(ctx context.Context, hash string, deleteFiles bool) error
+	RenameTorrent(ctx context.Context, hash, name string) error
+	SetTorrentCategory(ctx context.Context, hashes []string, category string) error
+	SetTorrentLocation(ctx context.Context, hashes []string, location string) error
+	GetTorrentFiles(ctx context.Context, hash string) ([]TorrentFile, error)
+	GetTorrentProperties(ctx context.Context, hash string) (*TorrentProperties, error)
+	GetLog(ctx context.Context, opts LogOptions) ([]LogEntry, error)
+	ExportTorrent(ctx context.Context, hash string) ([]byte, error)
+	RenameFile(ctx context.Context, hash, oldPath, newPath string) error
+	SetFilePriority(ctx context.Context, hash string, indexes []int, priority int) error
+	PauseTorrents(ctx context.Context, hashes []string) error
+	ResumeTorrents(ctx context.Context, hashes []string) error
+	TopPriority(ctx context.Context, hashes []string) error
+	BottomPriority(ctx context.Context, hashes []string) error
+	IncreasePriority(ctx context.Context, hashes []string) error
+	DecreasePriority(ctx context.Context, hashes []string) error
+	PauseAll(ctx context.Context) error
+	PauseCategory(ctx context.Context, category string) error
+	ResumeCategory(ctx context.Context, category string) error
+	GetCategories(ctx context.Context) (map[string]Category, error)
+	CreateCategory(ctx context.Context, name, savePath string) error
+	EditCategory(ctx context.Context, name, savePath string) error
+	GetMainData(ctx context.Context) (*MainData, error)
+	GetPreferences(ctx context.Context) (map[string]any, error)
+	SetPreferences(ctx context.Context, preferences map[string]any) error
+	GetAppVersion(ctx context.Context) (string, error)
+	GetAPIVersion(ctx context.Context) (string, error)
+	Capabilities() Capabilities
 	Ping(ctx context.Context) error
 }
@@ -0,0 +1,54 @@
+package qbittorrent
+
+import (
+	"path"
+	"strings"
+)
+
+// globStar is the pattern segment matching any number of path segments, including none
+const globStar = "**"
+
+// MatchFilePath reports whether a torrent file path, as returned by GetTorrentFiles, matches pattern.
+// Both are split on "/" and matched segment by segment with path.Match syntax, so "*" never crosses
+// a separator. A "**" segment matches zero or more whole segments, e.g. "**/*.mkv" matches
+// "Show/Season 1/E01.mkv" as well as "E01.mkv". Matching is case-sensitive.
+// The only possible error is path.ErrBadPattern, reported even when the pattern cannot match.
+func MatchFilePath(pattern, name string) (bool, error) {
+	patternSegments := strings.Split(pattern, "/")
+	for _, segment := range patternSegments {
+		if segment == globStar {
+			continue
+		}
+		if _, err := path.Match(segment, ""); err != nil {
+			return false, err
+		}
+	}
+	return matchSegments(patternSegments, strings.Split(name, "/")), nil
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == globStar {
+			for len(pattern) > 0 && pattern[0] == globStar {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 0 {
+				return true
+			}
+			for i := range len(name) + 1 {
+				if matchSegments(pattern, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if matched, _ := path.Match(pattern[0], name[0]); !matched {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
@@ -0,0 +1,50 @@
+package qbittorrent
+
+import (
+	"errors"
+	"path"
+	"testing"
+)
+
+func TestMatchFilePath(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"*/Sample/*", "Movie/Sample/sample.mkv", true},
+		{"*/Sample/*", "Movie/Extras/Sample/sample.mkv", false},
+		{"*.mkv", "Movie/movie.mkv", false},
+		{"**/*.mkv", "movie.mkv", true},
+		{"**/*.mkv", "Movie/movie.mkv", true},
+		{"**/*.mkv", "Show/Season 1/Disc 2/E01.mkv", true},
+		{"**/*.mkv", "Show/Season 1/E01.srt", false},
+		{"**/Sample/**", "Show/Season 1/Sample/clip/sample.mkv", true},
+		{"**/Sample/**", "Show/Season 1/Samples/sample.mkv", false},
+		{"Show/**/E01.mkv", "Show/E01.mkv", true},
+		{"Show/**/E01.mkv", "Show/Season 1/E01.mkv", true},
+		{"Show/**/E01.mkv", "Other/Season 1/E01.mkv", false},
+		{"Show/**/**/E0?.mkv", "Show/Season 1/E02.mkv", true},
+		{"**", "Show/Season 1/E01.mkv", true},
+		{"**/*.MKV", "Show/E01.mkv", false},
+		{"**/[Ss]ample/*", "Show/sample/clip.mkv", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			got, err := MatchFilePath(tt.pattern, tt.name)
+			if err != nil {
+				t.Fatalf("MatchFilePath returned error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("MatchFilePath(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchFilePath_BadPattern(t *testing.T) {
+	// The bad segment is reported even though the first segment never matches
+	if _, err := MatchFilePath("Other/**/[", "Show/E01.mkv"); !errors.Is(err, path.ErrBadPattern) {
+		t.Errorf("expected path.ErrBadPattern, got %v", err)
+	}
+}
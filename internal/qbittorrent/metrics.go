@@ -0,0 +1,39 @@
+package qbittorrent
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// poolSize tracks how many authenticated clients the pool currently caches
+	poolSize = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "qbittorrent_client_pool_size",
+		Help: "Number of authenticated qBittorrent clients cached in the pool",
+	})
+	// poolHits counts lookups served by a cached client
+	poolHits = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "qbittorrent_client_pool_hits_total",
+		Help: "Total number of client pool lookups served by a cached session",
+	})
+	// poolMisses counts lookups that required a new login
+	poolMisses = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "qbittorrent_client_pool_misses_total",
+		Help: "Total number of client pool lookups that required a new login",
+	})
+	// poolEvictions counts entries dropped to honour the pool max size
+	poolEvictions = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "qbittorrent_client_pool_evictions_total",
+		Help: "Total number of cached clients evicted because the pool reached its max size",
+	})
+	// circuitBreakerOpens counts how often a qBittorrent URL started failing fast
+	circuitBreakerOpens = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "qbittorrent_client_circuit_breaker_opens_total",
+		Help: "Total number of times a qBittorrent circuit breaker opened after repeated failures",
+	})
+)
+
+// Register the pool metrics with the controller-runtime registry served on the metrics endpoint
+func init() {
+	metrics.Registry.MustRegister(poolSize, poolHits, poolMisses, poolEvictions, circuitBreakerOpens)
+}
@@ -5,65 +5,298 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/sync/singleflight"
+	"golang.org/x/time/rate"
 )
 
+// ClientFactory builds an unauthenticated client for a qBittorrent base URL
+type ClientFactory func(baseURL string) QBTClient
+
 type ClientPool struct {
-	mu      sync.RWMutex
-	clients map[string]*poolEntry
-	ttl     time.Duration
+	mu        sync.RWMutex
+	clients   map[string]*poolEntry
+	ttl       time.Duration
+	newClient ClientFactory
+	// maxSessionAge forces a new login for entries older than this value,
+	// regardless of lastUsed. Zero disables proactive refresh.
+	maxSessionAge time.Duration
+	// maxSize caps the cached entries, evicting the least recently used one
+	// when a new login would exceed it. Zero means unbounded.
+	maxSize int
+	// breakers holds a circuit breaker per qBittorrent URL, shared by every client of that URL
+	breakers       map[string]*CircuitBreaker
+	breakerOptions CircuitBreakerOptions
+	// limiters holds a rate limiter per qBittorrent URL, shared by every client of that URL
+	limiters         map[string]*rate.Limiter
+	rateLimitOptions RateLimitOptions
+
+	traceLogger logr.Logger
+
+	// logins shares an in-flight login between concurrent lookups of the same credentials,
+	// e.g. Torrent reconciles started while the TCC health check logs in
+	logins singleflight.Group
+}
+
+// ClientPoolOptions tunes the pool memory footprint against the re-login frequency
+type ClientPoolOptions struct {
+	// TTL drops entries unused for longer than this value
+	TTL time.Duration
+	// MaxSessionAge forces a new login for older entries. Zero disables proactive refresh.
+	MaxSessionAge time.Duration
+	// MaxSize caps the number of cached entries. Zero means unbounded.
+	MaxSize int
+	// UserAgent overrides the User-Agent sent by new clients. Empty keeps DefaultUserAgent.
+	UserAgent string
+	// CircuitBreaker makes the clients of a failing URL fail fast
+	CircuitBreaker CircuitBreakerOptions
+	// RateLimit throttles the requests sent to each qBittorrent URL
+	RateLimit RateLimitOptions
+	// TraceLogger receives a line per request sent by new clients. The zero value disables tracing.
+	TraceLogger logr.Logger
+}
+
+// RateLimitOptions tunes the token bucket throttling the requests sent to a qBittorrent URL
+type RateLimitOptions struct {
+	// QPS is the sustained number of requests per second. Zero disables rate limiting.
+	QPS float64
+	// Burst is the number of requests sent without waiting after an idle period, at least 1
+	Burst int
+}
+
+// CircuitBreakerOptions tunes when the requests towards a failing qBittorrent URL are short-circuited
+type CircuitBreakerOptions struct {
+	// Threshold is the number of consecutive failures opening the breaker. Zero disables it.
+	Threshold int
+	// Cooldown is how long an open breaker fails requests fast before probing the URL again
+	Cooldown time.Duration
 }
 
 type poolEntry struct {
-	client   *Client
-	credHash string
-	lastUsed time.Time
+	client    QBTClient
+	url       string
+	credHash  string
+	lastUsed  time.Time
+	createdAt time.Time
 }
 
 func NewClientPool(ttl time.Duration) *ClientPool {
+	return NewClientPoolWithMaxSessionAge(ttl, 0)
+}
+
+// qBittorrent expires WebUI sessions on its own timer (WebUI\SessionTimeout, 3600s by default),
+// even for sessions in use. Setting maxSessionAge below that timeout avoids mid-TTL 403s.
+func NewClientPoolWithMaxSessionAge(ttl, maxSessionAge time.Duration) *ClientPool {
+	return NewClientPoolWithOptions(ClientPoolOptions{TTL: ttl, MaxSessionAge: maxSessionAge})
+}
+
+func NewClientPoolWithOptions(opts ClientPoolOptions) *ClientPool {
 	return &ClientPool{
-		clients: make(map[string]*poolEntry),
-		ttl:     ttl,
+		clients:          make(map[string]*poolEntry),
+		ttl:              opts.TTL,
+		maxSessionAge:    opts.MaxSessionAge,
+		maxSize:          opts.MaxSize,
+		breakers:         make(map[string]*CircuitBreaker),
+		breakerOptions:   opts.CircuitBreaker,
+		limiters:         make(map[string]*rate.Limiter),
+		rateLimitOptions: opts.RateLimit,
+		traceLogger:      opts.TraceLogger,
+		newClient: func(baseURL string) QBTClient {
+			client := NewClient(baseURL)
+			if opts.UserAgent != "" {
+				client.SetUserAgent(opts.UserAgent)
+			}
+			return client
+		},
 	}
 }
 
-func (p *ClientPool) GetOrCreate(ctx context.Context, url, username, password string) (*Client, error) {
+// TTL returns how long unused entries are kept
+func (p *ClientPool) TTL() time.Duration {
+	return p.ttl
+}
+
+// loginTimeout bounds a shared login, which runs detached from the context of the lookup that started it
+const loginTimeout = 30 * time.Second
+
+// SessionTimeoutMargin is kept between the longest session reuse and qBittorrent's WebUI session timeout
+const SessionTimeoutMargin = time.Minute
+
+// MinSessionTimeout returns the shortest qBittorrent WebUI session timeout that never expires a session
+// the pool may still reuse: an entry is reused after up to TTL of inactivity and up to MaxSessionAge
+// after its login
+func (p *ClientPool) MinSessionTimeout() time.Duration {
+	return max(p.ttl, p.maxSessionAge) + SessionTimeoutMargin
+}
+
+// MaxSize returns the cap on cached entries, zero when unbounded
+func (p *ClientPool) MaxSize() int {
+	return p.maxSize
+}
+
+// Len returns the number of cached entries
+func (p *ClientPool) Len() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return len(p.clients)
+}
+
+// SetClientFactory replaces how new clients are built, e.g. to inject fakes in tests
+func (p *ClientPool) SetClientFactory(factory ClientFactory) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.newClient = factory
+}
+
+// GetOrCreate returns the cached session for the URL and credentials, logging in on a miss.
+// Every controller shares the pool, so the session a TCC health check logs in with is the one
+// its Torrents reuse; lookups arriving while that login is in flight wait for it instead of logging in again
+func (p *ClientPool) GetOrCreate(ctx context.Context, url, username, password string) (QBTClient, error) {
 	credHash := hashCredentials(url, username, password)
 
 	p.mu.RLock()
 	entry, exists := p.clients[credHash]
 	p.mu.RUnlock()
 
-	if exists && entry.credHash == credHash {
+	if exists && entry.credHash == credHash && !p.sessionExpired(entry) {
 		p.mu.Lock()
 		entry.lastUsed = time.Now()
 		p.mu.Unlock()
-		go scheduleRemove(p, credHash)
+		poolHits.Inc()
 		return entry.client, nil
 	}
 
-	// Create new client and login
-	client := NewClient(url)
-	if err := client.Login(ctx, username, password); err != nil {
-		return nil, fmt.Errorf("failed to login for credentials[%s, %s] url[%s]: %w",
-			username, password, url, err)
+	return p.create(ctx, credHash, url, func(ctx context.Context, client QBTClient) error {
+		if err := client.Login(ctx, username, password); err != nil {
+			return fmt.Errorf("failed to login for credentials[%s, %s] url[%s]: %w",
+				username, password, url, err)
+		}
+		return nil
+	})
+}
+
+// GetOrCreateWithToken returns the cached client authenticating every request with a bearer token
+// instead of logging in. Entries are keyed by the token hash, so a rotated token gets a new client
+func (p *ClientPool) GetOrCreateWithToken(ctx context.Context, url, token string) (QBTClient, error) {
+	credHash := hashToken(url, token)
+
+	p.mu.RLock()
+	entry, exists := p.clients[credHash]
+	p.mu.RUnlock()
+
+	if exists && !p.sessionExpired(entry) {
+		p.mu.Lock()
+		entry.lastUsed = time.Now()
+		p.mu.Unlock()
+		poolHits.Inc()
+		return entry.client, nil
 	}
 
+	return p.create(ctx, credHash, url, func(_ context.Context, client QBTClient) error {
+		authenticated, ok := client.(interface{ SetBearerToken(string) })
+		if !ok {
+			return fmt.Errorf("client for url[%s] does not support bearer token authentication", url)
+		}
+		authenticated.SetBearerToken(token)
+		return nil
+	})
+}
+
+// create builds, authenticates and caches a client on a miss, sharing the work with concurrent misses.
+// The shared login does not inherit the cancellation of the lookup that started it, so that a caller
+// giving up does not fail the others waiting for it; each caller still stops waiting once its ctx is done
+func (p *ClientPool) create(ctx context.Context, credHash, url string, authenticate func(context.Context, QBTClient) error) (QBTClient, error) {
+	var created atomic.Bool
+	results := p.logins.DoChan(credHash, func() (interface{}, error) {
+		created.Store(true)
+		loginCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), loginTimeout)
+		defer cancel()
+		return p.login(loginCtx, credHash, url, authenticate)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-results:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		if !created.Load() {
+			poolHits.Inc()
+		}
+		return result.Val.(QBTClient), nil
+	}
+}
+
+// login creates a client for the URL, authenticates it and caches the session
+func (p *ClientPool) login(ctx context.Context, credHash, url string, authenticate func(context.Context, QBTClient) error) (QBTClient, error) {
+	poolMisses.Inc()
+	p.mu.RLock()
+	client := p.newClient(url)
+	p.mu.RUnlock()
+	if throttled, ok := client.(interface{ SetRateLimiter(*rate.Limiter) }); ok {
+		if limiter := p.Limiter(url); limiter != nil {
+			throttled.SetRateLimiter(limiter)
+		}
+	}
+	if guarded, ok := client.(interface{ SetCircuitBreaker(*CircuitBreaker) }); ok {
+		if breaker := p.Breaker(url); breaker != nil {
+			guarded.SetCircuitBreaker(breaker)
+		}
+	}
+	if traced, ok := client.(interface{ SetTraceLogger(logr.Logger) }); ok && p.traceLogger.GetSink() != nil {
+		traced.SetTraceLogger(p.traceLogger)
+	}
+	if err := authenticate(ctx, client); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
 	p.mu.Lock()
+	if _, replacing := p.clients[credHash]; !replacing {
+		p.evictForInsert()
+	}
 	p.clients[credHash] = &poolEntry{
-		client:   client,
-		credHash: credHash,
-		lastUsed: time.Now(),
+		client:    client,
+		url:       url,
+		credHash:  credHash,
+		lastUsed:  now,
+		createdAt: now,
 	}
+	poolSize.Set(float64(len(p.clients)))
 	p.mu.Unlock()
 
-	go scheduleRemove(p, credHash)
 	return client, nil
 }
 
+// Cached returns the most recently used client of a live session for the URL, whatever credentials
+// it logged in with, so that an instance can be reached after its credentials are lost
+func (p *ClientPool) Cached(url string) (QBTClient, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	var found *poolEntry
+	for _, entry := range p.clients {
+		if entry.url != url || p.sessionExpired(entry) {
+			continue
+		}
+		if found == nil || entry.lastUsed.After(found.lastUsed) {
+			found = entry
+		}
+	}
+	if found == nil {
+		return nil, false
+	}
+	return found.client, true
+}
+
 func (p *ClientPool) Remove(credHash string) {
 	p.mu.Lock()
 	delete(p.clients, credHash)
+	p.pruneURLs()
+	poolSize.Set(float64(len(p.clients)))
 	p.mu.Unlock()
 }
 
@@ -76,25 +309,112 @@ func (p *ClientPool) Cleanup() {
 			delete(p.clients, key)
 		}
 	}
+	p.pruneURLs()
+	poolSize.Set(float64(len(p.clients)))
 }
 
-func scheduleRemove(pool *ClientPool, credHash string) {
-	time.AfterFunc(pool.ttl, func() {
-		pool.mu.RLock()
-		entry, exists := pool.clients[credHash]
-		pool.mu.RUnlock()
+// Drop the per-URL state of the URLs no cached entry uses anymore, e.g. of a deleted TCC.
+// An open breaker is kept until its cool-down ends, so that a failing URL whose logins never
+// succeed keeps failing fast. Callers must hold the write lock.
+func (p *ClientPool) pruneURLs() {
+	used := make(map[string]bool, len(p.clients))
+	for _, entry := range p.clients {
+		used[entry.url] = true
+	}
+	for url, breaker := range p.breakers {
+		if !used[url] && breaker.State() != CircuitOpen {
+			delete(p.breakers, url)
+		}
+	}
+	for url := range p.limiters {
+		if !used[url] {
+			delete(p.limiters, url)
+		}
+	}
+}
 
-		if !exists {
-			return
+// Drop the least recently used entries until a new one fits within maxSize.
+// Callers must hold the write lock.
+func (p *ClientPool) evictForInsert() {
+	if p.maxSize <= 0 {
+		return
+	}
+	for len(p.clients) >= p.maxSize {
+		var oldestKey string
+		var oldest time.Time
+		for key, entry := range p.clients {
+			if oldestKey == "" || entry.lastUsed.Before(oldest) {
+				oldestKey, oldest = key, entry.lastUsed
+			}
 		}
+		delete(p.clients, oldestKey)
+		poolEvictions.Inc()
+	}
+}
 
-		if time.Since(entry.lastUsed) > pool.ttl {
-			pool.Remove(credHash)
+// Breaker returns the circuit breaker shared by the clients of url, or nil when breakers are disabled
+func (p *ClientPool) Breaker(url string) *CircuitBreaker {
+	if p.breakerOptions.Threshold <= 0 {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	breaker, ok := p.breakers[url]
+	if !ok {
+		breaker = NewCircuitBreaker(p.breakerOptions.Threshold, p.breakerOptions.Cooldown)
+		p.breakers[url] = breaker
+	}
+	return breaker
+}
+
+// Limiter returns the rate limiter shared by the clients of url, or nil when rate limiting is disabled
+func (p *ClientPool) Limiter(url string) *rate.Limiter {
+	if p.rateLimitOptions.QPS <= 0 {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	limiter, ok := p.limiters[url]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(p.rateLimitOptions.QPS), max(p.rateLimitOptions.Burst, 1))
+		p.limiters[url] = limiter
+	}
+	return limiter
+}
+
+// Check whether the entry session is old enough to require a proactive re-login
+func (p *ClientPool) sessionExpired(entry *poolEntry) bool {
+	return p.maxSessionAge > 0 && time.Since(entry.createdAt) > p.maxSessionAge
+}
+
+// Start drops the entries unused for longer than the TTL every TTL, until ctx is done.
+// It implements the controller-runtime Runnable interface: added to the manager,
+// the sweep only runs on the elected leader, the only replica reconciling
+func (p *ClientPool) Start(ctx context.Context) error {
+	ticker := time.NewTicker(max(p.ttl, time.Second))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			p.Cleanup()
 		}
-	})
+	}
+}
+
+// NeedLeaderElection makes the manager start the cleanup sweep only once this replica is the leader
+func (p *ClientPool) NeedLeaderElection() bool {
+	return true
 }
 
 func hashCredentials(url, username, password string) string {
 	h := sha256.Sum256([]byte(url + "|" + username + "|" + password))
 	return fmt.Sprintf("%x", h)
 }
+
+// hashToken keys bearer token clients apart from the username and password ones
+func hashToken(url, token string) string {
+	h := sha256.Sum256([]byte("bearer|" + url + "|" + token))
+	return fmt.Sprintf("%x", h)
+}
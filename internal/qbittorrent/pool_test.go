@@ -1,8 +1,18 @@
 package qbittorrent
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
 func TestNewClientPool(t *testing.T) {
@@ -108,3 +118,446 @@ func TestCleanupAllExpired(t *testing.T) {
 		t.Errorf("expected 0 entries after cleanup, got %d", len(pool.clients))
 	}
 }
+
+func TestStart_NeedsLeaderElection(t *testing.T) {
+	var runnable manager.Runnable = NewClientPool(time.Minute)
+
+	// The manager starts runnables that do not opt out of leader election only on the leader
+	leaderOnly, ok := runnable.(manager.LeaderElectionRunnable)
+	if !ok || !leaderOnly.NeedLeaderElection() {
+		t.Fatal("expected the client pool cleanup to run only on the leader")
+	}
+}
+
+func TestStart_SweepsExpiredEntries(t *testing.T) {
+	pool := NewClientPool(time.Millisecond)
+	pool.clients["old"] = &poolEntry{
+		client:   &Client{},
+		credHash: "h1",
+		lastUsed: time.Now().Add(-time.Minute),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- pool.Start(ctx) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for pool.Len() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the expired entry to be swept")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Start returned error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Start did not return after the context was cancelled")
+	}
+}
+
+// newLoginServer returns a fake qBittorrent server answering the login endpoint
+// and counting how many logins were performed
+func newLoginServer(t *testing.T, logins *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/auth/login" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		n := logins.Add(1)
+		http.SetCookie(w, &http.Cookie{Name: "SID", Value: fmt.Sprintf("sid-%d", n)})
+		_, _ = w.Write([]byte("Ok."))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetOrCreate_ReusesFreshSession(t *testing.T) {
+	var logins atomic.Int32
+	server := newLoginServer(t, &logins)
+	pool := NewClientPoolWithMaxSessionAge(5*time.Minute, time.Hour)
+
+	c1, err := pool.GetOrCreate(context.Background(), server.URL, "admin", "pass")
+	if err != nil {
+		t.Fatalf("GetOrCreate returned error: %v", err)
+	}
+	c2, err := pool.GetOrCreate(context.Background(), server.URL, "admin", "pass")
+	if err != nil {
+		t.Fatalf("GetOrCreate returned error: %v", err)
+	}
+
+	if c1 != c2 {
+		t.Error("expected cached client to be reused")
+	}
+	if logins.Load() != 1 {
+		t.Errorf("expected 1 login, got %d", logins.Load())
+	}
+}
+
+func TestGetOrCreate_SharesInFlightLogin(t *testing.T) {
+	var logins atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		logins.Add(1)
+		http.SetCookie(w, &http.Cookie{Name: "SID", Value: "sid"})
+		_, _ = w.Write([]byte("Ok."))
+	}))
+	t.Cleanup(server.Close)
+	pool := NewClientPool(5 * time.Minute)
+
+	const callers = 5
+	clients := make([]QBTClient, callers)
+	var wg sync.WaitGroup
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c, err := pool.GetOrCreate(context.Background(), server.URL, "admin", "pass")
+			if err != nil {
+				t.Errorf("GetOrCreate returned error: %v", err)
+			}
+			clients[i] = c
+		}()
+	}
+	// Let every caller reach the pool while the first login is held by the server
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if logins.Load() != 1 {
+		t.Errorf("expected concurrent lookups to share 1 login, got %d", logins.Load())
+	}
+	for i, c := range clients {
+		if c != clients[0] {
+			t.Errorf("caller %d got a different client", i)
+		}
+	}
+}
+
+func TestGetOrCreate_SharedLoginOutlivesFirstCaller(t *testing.T) {
+	var logins atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		if r.Context().Err() != nil {
+			return
+		}
+		logins.Add(1)
+		http.SetCookie(w, &http.Cookie{Name: "SID", Value: "sid"})
+		_, _ = w.Write([]byte("Ok."))
+	}))
+	t.Cleanup(server.Close)
+	pool := NewClientPool(5 * time.Minute)
+
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := pool.GetOrCreate(firstCtx, server.URL, "admin", "pass")
+		firstErr <- err
+	}()
+	// Let the first caller start the login before the second one joins it
+	time.Sleep(50 * time.Millisecond)
+	secondErr := make(chan error, 1)
+	go func() {
+		_, err := pool.GetOrCreate(context.Background(), server.URL, "admin", "pass")
+		secondErr <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	cancelFirst()
+	if err := <-firstErr; err != context.Canceled {
+		t.Errorf("expected the cancelled caller to get context.Canceled, got %v", err)
+	}
+	close(release)
+	if err := <-secondErr; err != nil {
+		t.Fatalf("expected the shared login to survive the first caller, got %v", err)
+	}
+	if logins.Load() != 1 {
+		t.Errorf("expected 1 login, got %d", logins.Load())
+	}
+	if _, ok := pool.Cached(server.URL); !ok {
+		t.Error("expected the session to be cached")
+	}
+}
+
+func TestGetOrCreateWithToken(t *testing.T) {
+	var logins atomic.Int32
+	server := newLoginServer(t, &logins)
+	pool := NewClientPool(5 * time.Minute)
+
+	c1, err := pool.GetOrCreateWithToken(context.Background(), server.URL, "token-1")
+	if err != nil {
+		t.Fatalf("GetOrCreateWithToken returned error: %v", err)
+	}
+	c2, err := pool.GetOrCreateWithToken(context.Background(), server.URL, "token-1")
+	if err != nil {
+		t.Fatalf("GetOrCreateWithToken returned error: %v", err)
+	}
+	if c1 != c2 {
+		t.Error("expected the client of the same token to be reused")
+	}
+
+	rotated, err := pool.GetOrCreateWithToken(context.Background(), server.URL, "token-2")
+	if err != nil {
+		t.Fatalf("GetOrCreateWithToken returned error: %v", err)
+	}
+	if rotated == c1 {
+		t.Error("expected a rotated token to get its own client")
+	}
+	if logins.Load() != 0 {
+		t.Errorf("expected no login with bearer tokens, got %d", logins.Load())
+	}
+	if hashToken(server.URL, "token-1") == hashCredentials(server.URL, "", "token-1") {
+		t.Error("expected token keys apart from credential keys")
+	}
+}
+
+func TestGetOrCreate_ProactiveRefresh(t *testing.T) {
+	var logins atomic.Int32
+	server := newLoginServer(t, &logins)
+	pool := NewClientPoolWithMaxSessionAge(5*time.Minute, 10*time.Minute)
+
+	c1, err := pool.GetOrCreate(context.Background(), server.URL, "admin", "pass")
+	if err != nil {
+		t.Fatalf("GetOrCreate returned error: %v", err)
+	}
+
+	// Age the session past maxSessionAge while keeping it recently used
+	credHash := hashCredentials(server.URL, "admin", "pass")
+	pool.mu.Lock()
+	pool.clients[credHash].createdAt = time.Now().Add(-time.Hour)
+	pool.clients[credHash].lastUsed = time.Now()
+	pool.mu.Unlock()
+
+	c2, err := pool.GetOrCreate(context.Background(), server.URL, "admin", "pass")
+	if err != nil {
+		t.Fatalf("GetOrCreate returned error: %v", err)
+	}
+
+	if c1 == c2 {
+		t.Error("expected a new client after the session exceeded maxSessionAge")
+	}
+	if logins.Load() != 2 {
+		t.Errorf("expected 2 logins, got %d", logins.Load())
+	}
+	if sid := c2.(*Client).sessionID; sid != "sid-2" {
+		t.Errorf("expected refreshed session ID sid-2, got %s", sid)
+	}
+}
+
+func TestGetOrCreate_NoRefreshWhenDisabled(t *testing.T) {
+	var logins atomic.Int32
+	server := newLoginServer(t, &logins)
+	pool := NewClientPool(5 * time.Minute)
+
+	if _, err := pool.GetOrCreate(context.Background(), server.URL, "admin", "pass"); err != nil {
+		t.Fatalf("GetOrCreate returned error: %v", err)
+	}
+	credHash := hashCredentials(server.URL, "admin", "pass")
+	pool.mu.Lock()
+	pool.clients[credHash].createdAt = time.Now().Add(-24 * time.Hour)
+	pool.mu.Unlock()
+
+	if _, err := pool.GetOrCreate(context.Background(), server.URL, "admin", "pass"); err != nil {
+		t.Fatalf("GetOrCreate returned error: %v", err)
+	}
+	if logins.Load() != 1 {
+		t.Errorf("expected 1 login with proactive refresh disabled, got %d", logins.Load())
+	}
+}
+
+func TestNewClientPoolWithOptions(t *testing.T) {
+	pool := NewClientPoolWithOptions(ClientPoolOptions{TTL: 2 * time.Minute, MaxSessionAge: time.Hour, MaxSize: 3})
+	if pool.TTL() != 2*time.Minute {
+		t.Errorf("expected TTL 2m, got %v", pool.TTL())
+	}
+	if pool.maxSessionAge != time.Hour {
+		t.Errorf("expected max session age 1h, got %v", pool.maxSessionAge)
+	}
+	if pool.MaxSize() != 3 {
+		t.Errorf("expected max size 3, got %d", pool.MaxSize())
+	}
+}
+
+func TestMinSessionTimeout(t *testing.T) {
+	tests := []struct {
+		name string
+		opts ClientPoolOptions
+		want time.Duration
+	}{
+		{"TTL only", ClientPoolOptions{TTL: 5 * time.Minute}, 6 * time.Minute},
+		{"max session age above TTL", ClientPoolOptions{TTL: time.Minute, MaxSessionAge: 30 * time.Minute}, 31 * time.Minute},
+		{"max session age below TTL", ClientPoolOptions{TTL: 10 * time.Minute, MaxSessionAge: 5 * time.Minute}, 11 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewClientPoolWithOptions(tt.opts).MinSessionTimeout(); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCached_ReturnsLiveSessionForURL(t *testing.T) {
+	var logins atomic.Int32
+	server := newLoginServer(t, &logins)
+	other := newLoginServer(t, &logins)
+	pool := NewClientPoolWithMaxSessionAge(5*time.Minute, time.Hour)
+
+	if _, ok := pool.Cached(server.URL); ok {
+		t.Fatal("expected no cached client before any login")
+	}
+	client, err := pool.GetOrCreate(context.Background(), server.URL, "admin", "pass")
+	if err != nil {
+		t.Fatalf("GetOrCreate returned error: %v", err)
+	}
+	if _, err := pool.GetOrCreate(context.Background(), other.URL, "admin", "pass"); err != nil {
+		t.Fatalf("GetOrCreate returned error: %v", err)
+	}
+
+	cached, ok := pool.Cached(server.URL)
+	if !ok || cached != client {
+		t.Errorf("expected the client logged in to %s, got %v", server.URL, cached)
+	}
+
+	pool.maxSessionAge = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	if _, ok := pool.Cached(server.URL); ok {
+		t.Error("expected no client for an expired session")
+	}
+}
+
+func TestGetOrCreate_EvictsLeastRecentlyUsed(t *testing.T) {
+	var logins atomic.Int32
+	server := newLoginServer(t, &logins)
+	pool := NewClientPoolWithOptions(ClientPoolOptions{TTL: 5 * time.Minute, MaxSize: 2})
+
+	for _, user := range []string{"alice", "bob"} {
+		if _, err := pool.GetOrCreate(context.Background(), server.URL, user, "pass"); err != nil {
+			t.Fatalf("GetOrCreate returned error: %v", err)
+		}
+	}
+	// Make alice the least recently used entry
+	pool.mu.Lock()
+	pool.clients[hashCredentials(server.URL, "alice", "pass")].lastUsed = time.Now().Add(-time.Minute)
+	pool.mu.Unlock()
+
+	evictionsBefore := counterValue(t, poolEvictions)
+	if _, err := pool.GetOrCreate(context.Background(), server.URL, "carol", "pass"); err != nil {
+		t.Fatalf("GetOrCreate returned error: %v", err)
+	}
+
+	if pool.Len() != 2 {
+		t.Errorf("expected pool capped at 2 entries, got %d", pool.Len())
+	}
+	if _, exists := pool.clients[hashCredentials(server.URL, "alice", "pass")]; exists {
+		t.Error("expected the least recently used entry to be evicted")
+	}
+	if _, exists := pool.clients[hashCredentials(server.URL, "bob", "pass")]; !exists {
+		t.Error("expected the recently used entry to be kept")
+	}
+	if got := counterValue(t, poolEvictions) - evictionsBefore; got != 1 {
+		t.Errorf("expected 1 eviction, got %v", got)
+	}
+}
+
+func TestGetOrCreate_Metrics(t *testing.T) {
+	var logins atomic.Int32
+	server := newLoginServer(t, &logins)
+	pool := NewClientPool(5 * time.Minute)
+
+	hitsBefore := counterValue(t, poolHits)
+	missesBefore := counterValue(t, poolMisses)
+
+	for range 3 {
+		if _, err := pool.GetOrCreate(context.Background(), server.URL, "admin", "pass"); err != nil {
+			t.Fatalf("GetOrCreate returned error: %v", err)
+		}
+	}
+
+	if got := counterValue(t, poolMisses) - missesBefore; got != 1 {
+		t.Errorf("expected 1 miss, got %v", got)
+	}
+	if got := counterValue(t, poolHits) - hitsBefore; got != 2 {
+		t.Errorf("expected 2 hits, got %v", got)
+	}
+	if got := gaugeValue(t, poolSize); got != 1 {
+		t.Errorf("expected pool size gauge 1, got %v", got)
+	}
+
+	pool.Remove(hashCredentials(server.URL, "admin", "pass"))
+	if got := gaugeValue(t, poolSize); got != 0 {
+		t.Errorf("expected pool size gauge 0 after remove, got %v", got)
+	}
+}
+
+func TestPoolSizeGauge_TracksEntries(t *testing.T) {
+	var logins atomic.Int32
+	server := newLoginServer(t, &logins)
+	pool := NewClientPool(5 * time.Minute)
+
+	for _, username := range []string{"alice", "bob"} {
+		if _, err := pool.GetOrCreate(context.Background(), server.URL, username, "pass"); err != nil {
+			t.Fatalf("GetOrCreate returned error: %v", err)
+		}
+	}
+	if got := gaugeValue(t, poolSize); got != 2 {
+		t.Errorf("expected pool size gauge 2, got %v", got)
+	}
+
+	// Expire alice so that Cleanup drops it
+	pool.mu.Lock()
+	pool.clients[hashCredentials(server.URL, "alice", "pass")].lastUsed = time.Now().Add(-time.Hour)
+	pool.mu.Unlock()
+	pool.Cleanup()
+	if got := gaugeValue(t, poolSize); got != 1 {
+		t.Errorf("expected pool size gauge 1 after cleanup, got %v", got)
+	}
+
+	pool.Remove(hashCredentials(server.URL, "bob", "pass"))
+	if got := gaugeValue(t, poolSize); got != 0 {
+		t.Errorf("expected pool size gauge 0 after remove, got %v", got)
+	}
+}
+
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	t.Helper()
+	m := &dto.Metric{}
+	if err := c.Write(m); err != nil {
+		t.Fatalf("failed to read counter: %v", err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+func gaugeValue(t *testing.T, g prometheus.Gauge) float64 {
+	t.Helper()
+	m := &dto.Metric{}
+	if err := g.Write(m); err != nil {
+		t.Fatalf("failed to read gauge: %v", err)
+	}
+	return m.GetGauge().GetValue()
+}
+
+func TestNewClientPoolWithOptions_UserAgent(t *testing.T) {
+	var userAgent atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent.Store(r.UserAgent())
+		http.SetCookie(w, &http.Cookie{Name: "SID", Value: "sid"})
+		_, _ = w.Write([]byte("Ok."))
+	}))
+	t.Cleanup(server.Close)
+
+	pool := NewClientPoolWithOptions(ClientPoolOptions{TTL: 5 * time.Minute, UserAgent: "media-stack/1.0"})
+	if _, err := pool.GetOrCreate(context.Background(), server.URL, "admin", "pass"); err != nil {
+		t.Fatalf("GetOrCreate returned error: %v", err)
+	}
+	if got := userAgent.Load(); got != "media-stack/1.0" {
+		t.Errorf("expected User-Agent media-stack/1.0 on login, got %v", got)
+	}
+}
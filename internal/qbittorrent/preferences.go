@@ -0,0 +1,118 @@
+package qbittorrent
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// Preference keys of the peer discovery mechanisms, as named by the WebUI API
+const (
+	PreferenceDHT = "dht"
+	PreferencePeX = "pex"
+	PreferenceLSD = "lsd"
+)
+
+// Preference keys of the torrent queue, as named by the WebUI API.
+// The active limits are only enforced while queueing is enabled.
+const (
+	PreferenceQueueingEnabled    = "queueing_enabled"
+	PreferenceMaxActiveDownloads = "max_active_downloads"
+	PreferenceMaxActiveUploads   = "max_active_uploads"
+	PreferenceMaxActiveTorrents  = "max_active_torrents"
+)
+
+// Preference keys of the "keep incomplete torrents in" setting, as named by the WebUI API
+const (
+	PreferenceTempPathEnabled = "temp_path_enabled"
+	PreferenceTempPath        = "temp_path"
+)
+
+// Preference keys of the IP filter, as named by the WebUI API
+const (
+	PreferenceIPFilterEnabled = "ip_filter_enabled"
+	PreferenceIPFilterPath    = "ip_filter_path"
+)
+
+// Preference keys of the alternative WebUI, as named by the WebUI API
+const (
+	PreferenceAlternativeWebUIEnabled = "alternative_webui_enabled"
+	PreferenceAlternativeWebUIPath    = "alternative_webui_path"
+)
+
+// Preference keys of the alternative speed limits scheduler, as named by the WebUI API
+const (
+	PreferenceSchedulerEnabled = "scheduler_enabled"
+	PreferenceScheduleFromHour = "schedule_from_hour"
+	PreferenceScheduleFromMin  = "schedule_from_min"
+	PreferenceScheduleToHour   = "schedule_to_hour"
+	PreferenceScheduleToMin    = "schedule_to_min"
+	PreferenceSchedulerDays    = "scheduler_days"
+)
+
+// SchedulerDays maps the days the scheduler applies to onto the values of the scheduler_days preference
+var SchedulerDays = map[string]int{
+	"EveryDay":  0,
+	"Weekdays":  1,
+	"Weekends":  2,
+	"Monday":    3,
+	"Tuesday":   4,
+	"Wednesday": 5,
+	"Thursday":  6,
+	"Friday":    7,
+	"Saturday":  8,
+	"Sunday":    9,
+}
+
+// Preference keys of the WebUI credentials, as named by the WebUI API. The password is only ever written
+const (
+	PreferenceWebUIUsername = "web_ui_username"
+	PreferenceWebUIPassword = "web_ui_password"
+)
+
+// PreferenceWebUISessionTimeout is the WebUI session timeout in seconds, as named by the WebUI API
+const PreferenceWebUISessionTimeout = "web_ui_session_timeout"
+
+// Preference keys of the connection limits every torrent of the instance is capped to, as named by the WebUI API
+const (
+	PreferenceMaxConnectionsPerTorrent = "max_connec_per_torrent"
+	PreferenceMaxUploadsPerTorrent     = "max_uploads_per_torrent"
+)
+
+// DiffPreferences returns the desired preferences whose value differs from the current one.
+// Keys that are not desired are ignored, so preferences managed out-of-band are never touched.
+// Values are compared through their JSON representation, so 1 and 1.0 are equal.
+func DiffPreferences(current, desired map[string]any) map[string]any {
+	drifted := make(map[string]any)
+	for key, want := range desired {
+		got, ok := current[key]
+		if !ok || !jsonEqual(got, want) {
+			drifted[key] = want
+		}
+	}
+	return drifted
+}
+
+// PreferenceKeys returns the sorted keys of the preferences, to log them without their values
+func PreferenceKeys(preferences map[string]any) []string {
+	keys := make([]string, 0, len(preferences))
+	for key := range preferences {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func jsonEqual(a, b any) bool {
+	rawA, errA := json.Marshal(a)
+	rawB, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+
+	var normA, normB any
+	if json.Unmarshal(rawA, &normA) != nil || json.Unmarshal(rawB, &normB) != nil {
+		return false
+	}
+	return reflect.DeepEqual(normA, normB)
+}
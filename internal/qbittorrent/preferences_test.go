@@ -0,0 +1,37 @@
+package qbittorrent
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffPreferences(t *testing.T) {
+	current := map[string]any{
+		"max_active_downloads": float64(3),
+		"dht":                  true,
+		"save_path":            "/downloads",
+		"web_ui_port":          float64(8080),
+	}
+	desired := map[string]any{
+		"max_active_downloads": 5,
+		"dht":                  true,
+		"save_path":            "/downloads",
+		"queueing_enabled":     true,
+	}
+
+	got := DiffPreferences(current, desired)
+	want := map[string]any{
+		"max_active_downloads": 5,
+		"queueing_enabled":     true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected drifted preferences %v, got %v", want, got)
+	}
+}
+
+func TestDiffPreferences_NumericEquality(t *testing.T) {
+	got := DiffPreferences(map[string]any{"max_ratio": float64(2)}, map[string]any{"max_ratio": 2})
+	if len(got) != 0 {
+		t.Errorf("expected no drift between 2 and 2.0, got %v", got)
+	}
+}
@@ -0,0 +1,22 @@
+package qbittorrent
+
+import (
+	"fmt"
+	"net/http"
+
+	"golang.org/x/time/rate"
+)
+
+// rateLimitTransport spaces the outbound requests towards a qBittorrent instance with a token bucket
+// shared by every client of its URL. Requests over the limit wait for a token, until their context ends.
+type rateLimitTransport struct {
+	next    http.RoundTripper
+	limiter *rate.Limiter
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRateLimited, err)
+	}
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,118 @@
+package qbittorrent
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_SpacesRequests(t *testing.T) {
+	var mu sync.Mutex
+	var arrivals []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		arrivals = append(arrivals, time.Now())
+		mu.Unlock()
+		_, _ = w.Write([]byte("v5.1.4"))
+	}))
+	t.Cleanup(server.Close)
+
+	pool := NewClientPoolWithOptions(ClientPoolOptions{
+		TTL:       time.Minute,
+		RateLimit: RateLimitOptions{QPS: 20, Burst: 1},
+	})
+	client := NewClient(server.URL)
+	client.SetRateLimiter(pool.Limiter(server.URL))
+
+	for range 4 {
+		if _, err := client.GetAppVersion(context.Background()); err != nil {
+			t.Fatalf("GetAppVersion returned error: %v", err)
+		}
+	}
+
+	// At 20 requests per second with no burst, consecutive requests are 50ms apart
+	for i := 1; i < len(arrivals); i++ {
+		if gap := arrivals[i].Sub(arrivals[i-1]); gap < 40*time.Millisecond {
+			t.Errorf("request %d sent %v after the previous one, expected about 50ms", i, gap)
+		}
+	}
+}
+
+func TestRateLimiter_WaitHonoursContext(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK, "v5.1.4")
+	pool := NewClientPoolWithOptions(ClientPoolOptions{
+		TTL:       time.Minute,
+		RateLimit: RateLimitOptions{QPS: 0.1, Burst: 1},
+	})
+	client := NewClient(server.URL)
+	client.SetRateLimiter(pool.Limiter(server.URL))
+
+	if _, err := client.GetAppVersion(context.Background()); err != nil {
+		t.Fatalf("GetAppVersion returned error: %v", err)
+	}
+
+	// The next token is 10s away: a shorter deadline fails without contacting qBittorrent
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := client.GetAppVersion(ctx)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the wait to end with the context, took %v", elapsed)
+	}
+	if len(*requests) != 1 {
+		t.Errorf("expected a single request to reach qBittorrent, got %d", len(*requests))
+	}
+}
+
+func TestGetOrCreate_SharesRateLimiterPerURL(t *testing.T) {
+	pool := NewClientPoolWithOptions(ClientPoolOptions{
+		TTL:       time.Minute,
+		RateLimit: RateLimitOptions{QPS: 5},
+	})
+	limiter := pool.Limiter("http://qbittorrent:8080")
+	if limiter == nil || limiter.Burst() != 1 {
+		t.Fatalf("expected a limiter with a minimum burst of 1, got %+v", limiter)
+	}
+	if pool.Limiter("http://qbittorrent:8080") != limiter {
+		t.Error("expected the clients of a URL to share its limiter")
+	}
+	if pool.Limiter("http://other:8080") == limiter {
+		t.Error("expected each URL to get its own limiter")
+	}
+
+	disabled := NewClientPoolWithOptions(ClientPoolOptions{TTL: time.Minute})
+	if limiter := disabled.Limiter("http://qbittorrent:8080"); limiter != nil {
+		t.Errorf("expected no limiter without a QPS, got %+v", limiter)
+	}
+}
+
+func TestCleanup_PrunesLimitersOfUnusedURLs(t *testing.T) {
+	pool := NewClientPoolWithOptions(ClientPoolOptions{
+		TTL:       time.Second,
+		RateLimit: RateLimitOptions{QPS: 5},
+	})
+	pool.clients["live"] = &poolEntry{client: &Client{}, url: "http://live:8080", lastUsed: time.Now()}
+	pool.clients["gone"] = &poolEntry{client: &Client{}, url: "http://gone:8080", lastUsed: time.Now().Add(-time.Minute)}
+	live := pool.Limiter("http://live:8080")
+	pool.Limiter("http://gone:8080")
+
+	pool.Cleanup()
+	if pool.Limiter("http://live:8080") != live {
+		t.Error("expected the limiter of a cached URL to be kept")
+	}
+	if _, ok := pool.limiters["http://gone:8080"]; ok {
+		t.Error("expected the limiter of an expired URL to be pruned")
+	}
+
+	pool.Remove("live")
+	if len(pool.limiters) != 0 {
+		t.Errorf("expected every limiter to be pruned, got %v", pool.limiters)
+	}
+}
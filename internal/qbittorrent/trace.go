@@ -0,0 +1,163 @@
+package qbittorrent
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// redacted replaces the value of secrets in trace logs
+const redacted = "[REDACTED]"
+
+// sensitiveParams are the query and form parameters never written to trace logs
+var sensitiveParams = map[string]bool{
+	"username": true,
+	"password": true,
+}
+
+// sensitivePreferenceWords mark the preferences never written to trace logs from the json field of
+// setPreferences, e.g. proxy_password, mail_notification_password, dyndns_password or web_ui_api_key
+var sensitivePreferenceWords = map[string]bool{
+	"password": true,
+	"passwd":   true,
+	"secret":   true,
+	"key":      true,
+	"token":    true,
+}
+
+// traceTransport logs every request towards a qBittorrent instance with its outcome and duration.
+// It logs at Info level on a dedicated logger, so tracing does not depend on the controller log level.
+// Credentials, secret preferences and session cookies are redacted; multipart bodies, e.g. .torrent uploads, are not logged.
+type traceTransport struct {
+	next   http.RoundTripper
+	logger logr.Logger
+}
+
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	keysAndValues := []any{
+		"method", req.Method,
+		"path", req.URL.Path,
+	}
+	if query := req.URL.Query(); len(query) > 0 {
+		keysAndValues = append(keysAndValues, "query", redactValues(query))
+	}
+	if form := requestForm(req); len(form) > 0 {
+		keysAndValues = append(keysAndValues, "form", redactValues(form))
+	}
+	if cookies := req.Cookies(); len(cookies) > 0 {
+		keysAndValues = append(keysAndValues, "cookies", redactCookies(cookies))
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	keysAndValues = append(keysAndValues, "duration", time.Since(start).String())
+
+	if err != nil {
+		t.logger.Info("qBittorrent API call failed", append(keysAndValues, "error", err.Error())...)
+		return resp, err
+	}
+	keysAndValues = append(keysAndValues, "status", resp.StatusCode)
+	if cookies := resp.Cookies(); len(cookies) > 0 {
+		keysAndValues = append(keysAndValues, "setCookies", redactCookies(cookies))
+	}
+	t.logger.Info("qBittorrent API call", keysAndValues...)
+	return resp, err
+}
+
+// requestForm returns the URL-encoded form of the request body without consuming it,
+// or nil for other bodies and for bodies that cannot be read again
+func requestForm(req *http.Request) url.Values {
+	if req.Body == nil || req.GetBody == nil {
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/x-www-form-urlencoded" {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = body.Close() }()
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return nil
+	}
+	form, err := url.ParseQuery(string(raw))
+	if err != nil {
+		return nil
+	}
+	return form
+}
+
+// redactValues encodes the parameters, replacing the value of the sensitive ones
+func redactValues(values url.Values) string {
+	clean := make(url.Values, len(values))
+	for key, vals := range values {
+		if sensitiveParams[strings.ToLower(key)] {
+			clean[key] = []string{redacted}
+			continue
+		}
+		if key == "json" {
+			clean[key] = redactPreferences(vals)
+			continue
+		}
+		clean[key] = vals
+	}
+	// Keep the brackets readable, the encoding only matters to the server
+	encoded, err := url.QueryUnescape(clean.Encode())
+	if err != nil {
+		return clean.Encode()
+	}
+	return encoded
+}
+
+// redactPreferences replaces the value of the sensitive preferences in JSON objects,
+// or the whole value when it cannot be decoded
+func redactPreferences(values []string) []string {
+	clean := make([]string, 0, len(values))
+	for _, value := range values {
+		var preferences map[string]any
+		if err := json.Unmarshal([]byte(value), &preferences); err != nil {
+			clean = append(clean, redacted)
+			continue
+		}
+		for key := range preferences {
+			if isSensitivePreference(key) {
+				preferences[key] = redacted
+			}
+		}
+		encoded, err := json.Marshal(preferences)
+		if err != nil {
+			clean = append(clean, redacted)
+			continue
+		}
+		clean = append(clean, string(encoded))
+	}
+	return clean
+}
+
+// isSensitivePreference reports whether a preference holds a secret, from the words of its key
+func isSensitivePreference(key string) bool {
+	for _, word := range strings.Split(strings.ToLower(key), "_") {
+		if sensitivePreferenceWords[word] {
+			return true
+		}
+	}
+	return false
+}
+
+// redactCookies lists the cookie names, every cookie value being a session secret
+func redactCookies(cookies []*http.Cookie) string {
+	names := make([]string, 0, len(cookies))
+	for _, cookie := range cookies {
+		names = append(names, cookie.Name+"="+redacted)
+	}
+	return strings.Join(names, "; ")
+}
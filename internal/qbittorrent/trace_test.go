@@ -0,0 +1,161 @@
+package qbittorrent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+)
+
+// newTraceRecorder returns a logger collecting the formatted trace lines
+func newTraceRecorder() (logr.Logger, func() []string) {
+	var mu sync.Mutex
+	var lines []string
+	logger := funcr.New(func(prefix, args string) {
+		mu.Lock()
+		defer mu.Unlock()
+		lines = append(lines, args)
+	}, funcr.Options{})
+	return logger, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), lines...)
+	}
+}
+
+func TestTraceLogger_RedactsCredentialsAndCookies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/auth/login" {
+			http.SetCookie(w, &http.Cookie{Name: "SID", Value: "secret-session-id"})
+		}
+		_, _ = w.Write([]byte("[]"))
+	}))
+	t.Cleanup(server.Close)
+
+	logger, lines := newTraceRecorder()
+	client := NewClient(server.URL)
+	client.SetTraceLogger(logger)
+
+	if err := client.Login(context.Background(), "admin", "hunter2"); err != nil {
+		t.Fatalf("Login returned error: %v", err)
+	}
+	if _, err := client.GetTorrentsInfo(context.Background()); err != nil {
+		t.Fatalf("GetTorrentsInfo returned error: %v", err)
+	}
+
+	got := lines()
+	if len(got) != 2 {
+		t.Fatalf("expected a trace line per request, got %d: %v", len(got), got)
+	}
+	for _, secret := range []string{"admin", "hunter2", "secret-session-id"} {
+		for _, line := range got {
+			if strings.Contains(line, secret) {
+				t.Errorf("trace line leaks %q: %s", secret, line)
+			}
+		}
+	}
+
+	for _, want := range []string{`"method"="POST"`, `"path"="/api/v2/auth/login"`, `"status"=200`, `"duration"=`,
+		"password=" + redacted, "username=" + redacted, "SID=" + redacted} {
+		if !strings.Contains(got[0], want) {
+			t.Errorf("expected %s in the login trace: %s", want, got[0])
+		}
+	}
+	for _, want := range []string{`"method"="GET"`, `"path"="/api/v2/torrents/info"`, `"cookies"="SID=` + redacted} {
+		if !strings.Contains(got[1], want) {
+			t.Errorf("expected %s in the torrents trace: %s", want, got[1])
+		}
+	}
+}
+
+func TestTraceLogger_RedactsSecretPreferences(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(server.Close)
+
+	logger, lines := newTraceRecorder()
+	client := NewClient(server.URL)
+	client.SetTraceLogger(logger)
+
+	secrets := map[string]string{
+		"web_ui_password":            "hunter2",
+		"proxy_password":             "proxy-secret",
+		"mail_notification_password": "mail-secret",
+		"dyndns_password":            "dyndns-secret",
+		"web_ui_api_key":             "api-key-secret",
+	}
+	preferences := map[string]any{
+		"web_ui_username":      "admin",
+		"max_active_downloads": 3,
+	}
+	for key, value := range secrets {
+		preferences[key] = value
+	}
+	if err := client.SetPreferences(context.Background(), preferences); err != nil {
+		t.Fatalf("SetPreferences returned error: %v", err)
+	}
+
+	got := lines()
+	if len(got) != 1 {
+		t.Fatalf("expected a trace line, got %v", got)
+	}
+	for key, secret := range secrets {
+		if strings.Contains(got[0], secret) {
+			t.Errorf("trace line leaks %s: %s", key, got[0])
+		}
+		// funcr escapes the quotes of the JSON form value
+		if want := `\"` + key + `\":\"` + redacted + `\"`; !strings.Contains(got[0], want) {
+			t.Errorf("expected %s in the trace: %s", want, got[0])
+		}
+	}
+	for _, want := range []string{`\"web_ui_username\":\"admin\"`, `\"max_active_downloads\":3`} {
+		if !strings.Contains(got[0], want) {
+			t.Errorf("expected %s in the trace: %s", want, got[0])
+		}
+	}
+}
+
+func TestTraceLogger_LogsTransportErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	logger, lines := newTraceRecorder()
+	client := NewClient(server.URL)
+	client.SetTraceLogger(logger)
+
+	if _, err := client.GetTorrentsInfo(context.Background()); err == nil {
+		t.Fatal("expected an error from a closed server")
+	}
+	got := lines()
+	if len(got) != 1 || !strings.Contains(got[0], "qBittorrent API call failed") || !strings.Contains(got[0], `"error"=`) {
+		t.Errorf("expected a failed call trace line, got %v", got)
+	}
+}
+
+func TestClientPool_TraceLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "SID", Value: "sid"})
+	}))
+	t.Cleanup(server.Close)
+
+	logger, lines := newTraceRecorder()
+	pool := NewClientPoolWithOptions(ClientPoolOptions{TTL: 0, TraceLogger: logger})
+	if _, err := pool.GetOrCreate(context.Background(), server.URL, "admin", "pass"); err != nil {
+		t.Fatalf("GetOrCreate returned error: %v", err)
+	}
+	if len(lines()) != 1 {
+		t.Errorf("expected the pool clients to be traced, got %v", lines())
+	}
+
+	untraced := NewClientPoolWithOptions(ClientPoolOptions{})
+	if _, err := untraced.GetOrCreate(context.Background(), server.URL, "admin", "pass"); err != nil {
+		t.Fatalf("GetOrCreate returned error: %v", err)
+	}
+	if len(lines()) != 1 {
+		t.Errorf("expected no trace without a trace logger, got %v", lines())
+	}
+}
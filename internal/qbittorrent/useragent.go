@@ -0,0 +1,30 @@
+package qbittorrent
+
+import "net/http"
+
+// Version is the operator version reported in the default User-Agent. It is set at build time with
+// -ldflags "-X github.com/guidonguido/qbittorrent-operator/internal/qbittorrent.Version=<version>"
+var Version = "dev"
+
+// DefaultUserAgent identifies the operator to qBittorrent and to the proxies in front of it
+func DefaultUserAgent() string {
+	return "qbittorrent-operator/" + Version
+}
+
+// userAgentTransport sets the User-Agent header on every outbound request,
+// including the ones built by helpers such as http.Client.PostForm.
+// A header already set by an outer userAgentTransport is kept, so the latest override wins
+type userAgentTransport struct {
+	next      http.RoundTripper
+	userAgent string
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") != "" {
+		return t.next.RoundTrip(req)
+	}
+	// A RoundTripper must not modify the caller's request
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+	return t.next.RoundTrip(req)
+}
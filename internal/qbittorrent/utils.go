@@ -1,13 +1,18 @@
 package qbittorrent
 
 import (
+	"encoding/base32"
+	"encoding/hex"
 	"fmt"
 	"strings"
 )
 
+// base32HashLength is the length of a base32-encoded v1 infohash, as allowed in magnet URIs
+const base32HashLength = 32
+
 func GetTorrentHash(magnetURI string) (string, error) {
-	// Find the btih: prefix
-	btihIndex := strings.Index(magnetURI, "btih:")
+	// Find the btih: prefix, whatever its casing
+	btihIndex := strings.Index(strings.ToLower(magnetURI), "btih:")
 	if btihIndex == -1 {
 		return "", fmt.Errorf("'btih:' not found")
 	}
@@ -21,8 +26,19 @@ func GetTorrentHash(magnetURI string) (string, error) {
 	// Hash ends at the next '&' or end of string
 	hashEnd := strings.Index(magnetURI[hashStart:], "&")
 	if hashEnd == -1 {
-		return magnetURI[hashStart:], nil
+		return NormalizeTorrentHash(magnetURI[hashStart:]), nil
 	}
 
-	return magnetURI[hashStart : hashStart+hashEnd], nil
+	return NormalizeTorrentHash(magnetURI[hashStart : hashStart+hashEnd]), nil
+}
+
+// NormalizeTorrentHash returns the canonical lowercase hex form qBittorrent reports,
+// so hashes from differently written magnets compare equal
+func NormalizeTorrentHash(hash string) string {
+	if len(hash) == base32HashLength {
+		if decoded, err := base32.StdEncoding.DecodeString(strings.ToUpper(hash)); err == nil {
+			return hex.EncodeToString(decoded)
+		}
+	}
+	return strings.ToLower(hash)
 }
@@ -0,0 +1,39 @@
+package qbittorrent
+
+import "testing"
+
+func TestGetTorrentHash(t *testing.T) {
+	const want = "dd8255ecdc7ca55fb0bbf81323d87062db1f6d1c"
+
+	tests := []struct {
+		name   string
+		magnet string
+	}{
+		{"lowercase hex", "magnet:?xt=urn:btih:dd8255ecdc7ca55fb0bbf81323d87062db1f6d1c&dn=Sintel"},
+		{"uppercase hex", "magnet:?xt=urn:btih:DD8255ECDC7CA55FB0BBF81323D87062DB1F6D1C&dn=Sintel"},
+		{"uppercase prefix", "magnet:?xt=urn:BTIH:Dd8255ecdc7ca55fb0bbf81323d87062db1f6d1c"},
+		{"extra trackers", "magnet:?xt=urn:btih:DD8255ECDC7CA55FB0BBF81323D87062DB1F6D1C&tr=udp%3A%2F%2Ftracker.example.org%3A1337"},
+		{"base32", "magnet:?xt=urn:btih:3WBFL3G4PSSV7MF37AJSHWDQMLNR63I4&dn=Sintel"},
+		{"lowercase base32", "magnet:?xt=urn:btih:3wbfl3g4pssv7mf37ajshwdqmlnr63i4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetTorrentHash(tt.magnet)
+			if err != nil {
+				t.Fatalf("GetTorrentHash returned error: %v", err)
+			}
+			if got != want {
+				t.Errorf("expected hash %s, got %s", want, got)
+			}
+		})
+	}
+}
+
+func TestGetTorrentHash_Invalid(t *testing.T) {
+	for _, magnet := range []string{"magnet:?dn=Sintel", "magnet:?xt=urn:btih:"} {
+		if _, err := GetTorrentHash(magnet); err == nil {
+			t.Errorf("expected error for %q", magnet)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package qbittorrent
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Minimum WebUI API versions required by the endpoints used by the operator
+const (
+	// torrents/renameFile takes oldPath/newPath since API 2.8.0 (qBittorrent 4.3.3)
+	MinAPIVersionRenameFile = "2.8.0"
+	// torrents/pause and torrents/resume became torrents/stop and torrents/start in API 2.11.0 (qBittorrent 5.0)
+	MinAPIVersionStopStart = "2.11.0"
+	// torrents/add takes stopCondition since API 2.8.18 (qBittorrent 4.5.0)
+	MinAPIVersionStopCondition = "2.8.18"
+	// torrents/add takes addToTopOfQueue since API 2.8.18 (qBittorrent 4.5.0)
+	MinAPIVersionAddToTopOfQueue = "2.8.18"
+	// torrents/export was added in API 2.8.14 (qBittorrent 4.5.0)
+	MinAPIVersionExport = "2.8.14"
+)
+
+// DefaultMinQBittorrentVersion is the oldest qBittorrent release the operator supports:
+// the config-init container writes PBKDF2 password hashes, which qBittorrent reads since 4.2.0
+const DefaultMinQBittorrentVersion = "4.2.0"
+
+// Capabilities describes the features available on a qBittorrent instance,
+// computed from its WebUI API version
+type Capabilities struct {
+	// RenameFile reports support for torrents/renameFile with oldPath/newPath
+	RenameFile bool
+	// StopStart reports that pausing/resuming uses torrents/stop and torrents/start
+	StopStart bool
+	// StopCondition reports support for stopping a torrent once its metadata is received
+	StopCondition bool
+	// AddToTopOfQueue reports support for adding a torrent at the top of the queue
+	AddToTopOfQueue bool
+	// Export reports support for exporting the .torrent file of a torrent
+	Export bool
+	// PerTorrentConnectionLimits reports support for per-torrent connection and upload slot limits
+	PerTorrentConnectionLimits bool
+}
+
+// CapabilitiesFor computes the capabilities of the given WebUI API version.
+// An unknown version enables every feature gated on a minimum version, see APIVersionAtLeast.
+func CapabilitiesFor(apiVersion string) Capabilities {
+	return Capabilities{
+		RenameFile:      APIVersionAtLeast(apiVersion, MinAPIVersionRenameFile),
+		StopStart:       APIVersionAtLeast(apiVersion, MinAPIVersionStopStart),
+		StopCondition:   APIVersionAtLeast(apiVersion, MinAPIVersionStopCondition),
+		AddToTopOfQueue: APIVersionAtLeast(apiVersion, MinAPIVersionAddToTopOfQueue),
+		Export:          APIVersionAtLeast(apiVersion, MinAPIVersionExport),
+		// No WebUI API version exposes them yet: the instance-wide max_connec_per_torrent
+		// and max_uploads_per_torrent preferences are applied instead
+		PerTorrentConnectionLimits: false,
+	}
+}
+
+// CompareAPIVersions compares two dotted WebUI API versions (e.g. "2.8.3").
+// It returns -1, 0 or 1 when a is lower than, equal to or greater than b.
+// Missing components are treated as zero, so "2.8" equals "2.8.0".
+func CompareAPIVersions(a, b string) (int, error) {
+	partsA, err := parseAPIVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	partsB, err := parseAPIVersion(b)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := 0; i < max(len(partsA), len(partsB)); i++ {
+		var x, y int
+		if i < len(partsA) {
+			x = partsA[i]
+		}
+		if i < len(partsB) {
+			y = partsB[i]
+		}
+		if x != y {
+			if x < y {
+				return -1, nil
+			}
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// APIVersionAtLeast reports whether version is greater than or equal to minimum.
+// An unknown (empty or unparsable) version is assumed to be recent enough,
+// so that features are not blocked before the API version has been detected.
+func APIVersionAtLeast(version, minimum string) bool {
+	cmp, err := CompareAPIVersions(version, minimum)
+	if err != nil {
+		return true
+	}
+	return cmp >= 0
+}
+
+func parseAPIVersion(version string) ([]int, error) {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if trimmed == "" {
+		return nil, fmt.Errorf("empty API version")
+	}
+
+	fields := strings.Split(trimmed, ".")
+	parts := make([]int, 0, len(fields))
+	for _, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid API version %q", version)
+		}
+		parts = append(parts, n)
+	}
+	return parts, nil
+}
+
+// AppVersionBelow reports whether the qBittorrent version (e.g. "v4.1.9") is lower than minimum.
+// Pre-release suffixes such as "beta1" are ignored. An unknown (empty or unparsable) version is
+// not below, so that nothing is reported before the version has been detected.
+func AppVersionBelow(version, minimum string) bool {
+	cmp, err := CompareAPIVersions(releaseVersion(version), releaseVersion(minimum))
+	return err == nil && cmp < 0
+}
+
+// ValidateAppVersion returns an error unless version is a dotted qBittorrent version, e.g. "4.2.0"
+func ValidateAppVersion(version string) error {
+	_, err := parseAPIVersion(releaseVersion(version))
+	return err
+}
+
+// releaseVersion strips the "v" prefix and the pre-release suffix of a version, e.g. "v5.0.0rc1" becomes "5.0.0"
+func releaseVersion(version string) string {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if end := strings.IndexFunc(version, func(r rune) bool { return r != '.' && !unicode.IsDigit(r) }); end >= 0 {
+		version = version[:end]
+	}
+	return strings.TrimSuffix(version, ".")
+}
@@ -0,0 +1,99 @@
+package qbittorrent
+
+import "testing"
+
+func TestCompareAPIVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"2.8.0", "2.8.0", 0},
+		{"2.8", "2.8.0", 0},
+		{"2.7.9", "2.8.0", -1},
+		{"2.10.0", "2.9.3", 1},
+		{"v2.9.3", "2.9.3", 0},
+	}
+	for _, tt := range tests {
+		got, err := CompareAPIVersions(tt.a, tt.b)
+		if err != nil {
+			t.Fatalf("CompareAPIVersions(%q, %q) unexpected error: %v", tt.a, tt.b, err)
+		}
+		if got != tt.want {
+			t.Errorf("CompareAPIVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	if _, err := CompareAPIVersions("2.x", "2.8.0"); err == nil {
+		t.Error("expected an error for an invalid version")
+	}
+}
+
+func TestAPIVersionAtLeast(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"2.9.3", true},
+		{"2.8.0", true},
+		{"2.7.0", false},
+		{"", true},
+		{"garbage", true},
+	}
+	for _, tt := range tests {
+		if got := APIVersionAtLeast(tt.version, MinAPIVersionRenameFile); got != tt.want {
+			t.Errorf("APIVersionAtLeast(%q, %q) = %v, want %v", tt.version, MinAPIVersionRenameFile, got, tt.want)
+		}
+	}
+}
+
+func TestAppVersionBelow(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"v4.1.9", true},
+		{"4.1.9", true},
+		{"v4.2.0", false},
+		{"v5.1.4", false},
+		{"v4.2.0beta1", false},
+		{"v4.1.0rc2", true},
+		{"5.0.0-alpha", false},
+		{"", false},
+		{"garbage", false},
+	}
+	for _, tt := range tests {
+		if got := AppVersionBelow(tt.version, DefaultMinQBittorrentVersion); got != tt.want {
+			t.Errorf("AppVersionBelow(%q, %q) = %v, want %v", tt.version, DefaultMinQBittorrentVersion, got, tt.want)
+		}
+	}
+}
+
+func TestValidateAppVersion(t *testing.T) {
+	for _, version := range []string{"4.2.0", "v4.6", "5.0.0rc1"} {
+		if err := ValidateAppVersion(version); err != nil {
+			t.Errorf("ValidateAppVersion(%q) unexpected error: %v", version, err)
+		}
+	}
+	for _, version := range []string{"", "latest", "beta"} {
+		if err := ValidateAppVersion(version); err == nil {
+			t.Errorf("ValidateAppVersion(%q) expected an error", version)
+		}
+	}
+}
+
+func TestCapabilitiesFor(t *testing.T) {
+	if !CapabilitiesFor("").RenameFile {
+		t.Error("expected every feature to be enabled for an unknown version")
+	}
+	if !CapabilitiesFor("2.9.3").RenameFile {
+		t.Error("expected RenameFile on API 2.9.3")
+	}
+	if CapabilitiesFor("2.7.0").RenameFile {
+		t.Error("expected no RenameFile on API 2.7.0")
+	}
+	for _, version := range []string{"", "2.8.3", "2.11.4"} {
+		if CapabilitiesFor(version).PerTorrentConnectionLimits {
+			t.Errorf("expected no per-torrent connection limits on API %q", version)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	torrentv1alpha1 "github.com/guidonguido/qbittorrent-operator/api/v1alpha1"
+	"github.com/guidonguido/qbittorrent-operator/internal/qbittorrent"
+)
+
+var torrentlog = logf.Log.WithName("torrent-webhook")
+
+// SetupTorrentWebhookWithManager registers the webhook for Torrent in the manager.
+// categoryFromNamespace makes the defaulter set spec.category to the namespace when unset;
+// without it the defaulter has nothing to do, so it is not served.
+func SetupTorrentWebhookWithManager(mgr ctrl.Manager, categoryFromNamespace bool) error {
+	builder := ctrl.NewWebhookManagedBy(mgr).For(&torrentv1alpha1.Torrent{}).
+		WithValidator(&TorrentCustomValidator{})
+	if categoryFromNamespace {
+		builder = builder.WithDefaulter(&TorrentCustomDefaulter{CategoryFromNamespace: true})
+	}
+	return builder.Complete()
+}
+
+// The defaulter is optional, so its failures, including the path not being served, are ignored
+// +kubebuilder:webhook:path=/mutate-torrent-qbittorrent-io-v1alpha1-torrent,mutating=true,failurePolicy=ignore,sideEffects=None,groups=torrent.qbittorrent.io,resources=torrents,verbs=create,versions=v1alpha1,name=mtorrent-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// TorrentCustomDefaulter sets defaults on new Torrents. The category is only applied when the
+// controller adds the torrent, so updates are left untouched.
+type TorrentCustomDefaulter struct {
+	// CategoryFromNamespace groups the torrents of each namespace in a category named after it,
+	// unless spec.category is set
+	CategoryFromNamespace bool
+}
+
+var _ webhook.CustomDefaulter = &TorrentCustomDefaulter{}
+
+// Default sets the defaults of a new Torrent.
+func (d *TorrentCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	torrent, ok := obj.(*torrentv1alpha1.Torrent)
+	if !ok {
+		return fmt.Errorf("expected a Torrent object but got %T", obj)
+	}
+
+	if d.CategoryFromNamespace && torrent.Spec.Category == "" {
+		// The object may omit its namespace when created through the namespaced API path
+		namespace := torrent.Namespace
+		if req, err := admission.RequestFromContext(ctx); err == nil && req.Namespace != "" {
+			namespace = req.Namespace
+		}
+		if namespace != "" {
+			torrentlog.Info("Defaulting Torrent category to its namespace", "name", torrent.GetName(), "namespace", namespace)
+			torrent.Spec.Category = namespace
+		}
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-torrent-qbittorrent-io-v1alpha1-torrent,mutating=false,failurePolicy=fail,sideEffects=None,groups=torrent.qbittorrent.io,resources=torrents,verbs=create;update,versions=v1alpha1,name=vtorrent-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// TorrentCustomValidator rejects Torrent specs combining fields in ways the controller
+// cannot honour, which the CRD schema alone does not express.
+type TorrentCustomValidator struct{}
+
+var _ webhook.CustomValidator = &TorrentCustomValidator{}
+
+// ValidateCreate validates the spec of a new Torrent.
+func (v *TorrentCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	torrent, ok := obj.(*torrentv1alpha1.Torrent)
+	if !ok {
+		return nil, fmt.Errorf("expected a Torrent object but got %T", obj)
+	}
+	torrentlog.Info("Validation for Torrent upon creation", "name", torrent.GetName(), "namespace", torrent.GetNamespace())
+
+	return nil, validateTorrent(torrent)
+}
+
+// ValidateUpdate validates the updated spec, except for Torrents being deleted so that
+// their finalizer can always be removed.
+func (v *TorrentCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	torrent, ok := newObj.(*torrentv1alpha1.Torrent)
+	if !ok {
+		return nil, fmt.Errorf("expected a Torrent object but got %T", newObj)
+	}
+	torrentlog.Info("Validation for Torrent upon update", "name", torrent.GetName(), "namespace", torrent.GetNamespace())
+
+	if !torrent.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
+	return nil, validateTorrent(torrent)
+}
+
+// ValidateDelete allows every deletion.
+func (v *TorrentCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateTorrent returns an Invalid error listing every rejected field, or nil
+func validateTorrent(torrent *torrentv1alpha1.Torrent) error {
+	specPath := field.NewPath("spec")
+	var errs field.ErrorList
+	errs = append(errs, validateClientSelection(&torrent.Spec, specPath)...)
+	errs = append(errs, validateSources(&torrent.Spec, specPath)...)
+	errs = append(errs, validateCategory(&torrent.Spec, specPath)...)
+	errs = append(errs, validateFileRenames(torrent.Spec.FileRenames, specPath.Child("fileRenames"))...)
+	errs = append(errs, validateFilePriorities(torrent.Spec.FilePriorities, specPath.Child("filePriorities"))...)
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(torrentv1alpha1.GroupVersion.WithKind("Torrent").GroupKind(), torrent.Name, errs)
+}
+
+// An explicit reference and a selector are mutually exclusive: the selector would be silently ignored
+func validateClientSelection(spec *torrentv1alpha1.TorrentSpec, specPath *field.Path) field.ErrorList {
+	if spec.ClientConfigRef != nil && len(spec.Selector) > 0 {
+		return field.ErrorList{field.Forbidden(specPath.Child("selector"),
+			"may not be set together with spec.clientConfigRef, which already selects the TorrentClientConfiguration")}
+	}
+	return nil
+}
+
+// The category save path only applies when the controller creates the category
+func validateCategory(spec *torrentv1alpha1.TorrentSpec, specPath *field.Path) field.ErrorList {
+	if spec.CategorySavePath == "" {
+		return nil
+	}
+	if spec.Category == "" || spec.CreateCategoryIfMissing == nil || !*spec.CreateCategoryIfMissing {
+		return field.ErrorList{field.Forbidden(specPath.Child("categorySavePath"),
+			"may only be set together with spec.category and spec.createCategoryIfMissing: the save path of an existing category is never changed")}
+	}
+	return nil
+}
+
+// Every source must be a distinct magnet link for the same info hash, as they are fallbacks for the same content
+func validateSources(spec *torrentv1alpha1.TorrentSpec, specPath *field.Path) field.ErrorList {
+	type source struct {
+		path *field.Path
+		uri  string
+	}
+	var sources []source
+	if spec.MagnetURI != "" {
+		sources = append(sources, source{specPath.Child("magnet_uri"), spec.MagnetURI})
+	}
+	for i, uri := range spec.MagnetURIs {
+		sources = append(sources, source{specPath.Child("magnetURIs").Index(i), uri})
+	}
+
+	var errs field.ErrorList
+	seen := make(map[string]bool, len(sources))
+	var firstHash string
+	var firstPath *field.Path
+	for _, src := range sources {
+		if seen[src.uri] {
+			errs = append(errs, field.Duplicate(src.path, src.uri))
+			continue
+		}
+		seen[src.uri] = true
+
+		if !strings.HasPrefix(strings.ToLower(src.uri), "magnet:?") {
+			errs = append(errs, field.Invalid(src.path, src.uri, "must be a magnet link starting with \"magnet:?\""))
+			continue
+		}
+		hash, err := qbittorrent.GetTorrentHash(src.uri)
+		if err != nil {
+			errs = append(errs, field.Invalid(src.path, src.uri, fmt.Sprintf("must carry a BitTorrent info hash (xt=urn:btih:...): %v", err)))
+			continue
+		}
+		if firstPath == nil {
+			firstHash, firstPath = hash, src.path
+		} else if hash != firstHash {
+			errs = append(errs, field.Invalid(src.path, src.uri,
+				fmt.Sprintf("info hash %s differs from %s in %s: all sources must point to the same content", hash, firstHash, firstPath)))
+		}
+	}
+
+	// An explicit hash only locates an adopted torrent, and must agree with the sources
+	if spec.Hash != "" {
+		hashPath := specPath.Child("hash")
+		if spec.Adopt == nil || !*spec.Adopt {
+			errs = append(errs, field.Forbidden(hashPath, "may only be set together with spec.adopt: the operator cannot add a torrent from its hash alone"))
+		}
+		if hash := qbittorrent.NormalizeTorrentHash(spec.Hash); firstPath != nil && hash != firstHash {
+			errs = append(errs, field.Invalid(hashPath, spec.Hash,
+				fmt.Sprintf("info hash %s differs from %s in %s: it must identify the same torrent", hash, firstHash, firstPath)))
+		}
+	}
+	return errs
+}
+
+// Rename rules must be valid patterns targeting a path inside the torrent
+func validateFileRenames(fileRenames []torrentv1alpha1.FileRename, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	seen := make(map[string]bool, len(fileRenames))
+	for i, fileRename := range fileRenames {
+		rulePath := fldPath.Index(i)
+		if _, err := qbittorrent.MatchFilePath(fileRename.Match, ""); err != nil {
+			errs = append(errs, field.Invalid(rulePath.Child("match"), fileRename.Match, "must be a valid file path pattern"))
+		} else if seen[fileRename.Match] {
+			errs = append(errs, field.Duplicate(rulePath.Child("match"), fileRename.Match))
+		}
+		seen[fileRename.Match] = true
+
+		switch {
+		case fileRename.Rename == "":
+			errs = append(errs, field.Required(rulePath.Child("rename"), "the new file path must not be empty"))
+		case path.IsAbs(fileRename.Rename) || slices.Contains(strings.Split(fileRename.Rename, "/"), ".."):
+			errs = append(errs, field.Invalid(rulePath.Child("rename"), fileRename.Rename,
+				"must be a relative path inside the torrent, without \"..\" components"))
+		}
+	}
+	return errs
+}
+
+// Priority rules must be valid patterns; a repeated pattern would never match as the first rule wins
+func validateFilePriorities(filePriorities []torrentv1alpha1.FilePriority, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	seen := make(map[string]bool, len(filePriorities))
+	for i, filePriority := range filePriorities {
+		matchPath := fldPath.Index(i).Child("match")
+		if _, err := qbittorrent.MatchFilePath(filePriority.Match, ""); err != nil {
+			errs = append(errs, field.Invalid(matchPath, filePriority.Match, "must be a valid file path pattern"))
+		} else if seen[filePriority.Match] {
+			errs = append(errs, field.Duplicate(matchPath, filePriority.Match))
+		}
+		seen[filePriority.Match] = true
+	}
+	return errs
+}
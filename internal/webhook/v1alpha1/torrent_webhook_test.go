@@ -0,0 +1,166 @@
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	torrentv1alpha1 "github.com/guidonguido/qbittorrent-operator/api/v1alpha1"
+)
+
+var _ = Describe("Torrent Webhook", func() {
+	const magnet = "magnet:?xt=urn:btih:dd8255ecdc7ca55fb0bbf81323d87062db1f6d1c&dn=Big+Buck+Bunny"
+
+	var (
+		ctx       context.Context
+		torrent   *torrentv1alpha1.Torrent
+		validator TorrentCustomValidator
+	)
+
+	// expectInvalid asserts the Torrent is rejected, reporting each given field
+	expectInvalid := func(fields ...string) {
+		_, err := validator.ValidateCreate(ctx, torrent)
+		Expect(err).To(HaveOccurred())
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		for _, f := range fields {
+			Expect(err.Error()).To(ContainSubstring(f))
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		validator = TorrentCustomValidator{}
+		torrent = &torrentv1alpha1.Torrent{
+			ObjectMeta: metav1.ObjectMeta{Name: "big-buck-bunny", Namespace: "default"},
+			Spec: torrentv1alpha1.TorrentSpec{
+				MagnetURI: magnet,
+				MagnetURIs: []string{
+					"magnet:?xt=urn:btih:DD8255ECDC7CA55FB0BBF81323D87062DB1F6D1C&tr=udp://tracker.example:1337",
+				},
+				ClientConfigRef: &torrentv1alpha1.LocalObjectReference{Name: "qbittorrent"},
+				FileRenames: []torrentv1alpha1.FileRename{
+					{Match: "*/sample.mkv", Rename: "extras/sample.mkv"},
+				},
+			},
+		}
+	})
+
+	Context("When validating a Torrent", func() {
+		It("should accept a consistent spec", func() {
+			warnings, err := validator.ValidateCreate(ctx, torrent)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("should reject a selector together with an explicit clientConfigRef", func() {
+			torrent.Spec.Selector = map[string]string{"role": "private-tracker"}
+			expectInvalid("spec.selector", "spec.clientConfigRef")
+		})
+
+		It("should reject sources that are not magnet links with an info hash", func() {
+			torrent.Spec.MagnetURIs = []string{"https://example.com/big-buck-bunny.torrent", "magnet:?dn=Big+Buck+Bunny"}
+			expectInvalid("spec.magnetURIs[0]", "spec.magnetURIs[1]", "info hash")
+		})
+
+		It("should reject sources pointing to different content", func() {
+			torrent.Spec.MagnetURIs = []string{"magnet:?xt=urn:btih:08ada5a7a6183aae1e09d831df6748d566095a10"}
+			expectInvalid("spec.magnetURIs[0]", "same content")
+		})
+
+		It("should accept an adopted torrent identified only by its hash", func() {
+			torrent.Spec.MagnetURI = ""
+			torrent.Spec.MagnetURIs = nil
+			adopt := true
+			torrent.Spec.Adopt = &adopt
+			torrent.Spec.Hash = "DD8255ECDC7CA55FB0BBF81323D87062DB1F6D1C"
+			_, err := validator.ValidateCreate(ctx, torrent)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject a hash without adopt or differing from the sources", func() {
+			torrent.Spec.Hash = "08ada5a7a6183aae1e09d831df6748d566095a10"
+			expectInvalid("spec.hash", "spec.adopt", "same torrent")
+		})
+
+		It("should reject a category save path unless the category is created by the controller", func() {
+			torrent.Spec.CategorySavePath = "/downloads/tv"
+			expectInvalid("spec.categorySavePath", "spec.createCategoryIfMissing")
+
+			create := true
+			torrent.Spec.Category = "tv"
+			torrent.Spec.CreateCategoryIfMissing = &create
+			_, err := validator.ValidateCreate(ctx, torrent)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject duplicated sources", func() {
+			torrent.Spec.MagnetURIs = []string{magnet}
+			expectInvalid("spec.magnetURIs[0]", "Duplicate value")
+		})
+
+		It("should reject invalid and escaping file renames", func() {
+			torrent.Spec.FileRenames = []torrentv1alpha1.FileRename{
+				{Match: "[", Rename: "a.mkv"},
+				{Match: "*.nfo", Rename: "../../etc/info.nfo"},
+				{Match: "*.nfo", Rename: ""},
+			}
+			expectInvalid("spec.fileRenames[0].match", "spec.fileRenames[1].rename", "spec.fileRenames[2].match", "spec.fileRenames[2].rename")
+		})
+
+		It("should reject invalid and repeated file priority patterns", func() {
+			torrent.Spec.FilePriorities = []torrentv1alpha1.FilePriority{
+				{Match: "*/Sample/*", Priority: torrentv1alpha1.FilePrioritySkip},
+				{Match: "[", Priority: torrentv1alpha1.FilePriorityHigh},
+				{Match: "*/Sample/*", Priority: torrentv1alpha1.FilePriorityHigh},
+			}
+			expectInvalid("spec.filePriorities[1].match", "spec.filePriorities[2].match", "Duplicate value")
+		})
+
+		It("should validate updates but not block the finalizer removal of a deleted Torrent", func() {
+			torrent.Spec.Selector = map[string]string{"role": "private-tracker"}
+			_, err := validator.ValidateUpdate(ctx, torrent, torrent)
+			Expect(err).To(HaveOccurred())
+
+			now := metav1.Now()
+			torrent.DeletionTimestamp = &now
+			_, err = validator.ValidateUpdate(ctx, torrent, torrent)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When defaulting the category from the namespace", func() {
+		It("should leave the category unset unless enabled", func() {
+			defaulter := TorrentCustomDefaulter{}
+			Expect(defaulter.Default(ctx, torrent)).To(Succeed())
+			Expect(torrent.Spec.Category).To(BeEmpty())
+		})
+
+		It("should set an unset category to the namespace", func() {
+			defaulter := TorrentCustomDefaulter{CategoryFromNamespace: true}
+			Expect(defaulter.Default(ctx, torrent)).To(Succeed())
+			Expect(torrent.Spec.Category).To(Equal("default"))
+		})
+
+		It("should not overwrite an explicit category", func() {
+			torrent.Spec.Category = "movies"
+			defaulter := TorrentCustomDefaulter{CategoryFromNamespace: true}
+			Expect(defaulter.Default(ctx, torrent)).To(Succeed())
+			Expect(torrent.Spec.Category).To(Equal("movies"))
+		})
+
+		It("should take the namespace from the admission request when the object omits it", func() {
+			torrent.Namespace = ""
+			reqCtx := admission.NewContextWithRequest(ctx, admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{Namespace: "tenant-a"},
+			})
+			defaulter := TorrentCustomDefaulter{CategoryFromNamespace: true}
+			Expect(defaulter.Default(reqCtx, torrent)).To(Succeed())
+			Expect(torrent.Spec.Category).To(Equal("tenant-a"))
+		})
+	})
+})
@@ -0,0 +1,111 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	torrentv1alpha1 "github.com/guidonguido/qbittorrent-operator/api/v1alpha1"
+)
+
+// ForceDeleteAnnotation set to "true" on a TorrentClientConfiguration allows deleting it
+// even while Torrents still reference it
+const ForceDeleteAnnotation = "torrent.qbittorrent.io/force-delete"
+
+// maxListedTorrents caps the Torrent names reported in a rejection message
+const maxListedTorrents = 5
+
+var tcclog = logf.Log.WithName("torrentclientconfiguration-webhook")
+
+// SetupTorrentClientConfigurationWebhookWithManager registers the webhook for TorrentClientConfiguration in the manager.
+func SetupTorrentClientConfigurationWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&torrentv1alpha1.TorrentClientConfiguration{}).
+		WithValidator(&TorrentClientConfigurationCustomValidator{Client: mgr.GetClient()}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-torrent-qbittorrent-io-v1alpha1-torrentclientconfiguration,mutating=false,failurePolicy=fail,sideEffects=None,groups=torrent.qbittorrent.io,resources=torrentclientconfigurations,verbs=delete,versions=v1alpha1,name=vtorrentclientconfiguration-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// TorrentClientConfigurationCustomValidator rejects deleting a TorrentClientConfiguration
+// while Torrents in its namespace still use it.
+type TorrentClientConfigurationCustomValidator struct {
+	Client client.Client
+}
+
+var _ webhook.CustomValidator = &TorrentClientConfigurationCustomValidator{}
+
+// ValidateCreate allows every creation.
+func (v *TorrentClientConfigurationCustomValidator) ValidateCreate(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateUpdate allows every update.
+func (v *TorrentClientConfigurationCustomValidator) ValidateUpdate(_ context.Context, _, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateDelete rejects the deletion while Torrents reference the TorrentClientConfiguration,
+// either explicitly through spec.clientConfigRef, through a spec.selector matching its labels
+// or through the auto-discovered status.clientConfigurationName.
+func (v *TorrentClientConfigurationCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	tcc, ok := obj.(*torrentv1alpha1.TorrentClientConfiguration)
+	if !ok {
+		return nil, fmt.Errorf("expected a TorrentClientConfiguration object but got %T", obj)
+	}
+	tcclog.Info("Validation for TorrentClientConfiguration upon deletion", "name", tcc.GetName(), "namespace", tcc.GetNamespace())
+
+	torrentList := &torrentv1alpha1.TorrentList{}
+	if err := v.Client.List(ctx, torrentList, client.InNamespace(tcc.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list Torrents referencing %q: %w", tcc.Name, err)
+	}
+
+	var referencing []string
+	for _, torrent := range torrentList.Items {
+		if referencesTCC(&torrent, tcc) {
+			referencing = append(referencing, torrent.Name)
+		}
+	}
+	if len(referencing) == 0 {
+		return nil, nil
+	}
+	sort.Strings(referencing)
+
+	listed := referencing
+	if len(listed) > maxListedTorrents {
+		listed = append(listed[:maxListedTorrents:maxListedTorrents], fmt.Sprintf("and %d more", len(referencing)-maxListedTorrents))
+	}
+
+	if tcc.Annotations[ForceDeleteAnnotation] == "true" {
+		return admission.Warnings{fmt.Sprintf(
+			"force-deleting TorrentClientConfiguration %q still referenced by Torrents: %s",
+			tcc.Name, strings.Join(listed, ", "),
+		)}, nil
+	}
+
+	return nil, fmt.Errorf(
+		"TorrentClientConfiguration %q is still referenced by Torrents: %s; delete them first or set the %s=true annotation",
+		tcc.Name, strings.Join(listed, ", "), ForceDeleteAnnotation,
+	)
+}
+
+// referencesTCC mirrors the TCC resolution of the Torrent controller
+func referencesTCC(torrent *torrentv1alpha1.Torrent, tcc *torrentv1alpha1.TorrentClientConfiguration) bool {
+	if torrent.Spec.ClientConfigRef != nil {
+		return torrent.Spec.ClientConfigRef.Name == tcc.Name
+	}
+	if torrent.Status.ClientConfigurationName == tcc.Name {
+		return true
+	}
+	// A selector matching the labels resolves to this TCC even before the status records it
+	return len(torrent.Spec.Selector) > 0 &&
+		labels.SelectorFromSet(torrent.Spec.Selector).Matches(labels.Set(tcc.Labels))
+}
@@ -0,0 +1,107 @@
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	torrentv1alpha1 "github.com/guidonguido/qbittorrent-operator/api/v1alpha1"
+)
+
+var _ = Describe("TorrentClientConfiguration Webhook", func() {
+	var (
+		ctx       context.Context
+		tcc       *torrentv1alpha1.TorrentClientConfiguration
+		validator TorrentClientConfigurationCustomValidator
+	)
+
+	newValidator := func(torrents ...*torrentv1alpha1.Torrent) TorrentClientConfigurationCustomValidator {
+		scheme := runtime.NewScheme()
+		Expect(torrentv1alpha1.AddToScheme(scheme)).To(Succeed())
+		builder := fake.NewClientBuilder().WithScheme(scheme)
+		for _, torrent := range torrents {
+			builder = builder.WithObjects(torrent)
+		}
+		return TorrentClientConfigurationCustomValidator{Client: builder.Build()}
+	}
+
+	newTorrent := func(name, namespace string, ref *torrentv1alpha1.LocalObjectReference, resolved string) *torrentv1alpha1.Torrent {
+		return &torrentv1alpha1.Torrent{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: torrentv1alpha1.TorrentSpec{
+				MagnetURI:       "magnet:?xt=urn:btih:dd8255ecdc7ca55fb0bbf81323d87062db1f6d1c",
+				ClientConfigRef: ref,
+			},
+			Status: torrentv1alpha1.TorrentStatus{ClientConfigurationName: resolved},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		tcc = &torrentv1alpha1.TorrentClientConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "qbittorrent", Namespace: "default"},
+		}
+	})
+
+	Context("When deleting a TorrentClientConfiguration", func() {
+		It("should allow deletion when no Torrent references it", func() {
+			validator = newValidator(
+				newTorrent("other", "default", &torrentv1alpha1.LocalObjectReference{Name: "another-tcc"}, ""),
+				newTorrent("elsewhere", "media", nil, "qbittorrent"),
+			)
+			warnings, err := validator.ValidateDelete(ctx, tcc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("should deny deletion while a Torrent references it explicitly", func() {
+			validator = newValidator(
+				newTorrent("big-buck-bunny", "default", &torrentv1alpha1.LocalObjectReference{Name: "qbittorrent"}, ""),
+			)
+			_, err := validator.ValidateDelete(ctx, tcc)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("big-buck-bunny"))
+			Expect(err.Error()).To(ContainSubstring(ForceDeleteAnnotation))
+		})
+
+		It("should deny deletion while a Torrent resolved it through auto-discovery", func() {
+			validator = newValidator(newTorrent("sintel", "default", nil, "qbittorrent"))
+			_, err := validator.ValidateDelete(ctx, tcc)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("sintel"))
+		})
+
+		It("should deny deletion while a Torrent selects it through spec.selector", func() {
+			selecting := newTorrent("tears-of-steel", "default", nil, "")
+			selecting.Spec.Selector = map[string]string{"tier": "media"}
+			other := newTorrent("cosmos-laundromat", "default", nil, "")
+			other.Spec.Selector = map[string]string{"tier": "archive"}
+
+			validator = newValidator(other)
+			tcc.Labels = map[string]string{"tier": "media"}
+			_, err := validator.ValidateDelete(ctx, tcc)
+			Expect(err).NotTo(HaveOccurred())
+
+			validator = newValidator(selecting, other)
+			_, err = validator.ValidateDelete(ctx, tcc)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("tears-of-steel"))
+			Expect(err.Error()).NotTo(ContainSubstring("cosmos-laundromat"))
+		})
+
+		It("should allow deletion with a warning when the force annotation is set", func() {
+			validator = newValidator(
+				newTorrent("big-buck-bunny", "default", &torrentv1alpha1.LocalObjectReference{Name: "qbittorrent"}, ""),
+			)
+			tcc.Annotations = map[string]string{ForceDeleteAnnotation: "true"}
+			warnings, err := validator.ValidateDelete(ctx, tcc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(HaveLen(1))
+			Expect(warnings[0]).To(ContainSubstring("big-buck-bunny"))
+		})
+	})
+})